@@ -26,11 +26,23 @@ type JWTConfig struct {
 	Expiration time.Duration
 }
 
+// StorageConfig holds the blob storage configuration for uploaded files
+type StorageConfig struct {
+	Driver      string
+	LocalPath   string
+	S3Endpoint  string
+	S3Region    string
+	S3Bucket    string
+	S3AccessKey string
+	S3SecretKey string
+}
+
 // AppConfig holds application configuration
 type AppConfig struct {
 	Environment string
 	Database    DatabaseConfig
 	JWT         JWTConfig
+	Storage     StorageConfig
 }
 
 // LoadConfig loads configuration from environment variables
@@ -81,6 +93,17 @@ func LoadConfig() (*AppConfig, error) {
 		jwtExpiration = time.Duration(jwtExpirationInt) * time.Hour
 	}
 
+	// Storage configuration
+	storageDriver := os.Getenv("STORAGE_DRIVER")
+	if storageDriver == "" {
+		storageDriver = "local"
+	}
+
+	storagePath := os.Getenv("STORAGE_LOCAL_PATH")
+	if storagePath == "" {
+		storagePath = "uploads"
+	}
+
 	return &AppConfig{
 		Environment: env,
 		Database: DatabaseConfig{
@@ -95,5 +118,14 @@ func LoadConfig() (*AppConfig, error) {
 			Secret:     jwtSecret,
 			Expiration: jwtExpiration,
 		},
+		Storage: StorageConfig{
+			Driver:      storageDriver,
+			LocalPath:   storagePath,
+			S3Endpoint:  os.Getenv("S3_ENDPOINT"),
+			S3Region:    os.Getenv("S3_REGION"),
+			S3Bucket:    os.Getenv("S3_BUCKET"),
+			S3AccessKey: os.Getenv("S3_ACCESS_KEY"),
+			S3SecretKey: os.Getenv("S3_SECRET_KEY"),
+		},
 	}, nil
 }
@@ -0,0 +1,185 @@
+// Package dbtest connects tests to a scratch PostgreSQL database and fills it with what they need
+package dbtest
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"assessment-management-system/config"
+	"assessment-management-system/db"
+	"assessment-management-system/migrations"
+	"assessment-management-system/models"
+	"assessment-management-system/repositories"
+)
+
+var (
+	once     sync.Once
+	database *db.DB
+	openErr  error
+	sequence int64
+)
+
+// Open connects to the database named by TEST_DATABASE_URL and brings its schema up to date, or skips the test when
+// no test database is set. Tests share the database, so each one creates its own organization to work in.
+func Open(t *testing.T) *db.DB {
+	t.Helper()
+
+	url := os.Getenv("TEST_DATABASE_URL")
+	if url == "" {
+		t.Skip("TEST_DATABASE_URL is not set")
+	}
+
+	once.Do(func() {
+		database, openErr = db.Connect(config.DatabaseConfig{URL: url})
+		if openErr == nil {
+			openErr = migrations.RunMigrations(database)
+		}
+	})
+	if openErr != nil {
+		t.Fatalf("failed to open the test database: %v", openErr)
+	}
+	return database
+}
+
+// unique returns a name no other test uses
+func unique(prefix string) string {
+	return fmt.Sprintf("%s-%d-%d", prefix, time.Now().UnixNano(), atomic.AddInt64(&sequence, 1))
+}
+
+// Organization creates an organization
+func Organization(t *testing.T, database *db.DB) *models.Organization {
+	t.Helper()
+
+	organization, err := repositories.NewOrganizationRepository(database).Create(context.Background(), unique("org"), "")
+	if err != nil {
+		t.Fatalf("failed to create organization: %v", err)
+	}
+	return organization
+}
+
+// User creates a user of an organization with the given role
+func User(t *testing.T, database *db.DB, organizationID string, role models.UserRole) *models.User {
+	t.Helper()
+
+	email := unique(string(role)) + "@example.com"
+	user, err := repositories.NewUserRepository(database).Create(context.Background(), organizationID, email, "x",
+		"Test", string(role), string(role))
+	if err != nil {
+		t.Fatalf("failed to create %s: %v", role, err)
+	}
+	return user
+}
+
+// Term creates a term of an organization running from start to end
+func Term(t *testing.T, database *db.DB, organizationID string, start, end time.Time) *models.Term {
+	t.Helper()
+
+	term, err := repositories.NewTermRepository(database).Create(context.Background(), organizationID, models.CreateTermRequest{
+		Name:      unique("term"),
+		StartDate: start,
+		EndDate:   end,
+	})
+	if err != nil {
+		t.Fatalf("failed to create term: %v", err)
+	}
+	return term
+}
+
+// Course creates a course in a term that takes at most maxStudents students, any number when nil
+func Course(t *testing.T, database *db.DB, organizationID, termID, name string, maxStudents *int) *models.Course {
+	t.Helper()
+
+	course, err := repositories.NewCourseRepository(database).Create(context.Background(), organizationID, termID, name, "",
+		true, maxStudents)
+	if err != nil {
+		t.Fatalf("failed to create course: %v", err)
+	}
+	return course
+}
+
+// CurrentTerm creates a term of an organization that is running now
+func CurrentTerm(t *testing.T, database *db.DB, organizationID string) *models.Term {
+	t.Helper()
+
+	now := time.Now()
+	return Term(t, database, organizationID, now.AddDate(0, -1, 0), now.AddDate(0, 3, 0))
+}
+
+// Teach assigns a teacher to a course
+func Teach(t *testing.T, database *db.DB, courseID, teacherID string) {
+	t.Helper()
+
+	if err := repositories.NewCourseRepository(database).AssignTeacher(context.Background(), courseID, teacherID); err != nil {
+		t.Fatalf("failed to assign teacher: %v", err)
+	}
+}
+
+// Section creates a section of a course taught by the given teachers
+func Section(t *testing.T, database *db.DB, courseID string, teacherIDs ...string) *models.CourseSection {
+	t.Helper()
+	ctx := context.Background()
+
+	sections := repositories.NewSectionRepository(database)
+	section, err := sections.Create(ctx, courseID, unique("section"))
+	if err != nil {
+		t.Fatalf("failed to create section: %v", err)
+	}
+
+	for _, teacherID := range teacherIDs {
+		if err := sections.AssignTeacher(ctx, section.ID, teacherID); err != nil {
+			t.Fatalf("failed to assign section teacher: %v", err)
+		}
+	}
+	return section
+}
+
+// Enroll enrolls a student in a course, failing the test when the student has to wait for a seat
+func Enroll(t *testing.T, database *db.DB, courseID, studentID string, sectionID *string) {
+	t.Helper()
+
+	entry, err := repositories.NewCourseRepository(database).EnrollStudent(context.Background(), courseID, studentID, sectionID, nil)
+	if err != nil || entry != nil {
+		t.Fatalf("failed to enroll student: %v, %v", entry, err)
+	}
+}
+
+// Assessment creates a published assessment of the teacher from the request, with a title and a maximum score of
+// 100 unless the request sets them
+func Assessment(t *testing.T, database *db.DB, teacherID string, req models.CreateAssessmentRequest) *models.Assessment {
+	t.Helper()
+
+	if req.Title == "" {
+		req.Title = unique("assessment")
+	}
+	if req.Type == "" {
+		req.Type = models.AssessmentTypeAssignment
+	}
+	if req.MaxScore == 0 {
+		req.MaxScore = 100
+	}
+	if req.Status == "" {
+		req.Status = models.AssessmentStatusPublished
+	}
+
+	assessment, err := repositories.NewAssessmentRepository(database).Create(context.Background(), teacherID, req)
+	if err != nil {
+		t.Fatalf("failed to create assessment: %v", err)
+	}
+	return assessment
+}
+
+// Submission creates a student's submission to an assessment
+func Submission(t *testing.T, database *db.DB, assessmentID, studentID, content string) *models.AssessmentSubmission {
+	t.Helper()
+
+	submission, err := repositories.NewAssessmentRepository(database).CreateSubmission(context.Background(), assessmentID, studentID, content)
+	if err != nil {
+		t.Fatalf("failed to create submission: %v", err)
+	}
+	return submission
+}
@@ -0,0 +1,106 @@
+package admin
+
+import (
+	"net/http"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+
+	"assessment-management-system/middleware"
+	"assessment-management-system/models"
+	"assessment-management-system/services"
+	"assessment-management-system/utils"
+)
+
+// AnonymousGradingHandler handles revealing the students of assessments graded anonymously
+type AnonymousGradingHandler struct {
+	assessmentService *services.AssessmentService
+	courseService     *services.CourseService
+	validator         *validator.Validate
+}
+
+// NewAnonymousGradingHandler creates a new AnonymousGradingHandler
+func NewAnonymousGradingHandler(
+	assessmentService *services.AssessmentService,
+	courseService *services.CourseService,
+) *AnonymousGradingHandler {
+	return &AnonymousGradingHandler{
+		assessmentService: assessmentService,
+		courseService:     courseService,
+		validator:         utils.NewValidator(),
+	}
+}
+
+// authorizeAssessment loads the assessment and checks that it belongs to the admin's organization
+func (h *AnonymousGradingHandler) authorizeAssessment(c echo.Context) (*models.Assessment, *models.User, error) {
+	id := c.Param("id")
+	if id == "" {
+		return nil, nil, echo.NewHTTPError(http.StatusBadRequest, "Assessment ID is required")
+	}
+
+	// Get the admin's organization ID from the token
+	admin, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	assessment, err := h.assessmentService.GetAssessmentByID(c.Request().Context(), id)
+	if err != nil {
+		return nil, nil, echo.NewHTTPError(http.StatusInternalServerError, "Failed to retrieve assessment: "+err.Error())
+	}
+
+	if assessment == nil {
+		return nil, nil, echo.NewHTTPError(http.StatusNotFound, "Assessment not found")
+	}
+
+	// Verify that the assessment belongs to a course in the admin's organization
+	course, err := h.courseService.GetCourseByID(c.Request().Context(), assessment.CourseID)
+	if err != nil {
+		return nil, nil, echo.NewHTTPError(http.StatusInternalServerError, "Failed to retrieve course: "+err.Error())
+	}
+
+	if course == nil || course.OrganizationID != admin.OrganizationID {
+		return nil, nil, echo.NewHTTPError(http.StatusForbidden, "Access denied to assessment from another organization")
+	}
+
+	return assessment, admin, nil
+}
+
+// HandleUnmask handles revealing the students behind the pseudonyms of an assessment. Every unmasking is logged.
+func (h *AnonymousGradingHandler) HandleUnmask(c echo.Context) error {
+	var req models.UnmaskRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid request body")
+	}
+
+	if err := utils.ValidateStruct(h.validator, req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, utils.FormatValidationErrors(err))
+	}
+
+	assessment, admin, err := h.authorizeAssessment(c)
+	if err != nil {
+		return err
+	}
+
+	identities, err := h.assessmentService.UnmaskStudents(c.Request().Context(), assessment, admin.ID, req.Reason)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Failed to unmask students: "+err.Error())
+	}
+
+	return c.JSON(http.StatusOK, identities)
+}
+
+// HandleGetUnmaskLog handles listing every time the students of an assessment were revealed
+func (h *AnonymousGradingHandler) HandleGetUnmaskLog(c echo.Context) error {
+	assessment, _, err := h.authorizeAssessment(c)
+	if err != nil {
+		return err
+	}
+
+	entries, err := h.assessmentService.GetUnmaskLog(c.Request().Context(), assessment.ID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to retrieve unmask log: "+err.Error())
+	}
+
+	return c.JSON(http.StatusOK, entries)
+}
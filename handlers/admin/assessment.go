@@ -118,9 +118,8 @@ func (h *AssessmentHandler) HandleGetAssessmentSubmissions(c echo.Context) error
 	return c.JSON(http.StatusOK, submissions)
 }
 
-// HandleGetSubmissionGrades handles retrieving grades for a submission
-func (h *AssessmentHandler) HandleGetSubmissionGrades(c echo.Context) error {
-	submissionID := c.Param("submissionId")
+// authorizeSubmission checks that a submission belongs to the admin's organization
+func (h *AssessmentHandler) authorizeSubmission(c echo.Context, submissionID string) error {
 	if submissionID == "" {
 		return echo.NewHTTPError(http.StatusBadRequest, "Submission ID is required")
 	}
@@ -157,6 +156,16 @@ func (h *AssessmentHandler) HandleGetSubmissionGrades(c echo.Context) error {
 		return echo.NewHTTPError(http.StatusForbidden, "Access denied to submission from another organization")
 	}
 
+	return nil
+}
+
+// HandleGetSubmissionGrades handles retrieving grades for a submission
+func (h *AssessmentHandler) HandleGetSubmissionGrades(c echo.Context) error {
+	submissionID := c.Param("submissionId")
+	if err := h.authorizeSubmission(c, submissionID); err != nil {
+		return err
+	}
+
 	grade, err := h.assessmentService.GetSubmissionGrade(c.Request().Context(), submissionID)
 	if err != nil {
 		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to retrieve grade: "+err.Error())
@@ -168,3 +177,18 @@ func (h *AssessmentHandler) HandleGetSubmissionGrades(c echo.Context) error {
 
 	return c.JSON(http.StatusOK, grade)
 }
+
+// HandleGetGradeHistory handles retrieving every version of a submission's grade
+func (h *AssessmentHandler) HandleGetGradeHistory(c echo.Context) error {
+	submissionID := c.Param("submissionId")
+	if err := h.authorizeSubmission(c, submissionID); err != nil {
+		return err
+	}
+
+	versions, err := h.assessmentService.GetGradeHistory(c.Request().Context(), submissionID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to retrieve grade history: "+err.Error())
+	}
+
+	return c.JSON(http.StatusOK, versions)
+}
@@ -378,9 +378,16 @@ func (h *CourseHandler) HandleManageStudentEnrollment(c echo.Context) error {
 
 	action := c.QueryParam("action")
 	if action == "enroll" {
-		if err := h.courseService.EnrollStudentInCourse(c.Request().Context(), courseID, req.StudentID); err != nil {
+		waiting, err := h.courseService.EnrollStudentInCourse(c.Request().Context(), courseID, req.StudentID, req.SectionID)
+		if err != nil {
 			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to enroll student in course: "+err.Error())
 		}
+		if waiting != nil {
+			return c.JSON(http.StatusAccepted, map[string]interface{}{
+				"message":           "Course is full, student added to the waitlist",
+				"waitlist_position": waiting.Position,
+			})
+		}
 		return c.JSON(http.StatusOK, map[string]string{"message": "Student enrolled successfully"})
 	} else if action == "unenroll" {
 		if err := h.courseService.UnenrollStudentFromCourse(c.Request().Context(), courseID, req.StudentID); err != nil {
@@ -428,7 +435,7 @@ func (h *CourseHandler) HandleBulkEnrollStudents(c echo.Context) error {
 		return echo.NewHTTPError(http.StatusForbidden, "Access denied to course from another organization")
 	}
 
-	results, err := h.courseService.BulkEnrollStudents(c.Request().Context(), courseID, req.StudentIDs)
+	results, err := h.courseService.BulkEnrollStudents(c.Request().Context(), courseID, req.StudentIDs, req.SectionID)
 	if err != nil {
 		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to bulk enroll students: "+err.Error())
 	}
@@ -470,3 +477,18 @@ func (h *CourseHandler) HandleGetCourseStudents(c echo.Context) error {
 
 	return c.JSON(http.StatusOK, students)
 }
+
+// HandleGetWaitlist handles listing the students waiting for a seat in a course, in the order they will be enrolled
+func (h *CourseHandler) HandleGetWaitlist(c echo.Context) error {
+	course, err := h.authorizeCourse(c)
+	if err != nil {
+		return err
+	}
+
+	waitlist, err := h.courseService.GetWaitlist(c.Request().Context(), course.ID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to retrieve waitlist: "+err.Error())
+	}
+
+	return c.JSON(http.StatusOK, waitlist)
+}
@@ -0,0 +1,128 @@
+package admin
+
+import (
+	"net/http"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+
+	"assessment-management-system/handlers"
+	"assessment-management-system/middleware"
+	"assessment-management-system/models"
+	"assessment-management-system/services"
+	"assessment-management-system/utils"
+)
+
+// ExtensionHandler handles per-student extension routes for admin
+type ExtensionHandler struct {
+	extensionService  *services.ExtensionService
+	assessmentService *services.AssessmentService
+	courseService     *services.CourseService
+	validator         *validator.Validate
+}
+
+// NewExtensionHandler creates a new ExtensionHandler
+func NewExtensionHandler(
+	extensionService *services.ExtensionService,
+	assessmentService *services.AssessmentService,
+	courseService *services.CourseService,
+) *ExtensionHandler {
+	return &ExtensionHandler{
+		extensionService:  extensionService,
+		assessmentService: assessmentService,
+		courseService:     courseService,
+		validator:         utils.NewValidator(),
+	}
+}
+
+// authorizeAssessment loads the assessment and checks that it belongs to the admin's organization
+func (h *ExtensionHandler) authorizeAssessment(c echo.Context) (*models.Assessment, *models.User, error) {
+	id := c.Param("id")
+	if id == "" {
+		return nil, nil, echo.NewHTTPError(http.StatusBadRequest, "Assessment ID is required")
+	}
+
+	// Get the admin's organization ID from the token
+	admin, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	assessment, err := h.assessmentService.GetAssessmentByID(c.Request().Context(), id)
+	if err != nil {
+		return nil, nil, echo.NewHTTPError(http.StatusInternalServerError, "Failed to retrieve assessment: "+err.Error())
+	}
+
+	if assessment == nil {
+		return nil, nil, echo.NewHTTPError(http.StatusNotFound, "Assessment not found")
+	}
+
+	// Verify that the assessment belongs to a course in the admin's organization
+	course, err := h.courseService.GetCourseByID(c.Request().Context(), assessment.CourseID)
+	if err != nil {
+		return nil, nil, echo.NewHTTPError(http.StatusInternalServerError, "Failed to retrieve course: "+err.Error())
+	}
+
+	if course == nil || course.OrganizationID != admin.OrganizationID {
+		return nil, nil, echo.NewHTTPError(http.StatusForbidden, "Access denied to assessment from another organization")
+	}
+
+	if err := handlers.CheckCourseWritable(c, h.courseService, assessment.CourseID); err != nil {
+		return nil, nil, err
+	}
+
+	return assessment, admin, nil
+}
+
+// HandleGrantExtension handles granting or replacing a student's extension
+func (h *ExtensionHandler) HandleGrantExtension(c echo.Context) error {
+	var req models.GrantExtensionRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid request body")
+	}
+
+	if err := utils.ValidateStruct(h.validator, req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, utils.FormatValidationErrors(err))
+	}
+
+	assessment, admin, err := h.authorizeAssessment(c)
+	if err != nil {
+		return err
+	}
+
+	extension, err := h.extensionService.GrantExtension(c.Request().Context(), assessment.ID, c.Param("studentId"), admin.ID, req)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Failed to grant extension: "+err.Error())
+	}
+
+	return c.JSON(http.StatusOK, extension)
+}
+
+// HandleGetExtensions handles listing the extensions granted for an assessment
+func (h *ExtensionHandler) HandleGetExtensions(c echo.Context) error {
+	assessment, _, err := h.authorizeAssessment(c)
+	if err != nil {
+		return err
+	}
+
+	extensions, err := h.extensionService.GetExtensions(c.Request().Context(), assessment.ID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to retrieve extensions: "+err.Error())
+	}
+
+	return c.JSON(http.StatusOK, extensions)
+}
+
+// HandleRevokeExtension handles removing a student's extension
+func (h *ExtensionHandler) HandleRevokeExtension(c echo.Context) error {
+	assessment, _, err := h.authorizeAssessment(c)
+	if err != nil {
+		return err
+	}
+
+	if err := h.extensionService.RevokeExtension(c.Request().Context(), assessment.ID, c.Param("studentId")); err != nil {
+		return echo.NewHTTPError(http.StatusNotFound, "Failed to revoke extension: "+err.Error())
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
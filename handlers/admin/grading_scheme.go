@@ -0,0 +1,103 @@
+package admin
+
+import (
+	"net/http"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+
+	"assessment-management-system/middleware"
+	"assessment-management-system/models"
+	"assessment-management-system/services"
+	"assessment-management-system/utils"
+)
+
+// GradingSchemeHandler handles organization grading scheme routes for admin
+type GradingSchemeHandler struct {
+	schemeService *services.GradingSchemeService
+	validator     *validator.Validate
+}
+
+// NewGradingSchemeHandler creates a new GradingSchemeHandler
+func NewGradingSchemeHandler(schemeService *services.GradingSchemeService) *GradingSchemeHandler {
+	return &GradingSchemeHandler{
+		schemeService: schemeService,
+		validator:     utils.NewValidator(),
+	}
+}
+
+// authorizeOrganization checks that the admin belongs to the organization
+func (h *GradingSchemeHandler) authorizeOrganization(c echo.Context, organizationID string) error {
+	if organizationID == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "Organization ID is required")
+	}
+
+	// Get the admin's information from the token to verify permissions
+	admin, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		return err
+	}
+
+	if admin.OrganizationID != organizationID {
+		return echo.NewHTTPError(http.StatusForbidden, "You do not have permission to manage this organization")
+	}
+
+	return nil
+}
+
+// HandleSaveGradingScheme handles defining or replacing the grading scheme of an organization
+func (h *GradingSchemeHandler) HandleSaveGradingScheme(c echo.Context) error {
+	var req models.SaveGradingSchemeRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid request body")
+	}
+
+	if err := utils.ValidateStruct(h.validator, req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, utils.FormatValidationErrors(err))
+	}
+
+	organizationID := c.Param("id")
+	if err := h.authorizeOrganization(c, organizationID); err != nil {
+		return err
+	}
+
+	scheme, err := h.schemeService.SaveOrganizationScheme(c.Request().Context(), organizationID, req)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Failed to save grading scheme: "+err.Error())
+	}
+
+	return c.JSON(http.StatusOK, scheme)
+}
+
+// HandleGetGradingScheme handles retrieving the grading scheme of an organization
+func (h *GradingSchemeHandler) HandleGetGradingScheme(c echo.Context) error {
+	organizationID := c.Param("id")
+	if err := h.authorizeOrganization(c, organizationID); err != nil {
+		return err
+	}
+
+	scheme, err := h.schemeService.GetOrganizationScheme(c.Request().Context(), organizationID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to retrieve grading scheme: "+err.Error())
+	}
+
+	if scheme == nil {
+		return echo.NewHTTPError(http.StatusNotFound, "Grading scheme not found")
+	}
+
+	return c.JSON(http.StatusOK, scheme)
+}
+
+// HandleDeleteGradingScheme handles deleting the grading scheme of an organization
+func (h *GradingSchemeHandler) HandleDeleteGradingScheme(c echo.Context) error {
+	organizationID := c.Param("id")
+	if err := h.authorizeOrganization(c, organizationID); err != nil {
+		return err
+	}
+
+	if err := h.schemeService.DeleteOrganizationScheme(c.Request().Context(), organizationID); err != nil {
+		return echo.NewHTTPError(http.StatusNotFound, "Failed to delete grading scheme: "+err.Error())
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
@@ -0,0 +1,55 @@
+package admin
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"assessment-management-system/models"
+	"assessment-management-system/utils"
+)
+
+// HandleGetPrerequisites handles listing the prerequisites of a course
+func (h *CourseHandler) HandleGetPrerequisites(c echo.Context) error {
+	course, err := h.authorizeCourse(c)
+	if err != nil {
+		return err
+	}
+
+	prerequisites, err := h.courseService.GetPrerequisites(c.Request().Context(), course.ID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to retrieve prerequisites: "+err.Error())
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"mode":          course.PrerequisiteMode,
+		"prerequisites": prerequisites,
+	})
+}
+
+// HandleSetPrerequisites handles replacing the prerequisites of a course
+func (h *CourseHandler) HandleSetPrerequisites(c echo.Context) error {
+	var req models.SetPrerequisitesRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid request body")
+	}
+
+	if err := utils.ValidateStruct(h.validator, req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, utils.FormatValidationErrors(err))
+	}
+
+	course, err := h.authorizeCourse(c)
+	if err != nil {
+		return err
+	}
+
+	prerequisites, err := h.courseService.SetPrerequisites(c.Request().Context(), course.ID, req)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Failed to set prerequisites: "+err.Error())
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"mode":          req.Mode,
+		"prerequisites": prerequisites,
+	})
+}
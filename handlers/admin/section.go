@@ -0,0 +1,227 @@
+package admin
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"assessment-management-system/middleware"
+	"assessment-management-system/models"
+	"assessment-management-system/utils"
+)
+
+// authorizeCourse checks that the course in the URL belongs to the admin's organization
+func (h *CourseHandler) authorizeCourse(c echo.Context) (*models.Course, error) {
+	courseID := c.Param("id")
+	if courseID == "" {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, "Course ID is required")
+	}
+
+	// Get the admin's organization ID from the token
+	admin, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		return nil, err
+	}
+
+	course, err := h.courseService.GetCourseByID(c.Request().Context(), courseID)
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, "Failed to retrieve course: "+err.Error())
+	}
+
+	if course == nil {
+		return nil, echo.NewHTTPError(http.StatusNotFound, "Course not found")
+	}
+
+	if course.OrganizationID != admin.OrganizationID {
+		return nil, echo.NewHTTPError(http.StatusForbidden, "Access denied to course from another organization")
+	}
+
+	return course, nil
+}
+
+// authorizeSection loads the section in the URL and checks that it belongs to the course in the URL, which must
+// belong to the admin's organization
+func (h *CourseHandler) authorizeSection(c echo.Context) (*models.CourseSection, error) {
+	course, err := h.authorizeCourse(c)
+	if err != nil {
+		return nil, err
+	}
+
+	sectionID := c.Param("sectionId")
+	if sectionID == "" {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, "Section ID is required")
+	}
+
+	section, err := h.courseService.GetSection(c.Request().Context(), sectionID)
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, "Failed to retrieve section: "+err.Error())
+	}
+
+	if section == nil || section.CourseID != course.ID {
+		return nil, echo.NewHTTPError(http.StatusNotFound, "Section not found")
+	}
+
+	return section, nil
+}
+
+// HandleCreateSection handles creating a section in a course
+func (h *CourseHandler) HandleCreateSection(c echo.Context) error {
+	var req models.CreateSectionRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid request body")
+	}
+
+	if err := utils.ValidateStruct(h.validator, req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, utils.FormatValidationErrors(err))
+	}
+
+	course, err := h.authorizeCourse(c)
+	if err != nil {
+		return err
+	}
+
+	section, err := h.courseService.CreateSection(c.Request().Context(), course.ID, req)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Failed to create section: "+err.Error())
+	}
+
+	return c.JSON(http.StatusCreated, section)
+}
+
+// HandleGetSections handles listing the sections of a course with their teachers and student counts
+func (h *CourseHandler) HandleGetSections(c echo.Context) error {
+	course, err := h.authorizeCourse(c)
+	if err != nil {
+		return err
+	}
+
+	sections, err := h.courseService.GetCourseSections(c.Request().Context(), course.ID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to retrieve sections: "+err.Error())
+	}
+
+	return c.JSON(http.StatusOK, sections)
+}
+
+// HandleUpdateSection handles renaming a section
+func (h *CourseHandler) HandleUpdateSection(c echo.Context) error {
+	var req models.UpdateSectionRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid request body")
+	}
+
+	if err := utils.ValidateStruct(h.validator, req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, utils.FormatValidationErrors(err))
+	}
+
+	section, err := h.authorizeSection(c)
+	if err != nil {
+		return err
+	}
+
+	section, err = h.courseService.UpdateSection(c.Request().Context(), section, req)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Failed to update section: "+err.Error())
+	}
+
+	return c.JSON(http.StatusOK, section)
+}
+
+// HandleDeleteSection handles deleting a section without students or assessments
+func (h *CourseHandler) HandleDeleteSection(c echo.Context) error {
+	section, err := h.authorizeSection(c)
+	if err != nil {
+		return err
+	}
+
+	if err := h.courseService.DeleteSection(c.Request().Context(), section); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Failed to delete section: "+err.Error())
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// HandleGetSectionStudents handles listing the students enrolled in a section
+func (h *CourseHandler) HandleGetSectionStudents(c echo.Context) error {
+	section, err := h.authorizeSection(c)
+	if err != nil {
+		return err
+	}
+
+	students, err := h.courseService.GetSectionStudents(c.Request().Context(), section.ID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to retrieve section students: "+err.Error())
+	}
+
+	return c.JSON(http.StatusOK, students)
+}
+
+// HandleAssignSectionTeacher handles assigning one of the course's teachers to a section
+func (h *CourseHandler) HandleAssignSectionTeacher(c echo.Context) error {
+	var req models.AssignTeacherRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid request body")
+	}
+
+	if err := utils.ValidateStruct(h.validator, req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, utils.FormatValidationErrors(err))
+	}
+
+	section, err := h.authorizeSection(c)
+	if err != nil {
+		return err
+	}
+
+	if err := h.courseService.AssignSectionTeacher(c.Request().Context(), section, req.TeacherID); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Failed to assign teacher to section: "+err.Error())
+	}
+
+	return c.JSON(http.StatusOK, map[string]string{"message": "Teacher assigned to section successfully"})
+}
+
+// HandleRemoveSectionTeacher handles removing a teacher from a section
+func (h *CourseHandler) HandleRemoveSectionTeacher(c echo.Context) error {
+	teacherID := c.Param("teacherId")
+	if teacherID == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "Teacher ID is required")
+	}
+
+	section, err := h.authorizeSection(c)
+	if err != nil {
+		return err
+	}
+
+	if err := h.courseService.RemoveSectionTeacher(c.Request().Context(), section, teacherID); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Failed to remove teacher from section: "+err.Error())
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// HandleChangeStudentSection handles moving an enrolled student to another section of the course
+func (h *CourseHandler) HandleChangeStudentSection(c echo.Context) error {
+	studentID := c.Param("studentId")
+	if studentID == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "Student ID is required")
+	}
+
+	var req models.ChangeSectionRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid request body")
+	}
+
+	if err := utils.ValidateStruct(h.validator, req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, utils.FormatValidationErrors(err))
+	}
+
+	course, err := h.authorizeCourse(c)
+	if err != nil {
+		return err
+	}
+
+	if err := h.courseService.ChangeStudentSection(c.Request().Context(), course.ID, studentID, req.SectionID); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Failed to change section: "+err.Error())
+	}
+
+	return c.JSON(http.StatusOK, map[string]string{"message": "Student moved to section successfully"})
+}
@@ -0,0 +1,145 @@
+package admin
+
+import (
+	"net/http"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+
+	"assessment-management-system/middleware"
+	"assessment-management-system/models"
+	"assessment-management-system/services"
+	"assessment-management-system/utils"
+)
+
+// TermHandler handles academic term routes for admin
+type TermHandler struct {
+	termService *services.TermService
+	validator   *validator.Validate
+}
+
+// NewTermHandler creates a new TermHandler
+func NewTermHandler(termService *services.TermService) *TermHandler {
+	return &TermHandler{
+		termService: termService,
+		validator:   utils.NewValidator(),
+	}
+}
+
+// getTerm loads the term in the URL and checks that it belongs to the admin's organization
+func (h *TermHandler) getTerm(c echo.Context) (*models.Term, error) {
+	id := c.Param("id")
+	if id == "" {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, "Term ID is required")
+	}
+
+	// Get the admin's organization ID from the token
+	admin, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		return nil, err
+	}
+
+	term, err := h.termService.GetTerm(c.Request().Context(), id)
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, "Failed to retrieve term: "+err.Error())
+	}
+
+	if term == nil {
+		return nil, echo.NewHTTPError(http.StatusNotFound, "Term not found")
+	}
+
+	if term.OrganizationID != admin.OrganizationID {
+		return nil, echo.NewHTTPError(http.StatusForbidden, "Access denied to term from another organization")
+	}
+
+	return term, nil
+}
+
+// HandleCreateTerm handles creating an academic term in the admin's organization
+func (h *TermHandler) HandleCreateTerm(c echo.Context) error {
+	var req models.CreateTermRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid request body")
+	}
+
+	if err := utils.ValidateStruct(h.validator, req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, utils.FormatValidationErrors(err))
+	}
+
+	// Get the admin's organization ID from the token
+	admin, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		return err
+	}
+
+	term, err := h.termService.CreateTerm(c.Request().Context(), admin.OrganizationID, req)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Failed to create term: "+err.Error())
+	}
+
+	return c.JSON(http.StatusCreated, term)
+}
+
+// HandleGetTerms handles retrieving the academic terms of the admin's organization
+func (h *TermHandler) HandleGetTerms(c echo.Context) error {
+	// Get the admin's organization ID from the token
+	admin, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		return err
+	}
+
+	terms, err := h.termService.GetTerms(c.Request().Context(), admin.OrganizationID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to retrieve terms: "+err.Error())
+	}
+
+	return c.JSON(http.StatusOK, terms)
+}
+
+// HandleGetTerm handles retrieving an academic term by ID
+func (h *TermHandler) HandleGetTerm(c echo.Context) error {
+	term, err := h.getTerm(c)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, term)
+}
+
+// HandleUpdateTerm handles updating an academic term
+func (h *TermHandler) HandleUpdateTerm(c echo.Context) error {
+	var req models.UpdateTermRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid request body")
+	}
+
+	if err := utils.ValidateStruct(h.validator, req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, utils.FormatValidationErrors(err))
+	}
+
+	term, err := h.getTerm(c)
+	if err != nil {
+		return err
+	}
+
+	updated, err := h.termService.UpdateTerm(c.Request().Context(), term, req)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Failed to update term: "+err.Error())
+	}
+
+	return c.JSON(http.StatusOK, updated)
+}
+
+// HandleDeleteTerm handles deleting an academic term without courses
+func (h *TermHandler) HandleDeleteTerm(c echo.Context) error {
+	term, err := h.getTerm(c)
+	if err != nil {
+		return err
+	}
+
+	if err := h.termService.DeleteTerm(c.Request().Context(), term); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Failed to delete term: "+err.Error())
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
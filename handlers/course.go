@@ -0,0 +1,26 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"assessment-management-system/services"
+)
+
+// CheckCourseWritable refuses changes to a course whose term has ended; reading stays allowed
+func CheckCourseWritable(c echo.Context, courseService *services.CourseService, courseID string) error {
+	if c.Request().Method == http.MethodGet {
+		return nil
+	}
+
+	if err := courseService.CheckCourseWritable(c.Request().Context(), courseID); err != nil {
+		if errors.Is(err, services.ErrCourseArchived) {
+			return echo.NewHTTPError(http.StatusForbidden, "The course's term has ended, the course is read-only")
+		}
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to check course: "+err.Error())
+	}
+
+	return nil
+}
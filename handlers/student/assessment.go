@@ -1,12 +1,17 @@
 package student
 
 import (
+	"encoding/json"
+	"mime"
+	"mime/multipart"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/go-playground/validator/v10"
 	"github.com/labstack/echo/v4"
 
+	"assessment-management-system/handlers"
 	"assessment-management-system/middleware"
 	"assessment-management-system/models"
 	"assessment-management-system/services"
@@ -17,6 +22,7 @@ import (
 type AssessmentHandler struct {
 	assessmentService *services.AssessmentService
 	courseService     *services.CourseService
+	questionService   *services.QuestionService
 	validator         *validator.Validate
 }
 
@@ -24,14 +30,45 @@ type AssessmentHandler struct {
 func NewAssessmentHandler(
 	assessmentService *services.AssessmentService,
 	courseService *services.CourseService,
+	questionService *services.QuestionService,
 ) *AssessmentHandler {
 	return &AssessmentHandler{
 		assessmentService: assessmentService,
 		courseService:     courseService,
+		questionService:   questionService,
 		validator:         utils.NewValidator(),
 	}
 }
 
+// getVisibleAssessment loads an assessment as it applies to the student, with their extension if any. Assessments
+// students cannot see yet are reported as not found, and the student must be enrolled in the course.
+func (h *AssessmentHandler) getVisibleAssessment(c echo.Context, id, studentID string) (*models.Assessment, error) {
+	assessment, err := h.assessmentService.GetAssessmentForStudent(c.Request().Context(), id, studentID)
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, "Failed to retrieve assessment: "+err.Error())
+	}
+
+	if assessment == nil || !assessment.IsVisibleToStudents() {
+		return nil, echo.NewHTTPError(http.StatusNotFound, "Assessment not found")
+	}
+
+	// Check if the student is enrolled in the course
+	isEnrolled, err := h.courseService.IsStudentEnrolledInCourse(c.Request().Context(), assessment.CourseID, studentID)
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, "Failed to check enrollment: "+err.Error())
+	}
+
+	if !isEnrolled {
+		return nil, echo.NewHTTPError(http.StatusForbidden, "You are not enrolled in this course")
+	}
+
+	if err := handlers.CheckCourseWritable(c, h.courseService, assessment.CourseID); err != nil {
+		return nil, err
+	}
+
+	return assessment, nil
+}
+
 // HandleGetCourseAssessments handles retrieving all assessments for a course
 func (h *AssessmentHandler) HandleGetCourseAssessments(c echo.Context) error {
 	courseID := c.Param("courseId")
@@ -55,7 +92,8 @@ func (h *AssessmentHandler) HandleGetCourseAssessments(c echo.Context) error {
 		return echo.NewHTTPError(http.StatusForbidden, "You are not enrolled in this course")
 	}
 
-	assessments, err := h.assessmentService.GetAssessmentsByCourse(c.Request().Context(), courseID)
+	// Drafts, scheduled and archived assessments are hidden from students
+	assessments, err := h.assessmentService.GetVisibleAssessmentsByCourse(c.Request().Context(), courseID, student.ID)
 	if err != nil {
 		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to retrieve assessments: "+err.Error())
 	}
@@ -86,32 +124,107 @@ func (h *AssessmentHandler) HandleGetAssessmentByID(c echo.Context) error {
 		return err
 	}
 
-	assessment, err := h.assessmentService.GetAssessmentByID(c.Request().Context(), id)
+	if _, err := h.getVisibleAssessment(c, id, student.ID); err != nil {
+		return err
+	}
+
+	// Get the student's submission and grade for this assessment, if any
+	status, err := h.assessmentService.GetStudentAssessmentStatus(c.Request().Context(), id, student.ID)
 	if err != nil {
-		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to retrieve assessment: "+err.Error())
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to retrieve assessment status: "+err.Error())
 	}
 
-	if assessment == nil {
-		return echo.NewHTTPError(http.StatusNotFound, "Assessment not found")
+	return c.JSON(http.StatusOK, status)
+}
+
+// HandleGetQuestions handles retrieving the questions of an assessment without the answer keys
+func (h *AssessmentHandler) HandleGetQuestions(c echo.Context) error {
+	id := c.Param("id")
+	if id == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "Assessment ID is required")
 	}
 
-	// Check if the student is enrolled in the course
-	isEnrolled, err := h.courseService.IsStudentEnrolledInCourse(c.Request().Context(), assessment.CourseID, student.ID)
+	// Get the student's ID from the token
+	student, err := middleware.GetUserFromContext(c)
 	if err != nil {
-		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to check enrollment: "+err.Error())
+		return err
 	}
 
-	if !isEnrolled {
-		return echo.NewHTTPError(http.StatusForbidden, "You are not enrolled in this course")
+	assessment, err := h.getVisibleAssessment(c, id, student.ID)
+	if err != nil {
+		return err
 	}
 
-	// Get the student's submission and grade for this assessment, if any
-	status, err := h.assessmentService.GetStudentAssessmentStatus(c.Request().Context(), id, student.ID)
+	// Questions of a timed assessment are only shown while an attempt is running
+	if assessment.IsTimed() {
+		active, err := h.assessmentService.GetActiveAttempt(c.Request().Context(), assessment, student.ID)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to check attempt: "+err.Error())
+		}
+
+		if active == nil {
+			return echo.NewHTTPError(http.StatusForbidden, "Start an attempt to see the questions of this timed assessment")
+		}
+	}
+
+	questions, err := h.questionService.GetQuestionsForStudent(c.Request().Context(), id, student.ID)
 	if err != nil {
-		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to retrieve assessment status: "+err.Error())
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to retrieve questions: "+err.Error())
 	}
 
-	return c.JSON(http.StatusOK, status)
+	return c.JSON(http.StatusOK, questions)
+}
+
+// HandleStartAttempt handles starting a timed attempt, the time limit runs from this moment
+func (h *AssessmentHandler) HandleStartAttempt(c echo.Context) error {
+	id := c.Param("id")
+	if id == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "Assessment ID is required")
+	}
+
+	// Get the student's ID from the token
+	student, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		return err
+	}
+
+	if _, err := h.getVisibleAssessment(c, id, student.ID); err != nil {
+		return err
+	}
+
+	session, err := h.assessmentService.StartAttempt(c.Request().Context(), id, student.ID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Failed to start attempt: "+err.Error())
+	}
+
+	return c.JSON(http.StatusOK, session)
+}
+
+// bindSubmission reads a submission from either a JSON body or a multipart form. Multipart forms carry
+// the text in a "content" field, question answers as JSON in an "answers" field and files in "files" fields.
+func bindSubmission(c echo.Context) (models.CreateSubmissionRequest, []*multipart.FileHeader, error) {
+	var req models.CreateSubmissionRequest
+
+	if !strings.HasPrefix(c.Request().Header.Get(echo.HeaderContentType), echo.MIMEMultipartForm) {
+		if err := c.Bind(&req); err != nil {
+			return req, nil, echo.NewHTTPError(http.StatusBadRequest, "Invalid request body")
+		}
+		return req, nil, nil
+	}
+
+	form, err := c.MultipartForm()
+	if err != nil {
+		return req, nil, echo.NewHTTPError(http.StatusBadRequest, "Invalid multipart form")
+	}
+
+	req.Content = c.FormValue("content")
+	if answers := c.FormValue("answers"); answers != "" {
+		if err := json.Unmarshal([]byte(answers), &req.Answers); err != nil {
+			return req, nil, echo.NewHTTPError(http.StatusBadRequest, "Answers must be a JSON list")
+		}
+	}
+
+	return req, form.File["files"], nil
 }
 
 // HandleSubmitAssessment handles submitting an assessment
@@ -121,9 +234,9 @@ func (h *AssessmentHandler) HandleSubmitAssessment(c echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, "Assessment ID is required")
 	}
 
-	var req models.CreateSubmissionRequest
-	if err := c.Bind(&req); err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, "Invalid request body")
+	req, files, err := bindSubmission(c)
+	if err != nil {
+		return err
 	}
 
 	if err := utils.ValidateStruct(h.validator, req); err != nil {
@@ -136,43 +249,37 @@ func (h *AssessmentHandler) HandleSubmitAssessment(c echo.Context) error {
 		return err
 	}
 
-	assessment, err := h.assessmentService.GetAssessmentByID(c.Request().Context(), id)
+	assessment, err := h.getVisibleAssessment(c, id, student.ID)
 	if err != nil {
-		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to retrieve assessment: "+err.Error())
-	}
-
-	if assessment == nil {
-		return echo.NewHTTPError(http.StatusNotFound, "Assessment not found")
-	}
-
-	// Check if the student is enrolled in the course
-	isEnrolled, err := h.courseService.IsStudentEnrolledInCourse(c.Request().Context(), assessment.CourseID, student.ID)
-	if err != nil {
-		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to check enrollment: "+err.Error())
+		return err
 	}
 
-	if !isEnrolled {
-		return echo.NewHTTPError(http.StatusForbidden, "You are not enrolled in this course")
+	if assessment.Status != models.AssessmentStatusPublished {
+		return echo.NewHTTPError(http.StatusForbidden, "Assessment is closed for submissions")
 	}
 
-	// Check if the assessment is past due
-	if assessment.DueDate != nil && assessment.DueDate.Before(time.Now()) {
+	// Check if the assessment still accepts submissions, late work is accepted until the cutoff date.
+	// Timed attempts are checked against their own time window when submitted.
+	if deadline := assessment.SubmissionDeadline(); !assessment.IsTimed() && deadline != nil && deadline.Before(time.Now()) {
 		return echo.NewHTTPError(http.StatusForbidden, "Assessment is past due")
 	}
 
-	// Check if the student has already submitted
-	hasSubmitted, err := h.assessmentService.HasStudentSubmitted(c.Request().Context(), id, student.ID)
+	// Check if the student has attempts left
+	attempts, err := h.assessmentService.CountAttempts(c.Request().Context(), id, student.ID)
 	if err != nil {
 		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to check submission status: "+err.Error())
 	}
 
-	if hasSubmitted {
-		return echo.NewHTTPError(http.StatusForbidden, "You have already submitted this assessment")
+	if attempts >= assessment.MaxAttempts {
+		if assessment.MaxAttempts == 1 {
+			return echo.NewHTTPError(http.StatusForbidden, "You have already submitted this assessment")
+		}
+		return echo.NewHTTPError(http.StatusForbidden, "You have used all attempts at this assessment")
 	}
 
-	submission, err := h.assessmentService.SubmitAssessment(c.Request().Context(), id, student.ID, req.Content)
+	submission, err := h.assessmentService.SubmitAssessment(c.Request().Context(), id, student.ID, req, files)
 	if err != nil {
-		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to submit assessment: "+err.Error())
+		return echo.NewHTTPError(http.StatusBadRequest, "Failed to submit assessment: "+err.Error())
 	}
 
 	return c.JSON(http.StatusCreated, submission)
@@ -191,44 +298,77 @@ func (h *AssessmentHandler) HandleViewSubmission(c echo.Context) error {
 		return err
 	}
 
-	assessment, err := h.assessmentService.GetAssessmentByID(c.Request().Context(), id)
+	assessment, err := h.getVisibleAssessment(c, id, student.ID)
 	if err != nil {
-		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to retrieve assessment: "+err.Error())
+		return err
 	}
 
-	if assessment == nil {
-		return echo.NewHTTPError(http.StatusNotFound, "Assessment not found")
+	// The latest attempt is shown, with its grade once released
+	attempts, err := h.assessmentService.GetReleasedAttempts(c.Request().Context(), assessment, student.ID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to retrieve submission: "+err.Error())
 	}
 
-	// Check if the student is enrolled in the course
-	isEnrolled, err := h.courseService.IsStudentEnrolledInCourse(c.Request().Context(), assessment.CourseID, student.ID)
+	if len(attempts) == 0 {
+		return echo.NewHTTPError(http.StatusNotFound, "You have not submitted this assessment")
+	}
+
+	submission, grade := attempts[len(attempts)-1].Submission, attempts[len(attempts)-1].Grade
+	for _, attachment := range submission.Attachments {
+		attachment.DownloadURL = "/api/student/assessments/" + id + "/submission/attachments/" + attachment.ID
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"submission": submission,
+		"grade":      grade,
+	})
+}
+
+// HandleDownloadAttachment handles downloading a file the student attached to their own submission
+func (h *AssessmentHandler) HandleDownloadAttachment(c echo.Context) error {
+	id := c.Param("id")
+	attachmentID := c.Param("attachmentId")
+	if id == "" || attachmentID == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "Assessment ID and attachment ID are required")
+	}
+
+	// Get the student's ID from the token
+	student, err := middleware.GetUserFromContext(c)
 	if err != nil {
-		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to check enrollment: "+err.Error())
+		return err
 	}
 
-	if !isEnrolled {
-		return echo.NewHTTPError(http.StatusForbidden, "You are not enrolled in this course")
+	if _, err := h.getVisibleAssessment(c, id, student.ID); err != nil {
+		return err
 	}
 
-	submission, err := h.assessmentService.GetStudentSubmission(c.Request().Context(), id, student.ID)
+	attempts, err := h.assessmentService.GetStudentAttempts(c.Request().Context(), id, student.ID)
 	if err != nil {
 		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to retrieve submission: "+err.Error())
 	}
 
-	if submission == nil {
+	if len(attempts) == 0 {
 		return echo.NewHTTPError(http.StatusNotFound, "You have not submitted this assessment")
 	}
 
-	// Get the grade if available
-	grade, err := h.assessmentService.GetSubmissionGrade(c.Request().Context(), submission.ID)
+	// The file may belong to any of the student's attempts
+	submissionID := attempts[len(attempts)-1].Submission.ID
+	for _, attempt := range attempts {
+		for _, attachment := range attempt.Submission.Attachments {
+			if attachment.ID == attachmentID {
+				submissionID = attempt.Submission.ID
+			}
+		}
+	}
+
+	attachment, content, err := h.assessmentService.OpenAttachment(c.Request().Context(), submissionID, attachmentID)
 	if err != nil {
-		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to retrieve grade: "+err.Error())
+		return echo.NewHTTPError(http.StatusNotFound, "Failed to open attachment: "+err.Error())
 	}
+	defer content.Close()
 
-	return c.JSON(http.StatusOK, map[string]interface{}{
-		"submission": submission,
-		"grade":      grade,
-	})
+	c.Response().Header().Set(echo.HeaderContentDisposition, mime.FormatMediaType("attachment", map[string]string{"filename": attachment.FileName}))
+	return c.Stream(http.StatusOK, attachment.ContentType, content)
 }
 
 // HandleViewGrade handles viewing a student's grade for an assessment
@@ -244,23 +384,9 @@ func (h *AssessmentHandler) HandleViewGrade(c echo.Context) error {
 		return err
 	}
 
-	assessment, err := h.assessmentService.GetAssessmentByID(c.Request().Context(), id)
-	if err != nil {
-		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to retrieve assessment: "+err.Error())
-	}
-
-	if assessment == nil {
-		return echo.NewHTTPError(http.StatusNotFound, "Assessment not found")
-	}
-
-	// Check if the student is enrolled in the course
-	isEnrolled, err := h.courseService.IsStudentEnrolledInCourse(c.Request().Context(), assessment.CourseID, student.ID)
+	assessment, err := h.getVisibleAssessment(c, id, student.ID)
 	if err != nil {
-		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to check enrollment: "+err.Error())
-	}
-
-	if !isEnrolled {
-		return echo.NewHTTPError(http.StatusForbidden, "You are not enrolled in this course")
+		return err
 	}
 
 	submission, err := h.assessmentService.GetStudentSubmission(c.Request().Context(), id, student.ID)
@@ -272,7 +398,8 @@ func (h *AssessmentHandler) HandleViewGrade(c echo.Context) error {
 		return echo.NewHTTPError(http.StatusNotFound, "You have not submitted this assessment")
 	}
 
-	grade, err := h.assessmentService.GetSubmissionGrade(c.Request().Context(), submission.ID)
+	// The grade that counts depends on the assessment's attempt policy
+	grade, err := h.assessmentService.GetStudentGrade(c.Request().Context(), assessment, student.ID)
 	if err != nil {
 		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to retrieve grade: "+err.Error())
 	}
@@ -283,3 +410,35 @@ func (h *AssessmentHandler) HandleViewGrade(c echo.Context) error {
 
 	return c.JSON(http.StatusOK, grade)
 }
+
+// HandleGetAttempts handles listing all of a student's attempts at an assessment, oldest first
+func (h *AssessmentHandler) HandleGetAttempts(c echo.Context) error {
+	id := c.Param("id")
+	if id == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "Assessment ID is required")
+	}
+
+	// Get the student's ID from the token
+	student, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		return err
+	}
+
+	assessment, err := h.getVisibleAssessment(c, id, student.ID)
+	if err != nil {
+		return err
+	}
+
+	attempts, err := h.assessmentService.GetReleasedAttempts(c.Request().Context(), assessment, student.ID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to retrieve attempts: "+err.Error())
+	}
+
+	for _, attempt := range attempts {
+		for _, attachment := range attempt.Submission.Attachments {
+			attachment.DownloadURL = "/api/student/assessments/" + id + "/submission/attachments/" + attachment.ID
+		}
+	}
+
+	return c.JSON(http.StatusOK, attempts)
+}
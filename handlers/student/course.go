@@ -6,6 +6,7 @@ import (
 	"github.com/labstack/echo/v4"
 
 	"assessment-management-system/middleware"
+	"assessment-management-system/models"
 	"assessment-management-system/services"
 )
 
@@ -104,6 +105,12 @@ func (h *CourseHandler) HandleEnrollInCourse(c echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, "Course ID is required")
 	}
 
+	// The body is optional, it names the section to enroll into in courses with sections
+	var req models.EnrollRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid request body")
+	}
+
 	// Get the student's ID from the token
 	student, err := middleware.GetUserFromContext(c)
 	if err != nil {
@@ -139,14 +146,41 @@ func (h *CourseHandler) HandleEnrollInCourse(c echo.Context) error {
 		return echo.NewHTTPError(http.StatusForbidden, "You cannot enroll in a course without teachers")
 	}
 
-	// Enroll the student
-	if err := h.courseService.EnrollStudentInCourse(c.Request().Context(), courseID, student.ID); err != nil {
+	// Enroll the student, or put them on the waitlist when the course is full
+	waiting, err := h.courseService.EnrollStudentInCourse(c.Request().Context(), courseID, student.ID, req.SectionID)
+	if err != nil {
 		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to enroll in course: "+err.Error())
 	}
 
+	if waiting != nil {
+		return c.JSON(http.StatusAccepted, map[string]interface{}{
+			"message":           "Course is full, you have been added to the waitlist",
+			"waitlist_position": waiting.Position,
+		})
+	}
+
 	return c.JSON(http.StatusOK, map[string]string{"message": "Successfully enrolled in course"})
 }
 
+// HandleLeaveWaitlist handles a student leaving the waitlist of a course
+func (h *CourseHandler) HandleLeaveWaitlist(c echo.Context) error {
+	courseID := c.Param("id")
+	if courseID == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "Course ID is required")
+	}
+
+	student, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		return err
+	}
+
+	if err := h.courseService.LeaveWaitlist(c.Request().Context(), courseID, student.ID); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Failed to leave waitlist: "+err.Error())
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
 // HandleGetOrganizationDetails handles retrieving organization details
 func (h *CourseHandler) HandleGetOrganizationDetails(c echo.Context) error {
 	// Get the student's organization ID from the token
@@ -0,0 +1,71 @@
+package student
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"assessment-management-system/middleware"
+	"assessment-management-system/services"
+)
+
+// GradebookHandler handles course gradebook routes for students
+type GradebookHandler struct {
+	gradebookService *services.GradebookService
+	courseService    *services.CourseService
+}
+
+// NewGradebookHandler creates a new GradebookHandler
+func NewGradebookHandler(gradebookService *services.GradebookService, courseService *services.CourseService) *GradebookHandler {
+	return &GradebookHandler{
+		gradebookService: gradebookService,
+		courseService:    courseService,
+	}
+}
+
+// HandleGetStanding handles retrieving the student's own running grade in a course
+func (h *GradebookHandler) HandleGetStanding(c echo.Context) error {
+	courseID := c.Param("courseId")
+	if courseID == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "Course ID is required")
+	}
+
+	// Get the student's ID from the token
+	student, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		return err
+	}
+
+	// Check if the student is enrolled in the course
+	isEnrolled, err := h.courseService.IsStudentEnrolledInCourse(c.Request().Context(), courseID, student.ID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to check enrollment: "+err.Error())
+	}
+
+	if !isEnrolled {
+		return echo.NewHTTPError(http.StatusForbidden, "You are not enrolled in this course")
+	}
+
+	standing, err := h.gradebookService.GetStudentStanding(c.Request().Context(), courseID, student.ID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to retrieve standing: "+err.Error())
+	}
+
+	return c.JSON(http.StatusOK, standing)
+}
+
+// HandleGetTranscript handles retrieving the student's grades across all their courses
+func (h *GradebookHandler) HandleGetTranscript(c echo.Context) error {
+	// Get the student's ID from the token
+	student, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		return err
+	}
+
+	transcript, err := h.gradebookService.GetTranscript(c.Request().Context(), student.ID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to retrieve transcript: "+err.Error())
+	}
+
+	return c.JSON(http.StatusOK, transcript)
+}
@@ -0,0 +1,172 @@
+package student
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"assessment-management-system/handlers"
+	"assessment-management-system/middleware"
+	"assessment-management-system/models"
+	"assessment-management-system/services"
+)
+
+// GroupHandler handles course group routes for students
+type GroupHandler struct {
+	groupService  *services.GroupService
+	courseService *services.CourseService
+}
+
+// NewGroupHandler creates a new GroupHandler
+func NewGroupHandler(groupService *services.GroupService, courseService *services.CourseService) *GroupHandler {
+	return &GroupHandler{
+		groupService:  groupService,
+		courseService: courseService,
+	}
+}
+
+// authorizeCourse checks that the student is enrolled in the course
+func (h *GroupHandler) authorizeCourse(c echo.Context, courseID, studentID string) error {
+	if courseID == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "Course ID is required")
+	}
+
+	isEnrolled, err := h.courseService.IsStudentEnrolledInCourse(c.Request().Context(), courseID, studentID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to check enrollment: "+err.Error())
+	}
+
+	if !isEnrolled {
+		return echo.NewHTTPError(http.StatusForbidden, "You are not enrolled in this course")
+	}
+
+	if err := handlers.CheckCourseWritable(c, h.courseService, courseID); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// getGroup loads the group in the URL, which must belong to one of the student's courses
+func (h *GroupHandler) getGroup(c echo.Context, studentID string) (*models.CourseGroup, error) {
+	groupID := c.Param("groupId")
+	if groupID == "" {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, "Group ID is required")
+	}
+
+	group, err := h.groupService.GetGroup(c.Request().Context(), groupID)
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, "Failed to retrieve group: "+err.Error())
+	}
+
+	if group == nil {
+		return nil, echo.NewHTTPError(http.StatusNotFound, "Group not found")
+	}
+
+	if err := h.authorizeCourse(c, group.CourseID, studentID); err != nil {
+		return nil, err
+	}
+
+	return group, nil
+}
+
+// hideEmails removes the email addresses of classmates from groups shown to students
+func hideEmails(groups ...*models.CourseGroup) {
+	for _, group := range groups {
+		for _, member := range group.Members {
+			member.Email = ""
+		}
+	}
+}
+
+// HandleGetGroups handles listing the groups of a course
+func (h *GroupHandler) HandleGetGroups(c echo.Context) error {
+	courseID := c.Param("courseId")
+
+	// Get the student's ID from the token
+	student, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		return err
+	}
+
+	if err := h.authorizeCourse(c, courseID, student.ID); err != nil {
+		return err
+	}
+
+	groups, err := h.groupService.GetCourseGroups(c.Request().Context(), courseID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to retrieve groups: "+err.Error())
+	}
+
+	hideEmails(groups...)
+	return c.JSON(http.StatusOK, groups)
+}
+
+// HandleGetMyGroup handles retrieving the student's group in a course
+func (h *GroupHandler) HandleGetMyGroup(c echo.Context) error {
+	courseID := c.Param("courseId")
+
+	// Get the student's ID from the token
+	student, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		return err
+	}
+
+	if err := h.authorizeCourse(c, courseID, student.ID); err != nil {
+		return err
+	}
+
+	group, err := h.groupService.GetStudentGroup(c.Request().Context(), courseID, student.ID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to retrieve group: "+err.Error())
+	}
+
+	if group == nil {
+		return echo.NewHTTPError(http.StatusNotFound, "You are not in a group in this course")
+	}
+
+	hideEmails(group)
+	return c.JSON(http.StatusOK, group)
+}
+
+// HandleJoinGroup handles a student joining a self-signup group
+func (h *GroupHandler) HandleJoinGroup(c echo.Context) error {
+	// Get the student's ID from the token
+	student, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		return err
+	}
+
+	group, err := h.getGroup(c, student.ID)
+	if err != nil {
+		return err
+	}
+
+	group, err = h.groupService.JoinGroup(c.Request().Context(), group, student.ID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Failed to join group: "+err.Error())
+	}
+
+	hideEmails(group)
+	return c.JSON(http.StatusOK, group)
+}
+
+// HandleLeaveGroup handles a student leaving a self-signup group
+func (h *GroupHandler) HandleLeaveGroup(c echo.Context) error {
+	// Get the student's ID from the token
+	student, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		return err
+	}
+
+	group, err := h.getGroup(c, student.ID)
+	if err != nil {
+		return err
+	}
+
+	if err := h.groupService.LeaveGroup(c.Request().Context(), group, student.ID); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Failed to leave group: "+err.Error())
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
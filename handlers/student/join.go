@@ -0,0 +1,100 @@
+package student
+
+import (
+	"net/http"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+
+	"assessment-management-system/middleware"
+	"assessment-management-system/models"
+	"assessment-management-system/services"
+	"assessment-management-system/utils"
+)
+
+// JoinHandler handles enrolling with join codes and invite links for students
+type JoinHandler struct {
+	courseService *services.CourseService
+	jwtSecret     string
+	validator     *validator.Validate
+}
+
+// NewJoinHandler creates a new JoinHandler. Invite links are checked against a key derived from the JWT secret.
+func NewJoinHandler(courseService *services.CourseService, jwtSecret string) *JoinHandler {
+	return &JoinHandler{
+		courseService: courseService,
+		jwtSecret:     jwtSecret,
+		validator:     utils.NewValidator(),
+	}
+}
+
+// join enrolls the student with a join code and responds like an ordinary enrollment
+func (h *JoinHandler) join(c echo.Context, code *models.JoinCode, sectionID *string) error {
+	if code == nil {
+		return echo.NewHTTPError(http.StatusNotFound, "Join code not found")
+	}
+
+	// Get the student's ID from the token
+	student, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		return err
+	}
+
+	waiting, err := h.courseService.JoinCourse(c.Request().Context(), student.ID, code, sectionID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Failed to join course: "+err.Error())
+	}
+
+	if waiting != nil {
+		return c.JSON(http.StatusAccepted, map[string]interface{}{
+			"message":           "Course is full, you have been added to the waitlist",
+			"course_id":         code.CourseID,
+			"waitlist_position": waiting.Position,
+		})
+	}
+
+	return c.JSON(http.StatusOK, map[string]string{
+		"message":   "Successfully enrolled in course",
+		"course_id": code.CourseID,
+	})
+}
+
+// HandleJoinWithCode handles enrolling in a course with a join code
+func (h *JoinHandler) HandleJoinWithCode(c echo.Context) error {
+	var req models.JoinCourseRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid request body")
+	}
+
+	if err := utils.ValidateStruct(h.validator, req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, utils.FormatValidationErrors(err))
+	}
+
+	code, err := h.courseService.FindJoinCode(c.Request().Context(), req.Code)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to retrieve join code: "+err.Error())
+	}
+
+	return h.join(c, code, req.SectionID)
+}
+
+// HandleJoinWithInvite handles enrolling in a course with an invite link
+func (h *JoinHandler) HandleJoinWithInvite(c echo.Context) error {
+	codeID, err := utils.ValidateInviteToken(c.Param("token"), h.jwtSecret)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid or expired invite link")
+	}
+
+	// Courses with sections take the section from the body when the invite does not name one
+	var req models.EnrollRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid request body")
+	}
+
+	code, err := h.courseService.GetJoinCode(c.Request().Context(), codeID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to retrieve join code: "+err.Error())
+	}
+
+	return h.join(c, code, req.SectionID)
+}
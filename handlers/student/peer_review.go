@@ -0,0 +1,140 @@
+package student
+
+import (
+	"mime"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"assessment-management-system/handlers"
+	"assessment-management-system/middleware"
+	"assessment-management-system/models"
+	"assessment-management-system/utils"
+)
+
+// getAssignedReview loads the peer review in the URL and checks that it is assigned to the student
+func (h *AssessmentHandler) getAssignedReview(c echo.Context) (*models.PeerReview, error) {
+	id := c.Param("id")
+	if id == "" {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, "Peer review ID is required")
+	}
+
+	// Get the student's ID from the token
+	student, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		return nil, err
+	}
+
+	review, err := h.assessmentService.GetPeerReview(c.Request().Context(), id)
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, "Failed to retrieve peer review: "+err.Error())
+	}
+
+	if review == nil || review.ReviewerID != student.ID {
+		return nil, echo.NewHTTPError(http.StatusNotFound, "Peer review not found")
+	}
+
+	assessment, err := h.assessmentService.GetAssessmentByID(c.Request().Context(), review.AssessmentID)
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, "Failed to retrieve assessment: "+err.Error())
+	}
+
+	if err := handlers.CheckCourseWritable(c, h.courseService, assessment.CourseID); err != nil {
+		return nil, err
+	}
+
+	return review, nil
+}
+
+// HandleGetPeerReviews handles listing the peer reviews assigned to the student
+func (h *AssessmentHandler) HandleGetPeerReviews(c echo.Context) error {
+	// Get the student's ID from the token
+	student, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		return err
+	}
+
+	reviews, err := h.assessmentService.GetAssignedPeerReviews(c.Request().Context(), student.ID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to retrieve peer reviews: "+err.Error())
+	}
+
+	for _, review := range reviews {
+		for _, attachment := range review.Submission.Attachments {
+			attachment.DownloadURL = "/api/student/peer-reviews/" + review.ID + "/attachments/" + attachment.ID
+		}
+	}
+
+	return c.JSON(http.StatusOK, reviews)
+}
+
+// HandleSubmitPeerReview handles submitting or revising a peer review
+func (h *AssessmentHandler) HandleSubmitPeerReview(c echo.Context) error {
+	var req models.SubmitPeerReviewRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid request body")
+	}
+
+	if err := utils.ValidateStruct(h.validator, req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, utils.FormatValidationErrors(err))
+	}
+
+	review, err := h.getAssignedReview(c)
+	if err != nil {
+		return err
+	}
+
+	review, err = h.assessmentService.SubmitPeerReview(c.Request().Context(), review, req)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Failed to submit peer review: "+err.Error())
+	}
+
+	return c.JSON(http.StatusOK, review)
+}
+
+// HandleDownloadPeerReviewAttachment handles downloading a file attached to a submission the student reviews
+func (h *AssessmentHandler) HandleDownloadPeerReviewAttachment(c echo.Context) error {
+	attachmentID := c.Param("attachmentId")
+	if attachmentID == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "Attachment ID is required")
+	}
+
+	review, err := h.getAssignedReview(c)
+	if err != nil {
+		return err
+	}
+
+	attachment, content, err := h.assessmentService.OpenAttachment(c.Request().Context(), review.SubmissionID, attachmentID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusNotFound, "Failed to open attachment: "+err.Error())
+	}
+	defer content.Close()
+
+	c.Response().Header().Set(echo.HeaderContentDisposition, mime.FormatMediaType("attachment", map[string]string{"filename": attachment.FileName}))
+	return c.Stream(http.StatusOK, attachment.ContentType, content)
+}
+
+// HandleGetPeerFeedback handles retrieving the peer reviews of the student's own submission
+func (h *AssessmentHandler) HandleGetPeerFeedback(c echo.Context) error {
+	id := c.Param("id")
+	if id == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "Assessment ID is required")
+	}
+
+	// Get the student's ID from the token
+	student, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		return err
+	}
+
+	if _, err := h.getVisibleAssessment(c, id, student.ID); err != nil {
+		return err
+	}
+
+	feedback, err := h.assessmentService.GetPeerFeedback(c.Request().Context(), id, student.ID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusNotFound, "Failed to retrieve peer feedback: "+err.Error())
+	}
+
+	return c.JSON(http.StatusOK, feedback)
+}
@@ -0,0 +1,73 @@
+package student
+
+import (
+	"net/http"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+
+	"assessment-management-system/middleware"
+	"assessment-management-system/models"
+	"assessment-management-system/services"
+	"assessment-management-system/utils"
+)
+
+// RegradeHandler handles regrade request routes for students
+type RegradeHandler struct {
+	regradeService *services.RegradeService
+	validator      *validator.Validate
+}
+
+// NewRegradeHandler creates a new RegradeHandler
+func NewRegradeHandler(regradeService *services.RegradeService) *RegradeHandler {
+	return &RegradeHandler{
+		regradeService: regradeService,
+		validator:      utils.NewValidator(),
+	}
+}
+
+// HandleOpenRequest handles disputing the grade of one of the student's submissions
+func (h *RegradeHandler) HandleOpenRequest(c echo.Context) error {
+	submissionID := c.Param("submissionId")
+	if submissionID == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "Submission ID is required")
+	}
+
+	var req models.CreateRegradeRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid request body")
+	}
+
+	if err := utils.ValidateStruct(h.validator, req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, utils.FormatValidationErrors(err))
+	}
+
+	// Get the student's ID from the token
+	student, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		return err
+	}
+
+	request, err := h.regradeService.OpenRequest(c.Request().Context(), submissionID, student.ID, req)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Failed to open regrade request: "+err.Error())
+	}
+
+	return c.JSON(http.StatusCreated, request)
+}
+
+// HandleGetRequests handles listing the regrade requests the student opened
+func (h *RegradeHandler) HandleGetRequests(c echo.Context) error {
+	// Get the student's ID from the token
+	student, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		return err
+	}
+
+	requests, err := h.regradeService.GetStudentRequests(c.Request().Context(), student.ID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to retrieve regrade requests: "+err.Error())
+	}
+
+	return c.JSON(http.StatusOK, requests)
+}
@@ -1,11 +1,13 @@
 package teacher
 
 import (
+	"mime"
 	"net/http"
 
 	"github.com/go-playground/validator/v10"
 	"github.com/labstack/echo/v4"
 
+	"assessment-management-system/handlers"
 	"assessment-management-system/middleware"
 	"assessment-management-system/models"
 	"assessment-management-system/services"
@@ -58,6 +60,10 @@ func (h *AssessmentHandler) HandleCreateAssessment(c echo.Context) error {
 		return echo.NewHTTPError(http.StatusForbidden, "You are not assigned to this course")
 	}
 
+	if err := handlers.CheckCourseWritable(c, h.courseService, req.CourseID); err != nil {
+		return err
+	}
+
 	assessment, err := h.assessmentService.CreateAssessment(c.Request().Context(), teacher.ID, req)
 	if err != nil {
 		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to create assessment: "+err.Error())
@@ -171,6 +177,10 @@ func (h *AssessmentHandler) HandleUpdateAssessment(c echo.Context) error {
 		return echo.NewHTTPError(http.StatusForbidden, "You do not have permission to update this assessment")
 	}
 
+	if err := handlers.CheckCourseWritable(c, h.courseService, assessment.CourseID); err != nil {
+		return err
+	}
+
 	updatedAssessment, err := h.assessmentService.UpdateAssessment(c.Request().Context(), id, req)
 	if err != nil {
 		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to update assessment: "+err.Error())
@@ -207,6 +217,10 @@ func (h *AssessmentHandler) HandleDeleteAssessment(c echo.Context) error {
 		return echo.NewHTTPError(http.StatusForbidden, "You do not have permission to delete this assessment")
 	}
 
+	if err := handlers.CheckCourseWritable(c, h.courseService, assessment.CourseID); err != nil {
+		return err
+	}
+
 	if err := h.assessmentService.DeleteAssessment(c.Request().Context(), id); err != nil {
 		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to delete assessment: "+err.Error())
 	}
@@ -254,9 +268,76 @@ func (h *AssessmentHandler) HandleGetSubmissions(c echo.Context) error {
 		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to retrieve submissions: "+err.Error())
 	}
 
+	// Teachers of particular sections only see their own students' submissions
+	submissions, err = h.assessmentService.FilterSubmissionsForTeacher(c.Request().Context(), assessment.CourseID, teacher.ID, submissions)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to check sections: "+err.Error())
+	}
+
+	// Point every attachment at the teacher download route
+	for _, submission := range submissions {
+		for _, attachment := range submission.Attachments {
+			attachment.DownloadURL = "/api/teacher/submissions/" + submission.ID + "/attachments/" + attachment.ID
+		}
+	}
+
 	return c.JSON(http.StatusOK, submissions)
 }
 
+// HandleDownloadAttachment handles downloading a file attached to a submission
+func (h *AssessmentHandler) HandleDownloadAttachment(c echo.Context) error {
+	submissionID := c.Param("submissionId")
+	attachmentID := c.Param("attachmentId")
+	if submissionID == "" || attachmentID == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "Submission ID and attachment ID are required")
+	}
+
+	// Get the teacher's ID from the token
+	teacher, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		return err
+	}
+
+	submission, err := h.assessmentService.GetSubmissionByID(c.Request().Context(), submissionID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to retrieve submission: "+err.Error())
+	}
+
+	if submission == nil {
+		return echo.NewHTTPError(http.StatusNotFound, "Submission not found")
+	}
+
+	assessment, err := h.assessmentService.GetAssessmentByID(c.Request().Context(), submission.AssessmentID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to retrieve assessment: "+err.Error())
+	}
+
+	// Check if the teacher created the assessment or is assigned to the course
+	if assessment.TeacherID != teacher.ID {
+		isAssigned, err := h.courseService.IsTeacherAssignedToCourse(c.Request().Context(), assessment.CourseID, teacher.ID)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to check course assignment: "+err.Error())
+		}
+
+		if !isAssigned {
+			return echo.NewHTTPError(http.StatusForbidden, "You do not have permission to view this submission")
+		}
+	}
+
+	if err := h.checkSubmissionSection(c, assessment, submission); err != nil {
+		return err
+	}
+
+	attachment, content, err := h.assessmentService.OpenAttachment(c.Request().Context(), submissionID, attachmentID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusNotFound, "Failed to open attachment: "+err.Error())
+	}
+	defer content.Close()
+
+	c.Response().Header().Set(echo.HeaderContentDisposition, mime.FormatMediaType("attachment", map[string]string{"filename": attachment.FileName}))
+	return c.Stream(http.StatusOK, attachment.ContentType, content)
+}
+
 // HandleGradeSubmission handles grading a submission
 func (h *AssessmentHandler) HandleGradeSubmission(c echo.Context) error {
 	submissionID := c.Param("submissionId")
@@ -307,14 +388,37 @@ func (h *AssessmentHandler) HandleGradeSubmission(c echo.Context) error {
 		}
 	}
 
+	if err := h.checkSubmissionSection(c, assessment, submission); err != nil {
+		return err
+	}
+
+	if err := handlers.CheckCourseWritable(c, h.courseService, assessment.CourseID); err != nil {
+		return err
+	}
+
 	// Validate that the score doesn't exceed the maximum score
-	if req.Score > float64(assessment.MaxScore) {
+	if req.Score != nil && *req.Score > float64(assessment.MaxScore) {
 		return echo.NewHTTPError(http.StatusBadRequest, "Score cannot exceed the maximum score for this assessment")
 	}
 
-	grade, err := h.assessmentService.GradeSubmission(c.Request().Context(), submissionID, teacher.ID, req.Score, req.Feedback)
+	// Double-marked submissions record the teacher's mark until the moderator agrees the final mark
+	moderation, err := h.assessmentService.GetModeration(c.Request().Context(), submissionID, teacher.ID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to retrieve moderation: "+err.Error())
+	}
+
+	if moderation != nil && moderation.Status != models.ModerationStatusAgreed {
+		moderation, err = h.assessmentService.RecordModerationMark(c.Request().Context(), submissionID, teacher.ID, req)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Failed to record mark: "+err.Error())
+		}
+
+		return c.JSON(http.StatusOK, moderation)
+	}
+
+	grade, err := h.assessmentService.GradeSubmission(c.Request().Context(), submissionID, teacher.ID, req)
 	if err != nil {
-		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to grade submission: "+err.Error())
+		return echo.NewHTTPError(http.StatusBadRequest, "Failed to grade submission: "+err.Error())
 	}
 
 	return c.JSON(http.StatusOK, grade)
@@ -0,0 +1,167 @@
+package teacher
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+
+	"assessment-management-system/handlers"
+	"assessment-management-system/middleware"
+	"assessment-management-system/models"
+)
+
+// authorizeAssessment loads the assessment and checks that the teacher created it or is assigned to its course
+func (h *AssessmentHandler) authorizeAssessment(c echo.Context, assessmentID string) (*models.Assessment, error) {
+	if assessmentID == "" {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, "Assessment ID is required")
+	}
+
+	// Get the teacher's ID from the token
+	teacher, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		return nil, err
+	}
+
+	assessment, err := h.assessmentService.GetAssessmentByID(c.Request().Context(), assessmentID)
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, "Failed to retrieve assessment: "+err.Error())
+	}
+
+	if assessment == nil {
+		return nil, echo.NewHTTPError(http.StatusNotFound, "Assessment not found")
+	}
+
+	if assessment.TeacherID != teacher.ID {
+		isAssigned, err := h.courseService.IsTeacherAssignedToCourse(c.Request().Context(), assessment.CourseID, teacher.ID)
+		if err != nil {
+			return nil, echo.NewHTTPError(http.StatusInternalServerError, "Failed to check course assignment: "+err.Error())
+		}
+
+		if !isAssigned {
+			return nil, echo.NewHTTPError(http.StatusForbidden, "You do not have permission to view submissions for this assessment")
+		}
+	}
+
+	if err := handlers.CheckCourseWritable(c, h.courseService, assessment.CourseID); err != nil {
+		return nil, err
+	}
+
+	return assessment, nil
+}
+
+// checkSubmissionSection checks that the teacher may see a submission of the assessment, which teachers of
+// particular sections may only for their own students
+func (h *AssessmentHandler) checkSubmissionSection(c echo.Context, assessment *models.Assessment, submission *models.AssessmentSubmission) error {
+	// Get the teacher's ID from the token
+	teacher, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		return err
+	}
+
+	canSee, err := h.assessmentService.CanTeacherSeeSubmission(c.Request().Context(), assessment.CourseID, submission.ID, teacher.ID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to check section: "+err.Error())
+	}
+
+	if !canSee {
+		return echo.NewHTTPError(http.StatusForbidden, "This submission is from a section you do not teach")
+	}
+
+	return nil
+}
+
+// resolveStudent finds the student given in the URL, by pseudonym at assessments graded anonymously. Teachers of
+// particular sections can only look up their own students.
+func (h *AssessmentHandler) resolveStudent(c echo.Context, assessment *models.Assessment) (string, error) {
+	ref := c.Param("studentId")
+	if ref == "" {
+		return "", echo.NewHTTPError(http.StatusBadRequest, "Student ID is required")
+	}
+
+	studentID, err := h.assessmentService.ResolveStudent(c.Request().Context(), assessment, ref)
+	if err != nil {
+		return "", echo.NewHTTPError(http.StatusNotFound, "Failed to find student: "+err.Error())
+	}
+
+	// Get the teacher's ID from the token
+	teacher, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		return "", err
+	}
+
+	canSee, err := h.assessmentService.CanTeacherSeeStudent(c.Request().Context(), assessment.CourseID, teacher.ID, studentID)
+	if err != nil {
+		return "", echo.NewHTTPError(http.StatusInternalServerError, "Failed to check section: "+err.Error())
+	}
+
+	if !canSee {
+		return "", echo.NewHTTPError(http.StatusForbidden, "This student is in a section you do not teach")
+	}
+
+	return studentID, nil
+}
+
+// HandleGetStudentAttempts handles retrieving every attempt of a student at an assessment
+func (h *AssessmentHandler) HandleGetStudentAttempts(c echo.Context) error {
+	assessment, err := h.authorizeAssessment(c, c.Param("id"))
+	if err != nil {
+		return err
+	}
+
+	studentID, err := h.resolveStudent(c, assessment)
+	if err != nil {
+		return err
+	}
+
+	attempts, err := h.assessmentService.GetStudentAttempts(c.Request().Context(), assessment.ID, studentID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to retrieve attempts: "+err.Error())
+	}
+
+	if err := h.assessmentService.MaskAttempts(c.Request().Context(), assessment, attempts); err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to retrieve attempts: "+err.Error())
+	}
+
+	for _, attempt := range attempts {
+		for _, attachment := range attempt.Submission.Attachments {
+			attachment.DownloadURL = "/api/teacher/submissions/" + attempt.Submission.ID + "/attachments/" + attachment.ID
+		}
+	}
+
+	return c.JSON(http.StatusOK, attempts)
+}
+
+// HandleDiffAttempts handles comparing two attempts of a student, given as the "from" and "to" attempt numbers
+func (h *AssessmentHandler) HandleDiffAttempts(c echo.Context) error {
+	assessment, err := h.authorizeAssessment(c, c.Param("id"))
+	if err != nil {
+		return err
+	}
+
+	studentID, err := h.resolveStudent(c, assessment)
+	if err != nil {
+		return err
+	}
+
+	from, err := strconv.Atoi(c.QueryParam("from"))
+	if err != nil || from < 1 {
+		return echo.NewHTTPError(http.StatusBadRequest, "A valid \"from\" attempt number is required")
+	}
+
+	to, err := strconv.Atoi(c.QueryParam("to"))
+	if err != nil || to < 1 {
+		return echo.NewHTTPError(http.StatusBadRequest, "A valid \"to\" attempt number is required")
+	}
+
+	diff, err := h.assessmentService.DiffAttempts(c.Request().Context(), assessment.ID, studentID, from, to)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusNotFound, "Failed to compare attempts: "+err.Error())
+	}
+
+	if err := h.assessmentService.MaskDiff(c.Request().Context(), assessment, diff); err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to compare attempts: "+err.Error())
+	}
+
+	return c.JSON(http.StatusOK, diff)
+}
@@ -100,9 +100,46 @@ func (h *CourseHandler) HandleGetCourseStudents(c echo.Context) error {
 		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to retrieve course students: "+err.Error())
 	}
 
+	// Teachers of particular sections only see their own students
+	students, err = h.courseService.FilterStudentsForTeacher(c.Request().Context(), courseID, teacher.ID, students)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to check sections: "+err.Error())
+	}
+
 	return c.JSON(http.StatusOK, students)
 }
 
+// HandleGetCourseSections handles retrieving the sections of a course with their teachers
+func (h *CourseHandler) HandleGetCourseSections(c echo.Context) error {
+	courseID := c.Param("id")
+	if courseID == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "Course ID is required")
+	}
+
+	// Get the teacher's ID from the token
+	teacher, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		return err
+	}
+
+	// Check if the teacher is assigned to the course
+	isAssigned, err := h.courseService.IsTeacherAssignedToCourse(c.Request().Context(), courseID, teacher.ID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to check course assignment: "+err.Error())
+	}
+
+	if !isAssigned {
+		return echo.NewHTTPError(http.StatusForbidden, "You are not assigned to this course")
+	}
+
+	sections, err := h.courseService.GetCourseSections(c.Request().Context(), courseID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to retrieve sections: "+err.Error())
+	}
+
+	return c.JSON(http.StatusOK, sections)
+}
+
 // HandleGetOrganizationDetails handles retrieving organization details
 func (h *CourseHandler) HandleGetOrganizationDetails(c echo.Context) error {
 	// Get the teacher's organization ID from the token
@@ -0,0 +1,159 @@
+package teacher
+
+import (
+	"net/http"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+
+	"assessment-management-system/handlers"
+	"assessment-management-system/middleware"
+	"assessment-management-system/models"
+	"assessment-management-system/services"
+	"assessment-management-system/utils"
+)
+
+// ExtensionHandler handles per-student extension routes for teachers
+type ExtensionHandler struct {
+	extensionService  *services.ExtensionService
+	assessmentService *services.AssessmentService
+	courseService     *services.CourseService
+	validator         *validator.Validate
+}
+
+// NewExtensionHandler creates a new ExtensionHandler
+func NewExtensionHandler(
+	extensionService *services.ExtensionService,
+	assessmentService *services.AssessmentService,
+	courseService *services.CourseService,
+) *ExtensionHandler {
+	return &ExtensionHandler{
+		extensionService:  extensionService,
+		assessmentService: assessmentService,
+		courseService:     courseService,
+		validator:         utils.NewValidator(),
+	}
+}
+
+// authorizeAssessment loads the assessment and checks that the teacher created it or is assigned to its course
+func (h *ExtensionHandler) authorizeAssessment(c echo.Context) (*models.Assessment, *models.User, error) {
+	id := c.Param("id")
+	if id == "" {
+		return nil, nil, echo.NewHTTPError(http.StatusBadRequest, "Assessment ID is required")
+	}
+
+	// Get the teacher's ID from the token
+	teacher, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	assessment, err := h.assessmentService.GetAssessmentByID(c.Request().Context(), id)
+	if err != nil {
+		return nil, nil, echo.NewHTTPError(http.StatusInternalServerError, "Failed to retrieve assessment: "+err.Error())
+	}
+
+	if assessment == nil {
+		return nil, nil, echo.NewHTTPError(http.StatusNotFound, "Assessment not found")
+	}
+
+	if assessment.TeacherID != teacher.ID {
+		isAssigned, err := h.courseService.IsTeacherAssignedToCourse(c.Request().Context(), assessment.CourseID, teacher.ID)
+		if err != nil {
+			return nil, nil, echo.NewHTTPError(http.StatusInternalServerError, "Failed to check course assignment: "+err.Error())
+		}
+
+		if !isAssigned {
+			return nil, nil, echo.NewHTTPError(http.StatusForbidden, "You do not have permission to manage extensions for this assessment")
+		}
+	}
+
+	if err := handlers.CheckCourseWritable(c, h.courseService, assessment.CourseID); err != nil {
+		return nil, nil, err
+	}
+
+	return assessment, teacher, nil
+}
+
+// authorizeStudent checks that the student named in the path is in the sections the teacher teaches, if the
+// teacher has any
+func (h *ExtensionHandler) authorizeStudent(c echo.Context, assessment *models.Assessment, teacher *models.User) (string, error) {
+	studentID := c.Param("studentId")
+	if studentID == "" {
+		return "", echo.NewHTTPError(http.StatusBadRequest, "Student ID is required")
+	}
+
+	canSee, err := h.assessmentService.CanTeacherSeeStudent(c.Request().Context(), assessment.CourseID, teacher.ID, studentID)
+	if err != nil {
+		return "", echo.NewHTTPError(http.StatusInternalServerError, "Failed to check section: "+err.Error())
+	}
+
+	if !canSee {
+		return "", echo.NewHTTPError(http.StatusForbidden, "This student is in a section you do not teach")
+	}
+
+	return studentID, nil
+}
+
+// HandleGrantExtension handles granting or replacing a student's extension
+func (h *ExtensionHandler) HandleGrantExtension(c echo.Context) error {
+	var req models.GrantExtensionRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid request body")
+	}
+
+	if err := utils.ValidateStruct(h.validator, req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, utils.FormatValidationErrors(err))
+	}
+
+	assessment, teacher, err := h.authorizeAssessment(c)
+	if err != nil {
+		return err
+	}
+
+	studentID, err := h.authorizeStudent(c, assessment, teacher)
+	if err != nil {
+		return err
+	}
+
+	extension, err := h.extensionService.GrantExtension(c.Request().Context(), assessment.ID, studentID, teacher.ID, req)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Failed to grant extension: "+err.Error())
+	}
+
+	return c.JSON(http.StatusOK, extension)
+}
+
+// HandleGetExtensions handles listing the extensions granted for an assessment
+func (h *ExtensionHandler) HandleGetExtensions(c echo.Context) error {
+	assessment, _, err := h.authorizeAssessment(c)
+	if err != nil {
+		return err
+	}
+
+	extensions, err := h.extensionService.GetExtensions(c.Request().Context(), assessment.ID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to retrieve extensions: "+err.Error())
+	}
+
+	return c.JSON(http.StatusOK, extensions)
+}
+
+// HandleRevokeExtension handles removing a student's extension
+func (h *ExtensionHandler) HandleRevokeExtension(c echo.Context) error {
+	assessment, teacher, err := h.authorizeAssessment(c)
+	if err != nil {
+		return err
+	}
+
+	studentID, err := h.authorizeStudent(c, assessment, teacher)
+	if err != nil {
+		return err
+	}
+
+	if err := h.extensionService.RevokeExtension(c.Request().Context(), assessment.ID, studentID); err != nil {
+		return echo.NewHTTPError(http.StatusNotFound, "Failed to revoke extension: "+err.Error())
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
@@ -0,0 +1,102 @@
+package teacher_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+
+	"assessment-management-system/db"
+	"assessment-management-system/db/dbtest"
+	"assessment-management-system/handlers/teacher"
+	"assessment-management-system/models"
+	"assessment-management-system/repositories"
+	"assessment-management-system/services"
+)
+
+func newExtensionHandler(database *db.DB) *teacher.ExtensionHandler {
+	courseRepo := repositories.NewCourseRepository(database)
+	assessmentRepo := repositories.NewAssessmentRepository(database)
+	extensionRepo := repositories.NewExtensionRepository(database)
+	sectionRepo := repositories.NewSectionRepository(database)
+
+	assessmentService := services.NewAssessmentService(assessmentRepo, courseRepo, repositories.NewUserRepository(database),
+		repositories.NewQuestionRepository(database), repositories.NewQuestionBankRepository(database),
+		repositories.NewRubricRepository(database), extensionRepo, repositories.NewGradebookRepository(database),
+		repositories.NewRegradeRepository(database), repositories.NewModerationRepository(database),
+		repositories.NewPeerReviewRepository(database), repositories.NewGroupRepository(database),
+		repositories.NewSimilarityRepository(database), sectionRepo, nil)
+	gradebookService := services.NewGradebookService(repositories.NewGradebookRepository(database), assessmentRepo, courseRepo,
+		repositories.NewGradingSchemeRepository(database), sectionRepo)
+	courseService := services.NewCourseService(courseRepo, repositories.NewUserRepository(database),
+		repositories.NewOrganizationRepository(database), repositories.NewTermRepository(database), sectionRepo,
+		repositories.NewPrerequisiteRepository(database), repositories.NewJoinCodeRepository(database), gradebookService)
+
+	return teacher.NewExtensionHandler(services.NewExtensionService(extensionRepo, assessmentRepo, courseRepo),
+		assessmentService, courseService)
+}
+
+// serveExtension calls an extension handler as the teacher for a student's extension on an assessment
+func serveExtension(handler echo.HandlerFunc, method string, user *models.User, assessmentID, studentID string) int {
+	e := echo.New()
+	req := httptest.NewRequest(method, "/", strings.NewReader(`{"max_attempts": 2, "reason": "Illness"}`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+
+	c := e.NewContext(req, rec)
+	c.SetParamNames("id", "studentId")
+	c.SetParamValues(assessmentID, studentID)
+	c.Set("user", user)
+
+	if err := handler(c); err != nil {
+		if httpErr, ok := err.(*echo.HTTPError); ok {
+			return httpErr.Code
+		}
+		return http.StatusInternalServerError
+	}
+	return rec.Code
+}
+
+func TestExtensionsKeepToTheTeachersSections(t *testing.T) {
+	database := dbtest.Open(t)
+	handler := newExtensionHandler(database)
+
+	org := dbtest.Organization(t, database)
+	term := dbtest.CurrentTerm(t, database, org.ID)
+	course := dbtest.Course(t, database, org.ID, term.ID, "Databases", nil)
+	lead := dbtest.User(t, database, org.ID, models.RoleTeacher)
+	tutor := dbtest.User(t, database, org.ID, models.RoleTeacher)
+	dbtest.Teach(t, database, course.ID, lead.ID)
+	dbtest.Teach(t, database, course.ID, tutor.ID)
+
+	ownSection := dbtest.Section(t, database, course.ID, tutor.ID)
+	otherSection := dbtest.Section(t, database, course.ID)
+	ownStudent := dbtest.User(t, database, org.ID, models.RoleStudent)
+	otherStudent := dbtest.User(t, database, org.ID, models.RoleStudent)
+	dbtest.Enroll(t, database, course.ID, ownStudent.ID, &ownSection.ID)
+	dbtest.Enroll(t, database, course.ID, otherStudent.ID, &otherSection.ID)
+
+	assessment := dbtest.Assessment(t, database, lead.ID, models.CreateAssessmentRequest{CourseID: course.ID})
+
+	if code := serveExtension(handler.HandleGrantExtension, http.MethodPut, tutor, assessment.ID, otherStudent.ID); code != http.StatusForbidden {
+		t.Errorf("granting an extension to a student of another section: status = %d, want %d", code, http.StatusForbidden)
+	}
+
+	if code := serveExtension(handler.HandleGrantExtension, http.MethodPut, lead, assessment.ID, otherStudent.ID); code != http.StatusOK {
+		t.Fatalf("the teacher of the whole course could not grant an extension: status = %d", code)
+	}
+
+	if code := serveExtension(handler.HandleRevokeExtension, http.MethodDelete, tutor, assessment.ID, otherStudent.ID); code != http.StatusForbidden {
+		t.Errorf("revoking the extension of a student of another section: status = %d, want %d", code, http.StatusForbidden)
+	}
+
+	if code := serveExtension(handler.HandleGrantExtension, http.MethodPut, tutor, assessment.ID, ownStudent.ID); code != http.StatusOK {
+		t.Errorf("granting an extension to the teacher's own student: status = %d, want %d", code, http.StatusOK)
+	}
+
+	if code := serveExtension(handler.HandleRevokeExtension, http.MethodDelete, tutor, assessment.ID, ownStudent.ID); code != http.StatusNoContent {
+		t.Errorf("revoking the extension of the teacher's own student: status = %d, want %d", code, http.StatusNoContent)
+	}
+}
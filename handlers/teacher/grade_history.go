@@ -0,0 +1,40 @@
+package teacher
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+// HandleGetGradeHistory handles retrieving every version of a submission's grade
+func (h *AssessmentHandler) HandleGetGradeHistory(c echo.Context) error {
+	submissionID := c.Param("submissionId")
+	if submissionID == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "Submission ID is required")
+	}
+
+	submission, err := h.assessmentService.GetSubmissionByID(c.Request().Context(), submissionID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to retrieve submission: "+err.Error())
+	}
+
+	if submission == nil {
+		return echo.NewHTTPError(http.StatusNotFound, "Submission not found")
+	}
+
+	assessment, err := h.authorizeAssessment(c, submission.AssessmentID)
+	if err != nil {
+		return err
+	}
+
+	if err := h.checkSubmissionSection(c, assessment, submission); err != nil {
+		return err
+	}
+
+	versions, err := h.assessmentService.GetGradeHistory(c.Request().Context(), submissionID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to retrieve grade history: "+err.Error())
+	}
+
+	return c.JSON(http.StatusOK, versions)
+}
@@ -0,0 +1,71 @@
+package teacher
+
+import (
+	"bytes"
+	"mime"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"assessment-management-system/middleware"
+	"assessment-management-system/services"
+)
+
+// HandleExportGrades handles downloading the grade sheet of an assessment as CSV
+func (h *AssessmentHandler) HandleExportGrades(c echo.Context) error {
+	assessment, err := h.authorizeAssessment(c, c.Param("id"))
+	if err != nil {
+		return err
+	}
+
+	// Get the teacher's ID from the token
+	teacher, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		return err
+	}
+
+	rows, err := h.assessmentService.GetGradeSheet(c.Request().Context(), assessment, teacher.ID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to retrieve grade sheet: "+err.Error())
+	}
+
+	var sheet bytes.Buffer
+	if err := services.WriteGradeSheet(&sheet, rows); err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to write grade sheet: "+err.Error())
+	}
+
+	c.Response().Header().Set(echo.HeaderContentDisposition, mime.FormatMediaType("attachment", map[string]string{"filename": assessment.Title + " grades.csv"}))
+	return c.Blob(http.StatusOK, "text/csv; charset=utf-8", sheet.Bytes())
+}
+
+// HandleImportGrades handles uploading an edited grade sheet of an assessment, sent as the file field of a multipart form
+func (h *AssessmentHandler) HandleImportGrades(c echo.Context) error {
+	file, err := c.FormFile("file")
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "A grade sheet file is required")
+	}
+
+	assessment, err := h.authorizeAssessment(c, c.Param("id"))
+	if err != nil {
+		return err
+	}
+
+	// Get the teacher's ID from the token
+	teacher, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		return err
+	}
+
+	sheet, err := file.Open()
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Failed to read grade sheet: "+err.Error())
+	}
+	defer sheet.Close()
+
+	result, err := h.assessmentService.ImportGrades(c.Request().Context(), assessment, teacher.ID, sheet)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Failed to import grades: "+err.Error())
+	}
+
+	return c.JSON(http.StatusOK, result)
+}
@@ -0,0 +1,219 @@
+package teacher
+
+import (
+	"net/http"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+
+	"assessment-management-system/handlers"
+	"assessment-management-system/middleware"
+	"assessment-management-system/models"
+	"assessment-management-system/services"
+	"assessment-management-system/utils"
+)
+
+// GradebookHandler handles course gradebook routes for teachers
+type GradebookHandler struct {
+	gradebookService *services.GradebookService
+	schemeService    *services.GradingSchemeService
+	courseService    *services.CourseService
+	validator        *validator.Validate
+}
+
+// NewGradebookHandler creates a new GradebookHandler
+func NewGradebookHandler(
+	gradebookService *services.GradebookService,
+	schemeService *services.GradingSchemeService,
+	courseService *services.CourseService,
+) *GradebookHandler {
+	return &GradebookHandler{
+		gradebookService: gradebookService,
+		schemeService:    schemeService,
+		courseService:    courseService,
+		validator:        utils.NewValidator(),
+	}
+}
+
+// authorizeCourse checks that the teacher is assigned to the course
+func (h *GradebookHandler) authorizeCourse(c echo.Context, courseID string) error {
+	if courseID == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "Course ID is required")
+	}
+
+	// Get the teacher's ID from the token
+	teacher, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		return err
+	}
+
+	isAssigned, err := h.courseService.IsTeacherAssignedToCourse(c.Request().Context(), courseID, teacher.ID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to check course assignment: "+err.Error())
+	}
+
+	if !isAssigned {
+		return echo.NewHTTPError(http.StatusForbidden, "You are not assigned to this course")
+	}
+
+	if err := handlers.CheckCourseWritable(c, h.courseService, courseID); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// HandleCreateCategory handles adding a grade category to a course
+func (h *GradebookHandler) HandleCreateCategory(c echo.Context) error {
+	var req models.CreateGradeCategoryRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid request body")
+	}
+
+	if err := utils.ValidateStruct(h.validator, req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, utils.FormatValidationErrors(err))
+	}
+
+	courseID := c.Param("courseId")
+	if err := h.authorizeCourse(c, courseID); err != nil {
+		return err
+	}
+
+	category, err := h.gradebookService.CreateCategory(c.Request().Context(), courseID, req)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Failed to create grade category: "+err.Error())
+	}
+
+	return c.JSON(http.StatusCreated, category)
+}
+
+// HandleGetCategories handles listing the grade categories of a course
+func (h *GradebookHandler) HandleGetCategories(c echo.Context) error {
+	courseID := c.Param("courseId")
+	if err := h.authorizeCourse(c, courseID); err != nil {
+		return err
+	}
+
+	categories, err := h.gradebookService.GetCategories(c.Request().Context(), courseID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to retrieve grade categories: "+err.Error())
+	}
+
+	return c.JSON(http.StatusOK, categories)
+}
+
+// HandleUpdateCategory handles updating a grade category
+func (h *GradebookHandler) HandleUpdateCategory(c echo.Context) error {
+	var req models.UpdateGradeCategoryRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid request body")
+	}
+
+	if err := utils.ValidateStruct(h.validator, req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, utils.FormatValidationErrors(err))
+	}
+
+	courseID := c.Param("courseId")
+	if err := h.authorizeCourse(c, courseID); err != nil {
+		return err
+	}
+
+	category, err := h.gradebookService.UpdateCategory(c.Request().Context(), courseID, c.Param("categoryId"), req)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Failed to update grade category: "+err.Error())
+	}
+
+	return c.JSON(http.StatusOK, category)
+}
+
+// HandleDeleteCategory handles deleting a grade category
+func (h *GradebookHandler) HandleDeleteCategory(c echo.Context) error {
+	courseID := c.Param("courseId")
+	if err := h.authorizeCourse(c, courseID); err != nil {
+		return err
+	}
+
+	if err := h.gradebookService.DeleteCategory(c.Request().Context(), courseID, c.Param("categoryId")); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Failed to delete grade category: "+err.Error())
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// HandleGetGradebook handles retrieving the gradebook of a course, every enrolled student against every assessment
+func (h *GradebookHandler) HandleGetGradebook(c echo.Context) error {
+	courseID := c.Param("courseId")
+	if err := h.authorizeCourse(c, courseID); err != nil {
+		return err
+	}
+
+	// Get the teacher's ID from the token
+	teacher, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		return err
+	}
+
+	gradebook, err := h.gradebookService.GetGradebook(c.Request().Context(), courseID, teacher.ID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to retrieve gradebook: "+err.Error())
+	}
+
+	return c.JSON(http.StatusOK, gradebook)
+}
+
+// HandleSaveGradingScheme handles overriding the organization's grading scheme for a course
+func (h *GradebookHandler) HandleSaveGradingScheme(c echo.Context) error {
+	var req models.SaveGradingSchemeRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid request body")
+	}
+
+	if err := utils.ValidateStruct(h.validator, req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, utils.FormatValidationErrors(err))
+	}
+
+	courseID := c.Param("courseId")
+	if err := h.authorizeCourse(c, courseID); err != nil {
+		return err
+	}
+
+	scheme, err := h.schemeService.SaveCourseScheme(c.Request().Context(), courseID, req)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Failed to save grading scheme: "+err.Error())
+	}
+
+	return c.JSON(http.StatusOK, scheme)
+}
+
+// HandleGetGradingScheme handles retrieving the grading scheme that applies to a course
+func (h *GradebookHandler) HandleGetGradingScheme(c echo.Context) error {
+	courseID := c.Param("courseId")
+	if err := h.authorizeCourse(c, courseID); err != nil {
+		return err
+	}
+
+	scheme, err := h.schemeService.GetCourseScheme(c.Request().Context(), courseID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to retrieve grading scheme: "+err.Error())
+	}
+
+	if scheme == nil {
+		return echo.NewHTTPError(http.StatusNotFound, "Grading scheme not found")
+	}
+
+	return c.JSON(http.StatusOK, scheme)
+}
+
+// HandleDeleteGradingScheme handles removing a course's own grading scheme
+func (h *GradebookHandler) HandleDeleteGradingScheme(c echo.Context) error {
+	courseID := c.Param("courseId")
+	if err := h.authorizeCourse(c, courseID); err != nil {
+		return err
+	}
+
+	if err := h.schemeService.DeleteCourseScheme(c.Request().Context(), courseID); err != nil {
+		return echo.NewHTTPError(http.StatusNotFound, "Failed to delete grading scheme: "+err.Error())
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
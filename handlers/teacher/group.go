@@ -0,0 +1,207 @@
+package teacher
+
+import (
+	"net/http"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+
+	"assessment-management-system/handlers"
+	"assessment-management-system/middleware"
+	"assessment-management-system/models"
+	"assessment-management-system/services"
+	"assessment-management-system/utils"
+)
+
+// GroupHandler handles course group routes for teachers
+type GroupHandler struct {
+	groupService  *services.GroupService
+	courseService *services.CourseService
+	validator     *validator.Validate
+}
+
+// NewGroupHandler creates a new GroupHandler
+func NewGroupHandler(groupService *services.GroupService, courseService *services.CourseService) *GroupHandler {
+	return &GroupHandler{
+		groupService:  groupService,
+		courseService: courseService,
+		validator:     utils.NewValidator(),
+	}
+}
+
+// authorizeCourse checks that the teacher is assigned to the course
+func (h *GroupHandler) authorizeCourse(c echo.Context, courseID string) error {
+	if courseID == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "Course ID is required")
+	}
+
+	// Get the teacher's ID from the token
+	teacher, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		return err
+	}
+
+	isAssigned, err := h.courseService.IsTeacherAssignedToCourse(c.Request().Context(), courseID, teacher.ID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to check course assignment: "+err.Error())
+	}
+
+	if !isAssigned {
+		return echo.NewHTTPError(http.StatusForbidden, "You are not assigned to this course")
+	}
+
+	if err := handlers.CheckCourseWritable(c, h.courseService, courseID); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// authorizeGroup loads the group in the URL and checks that the teacher is assigned to its course
+func (h *GroupHandler) authorizeGroup(c echo.Context) (*models.CourseGroup, error) {
+	groupID := c.Param("groupId")
+	if groupID == "" {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, "Group ID is required")
+	}
+
+	group, err := h.groupService.GetGroup(c.Request().Context(), groupID)
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, "Failed to retrieve group: "+err.Error())
+	}
+
+	if group == nil {
+		return nil, echo.NewHTTPError(http.StatusNotFound, "Group not found")
+	}
+
+	if err := h.authorizeCourse(c, group.CourseID); err != nil {
+		return nil, err
+	}
+
+	return group, nil
+}
+
+// HandleCreateGroup handles creating a group in a course
+func (h *GroupHandler) HandleCreateGroup(c echo.Context) error {
+	courseID := c.Param("courseId")
+	if err := h.authorizeCourse(c, courseID); err != nil {
+		return err
+	}
+
+	var req models.CreateGroupRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid request body")
+	}
+
+	if err := utils.ValidateStruct(h.validator, req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, utils.FormatValidationErrors(err))
+	}
+
+	// Get the teacher's ID from the token
+	teacher, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		return err
+	}
+
+	group, err := h.groupService.CreateGroup(c.Request().Context(), courseID, teacher.ID, req)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Failed to create group: "+err.Error())
+	}
+
+	return c.JSON(http.StatusCreated, group)
+}
+
+// HandleGetGroups handles listing the groups of a course
+func (h *GroupHandler) HandleGetGroups(c echo.Context) error {
+	courseID := c.Param("courseId")
+	if err := h.authorizeCourse(c, courseID); err != nil {
+		return err
+	}
+
+	groups, err := h.groupService.GetCourseGroups(c.Request().Context(), courseID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to retrieve groups: "+err.Error())
+	}
+
+	return c.JSON(http.StatusOK, groups)
+}
+
+// HandleUpdateGroup handles updating a group
+func (h *GroupHandler) HandleUpdateGroup(c echo.Context) error {
+	var req models.UpdateGroupRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid request body")
+	}
+
+	if err := utils.ValidateStruct(h.validator, req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, utils.FormatValidationErrors(err))
+	}
+
+	group, err := h.authorizeGroup(c)
+	if err != nil {
+		return err
+	}
+
+	group, err = h.groupService.UpdateGroup(c.Request().Context(), group, req)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Failed to update group: "+err.Error())
+	}
+
+	return c.JSON(http.StatusOK, group)
+}
+
+// HandleDeleteGroup handles deleting a group
+func (h *GroupHandler) HandleDeleteGroup(c echo.Context) error {
+	group, err := h.authorizeGroup(c)
+	if err != nil {
+		return err
+	}
+
+	if err := h.groupService.DeleteGroup(c.Request().Context(), group.ID); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Failed to delete group: "+err.Error())
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// HandleAddMember handles placing a student in a group
+func (h *GroupHandler) HandleAddMember(c echo.Context) error {
+	var req models.AddGroupMemberRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid request body")
+	}
+
+	if err := utils.ValidateStruct(h.validator, req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, utils.FormatValidationErrors(err))
+	}
+
+	group, err := h.authorizeGroup(c)
+	if err != nil {
+		return err
+	}
+
+	group, err = h.groupService.AddMember(c.Request().Context(), group, req.StudentID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Failed to add member: "+err.Error())
+	}
+
+	return c.JSON(http.StatusOK, group)
+}
+
+// HandleRemoveMember handles taking a student out of a group
+func (h *GroupHandler) HandleRemoveMember(c echo.Context) error {
+	studentID := c.Param("studentId")
+	if studentID == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "Student ID is required")
+	}
+
+	group, err := h.authorizeGroup(c)
+	if err != nil {
+		return err
+	}
+
+	if err := h.groupService.RemoveMember(c.Request().Context(), group, studentID); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Failed to remove member: "+err.Error())
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
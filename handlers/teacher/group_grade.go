@@ -0,0 +1,74 @@
+package teacher
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"assessment-management-system/models"
+	"assessment-management-system/utils"
+)
+
+// HandleGetSubmissionMembers handles listing the members of the group that made a submission
+func (h *AssessmentHandler) HandleGetSubmissionMembers(c echo.Context) error {
+	submission, _, err := h.authorizeSubmission(c)
+	if err != nil {
+		return err
+	}
+
+	members, err := h.assessmentService.GetSubmissionMembers(c.Request().Context(), submission)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to retrieve group members: "+err.Error())
+	}
+
+	return c.JSON(http.StatusOK, members)
+}
+
+// HandleAdjustMemberGrade handles overriding the grade of a group submission for one member
+func (h *AssessmentHandler) HandleAdjustMemberGrade(c echo.Context) error {
+	studentID := c.Param("studentId")
+	if studentID == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "Student ID is required")
+	}
+
+	var req models.AdjustMemberGradeRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid request body")
+	}
+
+	if err := utils.ValidateStruct(h.validator, req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, utils.FormatValidationErrors(err))
+	}
+
+	submission, teacher, err := h.authorizeSubmission(c)
+	if err != nil {
+		return err
+	}
+
+	member, err := h.assessmentService.AdjustMemberGrade(c.Request().Context(), submission, studentID, teacher.ID, req)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Failed to adjust grade: "+err.Error())
+	}
+
+	return c.JSON(http.StatusOK, member)
+}
+
+// HandleClearMemberGrade handles giving a member of a group submission the group's grade again
+func (h *AssessmentHandler) HandleClearMemberGrade(c echo.Context) error {
+	studentID := c.Param("studentId")
+	if studentID == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "Student ID is required")
+	}
+
+	submission, teacher, err := h.authorizeSubmission(c)
+	if err != nil {
+		return err
+	}
+
+	member, err := h.assessmentService.ClearMemberAdjustment(c.Request().Context(), submission, studentID, teacher.ID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Failed to clear grade adjustment: "+err.Error())
+	}
+
+	return c.JSON(http.StatusOK, member)
+}
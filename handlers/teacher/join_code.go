@@ -0,0 +1,188 @@
+package teacher
+
+import (
+	"net/http"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+
+	"assessment-management-system/handlers"
+	"assessment-management-system/middleware"
+	"assessment-management-system/models"
+	"assessment-management-system/services"
+	"assessment-management-system/utils"
+)
+
+// JoinCodeHandler handles course join code routes for teachers
+type JoinCodeHandler struct {
+	courseService *services.CourseService
+	jwtSecret     string
+	validator     *validator.Validate
+}
+
+// NewJoinCodeHandler creates a new JoinCodeHandler. Invite links are signed with a key derived from the JWT secret.
+func NewJoinCodeHandler(courseService *services.CourseService, jwtSecret string) *JoinCodeHandler {
+	return &JoinCodeHandler{
+		courseService: courseService,
+		jwtSecret:     jwtSecret,
+		validator:     utils.NewValidator(),
+	}
+}
+
+// authorizeCourse checks that the teacher is assigned to the course
+func (h *JoinCodeHandler) authorizeCourse(c echo.Context, courseID, teacherID string) error {
+	if courseID == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "Course ID is required")
+	}
+
+	isAssigned, err := h.courseService.IsTeacherAssignedToCourse(c.Request().Context(), courseID, teacherID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to check course assignment: "+err.Error())
+	}
+
+	if !isAssigned {
+		return echo.NewHTTPError(http.StatusForbidden, "You are not assigned to this course")
+	}
+
+	if err := handlers.CheckCourseWritable(c, h.courseService, courseID); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// authorizeJoinCode loads the join code in the URL and checks that the teacher is assigned to its course
+func (h *JoinCodeHandler) authorizeJoinCode(c echo.Context, teacherID string) (*models.JoinCode, error) {
+	codeID := c.Param("codeId")
+	if codeID == "" {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, "Join code ID is required")
+	}
+
+	code, err := h.courseService.GetJoinCode(c.Request().Context(), codeID)
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, "Failed to retrieve join code: "+err.Error())
+	}
+
+	if code == nil {
+		return nil, echo.NewHTTPError(http.StatusNotFound, "Join code not found")
+	}
+
+	if err := h.authorizeCourse(c, code.CourseID, teacherID); err != nil {
+		return nil, err
+	}
+
+	return code, nil
+}
+
+// withInviteLink adds the signed invite link to a join code
+func (h *JoinCodeHandler) withInviteLink(code *models.JoinCode) error {
+	token, err := utils.GenerateInviteToken(code.ID, code.ExpiresAt, h.jwtSecret)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to sign invite link: "+err.Error())
+	}
+
+	code.InviteLink = "/api/student/invites/" + token
+	return nil
+}
+
+// HandleCreateJoinCode handles creating a join code, with its invite link, for a course
+func (h *JoinCodeHandler) HandleCreateJoinCode(c echo.Context) error {
+	var req models.CreateJoinCodeRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid request body")
+	}
+
+	if err := utils.ValidateStruct(h.validator, req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, utils.FormatValidationErrors(err))
+	}
+
+	// Get the teacher's ID from the token
+	teacher, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		return err
+	}
+
+	courseID := c.Param("courseId")
+	if err := h.authorizeCourse(c, courseID, teacher.ID); err != nil {
+		return err
+	}
+
+	code, err := h.courseService.CreateJoinCode(c.Request().Context(), courseID, teacher.ID, req)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Failed to create join code: "+err.Error())
+	}
+
+	if err := h.withInviteLink(code); err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusCreated, code)
+}
+
+// HandleGetJoinCodes handles listing the join codes of a course with their invite links
+func (h *JoinCodeHandler) HandleGetJoinCodes(c echo.Context) error {
+	// Get the teacher's ID from the token
+	teacher, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		return err
+	}
+
+	courseID := c.Param("courseId")
+	if err := h.authorizeCourse(c, courseID, teacher.ID); err != nil {
+		return err
+	}
+
+	codes, err := h.courseService.GetJoinCodes(c.Request().Context(), courseID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to retrieve join codes: "+err.Error())
+	}
+
+	for _, code := range codes {
+		if err := h.withInviteLink(code); err != nil {
+			return err
+		}
+	}
+
+	return c.JSON(http.StatusOK, codes)
+}
+
+// HandleRevokeJoinCode handles revoking a join code and its invite links
+func (h *JoinCodeHandler) HandleRevokeJoinCode(c echo.Context) error {
+	// Get the teacher's ID from the token
+	teacher, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		return err
+	}
+
+	code, err := h.authorizeJoinCode(c, teacher.ID)
+	if err != nil {
+		return err
+	}
+
+	if err := h.courseService.RevokeJoinCode(c.Request().Context(), code); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Failed to revoke join code: "+err.Error())
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// HandleGetJoinCodeStudents handles listing the students who enrolled with a join code
+func (h *JoinCodeHandler) HandleGetJoinCodeStudents(c echo.Context) error {
+	// Get the teacher's ID from the token
+	teacher, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		return err
+	}
+
+	code, err := h.authorizeJoinCode(c, teacher.ID)
+	if err != nil {
+		return err
+	}
+
+	students, err := h.courseService.GetJoinCodeStudents(c.Request().Context(), code.ID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to retrieve students: "+err.Error())
+	}
+
+	return c.JSON(http.StatusOK, students)
+}
@@ -0,0 +1,130 @@
+package teacher
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"assessment-management-system/middleware"
+	"assessment-management-system/models"
+	"assessment-management-system/utils"
+)
+
+// authorizeSubmission loads the submission in the URL and checks that the teacher can grade its assessment
+func (h *AssessmentHandler) authorizeSubmission(c echo.Context) (*models.AssessmentSubmission, *models.User, error) {
+	submissionID := c.Param("submissionId")
+	if submissionID == "" {
+		return nil, nil, echo.NewHTTPError(http.StatusBadRequest, "Submission ID is required")
+	}
+
+	// Get the teacher's ID from the token
+	teacher, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	submission, err := h.assessmentService.GetSubmissionByID(c.Request().Context(), submissionID)
+	if err != nil {
+		return nil, nil, echo.NewHTTPError(http.StatusInternalServerError, "Failed to retrieve submission: "+err.Error())
+	}
+
+	if submission == nil {
+		return nil, nil, echo.NewHTTPError(http.StatusNotFound, "Submission not found")
+	}
+
+	assessment, err := h.authorizeAssessment(c, submission.AssessmentID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := h.checkSubmissionSection(c, assessment, submission); err != nil {
+		return nil, nil, err
+	}
+
+	return submission, teacher, nil
+}
+
+// HandleAssignSecondMarker handles putting an exam submission up for double marking
+func (h *AssessmentHandler) HandleAssignSecondMarker(c echo.Context) error {
+	var req models.AssignSecondMarkerRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid request body")
+	}
+
+	if err := utils.ValidateStruct(h.validator, req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, utils.FormatValidationErrors(err))
+	}
+
+	submission, teacher, err := h.authorizeSubmission(c)
+	if err != nil {
+		return err
+	}
+
+	moderation, err := h.assessmentService.AssignSecondMarker(c.Request().Context(), submission.ID, teacher.ID, req)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Failed to assign second marker: "+err.Error())
+	}
+
+	moderation.HideMarksFrom(teacher.ID)
+	return c.JSON(http.StatusOK, moderation)
+}
+
+// HandleGetModeration handles retrieving the double marking of a submission
+func (h *AssessmentHandler) HandleGetModeration(c echo.Context) error {
+	submission, teacher, err := h.authorizeSubmission(c)
+	if err != nil {
+		return err
+	}
+
+	moderation, err := h.assessmentService.GetModeration(c.Request().Context(), submission.ID, teacher.ID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to retrieve moderation: "+err.Error())
+	}
+
+	if moderation == nil {
+		return echo.NewHTTPError(http.StatusNotFound, "This submission is not double marked")
+	}
+
+	return c.JSON(http.StatusOK, moderation)
+}
+
+// HandleAgreeMark handles setting the agreed final mark of a double-marked submission
+func (h *AssessmentHandler) HandleAgreeMark(c echo.Context) error {
+	var req models.AgreeMarkRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid request body")
+	}
+
+	if err := utils.ValidateStruct(h.validator, req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, utils.FormatValidationErrors(err))
+	}
+
+	submission, teacher, err := h.authorizeSubmission(c)
+	if err != nil {
+		return err
+	}
+
+	moderation, err := h.assessmentService.AgreeModeratedMark(c.Request().Context(), submission.ID, teacher.ID, req)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Failed to agree final mark: "+err.Error())
+	}
+
+	return c.JSON(http.StatusOK, moderation)
+}
+
+// HandleGetModerationQueue handles listing the double-marked submissions of the assessments the teacher can grade
+func (h *AssessmentHandler) HandleGetModerationQueue(c echo.Context) error {
+	// Get the teacher's ID from the token
+	teacher, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		return err
+	}
+
+	status := models.ModerationStatus(c.QueryParam("status"))
+	moderations, err := h.assessmentService.GetModerationQueue(c.Request().Context(), teacher.ID, status)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Failed to retrieve moderations: "+err.Error())
+	}
+
+	return c.JSON(http.StatusOK, moderations)
+}
@@ -0,0 +1,83 @@
+package teacher
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"assessment-management-system/middleware"
+	"assessment-management-system/models"
+	"assessment-management-system/utils"
+)
+
+// HandleDistributePeerReviews handles starting the peer-review phase of an assessment
+func (h *AssessmentHandler) HandleDistributePeerReviews(c echo.Context) error {
+	assessment, err := h.authorizeAssessment(c, c.Param("id"))
+	if err != nil {
+		return err
+	}
+
+	summaries, err := h.assessmentService.DistributePeerReviews(c.Request().Context(), assessment)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Failed to distribute peer reviews: "+err.Error())
+	}
+
+	return c.JSON(http.StatusCreated, summaries)
+}
+
+// HandleGetPeerReviews handles retrieving the peer reviews of an assessment with the aggregated peer scores
+func (h *AssessmentHandler) HandleGetPeerReviews(c echo.Context) error {
+	assessment, err := h.authorizeAssessment(c, c.Param("id"))
+	if err != nil {
+		return err
+	}
+
+	summaries, err := h.assessmentService.GetPeerReviewSummaries(c.Request().Context(), assessment)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to retrieve peer reviews: "+err.Error())
+	}
+
+	// Get the teacher's ID from the token
+	teacher, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		return err
+	}
+
+	// Teachers of particular sections only see the reviews of their own students' submissions
+	summaries, err = h.assessmentService.FilterPeerReviewsForTeacher(c.Request().Context(), assessment.CourseID, teacher.ID, summaries)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to check sections: "+err.Error())
+	}
+
+	return c.JSON(http.StatusOK, summaries)
+}
+
+// HandleApplyPeerScores handles folding the peer scores of an assessment into its grades
+func (h *AssessmentHandler) HandleApplyPeerScores(c echo.Context) error {
+	var req models.ApplyPeerScoresRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid request body")
+	}
+
+	if err := utils.ValidateStruct(h.validator, req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, utils.FormatValidationErrors(err))
+	}
+
+	assessment, err := h.authorizeAssessment(c, c.Param("id"))
+	if err != nil {
+		return err
+	}
+
+	// Get the teacher's ID from the token
+	teacher, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		return err
+	}
+
+	result, err := h.assessmentService.ApplyPeerScores(c.Request().Context(), assessment, teacher.ID, req)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Failed to apply peer scores: "+err.Error())
+	}
+
+	return c.JSON(http.StatusOK, result)
+}
@@ -0,0 +1,250 @@
+package teacher
+
+import (
+	"net/http"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+
+	"assessment-management-system/handlers"
+	"assessment-management-system/middleware"
+	"assessment-management-system/models"
+	"assessment-management-system/services"
+	"assessment-management-system/utils"
+)
+
+// QuestionHandler handles assessment question routes for teachers
+type QuestionHandler struct {
+	questionService   *services.QuestionService
+	assessmentService *services.AssessmentService
+	courseService     *services.CourseService
+	validator         *validator.Validate
+}
+
+// NewQuestionHandler creates a new QuestionHandler
+func NewQuestionHandler(
+	questionService *services.QuestionService,
+	assessmentService *services.AssessmentService,
+	courseService *services.CourseService,
+) *QuestionHandler {
+	return &QuestionHandler{
+		questionService:   questionService,
+		assessmentService: assessmentService,
+		courseService:     courseService,
+		validator:         utils.NewValidator(),
+	}
+}
+
+// authorizeAssessment loads the assessment and checks the teacher's access to it.
+// Only the teacher who created the assessment may change its questions; teachers
+// assigned to the course may view them.
+func (h *QuestionHandler) authorizeAssessment(c echo.Context, assessmentID string, requireOwner bool) (*models.Assessment, error) {
+	if assessmentID == "" {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, "Assessment ID is required")
+	}
+
+	// Get the teacher's ID from the token
+	teacher, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		return nil, err
+	}
+
+	assessment, err := h.assessmentService.GetAssessmentByID(c.Request().Context(), assessmentID)
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, "Failed to retrieve assessment: "+err.Error())
+	}
+
+	if assessment == nil {
+		return nil, echo.NewHTTPError(http.StatusNotFound, "Assessment not found")
+	}
+
+	if assessment.TeacherID != teacher.ID {
+		if requireOwner {
+			return nil, echo.NewHTTPError(http.StatusForbidden, "You do not have permission to modify the questions of this assessment")
+		}
+
+		isAssigned, err := h.courseService.IsTeacherAssignedToCourse(c.Request().Context(), assessment.CourseID, teacher.ID)
+		if err != nil {
+			return nil, echo.NewHTTPError(http.StatusInternalServerError, "Failed to check course assignment: "+err.Error())
+		}
+
+		if !isAssigned {
+			return nil, echo.NewHTTPError(http.StatusForbidden, "You do not have permission to view the questions of this assessment")
+		}
+	}
+
+	if err := handlers.CheckCourseWritable(c, h.courseService, assessment.CourseID); err != nil {
+		return nil, err
+	}
+
+	return assessment, nil
+}
+
+// HandleCreateQuestion handles adding a question to an assessment
+func (h *QuestionHandler) HandleCreateQuestion(c echo.Context) error {
+	var req models.CreateQuestionRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid request body")
+	}
+
+	if err := utils.ValidateStruct(h.validator, req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, utils.FormatValidationErrors(err))
+	}
+
+	assessment, err := h.authorizeAssessment(c, c.Param("id"), true)
+	if err != nil {
+		return err
+	}
+
+	question, err := h.questionService.AddQuestion(c.Request().Context(), assessment.ID, req)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Failed to create question: "+err.Error())
+	}
+
+	return c.JSON(http.StatusCreated, question)
+}
+
+// HandleGetQuestions handles retrieving all questions of an assessment, including answer keys
+func (h *QuestionHandler) HandleGetQuestions(c echo.Context) error {
+	assessment, err := h.authorizeAssessment(c, c.Param("id"), false)
+	if err != nil {
+		return err
+	}
+
+	questions, err := h.questionService.GetQuestions(c.Request().Context(), assessment.ID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to retrieve questions: "+err.Error())
+	}
+
+	return c.JSON(http.StatusOK, questions)
+}
+
+// HandleUpdateQuestion handles updating a question of an assessment
+func (h *QuestionHandler) HandleUpdateQuestion(c echo.Context) error {
+	questionID := c.Param("questionId")
+	if questionID == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "Question ID is required")
+	}
+
+	var req models.UpdateQuestionRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid request body")
+	}
+
+	if err := utils.ValidateStruct(h.validator, req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, utils.FormatValidationErrors(err))
+	}
+
+	assessment, err := h.authorizeAssessment(c, c.Param("id"), true)
+	if err != nil {
+		return err
+	}
+
+	question, err := h.questionService.UpdateQuestion(c.Request().Context(), assessment.ID, questionID, req)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Failed to update question: "+err.Error())
+	}
+
+	return c.JSON(http.StatusOK, question)
+}
+
+// HandleDeleteQuestion handles removing a question from an assessment
+func (h *QuestionHandler) HandleDeleteQuestion(c echo.Context) error {
+	questionID := c.Param("questionId")
+	if questionID == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "Question ID is required")
+	}
+
+	assessment, err := h.authorizeAssessment(c, c.Param("id"), true)
+	if err != nil {
+		return err
+	}
+
+	if err := h.questionService.DeleteQuestion(c.Request().Context(), assessment.ID, questionID); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Failed to delete question: "+err.Error())
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// HandleReorderQuestions handles changing the order of an assessment's questions
+func (h *QuestionHandler) HandleReorderQuestions(c echo.Context) error {
+	var req models.ReorderQuestionsRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid request body")
+	}
+
+	if err := utils.ValidateStruct(h.validator, req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, utils.FormatValidationErrors(err))
+	}
+
+	assessment, err := h.authorizeAssessment(c, c.Param("id"), true)
+	if err != nil {
+		return err
+	}
+
+	questions, err := h.questionService.ReorderQuestions(c.Request().Context(), assessment.ID, req.QuestionIDs)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Failed to reorder questions: "+err.Error())
+	}
+
+	return c.JSON(http.StatusOK, questions)
+}
+
+// HandleCreateDrawRule handles making an assessment draw random questions from the course's bank
+func (h *QuestionHandler) HandleCreateDrawRule(c echo.Context) error {
+	var req models.CreateDrawRuleRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid request body")
+	}
+
+	if err := utils.ValidateStruct(h.validator, req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, utils.FormatValidationErrors(err))
+	}
+
+	assessment, err := h.authorizeAssessment(c, c.Param("id"), true)
+	if err != nil {
+		return err
+	}
+
+	rule, err := h.questionService.AddDrawRule(c.Request().Context(), assessment, req)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Failed to create draw rule: "+err.Error())
+	}
+
+	return c.JSON(http.StatusCreated, rule)
+}
+
+// HandleGetDrawRules handles retrieving the draw rules of an assessment
+func (h *QuestionHandler) HandleGetDrawRules(c echo.Context) error {
+	assessment, err := h.authorizeAssessment(c, c.Param("id"), false)
+	if err != nil {
+		return err
+	}
+
+	rules, err := h.questionService.GetDrawRules(c.Request().Context(), assessment.ID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to retrieve draw rules: "+err.Error())
+	}
+
+	return c.JSON(http.StatusOK, rules)
+}
+
+// HandleDeleteDrawRule handles removing a draw rule from an assessment
+func (h *QuestionHandler) HandleDeleteDrawRule(c echo.Context) error {
+	drawID := c.Param("drawId")
+	if drawID == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "Draw rule ID is required")
+	}
+
+	assessment, err := h.authorizeAssessment(c, c.Param("id"), true)
+	if err != nil {
+		return err
+	}
+
+	if err := h.questionService.DeleteDrawRule(c.Request().Context(), assessment.ID, drawID); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Failed to delete draw rule: "+err.Error())
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
@@ -0,0 +1,160 @@
+package teacher
+
+import (
+	"net/http"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+
+	"assessment-management-system/handlers"
+	"assessment-management-system/middleware"
+	"assessment-management-system/models"
+	"assessment-management-system/services"
+	"assessment-management-system/utils"
+)
+
+// QuestionBankHandler handles course question bank routes for teachers
+type QuestionBankHandler struct {
+	bankService   *services.QuestionBankService
+	courseService *services.CourseService
+	validator     *validator.Validate
+}
+
+// NewQuestionBankHandler creates a new QuestionBankHandler
+func NewQuestionBankHandler(
+	bankService *services.QuestionBankService,
+	courseService *services.CourseService,
+) *QuestionBankHandler {
+	return &QuestionBankHandler{
+		bankService:   bankService,
+		courseService: courseService,
+		validator:     utils.NewValidator(),
+	}
+}
+
+// authorizeCourse checks that the teacher is assigned to the course and returns the teacher
+func (h *QuestionBankHandler) authorizeCourse(c echo.Context, courseID string) (*models.User, error) {
+	if courseID == "" {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, "Course ID is required")
+	}
+
+	// Get the teacher's ID from the token
+	teacher, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		return nil, err
+	}
+
+	isAssigned, err := h.courseService.IsTeacherAssignedToCourse(c.Request().Context(), courseID, teacher.ID)
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, "Failed to check course assignment: "+err.Error())
+	}
+
+	if !isAssigned {
+		return nil, echo.NewHTTPError(http.StatusForbidden, "You are not assigned to this course")
+	}
+
+	if err := handlers.CheckCourseWritable(c, h.courseService, courseID); err != nil {
+		return nil, err
+	}
+
+	return teacher, nil
+}
+
+// HandleCreateQuestion handles adding a question to a course's bank
+func (h *QuestionBankHandler) HandleCreateQuestion(c echo.Context) error {
+	var req models.CreateBankQuestionRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid request body")
+	}
+
+	if err := utils.ValidateStruct(h.validator, req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, utils.FormatValidationErrors(err))
+	}
+
+	courseID := c.Param("courseId")
+	teacher, err := h.authorizeCourse(c, courseID)
+	if err != nil {
+		return err
+	}
+
+	question, err := h.bankService.CreateQuestion(c.Request().Context(), courseID, teacher.ID, req)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Failed to create question: "+err.Error())
+	}
+
+	return c.JSON(http.StatusCreated, question)
+}
+
+// HandleGetQuestions handles listing a course's bank questions, optionally filtered by tag and difficulty
+func (h *QuestionBankHandler) HandleGetQuestions(c echo.Context) error {
+	courseID := c.Param("courseId")
+	if _, err := h.authorizeCourse(c, courseID); err != nil {
+		return err
+	}
+
+	difficulty := c.QueryParam("difficulty")
+	if difficulty != "" && difficulty != string(models.DifficultyEasy) &&
+		difficulty != string(models.DifficultyMedium) && difficulty != string(models.DifficultyHard) {
+		return echo.NewHTTPError(http.StatusBadRequest, "Difficulty must be one of easy, medium or hard")
+	}
+
+	questions, err := h.bankService.GetQuestions(c.Request().Context(), courseID, c.QueryParams()["tag"], difficulty)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to retrieve questions: "+err.Error())
+	}
+
+	return c.JSON(http.StatusOK, questions)
+}
+
+// HandleGetQuestion handles retrieving a single bank question
+func (h *QuestionBankHandler) HandleGetQuestion(c echo.Context) error {
+	courseID := c.Param("courseId")
+	if _, err := h.authorizeCourse(c, courseID); err != nil {
+		return err
+	}
+
+	question, err := h.bankService.GetQuestion(c.Request().Context(), courseID, c.Param("questionId"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	}
+
+	return c.JSON(http.StatusOK, question)
+}
+
+// HandleUpdateQuestion handles updating a bank question
+func (h *QuestionBankHandler) HandleUpdateQuestion(c echo.Context) error {
+	var req models.UpdateBankQuestionRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid request body")
+	}
+
+	if err := utils.ValidateStruct(h.validator, req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, utils.FormatValidationErrors(err))
+	}
+
+	courseID := c.Param("courseId")
+	if _, err := h.authorizeCourse(c, courseID); err != nil {
+		return err
+	}
+
+	question, err := h.bankService.UpdateQuestion(c.Request().Context(), courseID, c.Param("questionId"), req)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Failed to update question: "+err.Error())
+	}
+
+	return c.JSON(http.StatusOK, question)
+}
+
+// HandleDeleteQuestion handles removing a question from a course's bank
+func (h *QuestionBankHandler) HandleDeleteQuestion(c echo.Context) error {
+	courseID := c.Param("courseId")
+	if _, err := h.authorizeCourse(c, courseID); err != nil {
+		return err
+	}
+
+	if err := h.bankService.DeleteQuestion(c.Request().Context(), courseID, c.Param("questionId")); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Failed to delete question: "+err.Error())
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
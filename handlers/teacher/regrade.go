@@ -0,0 +1,156 @@
+package teacher
+
+import (
+	"net/http"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+
+	"assessment-management-system/handlers"
+	"assessment-management-system/middleware"
+	"assessment-management-system/models"
+	"assessment-management-system/services"
+	"assessment-management-system/utils"
+)
+
+// RegradeHandler handles regrade request routes for teachers
+type RegradeHandler struct {
+	regradeService    *services.RegradeService
+	assessmentService *services.AssessmentService
+	courseService     *services.CourseService
+	validator         *validator.Validate
+}
+
+// NewRegradeHandler creates a new RegradeHandler
+func NewRegradeHandler(
+	regradeService *services.RegradeService,
+	assessmentService *services.AssessmentService,
+	courseService *services.CourseService,
+) *RegradeHandler {
+	return &RegradeHandler{
+		regradeService:    regradeService,
+		assessmentService: assessmentService,
+		courseService:     courseService,
+		validator:         utils.NewValidator(),
+	}
+}
+
+// authorizeRequest loads the regrade request and checks that the teacher created its assessment or is assigned to
+// the assessment's course
+func (h *RegradeHandler) authorizeRequest(c echo.Context) (*models.RegradeRequest, *models.User, error) {
+	id := c.Param("id")
+	if id == "" {
+		return nil, nil, echo.NewHTTPError(http.StatusBadRequest, "Regrade request ID is required")
+	}
+
+	// Get the teacher's ID from the token
+	teacher, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	request, err := h.regradeService.GetRequest(c.Request().Context(), id)
+	if err != nil {
+		return nil, nil, echo.NewHTTPError(http.StatusInternalServerError, "Failed to retrieve regrade request: "+err.Error())
+	}
+
+	if request == nil {
+		return nil, nil, echo.NewHTTPError(http.StatusNotFound, "Regrade request not found")
+	}
+
+	assessment, err := h.assessmentService.GetAssessmentByID(c.Request().Context(), request.AssessmentID)
+	if err != nil {
+		return nil, nil, echo.NewHTTPError(http.StatusInternalServerError, "Failed to retrieve assessment: "+err.Error())
+	}
+
+	if assessment.TeacherID != teacher.ID {
+		isAssigned, err := h.courseService.IsTeacherAssignedToCourse(c.Request().Context(), assessment.CourseID, teacher.ID)
+		if err != nil {
+			return nil, nil, echo.NewHTTPError(http.StatusInternalServerError, "Failed to check course assignment: "+err.Error())
+		}
+
+		if !isAssigned {
+			return nil, nil, echo.NewHTTPError(http.StatusForbidden, "You do not have permission to resolve this regrade request")
+		}
+	}
+
+	canSee, err := h.assessmentService.CanTeacherSeeSubmission(c.Request().Context(), assessment.CourseID, request.SubmissionID, teacher.ID)
+	if err != nil {
+		return nil, nil, echo.NewHTTPError(http.StatusInternalServerError, "Failed to check section: "+err.Error())
+	}
+
+	if !canSee {
+		return nil, nil, echo.NewHTTPError(http.StatusForbidden, "This regrade request is from a section you do not teach")
+	}
+
+	if err := handlers.CheckCourseWritable(c, h.courseService, assessment.CourseID); err != nil {
+		return nil, nil, err
+	}
+
+	return request, teacher, nil
+}
+
+// HandleGetQueue handles listing the regrade requests of the assessments the teacher can grade
+func (h *RegradeHandler) HandleGetQueue(c echo.Context) error {
+	// Get the teacher's ID from the token
+	teacher, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		return err
+	}
+
+	status := models.RegradeStatus(c.QueryParam("status"))
+	requests, err := h.regradeService.GetTeacherQueue(c.Request().Context(), teacher.ID, status)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Failed to retrieve regrade requests: "+err.Error())
+	}
+
+	return c.JSON(http.StatusOK, requests)
+}
+
+// HandleAcceptRequest handles accepting a regrade request with a new score
+func (h *RegradeHandler) HandleAcceptRequest(c echo.Context) error {
+	var req models.AcceptRegradeRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid request body")
+	}
+
+	if err := utils.ValidateStruct(h.validator, req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, utils.FormatValidationErrors(err))
+	}
+
+	request, teacher, err := h.authorizeRequest(c)
+	if err != nil {
+		return err
+	}
+
+	request, err = h.regradeService.AcceptRequest(c.Request().Context(), request, teacher.ID, req)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Failed to accept regrade request: "+err.Error())
+	}
+
+	return c.JSON(http.StatusOK, request)
+}
+
+// HandleRejectRequest handles rejecting a regrade request with a comment
+func (h *RegradeHandler) HandleRejectRequest(c echo.Context) error {
+	var req models.RejectRegradeRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid request body")
+	}
+
+	if err := utils.ValidateStruct(h.validator, req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, utils.FormatValidationErrors(err))
+	}
+
+	request, teacher, err := h.authorizeRequest(c)
+	if err != nil {
+		return err
+	}
+
+	request, err = h.regradeService.RejectRequest(c.Request().Context(), request, teacher.ID, req)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Failed to reject regrade request: "+err.Error())
+	}
+
+	return c.JSON(http.StatusOK, request)
+}
@@ -0,0 +1,25 @@
+package teacher
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+// HandleReleaseGrades handles releasing every grade of an assessment that students cannot see yet
+func (h *AssessmentHandler) HandleReleaseGrades(c echo.Context) error {
+	assessment, err := h.authorizeAssessment(c, c.Param("id"))
+	if err != nil {
+		return err
+	}
+
+	released, err := h.assessmentService.ReleaseGrades(c.Request().Context(), assessment.ID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Failed to release grades: "+err.Error())
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"message":  "Grades released successfully",
+		"released": released,
+	})
+}
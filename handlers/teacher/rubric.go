@@ -0,0 +1,229 @@
+package teacher
+
+import (
+	"net/http"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+
+	"assessment-management-system/handlers"
+	"assessment-management-system/middleware"
+	"assessment-management-system/models"
+	"assessment-management-system/services"
+	"assessment-management-system/utils"
+)
+
+// RubricHandler handles grading rubric routes for teachers
+type RubricHandler struct {
+	rubricService     *services.RubricService
+	assessmentService *services.AssessmentService
+	courseService     *services.CourseService
+	validator         *validator.Validate
+}
+
+// NewRubricHandler creates a new RubricHandler
+func NewRubricHandler(
+	rubricService *services.RubricService,
+	assessmentService *services.AssessmentService,
+	courseService *services.CourseService,
+) *RubricHandler {
+	return &RubricHandler{
+		rubricService:     rubricService,
+		assessmentService: assessmentService,
+		courseService:     courseService,
+		validator:         utils.NewValidator(),
+	}
+}
+
+// authorizeCourse checks that the teacher is assigned to the course and returns the teacher
+func (h *RubricHandler) authorizeCourse(c echo.Context, courseID string) (*models.User, error) {
+	if courseID == "" {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, "Course ID is required")
+	}
+
+	// Get the teacher's ID from the token
+	teacher, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		return nil, err
+	}
+
+	isAssigned, err := h.courseService.IsTeacherAssignedToCourse(c.Request().Context(), courseID, teacher.ID)
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, "Failed to check course assignment: "+err.Error())
+	}
+
+	if !isAssigned {
+		return nil, echo.NewHTTPError(http.StatusForbidden, "You are not assigned to this course")
+	}
+
+	if err := handlers.CheckCourseWritable(c, h.courseService, courseID); err != nil {
+		return nil, err
+	}
+
+	return teacher, nil
+}
+
+// HandleCreateRubric handles creating a rubric for a course
+func (h *RubricHandler) HandleCreateRubric(c echo.Context) error {
+	var req models.CreateRubricRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid request body")
+	}
+
+	if err := utils.ValidateStruct(h.validator, req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, utils.FormatValidationErrors(err))
+	}
+
+	courseID := c.Param("courseId")
+	teacher, err := h.authorizeCourse(c, courseID)
+	if err != nil {
+		return err
+	}
+
+	rubric, err := h.rubricService.CreateRubric(c.Request().Context(), courseID, teacher.ID, req)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Failed to create rubric: "+err.Error())
+	}
+
+	return c.JSON(http.StatusCreated, rubric)
+}
+
+// HandleGetRubrics handles listing the rubrics of a course
+func (h *RubricHandler) HandleGetRubrics(c echo.Context) error {
+	courseID := c.Param("courseId")
+	if _, err := h.authorizeCourse(c, courseID); err != nil {
+		return err
+	}
+
+	rubrics, err := h.rubricService.GetRubrics(c.Request().Context(), courseID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to retrieve rubrics: "+err.Error())
+	}
+
+	return c.JSON(http.StatusOK, rubrics)
+}
+
+// HandleGetRubric handles retrieving a single rubric
+func (h *RubricHandler) HandleGetRubric(c echo.Context) error {
+	courseID := c.Param("courseId")
+	if _, err := h.authorizeCourse(c, courseID); err != nil {
+		return err
+	}
+
+	rubric, err := h.rubricService.GetRubric(c.Request().Context(), courseID, c.Param("rubricId"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	}
+
+	return c.JSON(http.StatusOK, rubric)
+}
+
+// HandleUpdateRubric handles updating a rubric
+func (h *RubricHandler) HandleUpdateRubric(c echo.Context) error {
+	var req models.UpdateRubricRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid request body")
+	}
+
+	if err := utils.ValidateStruct(h.validator, req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, utils.FormatValidationErrors(err))
+	}
+
+	courseID := c.Param("courseId")
+	if _, err := h.authorizeCourse(c, courseID); err != nil {
+		return err
+	}
+
+	rubric, err := h.rubricService.UpdateRubric(c.Request().Context(), courseID, c.Param("rubricId"), req)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Failed to update rubric: "+err.Error())
+	}
+
+	return c.JSON(http.StatusOK, rubric)
+}
+
+// HandleDeleteRubric handles deleting a rubric
+func (h *RubricHandler) HandleDeleteRubric(c echo.Context) error {
+	courseID := c.Param("courseId")
+	if _, err := h.authorizeCourse(c, courseID); err != nil {
+		return err
+	}
+
+	if err := h.rubricService.DeleteRubric(c.Request().Context(), courseID, c.Param("rubricId")); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Failed to delete rubric: "+err.Error())
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// getOwnedAssessment loads an assessment that the teacher created
+func (h *RubricHandler) getOwnedAssessment(c echo.Context) (*models.Assessment, error) {
+	id := c.Param("id")
+	if id == "" {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, "Assessment ID is required")
+	}
+
+	// Get the teacher's ID from the token
+	teacher, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		return nil, err
+	}
+
+	assessment, err := h.assessmentService.GetAssessmentByID(c.Request().Context(), id)
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, "Failed to retrieve assessment: "+err.Error())
+	}
+
+	if assessment == nil {
+		return nil, echo.NewHTTPError(http.StatusNotFound, "Assessment not found")
+	}
+
+	if assessment.TeacherID != teacher.ID {
+		return nil, echo.NewHTTPError(http.StatusForbidden, "You do not have permission to change the rubric of this assessment")
+	}
+
+	if err := handlers.CheckCourseWritable(c, h.courseService, assessment.CourseID); err != nil {
+		return nil, err
+	}
+
+	return assessment, nil
+}
+
+// HandleAttachRubric handles attaching a rubric to an assessment
+func (h *RubricHandler) HandleAttachRubric(c echo.Context) error {
+	var req models.AttachRubricRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid request body")
+	}
+
+	if err := utils.ValidateStruct(h.validator, req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, utils.FormatValidationErrors(err))
+	}
+
+	assessment, err := h.getOwnedAssessment(c)
+	if err != nil {
+		return err
+	}
+
+	updated, err := h.rubricService.AttachRubric(c.Request().Context(), assessment, req.RubricID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Failed to attach rubric: "+err.Error())
+	}
+
+	return c.JSON(http.StatusOK, updated)
+}
+
+// HandleDetachRubric handles removing the rubric from an assessment
+func (h *RubricHandler) HandleDetachRubric(c echo.Context) error {
+	assessment, err := h.getOwnedAssessment(c)
+	if err != nil {
+		return err
+	}
+
+	updated, err := h.rubricService.DetachRubric(c.Request().Context(), assessment)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Failed to detach rubric: "+err.Error())
+	}
+
+	return c.JSON(http.StatusOK, updated)
+}
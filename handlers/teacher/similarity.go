@@ -0,0 +1,129 @@
+package teacher
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"assessment-management-system/middleware"
+	"assessment-management-system/models"
+	"assessment-management-system/utils"
+)
+
+// HandleStartSimilarityCheck handles starting a background similarity check of an assessment's submissions
+func (h *AssessmentHandler) HandleStartSimilarityCheck(c echo.Context) error {
+	var req models.StartSimilarityCheckRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid request body")
+	}
+
+	if err := utils.ValidateStruct(h.validator, req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, utils.FormatValidationErrors(err))
+	}
+
+	assessment, err := h.authorizeAssessment(c, c.Param("id"))
+	if err != nil {
+		return err
+	}
+
+	// Get the teacher's ID from the token
+	teacher, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		return err
+	}
+
+	check, err := h.assessmentService.StartSimilarityCheck(c.Request().Context(), assessment, teacher.ID, req)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Failed to start similarity check: "+err.Error())
+	}
+
+	return c.JSON(http.StatusAccepted, check)
+}
+
+// HandleGetSimilarityChecks handles retrieving the similarity checks of an assessment
+func (h *AssessmentHandler) HandleGetSimilarityChecks(c echo.Context) error {
+	assessment, err := h.authorizeAssessment(c, c.Param("id"))
+	if err != nil {
+		return err
+	}
+
+	checks, err := h.assessmentService.GetSimilarityChecks(c.Request().Context(), assessment.ID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to retrieve similarity checks: "+err.Error())
+	}
+
+	return c.JSON(http.StatusOK, checks)
+}
+
+// HandleGetSimilarityCheck handles retrieving a similarity check with its matching pairs of submissions
+func (h *AssessmentHandler) HandleGetSimilarityCheck(c echo.Context) error {
+	assessment, err := h.authorizeAssessment(c, c.Param("id"))
+	if err != nil {
+		return err
+	}
+
+	check, err := h.assessmentService.GetSimilarityCheck(c.Request().Context(), assessment, c.Param("checkId"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to retrieve similarity check: "+err.Error())
+	}
+
+	if check == nil {
+		return echo.NewHTTPError(http.StatusNotFound, "Similarity check not found")
+	}
+
+	// Get the teacher's ID from the token
+	teacher, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		return err
+	}
+
+	// Teachers of particular sections only see the matches between their own students' submissions
+	matches := make([]*models.SimilarityMatch, 0, len(check.Matches))
+	for _, match := range check.Matches {
+		canSee, err := h.assessmentService.CanTeacherSeeMatch(c.Request().Context(), assessment.CourseID, teacher.ID, match)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to check sections: "+err.Error())
+		}
+
+		if canSee {
+			matches = append(matches, match)
+		}
+	}
+	check.Matches = matches
+
+	return c.JSON(http.StatusOK, check)
+}
+
+// HandleGetSimilarityReport handles retrieving a matching pair of submissions with both texts and the regions they share
+func (h *AssessmentHandler) HandleGetSimilarityReport(c echo.Context) error {
+	assessment, err := h.authorizeAssessment(c, c.Param("id"))
+	if err != nil {
+		return err
+	}
+
+	report, err := h.assessmentService.GetSimilarityReport(c.Request().Context(), assessment, c.Param("checkId"), c.Param("matchId"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to retrieve similarity report: "+err.Error())
+	}
+
+	if report == nil {
+		return echo.NewHTTPError(http.StatusNotFound, "Similarity match not found")
+	}
+
+	// Get the teacher's ID from the token
+	teacher, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		return err
+	}
+
+	canSee, err := h.assessmentService.CanTeacherSeeMatch(c.Request().Context(), assessment.CourseID, teacher.ID, report)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to check sections: "+err.Error())
+	}
+
+	if !canSee {
+		return echo.NewHTTPError(http.StatusForbidden, "This match involves a section you do not teach")
+	}
+
+	return c.JSON(http.StatusOK, report)
+}
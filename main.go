@@ -18,6 +18,7 @@ import (
 	"assessment-management-system/repositories"
 	"assessment-management-system/routes"
 	"assessment-management-system/services"
+	"assessment-management-system/storage"
 )
 
 func init() {
@@ -59,6 +60,20 @@ func main() {
 		log.Printf("Warning: Failed to seed initial data: %v", err)
 	}
 
+	// Similarity checks that were running when the server stopped will never finish
+	similarityRepo := repositories.NewSimilarityRepository(dbConn)
+	if failed, err := similarityRepo.FailRunning(context.Background(), "the server restarted while the check was running, start a new check"); err != nil {
+		log.Printf("Warning: Failed to fail interrupted similarity checks: %v", err)
+	} else if failed > 0 {
+		log.Printf("Marked %d interrupted similarity checks as failed", failed)
+	}
+
+	// Initialize blob storage for submission attachments
+	blobStorage, err := storage.New(appConfig.Storage)
+	if err != nil {
+		log.Fatalf("Failed to initialize storage: %v", err)
+	}
+
 	// Initialize Echo instance
 	e := echo.New()
 
@@ -69,7 +84,7 @@ func main() {
 	e.Use(customMiddleware.LoggingMiddleware())
 
 	// Initialize routes
-	routes.SetupRoutes(e, dbConn, appConfig)
+	routes.SetupRoutes(e, dbConn, appConfig, blobStorage)
 
 	// // Print all registered routes for debugging
 	// for _, route := range e.Routes() {
@@ -0,0 +1,33 @@
+-- Academic terms of an organization, each course offering running in one of them
+CREATE TABLE IF NOT EXISTS academic_terms (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    organization_id UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL,
+    start_date TIMESTAMP WITH TIME ZONE NOT NULL,
+    end_date TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT unique_term_name_per_org UNIQUE (organization_id, name),
+    CONSTRAINT term_dates CHECK (end_date > start_date)
+);
+
+CREATE INDEX IF NOT EXISTS idx_academic_terms_organization ON academic_terms(organization_id);
+
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_trigger WHERE tgname = 'update_academic_terms_timestamp') THEN
+CREATE TRIGGER update_academic_terms_timestamp
+    BEFORE UPDATE ON academic_terms
+    FOR EACH ROW EXECUTE FUNCTION update_timestamp();
+END IF;
+END $$;
+
+-- Courses created before terms existed have none
+ALTER TABLE courses ADD COLUMN IF NOT EXISTS term_id UUID REFERENCES academic_terms(id) ON DELETE RESTRICT;
+
+CREATE INDEX IF NOT EXISTS idx_courses_term ON courses(term_id);
+
+-- The same course is offered again every term, so names are unique per term
+ALTER TABLE courses DROP CONSTRAINT IF EXISTS unique_course_name_per_org;
+CREATE UNIQUE INDEX IF NOT EXISTS unique_course_name_per_term
+    ON courses (organization_id, name, COALESCE(term_id, '00000000-0000-0000-0000-000000000000'::uuid));
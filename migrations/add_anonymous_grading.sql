@@ -0,0 +1,15 @@
+-- Blind marking: teachers see pseudonyms instead of students until grades are released
+ALTER TABLE assessments ADD COLUMN IF NOT EXISTS anonymous_grading BOOLEAN NOT NULL DEFAULT false;
+-- Secret per assessment from which the pseudonyms are derived, so they cannot be computed from student IDs
+ALTER TABLE assessments ADD COLUMN IF NOT EXISTS anonymous_key UUID NOT NULL DEFAULT gen_random_uuid();
+
+-- Every time an admin reveals the students behind the pseudonyms of an assessment
+CREATE TABLE IF NOT EXISTS anonymous_unmask_log (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    assessment_id UUID NOT NULL REFERENCES assessments(id) ON DELETE CASCADE,
+    unmasked_by UUID NOT NULL REFERENCES users(id),
+    reason TEXT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_anonymous_unmask_log_assessment ON anonymous_unmask_log(assessment_id);
@@ -0,0 +1,26 @@
+-- Per-student overrides of an assessment's deadlines and limits
+CREATE TABLE IF NOT EXISTS assessment_extensions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    assessment_id UUID NOT NULL REFERENCES assessments(id) ON DELETE CASCADE,
+    student_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    due_date TIMESTAMP WITH TIME ZONE,
+    cutoff_date TIMESTAMP WITH TIME ZONE,
+    time_limit_minutes INT CHECK (time_limit_minutes > 0),
+    max_attempts INT CHECK (max_attempts > 0),
+    reason TEXT,
+    granted_by UUID NOT NULL REFERENCES users(id),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT unique_student_extension UNIQUE (assessment_id, student_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_assessment_extensions_student ON assessment_extensions(student_id);
+
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_trigger WHERE tgname = 'update_assessment_extensions_timestamp') THEN
+CREATE TRIGGER update_assessment_extensions_timestamp
+    BEFORE UPDATE ON assessment_extensions
+    FOR EACH ROW EXECUTE FUNCTION update_timestamp();
+END IF;
+END $$;
@@ -0,0 +1,5 @@
+-- The same assessment in an earlier term's offering of the course. Similarity checks compare submissions with
+-- the submissions to its earlier offerings.
+ALTER TABLE assessments ADD COLUMN IF NOT EXISTS previous_offering_id UUID REFERENCES assessments(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_assessments_previous_offering ON assessments(previous_offering_id);
@@ -0,0 +1,47 @@
+-- Question types enum
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_type WHERE typname = 'question_type') THEN
+CREATE TYPE question_type AS ENUM ('multiple_choice', 'multi_select', 'true_false', 'numeric', 'short_text');
+END IF;
+END $$;
+
+-- Ordered, auto-gradable questions inside an assessment
+CREATE TABLE IF NOT EXISTS assessment_questions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    assessment_id UUID NOT NULL REFERENCES assessments(id) ON DELETE CASCADE,
+    position INT NOT NULL,
+    type question_type NOT NULL,
+    prompt TEXT NOT NULL,
+    options TEXT[] NOT NULL DEFAULT '{}',
+    points NUMERIC(6, 2) NOT NULL CHECK (points > 0),
+    correct_answer JSONB NOT NULL,
+    tolerance NUMERIC(12, 4) NOT NULL DEFAULT 0,
+    case_sensitive BOOLEAN NOT NULL DEFAULT false,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_assessment_questions_assessment ON assessment_questions(assessment_id, position);
+
+-- Per-question answers recorded with a submission
+CREATE TABLE IF NOT EXISTS submission_answers (
+    submission_id UUID NOT NULL REFERENCES assessment_submissions(id) ON DELETE CASCADE,
+    question_id UUID NOT NULL REFERENCES assessment_questions(id) ON DELETE CASCADE,
+    answer JSONB,
+    is_correct BOOLEAN NOT NULL DEFAULT false,
+    points_awarded NUMERIC(6, 2) NOT NULL DEFAULT 0,
+    PRIMARY KEY (submission_id, question_id)
+);
+
+-- Mark grades that were produced by the auto-grader
+ALTER TABLE grades ADD COLUMN IF NOT EXISTS auto_graded BOOLEAN NOT NULL DEFAULT false;
+
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_trigger WHERE tgname = 'update_assessment_questions_timestamp') THEN
+CREATE TRIGGER update_assessment_questions_timestamp
+    BEFORE UPDATE ON assessment_questions
+    FOR EACH ROW EXECUTE FUNCTION update_timestamp();
+END IF;
+END $$;
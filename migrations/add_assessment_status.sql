@@ -0,0 +1,13 @@
+-- Publication lifecycle of an assessment
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_type WHERE typname = 'assessment_status') THEN
+CREATE TYPE assessment_status AS ENUM ('draft', 'scheduled', 'published', 'closed', 'archived');
+END IF;
+END $$;
+
+-- Existing assessments were already visible to students, so they start out published
+ALTER TABLE assessments ADD COLUMN IF NOT EXISTS status assessment_status NOT NULL DEFAULT 'published';
+ALTER TABLE assessments ADD COLUMN IF NOT EXISTS available_from TIMESTAMP WITH TIME ZONE;
+
+CREATE INDEX IF NOT EXISTS idx_assessments_status ON assessments(status);
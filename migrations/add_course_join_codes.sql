@@ -0,0 +1,23 @@
+-- Join codes and invite links that let students enroll in a course even when its enrollment is closed
+CREATE TABLE IF NOT EXISTS course_join_codes (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    course_id UUID NOT NULL REFERENCES courses(id) ON DELETE CASCADE,
+    section_id UUID REFERENCES course_sections(id) ON DELETE CASCADE,
+    code VARCHAR(16) NOT NULL,
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    max_uses INTEGER NOT NULL CHECK (max_uses > 0),
+    uses INTEGER NOT NULL DEFAULT 0,
+    revoked_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT unique_join_code UNIQUE (code),
+    CONSTRAINT join_code_uses_within_limit CHECK (uses <= max_uses)
+);
+
+CREATE INDEX IF NOT EXISTS idx_course_join_codes_course ON course_join_codes(course_id);
+
+-- The join code a student enrolled with, or waits for a seat with; NULL for ordinary enrollments
+ALTER TABLE course_enrollments ADD COLUMN IF NOT EXISTS join_code_id UUID REFERENCES course_join_codes(id) ON DELETE SET NULL;
+ALTER TABLE course_waitlist ADD COLUMN IF NOT EXISTS join_code_id UUID REFERENCES course_join_codes(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_course_enrollments_join_code ON course_enrollments(join_code_id);
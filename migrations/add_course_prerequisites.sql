@@ -0,0 +1,21 @@
+-- Whether a student must meet all of a course's prerequisites, or any one of them
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_type WHERE typname = 'prerequisite_mode') THEN
+CREATE TYPE prerequisite_mode AS ENUM ('all', 'any');
+END IF;
+END $$;
+
+ALTER TABLE courses ADD COLUMN IF NOT EXISTS prerequisite_mode prerequisite_mode NOT NULL DEFAULT 'all';
+
+-- A course that must be passed, once its term has ended, with a minimum final grade before enrolling
+CREATE TABLE IF NOT EXISTS course_prerequisites (
+    course_id UUID NOT NULL REFERENCES courses(id) ON DELETE CASCADE,
+    required_course_id UUID NOT NULL REFERENCES courses(id) ON DELETE CASCADE,
+    min_grade NUMERIC(5, 2) NOT NULL DEFAULT 0 CHECK (min_grade >= 0 AND min_grade <= 100),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (course_id, required_course_id),
+    CONSTRAINT prerequisite_not_self CHECK (course_id <> required_course_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_course_prerequisites_required ON course_prerequisites(required_course_id);
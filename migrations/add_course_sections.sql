@@ -0,0 +1,44 @@
+-- Sections of a course, each taught by its own teachers
+CREATE TABLE IF NOT EXISTS course_sections (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    course_id UUID NOT NULL REFERENCES courses(id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT unique_course_section_name UNIQUE (course_id, name)
+);
+
+CREATE INDEX IF NOT EXISTS idx_course_sections_course ON course_sections(course_id);
+
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_trigger WHERE tgname = 'update_course_sections_timestamp') THEN
+CREATE TRIGGER update_course_sections_timestamp
+    BEFORE UPDATE ON course_sections
+    FOR EACH ROW EXECUTE FUNCTION update_timestamp();
+END IF;
+END $$;
+
+-- Teachers assigned to a section only see and grade the work of its students
+CREATE TABLE IF NOT EXISTS section_teachers (
+    section_id UUID NOT NULL REFERENCES course_sections(id) ON DELETE CASCADE,
+    teacher_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (section_id, teacher_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_section_teachers_teacher ON section_teachers(teacher_id);
+
+-- Students enrolled before the course had sections have none
+ALTER TABLE course_enrollments ADD COLUMN IF NOT EXISTS section_id UUID REFERENCES course_sections(id) ON DELETE RESTRICT;
+
+CREATE INDEX IF NOT EXISTS idx_course_enrollments_section ON course_enrollments(section_id);
+
+-- Assessments without sections are for the whole course
+CREATE TABLE IF NOT EXISTS assessment_sections (
+    assessment_id UUID NOT NULL REFERENCES assessments(id) ON DELETE CASCADE,
+    section_id UUID NOT NULL REFERENCES course_sections(id) ON DELETE RESTRICT,
+    PRIMARY KEY (assessment_id, section_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_assessment_sections_section ON assessment_sections(section_id);
@@ -0,0 +1,14 @@
+-- Courses without a limit take any number of students
+ALTER TABLE courses ADD COLUMN IF NOT EXISTS max_students INTEGER CHECK (max_students > 0);
+
+-- Students waiting for a seat in a full course, first come first served
+CREATE TABLE IF NOT EXISTS course_waitlist (
+    course_id UUID NOT NULL REFERENCES courses(id) ON DELETE CASCADE,
+    student_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    section_id UUID REFERENCES course_sections(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (course_id, student_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_course_waitlist_order ON course_waitlist(course_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_course_waitlist_student ON course_waitlist(student_id);
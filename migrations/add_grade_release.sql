@@ -0,0 +1,13 @@
+-- When the grades of an assessment become visible to students
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_type WHERE typname = 'grade_release_mode') THEN
+CREATE TYPE grade_release_mode AS ENUM ('immediate', 'manual', 'scheduled');
+END IF;
+END $$;
+
+ALTER TABLE assessments ADD COLUMN IF NOT EXISTS grade_release_mode grade_release_mode NOT NULL DEFAULT 'immediate';
+ALTER TABLE assessments ADD COLUMN IF NOT EXISTS grades_release_at TIMESTAMP WITH TIME ZONE;
+
+-- Set when a teacher releases grades that were held back
+ALTER TABLE grades ADD COLUMN IF NOT EXISTS released_at TIMESTAMP WITH TIME ZONE;
@@ -0,0 +1,42 @@
+-- Immutable history of every grade change. The history is kept even when the grade itself is deleted,
+-- so it has no foreign keys.
+CREATE TABLE IF NOT EXISTS grade_versions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    submission_id UUID NOT NULL,
+    version INTEGER NOT NULL,
+    score NUMERIC(5, 2) NOT NULL,
+    raw_score NUMERIC(5, 2) NOT NULL,
+    late_penalty NUMERIC(5, 2) NOT NULL,
+    feedback TEXT,
+    -- The values before the change, NULL for the first version of a grade
+    previous_score NUMERIC(5, 2),
+    previous_raw_score NUMERIC(5, 2),
+    previous_late_penalty NUMERIC(5, 2),
+    previous_feedback TEXT,
+    changed_by UUID NOT NULL,
+    reason TEXT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT unique_grade_version UNIQUE (submission_id, version)
+);
+
+CREATE OR REPLACE FUNCTION prevent_grade_version_change()
+RETURNS TRIGGER AS $$
+BEGIN
+    RAISE EXCEPTION 'grade versions cannot be changed or deleted';
+END;
+$$ LANGUAGE plpgsql;
+
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_trigger WHERE tgname = 'grade_versions_immutable') THEN
+CREATE TRIGGER grade_versions_immutable
+    BEFORE UPDATE OR DELETE ON grade_versions
+    FOR EACH ROW EXECUTE FUNCTION prevent_grade_version_change();
+END IF;
+END $$;
+
+-- Grades given before the history existed start it as their first version
+INSERT INTO grade_versions (submission_id, version, score, raw_score, late_penalty, feedback, changed_by, reason, created_at)
+SELECT g.submission_id, 1, g.score, g.raw_score, g.late_penalty, g.feedback, g.graded_by, 'Recorded before grade history', g.graded_at
+FROM grades g
+WHERE NOT EXISTS (SELECT 1 FROM grade_versions v WHERE v.submission_id = g.submission_id);
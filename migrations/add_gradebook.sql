@@ -0,0 +1,26 @@
+-- Weighted grade categories of a course gradebook
+CREATE TABLE IF NOT EXISTS grade_categories (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    course_id UUID NOT NULL REFERENCES courses(id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL,
+    weight NUMERIC(5, 2) NOT NULL CHECK (weight >= 0 AND weight <= 100),
+    drop_lowest INT NOT NULL DEFAULT 0 CHECK (drop_lowest >= 0),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT unique_course_category UNIQUE (course_id, name)
+);
+
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_trigger WHERE tgname = 'update_grade_categories_timestamp') THEN
+CREATE TRIGGER update_grade_categories_timestamp
+    BEFORE UPDATE ON grade_categories
+    FOR EACH ROW EXECUTE FUNCTION update_timestamp();
+END IF;
+END $$;
+
+-- The category an assessment counts in, and whether its points only add to the grade
+ALTER TABLE assessments ADD COLUMN IF NOT EXISTS category_id UUID REFERENCES grade_categories(id) ON DELETE SET NULL;
+ALTER TABLE assessments ADD COLUMN IF NOT EXISTS extra_credit BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE INDEX IF NOT EXISTS idx_assessments_category ON assessments(category_id);
@@ -0,0 +1,24 @@
+-- Letter-grade schemes, defined for an organization and optionally overridden by a course
+CREATE TABLE IF NOT EXISTS grading_schemes (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    organization_id UUID REFERENCES organizations(id) ON DELETE CASCADE,
+    course_id UUID REFERENCES courses(id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL,
+    -- Bands ordered from the highest minimum percentage down: [{"min_percentage", "label", "gpa_points"}]
+    bands JSONB NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT grading_scheme_owner CHECK ((organization_id IS NULL) <> (course_id IS NULL))
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_grading_schemes_organization ON grading_schemes(organization_id) WHERE organization_id IS NOT NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_grading_schemes_course ON grading_schemes(course_id) WHERE course_id IS NOT NULL;
+
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_trigger WHERE tgname = 'update_grading_schemes_timestamp') THEN
+CREATE TRIGGER update_grading_schemes_timestamp
+    BEFORE UPDATE ON grading_schemes
+    FOR EACH ROW EXECUTE FUNCTION update_timestamp();
+END IF;
+END $$;
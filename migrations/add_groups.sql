@@ -0,0 +1,64 @@
+-- Student groups within a course, with group submissions shared by every member
+CREATE TABLE IF NOT EXISTS course_groups (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    course_id UUID NOT NULL REFERENCES courses(id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL,
+    -- NULL places no limit on the number of members
+    max_size INTEGER CHECK (max_size > 0),
+    -- Students may join and leave self-signup groups on their own
+    self_signup BOOLEAN NOT NULL DEFAULT false,
+    created_by UUID NOT NULL REFERENCES users(id),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT unique_course_group_name UNIQUE (course_id, name)
+);
+
+-- A student belongs to at most one group per course, and leaves it when unenrolled
+CREATE TABLE IF NOT EXISTS course_group_members (
+    group_id UUID NOT NULL REFERENCES course_groups(id) ON DELETE CASCADE,
+    course_id UUID NOT NULL,
+    student_id UUID NOT NULL,
+    joined_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (group_id, student_id),
+    CONSTRAINT unique_course_group_member UNIQUE (course_id, student_id),
+    FOREIGN KEY (course_id, student_id) REFERENCES course_enrollments(course_id, student_id) ON DELETE CASCADE
+);
+
+ALTER TABLE assessments ADD COLUMN IF NOT EXISTS group_submission BOOLEAN NOT NULL DEFAULT false;
+
+ALTER TABLE assessment_submissions ADD COLUMN IF NOT EXISTS group_id UUID REFERENCES course_groups(id) ON DELETE SET NULL;
+
+-- Attempts of a group are numbered per group, whichever member submits them
+CREATE UNIQUE INDEX IF NOT EXISTS unique_group_attempt ON assessment_submissions(assessment_id, group_id, attempt_number)
+    WHERE group_id IS NOT NULL;
+
+-- The members of a group when it submitted, who all share the submission's grade unless it is adjusted for them
+CREATE TABLE IF NOT EXISTS submission_group_members (
+    submission_id UUID NOT NULL REFERENCES assessment_submissions(id) ON DELETE CASCADE,
+    student_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    adjusted_score NUMERIC(5, 2),
+    adjustment_reason TEXT,
+    adjusted_by UUID REFERENCES users(id),
+    adjusted_at TIMESTAMP WITH TIME ZONE,
+    PRIMARY KEY (submission_id, student_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_submission_group_members_student ON submission_group_members(student_id);
+
+-- Every student a submission counts for: its author, or the members of the group that submitted it
+CREATE OR REPLACE VIEW submission_students AS
+    SELECT s.id AS submission_id, s.student_id, NULL::NUMERIC(5, 2) AS adjusted_score
+    FROM assessment_submissions s
+    WHERE NOT EXISTS (SELECT 1 FROM submission_group_members m WHERE m.submission_id = s.id)
+    UNION ALL
+    SELECT m.submission_id, m.student_id, m.adjusted_score
+    FROM submission_group_members m;
+
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_trigger WHERE tgname = 'update_course_groups_timestamp') THEN
+CREATE TRIGGER update_course_groups_timestamp
+    BEFORE UPDATE ON course_groups
+    FOR EACH ROW EXECUTE FUNCTION update_timestamp();
+END IF;
+END $$;
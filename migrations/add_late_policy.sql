@@ -0,0 +1,18 @@
+-- How late submissions are penalized
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_type WHERE typname = 'late_penalty_type') THEN
+CREATE TYPE late_penalty_type AS ENUM ('none', 'percent_per_day', 'flat');
+END IF;
+END $$;
+
+-- Late work is accepted until the cutoff date; without one, submissions close at the due date
+ALTER TABLE assessments ADD COLUMN IF NOT EXISTS cutoff_date TIMESTAMP WITH TIME ZONE;
+ALTER TABLE assessments ADD COLUMN IF NOT EXISTS late_penalty_type late_penalty_type NOT NULL DEFAULT 'none';
+ALTER TABLE assessments ADD COLUMN IF NOT EXISTS late_penalty_value NUMERIC(6, 2) NOT NULL DEFAULT 0 CHECK (late_penalty_value >= 0);
+
+-- Grades keep the score before the late penalty next to the penalized score
+ALTER TABLE grades ADD COLUMN IF NOT EXISTS raw_score NUMERIC(5, 2);
+ALTER TABLE grades ADD COLUMN IF NOT EXISTS late_penalty NUMERIC(5, 2) NOT NULL DEFAULT 0;
+UPDATE grades SET raw_score = score WHERE raw_score IS NULL;
+ALTER TABLE grades ALTER COLUMN raw_score SET NOT NULL;
@@ -0,0 +1,2 @@
+-- The member of a group submission whose own grade was adjusted, NULL for versions of the submission's grade
+ALTER TABLE grade_versions ADD COLUMN IF NOT EXISTS student_id UUID;
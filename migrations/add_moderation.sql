@@ -0,0 +1,38 @@
+-- Double marking of exam submissions by a first and a second marker, with a moderator agreeing the final mark
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_type WHERE typname = 'moderation_status') THEN
+CREATE TYPE moderation_status AS ENUM ('marking', 'ready', 'flagged', 'agreed');
+END IF;
+END $$;
+
+-- Marks further apart than this many points are flagged for the moderator
+ALTER TABLE assessments ADD COLUMN IF NOT EXISTS moderation_threshold NUMERIC(5, 2) NOT NULL DEFAULT 0 CHECK (moderation_threshold >= 0);
+
+CREATE TABLE IF NOT EXISTS submission_moderations (
+    submission_id UUID PRIMARY KEY REFERENCES assessment_submissions(id) ON DELETE CASCADE,
+    second_marker_id UUID NOT NULL REFERENCES users(id),
+    assigned_by UUID NOT NULL REFERENCES users(id),
+    first_marker_id UUID REFERENCES users(id),
+    first_score NUMERIC(5, 2),
+    first_feedback TEXT,
+    second_score NUMERIC(5, 2),
+    second_feedback TEXT,
+    status moderation_status NOT NULL DEFAULT 'marking',
+    moderator_id UUID REFERENCES users(id),
+    final_score NUMERIC(5, 2),
+    agreed_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_submission_moderations_second_marker ON submission_moderations(second_marker_id);
+
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_trigger WHERE tgname = 'update_submission_moderations_timestamp') THEN
+CREATE TRIGGER update_submission_moderations_timestamp
+    BEFORE UPDATE ON submission_moderations
+    FOR EACH ROW EXECUTE FUNCTION update_timestamp();
+END IF;
+END $$;
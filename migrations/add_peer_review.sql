@@ -0,0 +1,20 @@
+-- Peer review phase: after the due date every submission is reviewed by other students of the course
+ALTER TABLE assessments ADD COLUMN IF NOT EXISTS peer_reviews_per_submission INTEGER NOT NULL DEFAULT 0 CHECK (peer_reviews_per_submission >= 0);
+ALTER TABLE assessments ADD COLUMN IF NOT EXISTS peer_review_due_date TIMESTAMP WITH TIME ZONE;
+-- Weight in percent with which the peer scores were folded into the grades, NULL until they are
+ALTER TABLE assessments ADD COLUMN IF NOT EXISTS peer_review_weight NUMERIC(5, 2);
+
+CREATE TABLE IF NOT EXISTS peer_reviews (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    submission_id UUID NOT NULL REFERENCES assessment_submissions(id) ON DELETE CASCADE,
+    reviewer_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    score NUMERIC(5, 2),
+    comments TEXT,
+    -- The level chosen per criterion when the assessment has a rubric
+    rubric JSONB,
+    submitted_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT unique_peer_review UNIQUE (submission_id, reviewer_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_peer_reviews_reviewer ON peer_reviews(reviewer_id);
@@ -0,0 +1,71 @@
+-- Question difficulty enum
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_type WHERE typname = 'question_difficulty') THEN
+CREATE TYPE question_difficulty AS ENUM ('easy', 'medium', 'hard');
+END IF;
+END $$;
+
+-- Course-scoped bank of reusable questions
+CREATE TABLE IF NOT EXISTS question_bank (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    course_id UUID NOT NULL REFERENCES courses(id) ON DELETE CASCADE,
+    created_by UUID NOT NULL REFERENCES users(id),
+    type question_type NOT NULL,
+    prompt TEXT NOT NULL,
+    options TEXT[] NOT NULL DEFAULT '{}',
+    points NUMERIC(6, 2) NOT NULL CHECK (points > 0),
+    correct_answer JSONB NOT NULL,
+    tolerance NUMERIC(12, 4) NOT NULL DEFAULT 0,
+    case_sensitive BOOLEAN NOT NULL DEFAULT false,
+    tags TEXT[] NOT NULL DEFAULT '{}',
+    difficulty question_difficulty NOT NULL DEFAULT 'medium',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_question_bank_course ON question_bank(course_id);
+CREATE INDEX IF NOT EXISTS idx_question_bank_tags ON question_bank USING GIN (tags);
+
+-- Rules that draw random bank questions into an assessment
+CREATE TABLE IF NOT EXISTS assessment_question_draws (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    assessment_id UUID NOT NULL REFERENCES assessments(id) ON DELETE CASCADE,
+    tags TEXT[] NOT NULL DEFAULT '{}',
+    difficulty question_difficulty,
+    question_count INT NOT NULL CHECK (question_count > 0),
+    points_per_question NUMERIC(6, 2) CHECK (points_per_question > 0),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_assessment_question_draws_assessment ON assessment_question_draws(assessment_id);
+
+-- The questions each student drew, persisted so every student keeps the same draw
+CREATE TABLE IF NOT EXISTS student_drawn_questions (
+    assessment_id UUID NOT NULL REFERENCES assessments(id) ON DELETE CASCADE,
+    student_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    draw_id UUID NOT NULL REFERENCES assessment_question_draws(id) ON DELETE CASCADE,
+    question_id UUID NOT NULL REFERENCES question_bank(id),
+    position INT NOT NULL,
+    points NUMERIC(6, 2) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (assessment_id, student_id, question_id)
+);
+
+-- Submission answers may now reference bank questions, so they keep their own ordering
+ALTER TABLE submission_answers DROP CONSTRAINT IF EXISTS submission_answers_question_id_fkey;
+ALTER TABLE submission_answers ADD COLUMN IF NOT EXISTS position INT NOT NULL DEFAULT 0;
+
+UPDATE submission_answers sa
+SET position = q.position
+FROM assessment_questions q
+WHERE sa.question_id = q.id AND sa.position = 0;
+
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_trigger WHERE tgname = 'update_question_bank_timestamp') THEN
+CREATE TRIGGER update_question_bank_timestamp
+    BEFORE UPDATE ON question_bank
+    FOR EACH ROW EXECUTE FUNCTION update_timestamp();
+END IF;
+END $$;
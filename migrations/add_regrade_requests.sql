@@ -0,0 +1,38 @@
+-- Students disputing the grade of a submission
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_type WHERE typname = 'regrade_status') THEN
+CREATE TYPE regrade_status AS ENUM ('open', 'accepted', 'rejected');
+END IF;
+END $$;
+
+CREATE TABLE IF NOT EXISTS regrade_requests (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    submission_id UUID NOT NULL REFERENCES assessment_submissions(id) ON DELETE CASCADE,
+    student_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    -- The rubric criterion or question the student disputes, if not the whole grade
+    criterion_id UUID REFERENCES rubric_criteria(id) ON DELETE SET NULL,
+    question_id UUID REFERENCES assessment_questions(id) ON DELETE SET NULL,
+    reason TEXT NOT NULL,
+    status regrade_status NOT NULL DEFAULT 'open',
+    previous_score NUMERIC(5, 2),
+    new_score NUMERIC(5, 2),
+    response TEXT,
+    resolved_by UUID REFERENCES users(id),
+    resolved_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- A submission has at most one open request at a time
+CREATE UNIQUE INDEX IF NOT EXISTS idx_regrade_requests_open ON regrade_requests(submission_id) WHERE status = 'open';
+CREATE INDEX IF NOT EXISTS idx_regrade_requests_student ON regrade_requests(student_id);
+
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_trigger WHERE tgname = 'update_regrade_requests_timestamp') THEN
+CREATE TRIGGER update_regrade_requests_timestamp
+    BEFORE UPDATE ON regrade_requests
+    FOR EACH ROW EXECUTE FUNCTION update_timestamp();
+END IF;
+END $$;
@@ -0,0 +1,59 @@
+-- Reusable grading rubrics, scoped to a course
+CREATE TABLE IF NOT EXISTS rubrics (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    course_id UUID NOT NULL REFERENCES courses(id) ON DELETE CASCADE,
+    created_by UUID NOT NULL REFERENCES users(id),
+    title VARCHAR(255) NOT NULL,
+    description TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_rubrics_course ON rubrics(course_id);
+
+-- Ordered criteria of a rubric
+CREATE TABLE IF NOT EXISTS rubric_criteria (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    rubric_id UUID NOT NULL REFERENCES rubrics(id) ON DELETE CASCADE,
+    position INT NOT NULL,
+    title VARCHAR(255) NOT NULL,
+    description TEXT
+);
+
+CREATE INDEX IF NOT EXISTS idx_rubric_criteria_rubric ON rubric_criteria(rubric_id, position);
+
+-- Performance levels of a criterion
+CREATE TABLE IF NOT EXISTS rubric_levels (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    criterion_id UUID NOT NULL REFERENCES rubric_criteria(id) ON DELETE CASCADE,
+    position INT NOT NULL,
+    title VARCHAR(255) NOT NULL,
+    descriptor TEXT,
+    points NUMERIC(6, 2) NOT NULL CHECK (points >= 0)
+);
+
+CREATE INDEX IF NOT EXISTS idx_rubric_levels_criterion ON rubric_levels(criterion_id, position);
+
+-- Rubric attached to an assessment
+ALTER TABLE assessments ADD COLUMN IF NOT EXISTS rubric_id UUID REFERENCES rubrics(id);
+
+-- Level chosen for each criterion when grading with a rubric
+CREATE TABLE IF NOT EXISTS grade_rubric_scores (
+    submission_id UUID NOT NULL REFERENCES grades(submission_id) ON DELETE CASCADE,
+    criterion_id UUID NOT NULL REFERENCES rubric_criteria(id),
+    level_id UUID NOT NULL REFERENCES rubric_levels(id),
+    points NUMERIC(6, 2) NOT NULL,
+    comment TEXT,
+    PRIMARY KEY (submission_id, criterion_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_grade_rubric_scores_criterion ON grade_rubric_scores(criterion_id);
+
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_trigger WHERE tgname = 'update_rubrics_timestamp') THEN
+CREATE TRIGGER update_rubrics_timestamp
+    BEFORE UPDATE ON rubrics
+    FOR EACH ROW EXECUTE FUNCTION update_timestamp();
+END IF;
+END $$;
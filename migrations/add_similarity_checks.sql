@@ -0,0 +1,34 @@
+-- Similarity checks comparing the submissions of an assessment for copied work
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_type WHERE typname = 'similarity_check_status') THEN
+CREATE TYPE similarity_check_status AS ENUM ('running', 'completed', 'failed');
+END IF;
+END $$;
+
+CREATE TABLE IF NOT EXISTS similarity_checks (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    assessment_id UUID NOT NULL REFERENCES assessments(id) ON DELETE CASCADE,
+    include_previous BOOLEAN NOT NULL DEFAULT false,
+    threshold NUMERIC(5, 2) NOT NULL CHECK (threshold > 0 AND threshold <= 100),
+    status similarity_check_status NOT NULL DEFAULT 'running',
+    error TEXT,
+    submissions_checked INT NOT NULL DEFAULT 0,
+    requested_by UUID NOT NULL REFERENCES users(id),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    completed_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS idx_similarity_checks_assessment ON similarity_checks(assessment_id);
+
+-- Pairs of submissions at or above the check's threshold, with the matching regions of each
+CREATE TABLE IF NOT EXISTS similarity_matches (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    check_id UUID NOT NULL REFERENCES similarity_checks(id) ON DELETE CASCADE,
+    submission_id UUID NOT NULL REFERENCES assessment_submissions(id) ON DELETE CASCADE,
+    other_submission_id UUID NOT NULL REFERENCES assessment_submissions(id) ON DELETE CASCADE,
+    similarity NUMERIC(5, 2) NOT NULL,
+    regions JSONB NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_similarity_matches_check ON similarity_matches(check_id);
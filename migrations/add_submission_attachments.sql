@@ -0,0 +1,16 @@
+-- Per-assessment limits for uploaded files
+ALTER TABLE assessments ADD COLUMN IF NOT EXISTS max_attachment_size_mb INT NOT NULL DEFAULT 10 CHECK (max_attachment_size_mb > 0);
+ALTER TABLE assessments ADD COLUMN IF NOT EXISTS allowed_file_types TEXT[] NOT NULL DEFAULT '{}';
+
+-- Files handed in with a submission; the content lives in blob storage
+CREATE TABLE IF NOT EXISTS submission_attachments (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    submission_id UUID NOT NULL REFERENCES assessment_submissions(id) ON DELETE CASCADE,
+    file_name VARCHAR(255) NOT NULL,
+    content_type VARCHAR(255) NOT NULL,
+    size_bytes BIGINT NOT NULL,
+    storage_key TEXT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_submission_attachments_submission ON submission_attachments(submission_id);
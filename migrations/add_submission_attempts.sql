@@ -0,0 +1,22 @@
+-- Which attempt counts towards a student's grade when several are allowed
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_type WHERE typname = 'attempt_policy') THEN
+CREATE TYPE attempt_policy AS ENUM ('highest', 'latest', 'average');
+END IF;
+END $$;
+
+ALTER TABLE assessments ADD COLUMN IF NOT EXISTS max_attempts INT NOT NULL DEFAULT 1 CHECK (max_attempts > 0);
+ALTER TABLE assessments ADD COLUMN IF NOT EXISTS attempt_policy attempt_policy NOT NULL DEFAULT 'latest';
+
+-- Every resubmission is kept as a numbered attempt instead of a single row per student
+ALTER TABLE assessment_submissions DROP CONSTRAINT IF EXISTS unique_student_submission;
+ALTER TABLE assessment_submissions ADD COLUMN IF NOT EXISTS attempt_number INT NOT NULL DEFAULT 1;
+
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_constraint WHERE conname = 'unique_student_attempt') THEN
+ALTER TABLE assessment_submissions
+    ADD CONSTRAINT unique_student_attempt UNIQUE (assessment_id, student_id, attempt_number);
+END IF;
+END $$;
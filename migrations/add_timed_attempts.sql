@@ -0,0 +1,15 @@
+-- Time limits that start when the student opens the assessment
+ALTER TABLE assessments ADD COLUMN IF NOT EXISTS time_limit_minutes INT CHECK (time_limit_minutes > 0);
+ALTER TABLE assessments ADD COLUMN IF NOT EXISTS grace_period_seconds INT NOT NULL DEFAULT 60 CHECK (grace_period_seconds >= 0);
+
+-- A started attempt at a timed assessment; it is closed once the submission is linked
+CREATE TABLE IF NOT EXISTS attempt_sessions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    assessment_id UUID NOT NULL REFERENCES assessments(id) ON DELETE CASCADE,
+    student_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    started_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    ends_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    submission_id UUID REFERENCES assessment_submissions(id) ON DELETE SET NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_attempt_sessions_student ON attempt_sessions(assessment_id, student_id);
@@ -66,6 +66,32 @@ func RunMigrations(db *db.DB) error {
 	migrations := []string{
 		"init.sql",
 		"add_refresh_tokens.sql",
+		"add_assessment_questions.sql",
+		"add_question_bank.sql",
+		"add_rubrics.sql",
+		"add_submission_attachments.sql",
+		"add_submission_attempts.sql",
+		"add_late_policy.sql",
+		"add_assessment_extensions.sql",
+		"add_timed_attempts.sql",
+		"add_assessment_status.sql",
+		"add_grade_release.sql",
+		"add_gradebook.sql",
+		"add_grading_schemes.sql",
+		"add_regrade_requests.sql",
+		"add_grade_versions.sql",
+		"add_anonymous_grading.sql",
+		"add_moderation.sql",
+		"add_peer_review.sql",
+		"add_groups.sql",
+		"add_similarity_checks.sql",
+		"add_academic_terms.sql",
+		"add_course_sections.sql",
+		"add_course_waitlists.sql",
+		"add_course_prerequisites.sql",
+		"add_course_join_codes.sql",
+		"add_member_grade_versions.sql",
+		"add_assessment_offerings.sql",
 	}
 
 	// Execute each migration
@@ -0,0 +1,28 @@
+package models
+
+import (
+	"time"
+)
+
+// AnonymousIdentity reveals the student behind a pseudonym of an assessment graded anonymously
+type AnonymousIdentity struct {
+	Pseudonym string `json:"pseudonym"`
+	StudentID string `json:"student_id"`
+	FirstName string `json:"first_name"`
+	LastName  string `json:"last_name"`
+	Email     string `json:"email"`
+}
+
+// UnmaskLogEntry records an admin revealing the students of an assessment graded anonymously
+type UnmaskLogEntry struct {
+	ID           string    `json:"id"`
+	AssessmentID string    `json:"assessment_id"`
+	UnmaskedBy   string    `json:"unmasked_by"`
+	Reason       string    `json:"reason"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// UnmaskRequest represents the data needed to reveal the students of an assessment graded anonymously
+type UnmaskRequest struct {
+	Reason string `json:"reason" validate:"required,max=1000"`
+}
@@ -1,6 +1,9 @@
 package models
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
 	"time"
 )
 
@@ -14,36 +17,188 @@ const (
 	AssessmentTypeProject    AssessmentType = "project"
 )
 
+// SupportsRubric reports whether assessments of this type can be graded with a rubric
+func (t AssessmentType) SupportsRubric() bool {
+	return t == AssessmentTypeAssignment || t == AssessmentTypeProject
+}
+
+// AttemptPolicy decides which of a student's attempts counts towards their grade
+type AttemptPolicy string
+
+const (
+	AttemptPolicyHighest AttemptPolicy = "highest"
+	AttemptPolicyLatest  AttemptPolicy = "latest"
+	AttemptPolicyAverage AttemptPolicy = "average"
+)
+
+// AssessmentStatus is the stage of an assessment's publication lifecycle
+type AssessmentStatus string
+
+const (
+	// AssessmentStatusDraft is being written and hidden from students
+	AssessmentStatusDraft AssessmentStatus = "draft"
+	// AssessmentStatusScheduled is hidden until its available_from date, when it is published automatically
+	AssessmentStatusScheduled AssessmentStatus = "scheduled"
+	// AssessmentStatusPublished is visible to students and accepts submissions
+	AssessmentStatusPublished AssessmentStatus = "published"
+	// AssessmentStatusClosed is still visible to students but no longer accepts submissions
+	AssessmentStatusClosed AssessmentStatus = "closed"
+	// AssessmentStatusArchived is hidden from students
+	AssessmentStatusArchived AssessmentStatus = "archived"
+)
+
+// GradeReleaseMode decides when students can see the grades of an assessment
+type GradeReleaseMode string
+
+const (
+	// GradeReleaseImmediate shows every grade as soon as it is given
+	GradeReleaseImmediate GradeReleaseMode = "immediate"
+	// GradeReleaseManual holds grades back until the teacher releases them
+	GradeReleaseManual GradeReleaseMode = "manual"
+	// GradeReleaseScheduled holds grades back until grades_release_at, unless the teacher releases them earlier
+	GradeReleaseScheduled GradeReleaseMode = "scheduled"
+)
+
+// LatePenaltyType is how a late submission is penalized
+type LatePenaltyType string
+
+const (
+	LatePenaltyNone LatePenaltyType = "none"
+	// LatePenaltyPercentPerDay deducts a percentage of the maximum score for every started day past the due date
+	LatePenaltyPercentPerDay LatePenaltyType = "percent_per_day"
+	// LatePenaltyFlat deducts a fixed number of points from any late submission
+	LatePenaltyFlat LatePenaltyType = "flat"
+)
+
 // Assessment represents an assessment that teachers create for courses
 type Assessment struct {
-	ID          string         `json:"id"`
-	CourseID    string         `json:"course_id"`
-	TeacherID   string         `json:"teacher_id"`
-	Title       string         `json:"title"`
-	Description string         `json:"description"`
-	Type        AssessmentType `json:"type"`
-	MaxScore    int            `json:"max_score"`
-	DueDate     *time.Time     `json:"due_date"`
-	CreatedAt   time.Time      `json:"created_at"`
-	UpdatedAt   time.Time      `json:"updated_at"`
+	ID                       string           `json:"id"`
+	CourseID                 string           `json:"course_id"`
+	TeacherID                string           `json:"teacher_id"`
+	Title                    string           `json:"title"`
+	Description              string           `json:"description"`
+	Type                     AssessmentType   `json:"type"`
+	Status                   AssessmentStatus `json:"status"`
+	AvailableFrom            *time.Time       `json:"available_from"`
+	MaxScore                 int              `json:"max_score"`
+	DueDate                  *time.Time       `json:"due_date"`
+	CutoffDate               *time.Time       `json:"cutoff_date"`
+	LatePenaltyType          LatePenaltyType  `json:"late_penalty_type"`
+	LatePenaltyValue         float64          `json:"late_penalty_value"`
+	RubricID                 *string          `json:"rubric_id"`
+	MaxAttachmentSizeMB      int              `json:"max_attachment_size_mb"`
+	AllowedFileTypes         []string         `json:"allowed_file_types"`
+	MaxAttempts              int              `json:"max_attempts"`
+	AttemptPolicy            AttemptPolicy    `json:"attempt_policy"`
+	TimeLimitMinutes         *int             `json:"time_limit_minutes"`
+	GracePeriodSeconds       int              `json:"grace_period_seconds"`
+	GradeReleaseMode         GradeReleaseMode `json:"grade_release_mode"`
+	GradesReleaseAt          *time.Time       `json:"grades_release_at"`
+	CategoryID               *string          `json:"category_id"`
+	ExtraCredit              bool             `json:"extra_credit"`
+	AnonymousGrading         bool             `json:"anonymous_grading"`
+	ModerationThreshold      float64          `json:"moderation_threshold"`
+	PeerReviewsPerSubmission int              `json:"peer_reviews_per_submission"`
+	PeerReviewDueDate        *time.Time       `json:"peer_review_due_date"`
+	PeerReviewWeight         *float64         `json:"peer_review_weight"`
+	GroupSubmission          bool             `json:"group_submission"`
+	PreviousOfferingID       *string          `json:"previous_offering_id"` // The same assessment in an earlier term
+	SectionIDs               []string         `json:"section_ids"`          // Sections the assessment is for, all of the course when empty
+	AnonymousKey             string           `json:"-"`                    // Secret the student pseudonyms are derived from
+	CreatedAt                time.Time        `json:"created_at"`
+	UpdatedAt                time.Time        `json:"updated_at"`
+}
+
+// SubmissionDeadline returns the last moment submissions are accepted: the cutoff date when late work
+// is accepted, otherwise the due date. It returns nil when the assessment never closes.
+func (a *Assessment) SubmissionDeadline() *time.Time {
+	if a.CutoffDate != nil {
+		return a.CutoffDate
+	}
+	return a.DueDate
+}
+
+// IsVisibleToStudents reports whether students can see the assessment
+func (a *Assessment) IsVisibleToStudents() bool {
+	return a.Status == AssessmentStatusPublished || a.Status == AssessmentStatusClosed
+}
+
+// IsForSection reports whether the assessment is for the students of a section. Assessments without sections are
+// for the whole course; students without a section only get those.
+func (a *Assessment) IsForSection(sectionID *string) bool {
+	if len(a.SectionIDs) == 0 {
+		return true
+	}
+
+	if sectionID == nil {
+		return false
+	}
+
+	for _, id := range a.SectionIDs {
+		if id == *sectionID {
+			return true
+		}
+	}
+	return false
+}
+
+// AreGradesReleased reports whether the assessment's release mode shows grades to students at the given time,
+// without a teacher releasing them
+func (a *Assessment) AreGradesReleased(now time.Time) bool {
+	switch a.GradeReleaseMode {
+	case GradeReleaseImmediate:
+		return true
+	case GradeReleaseScheduled:
+		return a.GradesReleaseAt != nil && !a.GradesReleaseAt.After(now)
+	default:
+		return false
+	}
+}
+
+// IsGradeReleased reports whether a grade of the assessment is visible to the student at the given time
+func (a *Assessment) IsGradeReleased(grade *Grade, now time.Time) bool {
+	return grade.ReleasedAt != nil || a.AreGradesReleased(now)
+}
+
+// Pseudonym returns the stable name a student goes by at an assessment graded anonymously
+func (a *Assessment) Pseudonym(studentID string) string {
+	sum := sha256.Sum256([]byte(a.AnonymousKey + ":" + studentID))
+	return "Student " + strings.ToUpper(hex.EncodeToString(sum[:4]))
+}
+
+// IsTimed reports whether attempts at the assessment have a time limit
+func (a *Assessment) IsTimed() bool {
+	return a.TimeLimitMinutes != nil
 }
 
 // AssessmentSubmission represents a student's submission for an assessment
 type AssessmentSubmission struct {
-	ID           string    `json:"id"`
-	AssessmentID string    `json:"assessment_id"`
-	StudentID    string    `json:"student_id"`
-	Content      string    `json:"content"`
-	SubmittedAt  time.Time `json:"submitted_at"`
+	ID            string              `json:"id"`
+	AssessmentID  string              `json:"assessment_id"`
+	StudentID     string              `json:"student_id,omitempty"`
+	GroupID       *string             `json:"group_id,omitempty"`
+	Pseudonym     string              `json:"pseudonym,omitempty"` // Replaces StudentID while the submission is graded anonymously
+	AttemptNumber int                 `json:"attempt_number"`
+	Content       string              `json:"content"`
+	SubmittedAt   time.Time           `json:"submitted_at"`
+	Answers       []*SubmissionAnswer `json:"answers,omitempty"`
+	Attachments   []*Attachment       `json:"attachments,omitempty"`
 }
 
 // Grade represents the grade given to a student's assessment submission
 type Grade struct {
-	SubmissionID string    `json:"submission_id"`
-	Score        float64   `json:"score"`
-	Feedback     string    `json:"feedback"`
-	GradedBy     string    `json:"graded_by"`
-	GradedAt     time.Time `json:"graded_at"`
+	SubmissionID string            `json:"submission_id"`
+	Score        float64           `json:"score"`
+	RawScore     float64           `json:"raw_score"`
+	LatePenalty  float64           `json:"late_penalty"`
+	Feedback     string            `json:"feedback"`
+	GradedBy     string            `json:"graded_by"`
+	GradedAt     time.Time         `json:"graded_at"`
+	AutoGraded   bool              `json:"auto_graded"`
+	ReleasedAt   *time.Time        `json:"released_at,omitempty"`
+	Rubric       []*RubricScore    `json:"rubric,omitempty"`
+	Regrades     []*RegradeRequest `json:"regrades,omitempty"`
+	GroupScore   *float64          `json:"group_score,omitempty"` // The group's score when Score was adjusted for one member
 }
 
 // AssessmentWithSubmissionCount combines an assessment with submission statistics
@@ -66,38 +221,95 @@ type StudentAssessmentStatus struct {
 	Assessment   *Assessment           `json:"assessment"`
 	Submission   *AssessmentSubmission `json:"submission,omitempty"`
 	Grade        *Grade                `json:"grade,omitempty"`
+	FinalScore   *float64              `json:"final_score,omitempty"`
+	Extension    *Extension            `json:"extension,omitempty"`
 	HasSubmitted bool                  `json:"has_submitted"`
 	IsGraded     bool                  `json:"is_graded"`
-	DaysUntilDue int                   `json:"days_until_due,omitempty"`
-	IsOverdue    bool                  `json:"is_overdue"`
+	AttemptsUsed int                   `json:"attempts_used"`
+	AttemptsLeft int                   `json:"attempts_left"`
+	// ActiveAttempt and TimeRemainingSeconds are set while a timed attempt is running
+	ActiveAttempt        *AttemptSession `json:"active_attempt,omitempty"`
+	TimeRemainingSeconds *int            `json:"time_remaining_seconds,omitempty"`
+	DaysUntilDue         int             `json:"days_until_due,omitempty"`
+	IsOverdue            bool            `json:"is_overdue"`
 }
 
 // CreateAssessmentRequest represents the data needed to create a new assessment
 type CreateAssessmentRequest struct {
-	CourseID    string         `json:"course_id" validate:"required"`
-	Title       string         `json:"title" validate:"required,min=3,max=255"`
-	Description string         `json:"description"`
-	Type        AssessmentType `json:"type" validate:"required,oneof=quiz exam assignment project"`
-	MaxScore    int            `json:"max_score" validate:"required,min=1"`
-	DueDate     *time.Time     `json:"due_date"`
+	CourseID                 string           `json:"course_id" validate:"required"`
+	Title                    string           `json:"title" validate:"required,min=3,max=255"`
+	Description              string           `json:"description"`
+	Type                     AssessmentType   `json:"type" validate:"required,oneof=quiz exam assignment project"`
+	Status                   AssessmentStatus `json:"status" validate:"omitempty,oneof=draft scheduled published"`
+	AvailableFrom            *time.Time       `json:"available_from"`
+	MaxScore                 int              `json:"max_score" validate:"required,min=1"`
+	DueDate                  *time.Time       `json:"due_date"`
+	CutoffDate               *time.Time       `json:"cutoff_date"`
+	LatePenaltyType          LatePenaltyType  `json:"late_penalty_type" validate:"omitempty,oneof=none percent_per_day flat"`
+	LatePenaltyValue         float64          `json:"late_penalty_value" validate:"min=0"`
+	MaxAttachmentSizeMB      *int             `json:"max_attachment_size_mb" validate:"omitempty,min=1,max=500"`
+	AllowedFileTypes         []string         `json:"allowed_file_types" validate:"omitempty,dive,required,max=20"`
+	MaxAttempts              *int             `json:"max_attempts" validate:"omitempty,min=1,max=100"`
+	AttemptPolicy            AttemptPolicy    `json:"attempt_policy" validate:"omitempty,oneof=highest latest average"`
+	TimeLimitMinutes         *int             `json:"time_limit_minutes" validate:"omitempty,min=1"`
+	GracePeriodSeconds       *int             `json:"grace_period_seconds" validate:"omitempty,min=0,max=3600"`
+	GradeReleaseMode         GradeReleaseMode `json:"grade_release_mode" validate:"omitempty,oneof=immediate manual scheduled"`
+	GradesReleaseAt          *time.Time       `json:"grades_release_at"`
+	CategoryID               *string          `json:"category_id"`
+	ExtraCredit              bool             `json:"extra_credit"`
+	AnonymousGrading         bool             `json:"anonymous_grading"`
+	ModerationThreshold      float64          `json:"moderation_threshold" validate:"min=0"`
+	PeerReviewsPerSubmission int              `json:"peer_reviews_per_submission" validate:"min=0,max=10"`
+	PeerReviewDueDate        *time.Time       `json:"peer_review_due_date"`
+	GroupSubmission          bool             `json:"group_submission"`
+	PreviousOfferingID       *string          `json:"previous_offering_id"`
+	SectionIDs               []string         `json:"section_ids" validate:"omitempty,dive,required"`
 }
 
 // UpdateAssessmentRequest represents the data needed to update an assessment
 type UpdateAssessmentRequest struct {
-	Title       *string         `json:"title" validate:"omitempty,min=3,max=255"`
-	Description *string         `json:"description"`
-	Type        *AssessmentType `json:"type" validate:"omitempty,oneof=quiz exam assignment project"`
-	MaxScore    *int            `json:"max_score" validate:"omitempty,min=1"`
-	DueDate     *time.Time      `json:"due_date"`
+	Title                    *string           `json:"title" validate:"omitempty,min=3,max=255"`
+	Description              *string           `json:"description"`
+	Type                     *AssessmentType   `json:"type" validate:"omitempty,oneof=quiz exam assignment project"`
+	Status                   *AssessmentStatus `json:"status" validate:"omitempty,oneof=draft scheduled published closed archived"`
+	AvailableFrom            *time.Time        `json:"available_from"`
+	MaxScore                 *int              `json:"max_score" validate:"omitempty,min=1"`
+	DueDate                  *time.Time        `json:"due_date"`
+	CutoffDate               *time.Time        `json:"cutoff_date"`
+	LatePenaltyType          *LatePenaltyType  `json:"late_penalty_type" validate:"omitempty,oneof=none percent_per_day flat"`
+	LatePenaltyValue         *float64          `json:"late_penalty_value" validate:"omitempty,min=0"`
+	MaxAttachmentSizeMB      *int              `json:"max_attachment_size_mb" validate:"omitempty,min=1,max=500"`
+	AllowedFileTypes         []string          `json:"allowed_file_types" validate:"omitempty,dive,required,max=20"`
+	MaxAttempts              *int              `json:"max_attempts" validate:"omitempty,min=1,max=100"`
+	AttemptPolicy            *AttemptPolicy    `json:"attempt_policy" validate:"omitempty,oneof=highest latest average"`
+	TimeLimitMinutes         *int              `json:"time_limit_minutes" validate:"omitempty,min=1"`
+	GracePeriodSeconds       *int              `json:"grace_period_seconds" validate:"omitempty,min=0,max=3600"`
+	GradeReleaseMode         *GradeReleaseMode `json:"grade_release_mode" validate:"omitempty,oneof=immediate manual scheduled"`
+	GradesReleaseAt          *time.Time        `json:"grades_release_at"`
+	CategoryID               *string           `json:"category_id"`
+	ExtraCredit              *bool             `json:"extra_credit"`
+	AnonymousGrading         *bool             `json:"anonymous_grading"`
+	ModerationThreshold      *float64          `json:"moderation_threshold" validate:"omitempty,min=0"`
+	PeerReviewsPerSubmission *int              `json:"peer_reviews_per_submission" validate:"omitempty,min=0,max=10"`
+	PeerReviewDueDate        *time.Time        `json:"peer_review_due_date"`
+	GroupSubmission          *bool             `json:"group_submission"`
+	PreviousOfferingID       *string           `json:"previous_offering_id"`
+	SectionIDs               *[]string         `json:"section_ids" validate:"omitempty,dive,required"`
 }
 
-// CreateSubmissionRequest represents the data needed to create a new submission
+// CreateSubmissionRequest represents the data needed to create a new submission.
+// Free-text assessments need content or attached files, assessments with questions need answers.
 type CreateSubmissionRequest struct {
-	Content string `json:"content" validate:"required"`
+	Content string                `json:"content" form:"content"`
+	Answers []SubmitAnswerRequest `json:"answers" validate:"omitempty,dive"`
 }
 
-// GradeSubmissionRequest represents the data needed to grade a submission
+// GradeSubmissionRequest represents the data needed to grade a submission. Assessments with a rubric
+// are graded by choosing a level per criterion, the score is then computed from the rubric.
 type GradeSubmissionRequest struct {
-	Score    float64 `json:"score" validate:"required,min=0"`
-	Feedback string  `json:"feedback"`
+	Score    *float64                 `json:"score" validate:"required_without=Rubric,omitempty,min=0"`
+	Feedback string                   `json:"feedback"`
+	Rubric   []RubricSelectionRequest `json:"rubric" validate:"omitempty,dive"`
+	// Reason is recorded in the grade's history
+	Reason string `json:"reason" validate:"max=1000"`
 }
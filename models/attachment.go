@@ -0,0 +1,17 @@
+package models
+
+import (
+	"time"
+)
+
+// Attachment represents a file handed in with a submission
+type Attachment struct {
+	ID           string    `json:"id"`
+	SubmissionID string    `json:"submission_id"`
+	FileName     string    `json:"file_name"`
+	ContentType  string    `json:"content_type"`
+	SizeBytes    int64     `json:"size_bytes"`
+	StorageKey   string    `json:"-"`
+	DownloadURL  string    `json:"download_url,omitempty"`
+	CreatedAt    time.Time `json:"created_at"`
+}
@@ -0,0 +1,82 @@
+package models
+
+import (
+	"time"
+)
+
+// AttemptSession is a started attempt at a timed assessment. Submissions are accepted until EndsAt plus
+// the assessment's grace period; the session is closed once its submission is recorded.
+type AttemptSession struct {
+	ID           string    `json:"id"`
+	AssessmentID string    `json:"assessment_id"`
+	StudentID    string    `json:"student_id"`
+	StartedAt    time.Time `json:"started_at"`
+	EndsAt       time.Time `json:"ends_at"`
+	SubmissionID *string   `json:"submission_id"`
+}
+
+// AcceptsSubmissionAt reports whether the session is open and a submission at t is within its time window
+func (s *AttemptSession) AcceptsSubmissionAt(t time.Time, gracePeriodSeconds int) bool {
+	return s.SubmissionID == nil && !t.After(s.EndsAt.Add(time.Duration(gracePeriodSeconds)*time.Second))
+}
+
+// SubmissionAttempt is one of a student's attempts at an assessment together with its grade, if graded
+type SubmissionAttempt struct {
+	Submission *AssessmentSubmission `json:"submission"`
+	Grade      *Grade                `json:"grade,omitempty"`
+}
+
+// FinalGrade is the score that counts for an assessment after applying its attempt policy
+type FinalGrade struct {
+	Policy         AttemptPolicy `json:"policy"`
+	Score          float64       `json:"score"`
+	MaxScore       int           `json:"max_score"`
+	GradedAttempts int           `json:"graded_attempts"`
+	CountedAttempt int           `json:"counted_attempt,omitempty"`
+}
+
+// StudentGrade is the grade of the attempt that counts, or of the latest graded attempt when attempts
+// are averaged, together with the final score
+type StudentGrade struct {
+	*Grade
+	AttemptNumber int         `json:"attempt_number"`
+	Final         *FinalGrade `json:"final"`
+}
+
+// DiffOperation marks whether a line was kept, added or removed between two attempts
+type DiffOperation string
+
+const (
+	DiffEqual  DiffOperation = "equal"
+	DiffInsert DiffOperation = "insert"
+	DiffDelete DiffOperation = "delete"
+)
+
+// DiffLine is a single line of a line-by-line diff
+type DiffLine struct {
+	Operation DiffOperation `json:"op"`
+	Text      string        `json:"text"`
+}
+
+// AnswerChange describes how the answer to a question changed between two attempts
+type AnswerChange struct {
+	QuestionID   string          `json:"question_id"`
+	Position     int             `json:"position"`
+	Before       *QuestionAnswer `json:"before"`
+	After        *QuestionAnswer `json:"after"`
+	PointsBefore float64         `json:"points_before"`
+	PointsAfter  float64         `json:"points_after"`
+}
+
+// AttemptDiff shows how a student's work changed from one attempt to another
+type AttemptDiff struct {
+	StudentID          string          `json:"student_id"`
+	FromAttempt        int             `json:"from_attempt"`
+	ToAttempt          int             `json:"to_attempt"`
+	ScoreBefore        *float64        `json:"score_before"`
+	ScoreAfter         *float64        `json:"score_after"`
+	Content            []DiffLine      `json:"content"`
+	Answers            []*AnswerChange `json:"answers"`
+	AttachmentsAdded   []string        `json:"attachments_added"`
+	AttachmentsRemoved []string        `json:"attachments_removed"`
+}
@@ -11,8 +11,20 @@ type Course struct {
 	Name           string    `json:"name"`
 	Description    string    `json:"description"`
 	EnrollmentOpen bool      `json:"enrollment_open"`
+	MaxStudents    *int      `json:"max_students"` // No limit when nil
+	TermID         *string   `json:"term_id"`
+	Term           *Term     `json:"term,omitempty"`
 	CreatedAt      time.Time `json:"created_at"`
 	UpdatedAt      time.Time `json:"updated_at"`
+
+	PrerequisiteMode PrerequisiteMode      `json:"prerequisite_mode"`
+	Prerequisites    []*CoursePrerequisite `json:"prerequisites,omitempty"`
+}
+
+// IsArchived reports whether the course's term has ended, which makes the course read-only. Courses without a
+// term are never archived.
+func (c *Course) IsArchived(now time.Time) bool {
+	return c.Term != nil && c.Term.HasEnded(now)
 }
 
 // CourseWithTeachers represents a course with its assigned teachers
@@ -30,20 +42,35 @@ type CourseWithStudentCount struct {
 
 // CourseWithDetails combines a course with organization and teacher details
 type CourseWithDetails struct {
-	Course           *Course `json:"course"`
-	OrganizationID   string  `json:"organization_id"`
-	OrganizationName string  `json:"organization_name"`
-	Teachers         []*User `json:"teachers"`
-	StudentCount     int     `json:"student_count"`
+	Course           *Course          `json:"course"`
+	OrganizationID   string           `json:"organization_id"`
+	OrganizationName string           `json:"organization_name"`
+	Teachers         []*User          `json:"teachers"`
+	StudentCount     int              `json:"student_count"`
+	Sections         []*CourseSection `json:"sections,omitempty"`
+	WaitlistPosition *int             `json:"waitlist_position,omitempty"` // The student's place on the waitlist of a full course
+	LockedReason     string           `json:"locked_reason,omitempty"`     // Why the student does not meet the prerequisites
 }
 
 // CourseEnrollment represents a student's enrollment in a course
 type CourseEnrollment struct {
 	CourseID   string    `json:"course_id"`
 	StudentID  string    `json:"student_id"`
+	SectionID  *string   `json:"section_id"`
+	JoinCodeID *string   `json:"join_code_id"` // The join code the student enrolled with, if any
 	EnrolledAt time.Time `json:"enrolled_at"`
 }
 
+// WaitlistEntry is a student waiting for a seat in a full course. Students are enrolled in the order they joined.
+type WaitlistEntry struct {
+	CourseID  string    `json:"course_id"`
+	StudentID string    `json:"student_id"`
+	Student   *User     `json:"student,omitempty"`
+	SectionID *string   `json:"section_id"`
+	Position  int       `json:"position"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
 // CourseTeacher represents a teacher assigned to a course
 type CourseTeacher struct {
 	CourseID  string    `json:"course_id"`
@@ -56,7 +83,9 @@ type CreateCourseRequest struct {
 	Name           string `json:"name" validate:"required,min=3,max=255"`
 	Description    string `json:"description"`
 	EnrollmentOpen bool   `json:"enrollment_open"`
+	MaxStudents    *int   `json:"max_students" validate:"omitempty,min=0"`
 	OrganizationID string `json:"organization_id" validate:"required"`
+	TermID         string `json:"term_id" validate:"required"`
 }
 
 // UpdateCourseRequest represents the data needed to update a course
@@ -64,6 +93,8 @@ type UpdateCourseRequest struct {
 	Name           *string `json:"name" validate:"omitempty,min=3,max=255"`
 	Description    *string `json:"description"`
 	EnrollmentOpen *bool   `json:"enrollment_open"`
+	MaxStudents    *int    `json:"max_students" validate:"omitempty,min=0"` // 0 removes the limit
+	TermID         *string `json:"term_id"`
 }
 
 // AssignTeacherRequest represents the data needed to assign a teacher to a course
@@ -73,10 +104,17 @@ type AssignTeacherRequest struct {
 
 // EnrollStudentRequest represents the data needed to enroll a student in a course
 type EnrollStudentRequest struct {
-	StudentID string `json:"student_id" validate:"required"`
+	StudentID string  `json:"student_id" validate:"required"`
+	SectionID *string `json:"section_id"`
+}
+
+// EnrollRequest represents the data a student gives when enrolling in a course
+type EnrollRequest struct {
+	SectionID *string `json:"section_id"`
 }
 
 // BulkEnrollmentRequest represents the data needed for bulk enrollment
 type BulkEnrollmentRequest struct {
 	StudentIDs []string `json:"student_ids" validate:"required,min=1"`
+	SectionID  *string  `json:"section_id"`
 }
@@ -0,0 +1,52 @@
+package models
+
+import (
+	"time"
+)
+
+// Extension overrides an assessment's deadlines and limits for a single student. Fields left nil keep
+// the assessment's own setting.
+type Extension struct {
+	ID               string     `json:"id"`
+	AssessmentID     string     `json:"assessment_id"`
+	StudentID        string     `json:"student_id"`
+	DueDate          *time.Time `json:"due_date"`
+	CutoffDate       *time.Time `json:"cutoff_date"`
+	TimeLimitMinutes *int       `json:"time_limit_minutes"`
+	MaxAttempts      *int       `json:"max_attempts"`
+	Reason           string     `json:"reason"`
+	GrantedBy        string     `json:"granted_by"`
+	CreatedAt        time.Time  `json:"created_at"`
+	UpdatedAt        time.Time  `json:"updated_at"`
+}
+
+// Apply returns a copy of the assessment with the extension's overrides in place
+func (e *Extension) Apply(assessment *Assessment) *Assessment {
+	extended := *assessment
+	if e == nil {
+		return &extended
+	}
+
+	if e.DueDate != nil {
+		extended.DueDate = e.DueDate
+	}
+	if e.CutoffDate != nil {
+		extended.CutoffDate = e.CutoffDate
+	}
+	if e.TimeLimitMinutes != nil && extended.TimeLimitMinutes != nil {
+		extended.TimeLimitMinutes = e.TimeLimitMinutes
+	}
+	if e.MaxAttempts != nil {
+		extended.MaxAttempts = *e.MaxAttempts
+	}
+	return &extended
+}
+
+// GrantExtensionRequest represents the data needed to grant or replace a student's extension
+type GrantExtensionRequest struct {
+	DueDate          *time.Time `json:"due_date"`
+	CutoffDate       *time.Time `json:"cutoff_date"`
+	TimeLimitMinutes *int       `json:"time_limit_minutes" validate:"omitempty,min=1"`
+	MaxAttempts      *int       `json:"max_attempts" validate:"omitempty,min=1,max=100"`
+	Reason           string     `json:"reason" validate:"max=1000"`
+}
@@ -0,0 +1,28 @@
+package models
+
+// GradeSheetRow is one student's row of an assessment's grade sheet, the CSV teachers download, edit offline and
+// upload again.
+type GradeSheetRow struct {
+	StudentID    string   // The student's pseudonym while the student is hidden by anonymous grading
+	Name         string   // Empty while the student is hidden by anonymous grading
+	Email        string   // Empty while the student is hidden by anonymous grading
+	SubmissionID string   // The student's latest attempt, empty when the student has not submitted
+	RawScore     *float64 // The score before any late penalty, nil while ungraded
+	Feedback     string
+}
+
+// GradeImportResult reports what uploading a grade sheet changed. Rows with errors are left out and the valid rows
+// are applied together.
+type GradeImportResult struct {
+	Applied   int                 `json:"applied"`
+	Unchanged int                 `json:"unchanged"`
+	Skipped   int                 `json:"skipped"` // Rows without a score
+	Errors    []*GradeImportError `json:"errors"`
+}
+
+// GradeImportError explains why a row of an uploaded grade sheet was not applied
+type GradeImportError struct {
+	Row       int    `json:"row"` // The line of the CSV file, the header being line 1
+	StudentID string `json:"student_id,omitempty"`
+	Message   string `json:"message"`
+}
@@ -0,0 +1,29 @@
+package models
+
+import (
+	"time"
+)
+
+// GradeValues are the values of a grade at one point in its history
+type GradeValues struct {
+	Score       float64 `json:"score"`
+	RawScore    float64 `json:"raw_score"`
+	LatePenalty float64 `json:"late_penalty"`
+	Feedback    string  `json:"feedback"`
+}
+
+// GradeVersion is an immutable record of one change to a grade: who made it, when, why, and the values
+// before and after. Previous is nil for the version that created the grade. StudentID is set when the change
+// adjusted the grade of one member of a group submission.
+type GradeVersion struct {
+	ID           string       `json:"id"`
+	SubmissionID string       `json:"submission_id"`
+	StudentID    *string      `json:"student_id,omitempty"`
+	Pseudonym    string       `json:"pseudonym,omitempty"` // Set in place of the student while grading is anonymous
+	Version      int          `json:"version"`
+	Previous     *GradeValues `json:"previous"`
+	New          GradeValues  `json:"new"`
+	ChangedBy    string       `json:"changed_by"`
+	Reason       string       `json:"reason"`
+	CreatedAt    time.Time    `json:"created_at"`
+}
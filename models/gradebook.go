@@ -0,0 +1,106 @@
+package models
+
+import (
+	"time"
+)
+
+// GradeCategory groups the assessments of a course that share a weight in the course grade
+type GradeCategory struct {
+	ID       string  `json:"id"`
+	CourseID string  `json:"course_id"`
+	Name     string  `json:"name"`
+	Weight   float64 `json:"weight"`
+	// DropLowest is how many of the lowest scores in the category are left out of the course grade
+	DropLowest int       `json:"drop_lowest"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// GradebookColumn is an assessment as it appears in the gradebook
+type GradebookColumn struct {
+	AssessmentID string  `json:"assessment_id"`
+	Title        string  `json:"title"`
+	CategoryID   *string `json:"category_id"`
+	MaxScore     int     `json:"max_score"`
+	ExtraCredit  bool    `json:"extra_credit"`
+}
+
+// GradebookEntry is a student's score for one assessment. Score is nil until the assessment is graded.
+type GradebookEntry struct {
+	AssessmentID string   `json:"assessment_id"`
+	Score        *float64 `json:"score"`
+	Percentage   *float64 `json:"percentage"`
+	Letter       string   `json:"letter,omitempty"`
+	// Dropped is set when the score is one of the lowest of its category and left out of the course grade
+	Dropped bool `json:"dropped"`
+	// Masked is set when the assessment is graded anonymously and the grade is not released yet. The score is left
+	// out, and does not count toward the course grade, so that it cannot be tied to the student.
+	Masked bool `json:"masked,omitempty"`
+}
+
+// CategoryStanding is a student's result in one grade category
+type CategoryStanding struct {
+	CategoryID string   `json:"category_id"`
+	Name       string   `json:"name"`
+	Weight     float64  `json:"weight"`
+	Earned     float64  `json:"earned"`
+	Possible   float64  `json:"possible"`
+	Percentage *float64 `json:"percentage"`
+	Letter     string   `json:"letter,omitempty"`
+}
+
+// StudentStanding is a student's running grade in a course, computed from the graded assessments only
+type StudentStanding struct {
+	StudentID   string              `json:"student_id"`
+	StudentName string              `json:"student_name,omitempty"`
+	Entries     []*GradebookEntry   `json:"entries"`
+	Categories  []*CategoryStanding `json:"categories"`
+	Percentage  *float64            `json:"percentage"`
+	LetterGrade *LetterGrade        `json:"letter_grade,omitempty"`
+}
+
+// Gradebook is the matrix of students and assessments of a course
+type Gradebook struct {
+	CourseID      string             `json:"course_id"`
+	GradingScheme *GradingScheme     `json:"grading_scheme"`
+	Categories    []*GradeCategory   `json:"categories"`
+	Columns       []*GradebookColumn `json:"columns"`
+	Students      []*StudentStanding `json:"students"`
+}
+
+// TranscriptCourse is a student's running grade in one of their courses
+type TranscriptCourse struct {
+	CourseID    string       `json:"course_id"`
+	CourseName  string       `json:"course_name"`
+	Percentage  *float64     `json:"percentage"`
+	LetterGrade *LetterGrade `json:"letter_grade,omitempty"`
+}
+
+// Transcript lists a student's grades across their courses. GPA is the average of the GPA points of the
+// courses whose letter grade carries them.
+type Transcript struct {
+	StudentID string              `json:"student_id"`
+	Courses   []*TranscriptCourse `json:"courses"`
+	GPA       *float64            `json:"gpa"`
+}
+
+// StudentAssessmentScore is the score that counts for a student at an assessment under its attempt policy
+type StudentAssessmentScore struct {
+	StudentID    string
+	AssessmentID string
+	Score        float64
+}
+
+// CreateGradeCategoryRequest represents the data needed to add a grade category to a course
+type CreateGradeCategoryRequest struct {
+	Name       string  `json:"name" validate:"required,max=255"`
+	Weight     float64 `json:"weight" validate:"min=0,max=100"`
+	DropLowest int     `json:"drop_lowest" validate:"min=0"`
+}
+
+// UpdateGradeCategoryRequest represents the data needed to update a grade category
+type UpdateGradeCategoryRequest struct {
+	Name       *string  `json:"name" validate:"omitempty,max=255"`
+	Weight     *float64 `json:"weight" validate:"omitempty,min=0,max=100"`
+	DropLowest *int     `json:"drop_lowest" validate:"omitempty,min=0"`
+}
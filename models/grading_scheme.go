@@ -0,0 +1,47 @@
+package models
+
+import (
+	"time"
+)
+
+// GradeBand maps every percentage from MinPercentage up to the next band to a label
+type GradeBand struct {
+	MinPercentage float64  `json:"min_percentage" validate:"min=0,max=100"`
+	Label         string   `json:"label" validate:"required,max=20"`
+	GPAPoints     *float64 `json:"gpa_points" validate:"omitempty,min=0"`
+}
+
+// GradingScheme maps percentages to letter grades. It belongs to either an organization, as the default
+// of all its courses, or to a single course.
+type GradingScheme struct {
+	ID             string      `json:"id"`
+	OrganizationID *string     `json:"organization_id"`
+	CourseID       *string     `json:"course_id"`
+	Name           string      `json:"name"`
+	Bands          []GradeBand `json:"bands"`
+	CreatedAt      time.Time   `json:"created_at"`
+	UpdatedAt      time.Time   `json:"updated_at"`
+}
+
+// LetterGrade is the band of a grading scheme a percentage falls in
+type LetterGrade struct {
+	Label     string   `json:"label"`
+	GPAPoints *float64 `json:"gpa_points,omitempty"`
+}
+
+// Apply returns the letter grade of a percentage. Bands are kept ordered from the highest minimum down,
+// so the first band the percentage reaches is the one it falls in.
+func (s *GradingScheme) Apply(percentage float64) *LetterGrade {
+	for _, band := range s.Bands {
+		if percentage >= band.MinPercentage {
+			return &LetterGrade{Label: band.Label, GPAPoints: band.GPAPoints}
+		}
+	}
+	return nil
+}
+
+// SaveGradingSchemeRequest represents the data needed to define or replace a grading scheme
+type SaveGradingSchemeRequest struct {
+	Name  string      `json:"name" validate:"required,max=255"`
+	Bands []GradeBand `json:"bands" validate:"required,min=1,dive"`
+}
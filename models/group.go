@@ -0,0 +1,80 @@
+package models
+
+import (
+	"time"
+)
+
+// CourseGroup is a group of students within a course that submits group assessments together
+type CourseGroup struct {
+	ID         string         `json:"id"`
+	CourseID   string         `json:"course_id"`
+	Name       string         `json:"name"`
+	MaxSize    *int           `json:"max_size"`
+	SelfSignup bool           `json:"self_signup"`
+	CreatedBy  string         `json:"created_by"`
+	Members    []*GroupMember `json:"members"`
+	CreatedAt  time.Time      `json:"created_at"`
+	UpdatedAt  time.Time      `json:"updated_at"`
+}
+
+// IsFull reports whether the group has reached its size limit
+func (g *CourseGroup) IsFull() bool {
+	return g.MaxSize != nil && len(g.Members) >= *g.MaxSize
+}
+
+// HasMember reports whether the student belongs to the group
+func (g *CourseGroup) HasMember(studentID string) bool {
+	for _, member := range g.Members {
+		if member.StudentID == studentID {
+			return true
+		}
+	}
+	return false
+}
+
+// GroupMember is a student in a course group
+type GroupMember struct {
+	StudentID string    `json:"student_id"`
+	FirstName string    `json:"first_name"`
+	LastName  string    `json:"last_name"`
+	Email     string    `json:"email,omitempty"`
+	JoinedAt  time.Time `json:"joined_at"`
+}
+
+// SubmissionMember is a member of the group that made a group submission, as the group was when it submitted.
+// The submission's grade counts for every member, unless a teacher adjusted it for them.
+type SubmissionMember struct {
+	SubmissionID     string     `json:"submission_id"`
+	StudentID        string     `json:"student_id"`
+	Pseudonym        string     `json:"pseudonym,omitempty"` // Set in place of the student while grading is anonymous
+	AdjustedScore    *float64   `json:"adjusted_score"`
+	AdjustmentReason string     `json:"adjustment_reason"`
+	AdjustedBy       *string    `json:"adjusted_by"`
+	AdjustedAt       *time.Time `json:"adjusted_at"`
+}
+
+// CreateGroupRequest represents the data needed to create a course group
+type CreateGroupRequest struct {
+	Name       string `json:"name" validate:"required,min=1,max=255"`
+	MaxSize    *int   `json:"max_size" validate:"omitempty,min=1"`
+	SelfSignup bool   `json:"self_signup"`
+}
+
+// UpdateGroupRequest represents the data needed to update a course group
+type UpdateGroupRequest struct {
+	Name       *string `json:"name" validate:"omitempty,min=1,max=255"`
+	MaxSize    *int    `json:"max_size" validate:"omitempty,min=1"`
+	SelfSignup *bool   `json:"self_signup"`
+}
+
+// AddGroupMemberRequest represents a teacher placing a student in a group
+type AddGroupMemberRequest struct {
+	StudentID string `json:"student_id" validate:"required"`
+}
+
+// AdjustMemberGradeRequest represents a teacher overriding the grade of a group submission for one member.
+// Score replaces the final score the member gets, late penalty included.
+type AdjustMemberGradeRequest struct {
+	Score  *float64 `json:"score" validate:"required,min=0"`
+	Reason string   `json:"reason" validate:"required,max=1000"`
+}
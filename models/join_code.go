@@ -0,0 +1,49 @@
+package models
+
+import (
+	"time"
+)
+
+// JoinCode lets students enroll in a course, into a section if it names one, even when the course's enrollment
+// is closed. Codes work until they expire, are used up or are revoked.
+type JoinCode struct {
+	ID        string     `json:"id"`
+	CourseID  string     `json:"course_id"`
+	SectionID *string    `json:"section_id"`
+	Code      string     `json:"code"`
+	CreatedBy *string    `json:"created_by"`
+	ExpiresAt time.Time  `json:"expires_at"`
+	MaxUses   int        `json:"max_uses"`
+	Uses      int        `json:"uses"`
+	RevokedAt *time.Time `json:"revoked_at"`
+	CreatedAt time.Time  `json:"created_at"`
+	// InviteLink is a signed link that enrolls the student who opens it, in place of typing the code
+	InviteLink string `json:"invite_link,omitempty"`
+}
+
+// UnusableReason explains why a join code can no longer be used, or returns an empty reason when it can
+func (c *JoinCode) UnusableReason(now time.Time) string {
+	switch {
+	case c.RevokedAt != nil:
+		return "join code has been revoked"
+	case !now.Before(c.ExpiresAt):
+		return "join code has expired"
+	case c.Uses >= c.MaxUses:
+		return "join code has been used up"
+	}
+	return ""
+}
+
+// CreateJoinCodeRequest represents the data needed to create a join code for a course
+type CreateJoinCodeRequest struct {
+	SectionID *string   `json:"section_id"`
+	ExpiresAt time.Time `json:"expires_at" validate:"required"`
+	MaxUses   int       `json:"max_uses" validate:"required,min=1"`
+}
+
+// JoinCourseRequest represents the data needed to enroll in a course with a join code. The section is only
+// needed when the course has sections and the code does not name one.
+type JoinCourseRequest struct {
+	Code      string  `json:"code" validate:"required,max=16"`
+	SectionID *string `json:"section_id"`
+}
@@ -0,0 +1,117 @@
+package models
+
+import (
+	"errors"
+	"math"
+	"time"
+)
+
+// ModerationStatus is the state of the double marking of a submission
+type ModerationStatus string
+
+const (
+	// ModerationStatusMarking waits for one or both marks
+	ModerationStatusMarking ModerationStatus = "marking"
+	// ModerationStatusReady has both marks within the assessment's threshold of each other
+	ModerationStatusReady ModerationStatus = "ready"
+	// ModerationStatusFlagged has marks further apart than the assessment's threshold
+	ModerationStatusFlagged ModerationStatus = "flagged"
+	// ModerationStatusAgreed has the final mark set by a moderator
+	ModerationStatusAgreed ModerationStatus = "agreed"
+)
+
+// Moderation is the double marking of a submission. The first and second marker mark independently, scores are
+// raw scores before any late penalty, and a moderator agrees the final mark that becomes the grade.
+type Moderation struct {
+	SubmissionID   string           `json:"submission_id"`
+	AssessmentID   string           `json:"assessment_id"`
+	FirstMarkerID  *string          `json:"first_marker_id"`
+	FirstScore     *float64         `json:"first_score"`
+	FirstFeedback  string           `json:"first_feedback"`
+	SecondMarkerID string           `json:"second_marker_id"`
+	SecondScore    *float64         `json:"second_score"`
+	SecondFeedback string           `json:"second_feedback"`
+	Discrepancy    *float64         `json:"discrepancy"`
+	Status         ModerationStatus `json:"status"`
+	ModeratorID    *string          `json:"moderator_id"`
+	FinalScore     *float64         `json:"final_score"`
+	AgreedAt       *time.Time       `json:"agreed_at"`
+	AssignedBy     string           `json:"assigned_by"`
+	CreatedAt      time.Time        `json:"created_at"`
+	UpdatedAt      time.Time        `json:"updated_at"`
+}
+
+// Evaluate computes the discrepancy between the two marks and, until the final mark is agreed, the status
+// that follows from it
+func (m *Moderation) Evaluate(threshold float64) {
+	m.Discrepancy = nil
+	if m.FirstScore != nil && m.SecondScore != nil {
+		discrepancy := math.Abs(*m.FirstScore - *m.SecondScore)
+		m.Discrepancy = &discrepancy
+	}
+
+	if m.Status == ModerationStatusAgreed {
+		return
+	}
+
+	switch {
+	case m.Discrepancy == nil:
+		m.Status = ModerationStatusMarking
+	case *m.Discrepancy > threshold:
+		m.Status = ModerationStatusFlagged
+	default:
+		m.Status = ModerationStatusReady
+	}
+}
+
+// RecordMark records a teacher's raw score and feedback as the second marker's mark, or as the first marker's. The
+// first teacher other than the second marker to mark becomes the first marker; after that, only the two markers
+// can mark the submission, each revising their own mark until the final mark is agreed.
+func (m *Moderation) RecordMark(teacherID string, score float64, feedback string) error {
+	switch {
+	case m.Status == ModerationStatusAgreed:
+		return errors.New("the final mark of this submission is already agreed")
+	case teacherID == m.SecondMarkerID:
+		m.SecondScore = &score
+		m.SecondFeedback = feedback
+	case m.FirstMarkerID == nil || *m.FirstMarkerID == teacherID:
+		m.FirstMarkerID = &teacherID
+		m.FirstScore = &score
+		m.FirstFeedback = feedback
+	default:
+		return errors.New("only the first and second marker can mark this submission")
+	}
+	return nil
+}
+
+// HideMarksFrom clears the marks a teacher may not see yet: while marking is in progress, each marker sees only
+// their own mark
+func (m *Moderation) HideMarksFrom(teacherID string) {
+	if m.Status != ModerationStatusMarking {
+		return
+	}
+
+	if m.FirstMarkerID == nil || *m.FirstMarkerID != teacherID {
+		m.FirstScore = nil
+		m.FirstFeedback = ""
+	}
+	if m.SecondMarkerID != teacherID {
+		m.SecondScore = nil
+		m.SecondFeedback = ""
+	}
+}
+
+// AssignSecondMarkerRequest represents the data needed to put a submission up for double marking
+type AssignSecondMarkerRequest struct {
+	SecondMarkerID string `json:"second_marker_id" validate:"required"`
+}
+
+// AgreeMarkRequest represents the data needed to set the agreed final mark of a double-marked submission.
+// Score is the raw score, the late penalty is applied to it. Assessments with a rubric take a level per criterion
+// instead of a score.
+type AgreeMarkRequest struct {
+	Score    *float64                 `json:"score" validate:"required_without=Rubric,omitempty,min=0"`
+	Rubric   []RubricSelectionRequest `json:"rubric" validate:"omitempty,dive"`
+	Feedback string                   `json:"feedback"`
+	Reason   string                   `json:"reason" validate:"max=1000"`
+}
@@ -0,0 +1,99 @@
+package models
+
+import "testing"
+
+func TestModerationRecordMark(t *testing.T) {
+	moderation := &Moderation{SecondMarkerID: "second", Status: ModerationStatusMarking}
+
+	if err := moderation.RecordMark("first", 70, "Solid"); err != nil {
+		t.Fatalf("the first teacher to mark should become the first marker: %v", err)
+	}
+	if moderation.FirstMarkerID == nil || *moderation.FirstMarkerID != "first" || *moderation.FirstScore != 70 {
+		t.Fatalf("first mark not recorded: %+v", moderation)
+	}
+
+	// Neither a third teacher nor the moderator can replace the first mark
+	for _, teacherID := range []string{"third", "moderator"} {
+		if err := moderation.RecordMark(teacherID, 10, ""); err == nil {
+			t.Fatalf("%s replaced the first mark", teacherID)
+		}
+	}
+	if *moderation.FirstMarkerID != "first" || *moderation.FirstScore != 70 {
+		t.Fatalf("a refused mark changed the first mark: %+v", moderation)
+	}
+
+	if err := moderation.RecordMark("second", 80, "Good"); err != nil {
+		t.Fatalf("the second marker could not mark: %v", err)
+	}
+	if *moderation.SecondScore != 80 || *moderation.FirstScore != 70 {
+		t.Fatalf("second mark not recorded apart from the first: %+v", moderation)
+	}
+
+	// Each marker revises their own mark only
+	if err := moderation.RecordMark("first", 75, "Revised"); err != nil {
+		t.Fatalf("the first marker could not revise their mark: %v", err)
+	}
+	if *moderation.FirstScore != 75 || *moderation.SecondScore != 80 || moderation.FirstFeedback != "Revised" {
+		t.Fatalf("revision not recorded: %+v", moderation)
+	}
+
+	moderation.Status = ModerationStatusAgreed
+	if err := moderation.RecordMark("second", 90, ""); err == nil {
+		t.Fatalf("a mark was recorded after the final mark was agreed")
+	}
+}
+
+func TestModerationEvaluate(t *testing.T) {
+	first, second := 70.0, 80.0
+	tests := []struct {
+		name      string
+		first     *float64
+		second    *float64
+		status    ModerationStatus
+		threshold float64
+		want      ModerationStatus
+	}{
+		{"one mark", &first, nil, ModerationStatusMarking, 5, ModerationStatusMarking},
+		{"within threshold", &first, &second, ModerationStatusMarking, 10, ModerationStatusReady},
+		{"beyond threshold", &first, &second, ModerationStatusMarking, 5, ModerationStatusFlagged},
+		{"revised back within threshold", &first, &second, ModerationStatusFlagged, 15, ModerationStatusReady},
+		{"agreed stays agreed", &first, &second, ModerationStatusAgreed, 5, ModerationStatusAgreed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			moderation := &Moderation{FirstScore: tt.first, SecondScore: tt.second, Status: tt.status}
+			moderation.Evaluate(tt.threshold)
+			if moderation.Status != tt.want {
+				t.Fatalf("status = %s, want %s", moderation.Status, tt.want)
+			}
+		})
+	}
+}
+
+func TestModerationHideMarksFrom(t *testing.T) {
+	first, second := 70.0, 80.0
+	firstMarker := "first"
+	newModeration := func(status ModerationStatus) *Moderation {
+		return &Moderation{FirstMarkerID: &firstMarker, FirstScore: &first, SecondMarkerID: "second",
+			SecondScore: &second, Status: status}
+	}
+
+	moderation := newModeration(ModerationStatusMarking)
+	moderation.HideMarksFrom("first")
+	if moderation.FirstScore == nil || moderation.SecondScore != nil {
+		t.Fatalf("the first marker should only see their own mark while marking: %+v", moderation)
+	}
+
+	moderation = newModeration(ModerationStatusMarking)
+	moderation.HideMarksFrom("moderator")
+	if moderation.FirstScore != nil || moderation.SecondScore != nil {
+		t.Fatalf("others should see no marks while marking: %+v", moderation)
+	}
+
+	moderation = newModeration(ModerationStatusFlagged)
+	moderation.HideMarksFrom("moderator")
+	if moderation.FirstScore == nil || moderation.SecondScore == nil {
+		t.Fatalf("both marks should be shown once they are in: %+v", moderation)
+	}
+}
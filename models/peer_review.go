@@ -0,0 +1,91 @@
+package models
+
+import "time"
+
+// PeerReview is a student's review of another student's submission during an assessment's peer-review phase.
+// Reviewers stay anonymous to the author: the author never sees ReviewerID.
+type PeerReview struct {
+	ID           string                `json:"id"`
+	SubmissionID string                `json:"submission_id"`
+	AssessmentID string                `json:"assessment_id"`
+	ReviewerID   string                `json:"reviewer_id,omitempty"`
+	Score        *float64              `json:"score"`
+	Comments     string                `json:"comments"`
+	Rubric       []*RubricScore        `json:"rubric,omitempty"`
+	SubmittedAt  *time.Time            `json:"submitted_at"`
+	CreatedAt    time.Time             `json:"created_at"`
+	Submission   *AssessmentSubmission `json:"submission,omitempty"` // The work to review, without its author, as the reviewer sees it
+}
+
+// IsSubmitted reports whether the reviewer has handed in the review
+func (r *PeerReview) IsSubmitted() bool {
+	return r.SubmittedAt != nil
+}
+
+// PeerReviewAssignment pairs a submission with a student who reviews it
+type PeerReviewAssignment struct {
+	SubmissionID string
+	ReviewerID   string
+}
+
+// PeerReviewSummary aggregates the peer reviews of one submission for teachers
+type PeerReviewSummary struct {
+	SubmissionID    string        `json:"submission_id"`
+	StudentID       string        `json:"student_id,omitempty"`
+	Pseudonym       string        `json:"pseudonym,omitempty"`
+	ReviewsAssigned int           `json:"reviews_assigned"`
+	ReviewsDone     int           `json:"reviews_done"`
+	AverageScore    *float64      `json:"average_score"`
+	MinScore        *float64      `json:"min_score"`
+	MaxScore        *float64      `json:"max_score"`
+	Reviews         []*PeerReview `json:"reviews"`
+}
+
+// Summarize computes the review counts and the spread of the submitted peer scores
+func (s *PeerReviewSummary) Summarize() {
+	s.ReviewsAssigned = len(s.Reviews)
+	s.ReviewsDone = 0
+	s.AverageScore, s.MinScore, s.MaxScore = nil, nil, nil
+
+	var total float64
+	for _, review := range s.Reviews {
+		if !review.IsSubmitted() || review.Score == nil {
+			continue
+		}
+		score := *review.Score
+		s.ReviewsDone++
+		total += score
+		if s.MinScore == nil || score < *s.MinScore {
+			s.MinScore = &score
+		}
+		if s.MaxScore == nil || score > *s.MaxScore {
+			s.MaxScore = &score
+		}
+	}
+
+	if s.ReviewsDone > 0 {
+		average := total / float64(s.ReviewsDone)
+		s.AverageScore = &average
+	}
+}
+
+// SubmitPeerReviewRequest represents a peer review. Assessments with a rubric are reviewed by choosing a level
+// per criterion, the others with a score.
+type SubmitPeerReviewRequest struct {
+	Score    *float64                 `json:"score" validate:"required_without=Rubric,omitempty,min=0"`
+	Comments string                   `json:"comments" validate:"max=5000"`
+	Rubric   []RubricSelectionRequest `json:"rubric" validate:"omitempty,dive"`
+}
+
+// ApplyPeerScoresRequest represents folding the peer scores of an assessment into its grades. Weight is the
+// share of the peer average in the final raw score, in percent.
+type ApplyPeerScoresRequest struct {
+	Weight float64 `json:"weight" validate:"gt=0,max=100"`
+}
+
+// PeerScoresResult reports the grades that changed when peer scores were folded in
+type PeerScoresResult struct {
+	Weight  float64  `json:"weight"`
+	Graded  int      `json:"graded"`
+	Skipped []string `json:"skipped_submissions"`
+}
@@ -0,0 +1,37 @@
+package models
+
+import (
+	"time"
+)
+
+// PrerequisiteMode defines how the prerequisites of a course combine
+type PrerequisiteMode string
+
+const (
+	// PrerequisiteModeAll requires every prerequisite to be met
+	PrerequisiteModeAll PrerequisiteMode = "all"
+	// PrerequisiteModeAny requires at least one prerequisite to be met
+	PrerequisiteModeAny PrerequisiteMode = "any"
+)
+
+// CoursePrerequisite is a course that must be passed with a minimum final grade before enrolling in another course.
+// The grade counts once the required course's term has ended.
+type CoursePrerequisite struct {
+	CourseID           string    `json:"course_id"`
+	RequiredCourseID   string    `json:"required_course_id"`
+	RequiredCourseName string    `json:"required_course_name"`
+	MinGrade           float64   `json:"min_grade"` // Percentage
+	CreatedAt          time.Time `json:"created_at"`
+}
+
+// PrerequisiteRule represents one required course in a request to set the prerequisites of a course
+type PrerequisiteRule struct {
+	RequiredCourseID string  `json:"required_course_id" validate:"required"`
+	MinGrade         float64 `json:"min_grade" validate:"min=0,max=100"`
+}
+
+// SetPrerequisitesRequest represents the data needed to replace the prerequisites of a course
+type SetPrerequisitesRequest struct {
+	Mode          PrerequisiteMode   `json:"mode" validate:"required,oneof=all any"`
+	Prerequisites []PrerequisiteRule `json:"prerequisites" validate:"dive"`
+}
@@ -0,0 +1,97 @@
+package models
+
+import (
+	"time"
+)
+
+// QuestionType represents the kind of question inside an assessment
+type QuestionType string
+
+const (
+	QuestionTypeMultipleChoice QuestionType = "multiple_choice"
+	QuestionTypeMultiSelect    QuestionType = "multi_select"
+	QuestionTypeTrueFalse      QuestionType = "true_false"
+	QuestionTypeNumeric        QuestionType = "numeric"
+	QuestionTypeShortText      QuestionType = "short_text"
+)
+
+// QuestionAnswer holds an answer to a question. Only the field matching the question type is used:
+// choice for multiple choice, choices for multi-select, boolean for true/false, number for numeric
+// and text for short text questions.
+type QuestionAnswer struct {
+	Choice  *int     `json:"choice,omitempty"`
+	Choices []int    `json:"choices,omitempty"`
+	Boolean *bool    `json:"boolean,omitempty"`
+	Number  *float64 `json:"number,omitempty"`
+	Text    *string  `json:"text,omitempty"`
+}
+
+// Question represents a single auto-gradable question within an assessment
+type Question struct {
+	ID            string          `json:"id"`
+	AssessmentID  string          `json:"assessment_id"`
+	Position      int             `json:"position"`
+	Type          QuestionType    `json:"type"`
+	Prompt        string          `json:"prompt"`
+	Options       []string        `json:"options,omitempty"`
+	Points        float64         `json:"points"`
+	CorrectAnswer *QuestionAnswer `json:"correct_answer,omitempty"`
+	Tolerance     float64         `json:"tolerance,omitempty"`
+	CaseSensitive bool            `json:"case_sensitive,omitempty"`
+	FromBank      bool            `json:"from_bank,omitempty"`
+	CreatedAt     time.Time       `json:"created_at"`
+	UpdatedAt     time.Time       `json:"updated_at"`
+}
+
+// WithoutAnswer returns a copy of the question with the answer key removed, safe to show to students
+func (q *Question) WithoutAnswer() *Question {
+	redacted := *q
+	redacted.CorrectAnswer = nil
+	redacted.Tolerance = 0
+	redacted.CaseSensitive = false
+	return &redacted
+}
+
+// SubmissionAnswer represents a student's answer to a single question and its automatic score
+type SubmissionAnswer struct {
+	SubmissionID  string          `json:"submission_id"`
+	QuestionID    string          `json:"question_id"`
+	Position      int             `json:"position"`
+	Answer        *QuestionAnswer `json:"answer"`
+	IsCorrect     bool            `json:"is_correct"`
+	PointsAwarded float64         `json:"points_awarded"`
+}
+
+// CreateQuestionRequest represents the data needed to add a question to an assessment
+type CreateQuestionRequest struct {
+	Type          QuestionType    `json:"type" validate:"required,oneof=multiple_choice multi_select true_false numeric short_text"`
+	Prompt        string          `json:"prompt" validate:"required"`
+	Options       []string        `json:"options"`
+	Points        float64         `json:"points" validate:"required,gt=0"`
+	CorrectAnswer *QuestionAnswer `json:"correct_answer" validate:"required"`
+	Tolerance     float64         `json:"tolerance" validate:"min=0"`
+	CaseSensitive bool            `json:"case_sensitive"`
+	Position      *int            `json:"position" validate:"omitempty,min=1"`
+}
+
+// UpdateQuestionRequest represents the data needed to update a question
+type UpdateQuestionRequest struct {
+	Type          *QuestionType   `json:"type" validate:"omitempty,oneof=multiple_choice multi_select true_false numeric short_text"`
+	Prompt        *string         `json:"prompt" validate:"omitempty,min=1"`
+	Options       []string        `json:"options"`
+	Points        *float64        `json:"points" validate:"omitempty,gt=0"`
+	CorrectAnswer *QuestionAnswer `json:"correct_answer"`
+	Tolerance     *float64        `json:"tolerance" validate:"omitempty,min=0"`
+	CaseSensitive *bool           `json:"case_sensitive"`
+}
+
+// ReorderQuestionsRequest represents the new order of an assessment's questions
+type ReorderQuestionsRequest struct {
+	QuestionIDs []string `json:"question_ids" validate:"required,min=1"`
+}
+
+// SubmitAnswerRequest represents a student's answer to one question in a submission
+type SubmitAnswerRequest struct {
+	QuestionID string          `json:"question_id" validate:"required"`
+	Answer     *QuestionAnswer `json:"answer"`
+}
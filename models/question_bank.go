@@ -0,0 +1,104 @@
+package models
+
+import (
+	"time"
+)
+
+// QuestionDifficulty represents how hard a bank question is
+type QuestionDifficulty string
+
+const (
+	DifficultyEasy   QuestionDifficulty = "easy"
+	DifficultyMedium QuestionDifficulty = "medium"
+	DifficultyHard   QuestionDifficulty = "hard"
+)
+
+// BankQuestion represents a reusable question in a course's question bank
+type BankQuestion struct {
+	ID            string             `json:"id"`
+	CourseID      string             `json:"course_id"`
+	CreatedBy     string             `json:"created_by"`
+	Type          QuestionType       `json:"type"`
+	Prompt        string             `json:"prompt"`
+	Options       []string           `json:"options,omitempty"`
+	Points        float64            `json:"points"`
+	CorrectAnswer *QuestionAnswer    `json:"correct_answer,omitempty"`
+	Tolerance     float64            `json:"tolerance,omitempty"`
+	CaseSensitive bool               `json:"case_sensitive,omitempty"`
+	Tags          []string           `json:"tags"`
+	Difficulty    QuestionDifficulty `json:"difficulty"`
+	CreatedAt     time.Time          `json:"created_at"`
+	UpdatedAt     time.Time          `json:"updated_at"`
+}
+
+// AsQuestion converts a bank question into an assessment question at the given position and point value
+func (q *BankQuestion) AsQuestion(assessmentID string, position int, points float64) *Question {
+	return &Question{
+		ID:            q.ID,
+		AssessmentID:  assessmentID,
+		Position:      position,
+		Type:          q.Type,
+		Prompt:        q.Prompt,
+		Options:       q.Options,
+		Points:        points,
+		CorrectAnswer: q.CorrectAnswer,
+		Tolerance:     q.Tolerance,
+		CaseSensitive: q.CaseSensitive,
+		FromBank:      true,
+		CreatedAt:     q.CreatedAt,
+		UpdatedAt:     q.UpdatedAt,
+	}
+}
+
+// QuestionDrawRule describes how many random bank questions an assessment draws for each student
+type QuestionDrawRule struct {
+	ID                string              `json:"id"`
+	AssessmentID      string              `json:"assessment_id"`
+	Tags              []string            `json:"tags"`
+	Difficulty        *QuestionDifficulty `json:"difficulty,omitempty"`
+	QuestionCount     int                 `json:"question_count"`
+	PointsPerQuestion *float64            `json:"points_per_question,omitempty"`
+	CreatedAt         time.Time           `json:"created_at"`
+}
+
+// DrawnQuestion represents one bank question drawn for a student
+type DrawnQuestion struct {
+	DrawID     string  `json:"draw_id"`
+	QuestionID string  `json:"question_id"`
+	Position   int     `json:"position"`
+	Points     float64 `json:"points"`
+}
+
+// CreateBankQuestionRequest represents the data needed to add a question to a course's bank
+type CreateBankQuestionRequest struct {
+	Type          QuestionType       `json:"type" validate:"required,oneof=multiple_choice multi_select true_false numeric short_text"`
+	Prompt        string             `json:"prompt" validate:"required"`
+	Options       []string           `json:"options"`
+	Points        float64            `json:"points" validate:"required,gt=0"`
+	CorrectAnswer *QuestionAnswer    `json:"correct_answer" validate:"required"`
+	Tolerance     float64            `json:"tolerance" validate:"min=0"`
+	CaseSensitive bool               `json:"case_sensitive"`
+	Tags          []string           `json:"tags" validate:"omitempty,dive,required,max=50"`
+	Difficulty    QuestionDifficulty `json:"difficulty" validate:"omitempty,oneof=easy medium hard"`
+}
+
+// UpdateBankQuestionRequest represents the data needed to update a bank question
+type UpdateBankQuestionRequest struct {
+	Type          *QuestionType       `json:"type" validate:"omitempty,oneof=multiple_choice multi_select true_false numeric short_text"`
+	Prompt        *string             `json:"prompt" validate:"omitempty,min=1"`
+	Options       []string            `json:"options"`
+	Points        *float64            `json:"points" validate:"omitempty,gt=0"`
+	CorrectAnswer *QuestionAnswer     `json:"correct_answer"`
+	Tolerance     *float64            `json:"tolerance" validate:"omitempty,min=0"`
+	CaseSensitive *bool               `json:"case_sensitive"`
+	Tags          []string            `json:"tags" validate:"omitempty,dive,required,max=50"`
+	Difficulty    *QuestionDifficulty `json:"difficulty" validate:"omitempty,oneof=easy medium hard"`
+}
+
+// CreateDrawRuleRequest represents the data needed to draw random bank questions into an assessment
+type CreateDrawRuleRequest struct {
+	Tags              []string            `json:"tags" validate:"omitempty,dive,required"`
+	Difficulty        *QuestionDifficulty `json:"difficulty" validate:"omitempty,oneof=easy medium hard"`
+	QuestionCount     int                 `json:"question_count" validate:"required,min=1"`
+	PointsPerQuestion *float64            `json:"points_per_question" validate:"omitempty,gt=0"`
+}
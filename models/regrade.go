@@ -0,0 +1,55 @@
+package models
+
+import (
+	"time"
+)
+
+// RegradeStatus is the state of a regrade request
+type RegradeStatus string
+
+const (
+	RegradeStatusOpen     RegradeStatus = "open"
+	RegradeStatusAccepted RegradeStatus = "accepted"
+	RegradeStatusRejected RegradeStatus = "rejected"
+)
+
+// RegradeRequest is a student's dispute of the grade of a submission, optionally about a single rubric
+// criterion or question. Once resolved it records the score before and, when accepted, after the regrade.
+type RegradeRequest struct {
+	ID            string        `json:"id"`
+	SubmissionID  string        `json:"submission_id"`
+	AssessmentID  string        `json:"assessment_id"`
+	StudentID     string        `json:"student_id"`
+	CriterionID   *string       `json:"criterion_id"`
+	QuestionID    *string       `json:"question_id"`
+	Reason        string        `json:"reason"`
+	Status        RegradeStatus `json:"status"`
+	PreviousScore *float64      `json:"previous_score"`
+	NewScore      *float64      `json:"new_score"`
+	Response      string        `json:"response"`
+	ResolvedBy    *string       `json:"resolved_by"`
+	ResolvedAt    *time.Time    `json:"resolved_at"`
+	CreatedAt     time.Time     `json:"created_at"`
+	UpdatedAt     time.Time     `json:"updated_at"`
+}
+
+// CreateRegradeRequest represents the data needed to dispute a grade
+type CreateRegradeRequest struct {
+	Reason      string  `json:"reason" validate:"required,max=5000"`
+	CriterionID *string `json:"criterion_id"`
+	QuestionID  *string `json:"question_id"`
+}
+
+// AcceptRegradeRequest represents the data needed to accept a regrade request. Score is the new score
+// before any late penalty, which is applied again. Assessments graded with a rubric are rescored with a level
+// for every criterion instead.
+type AcceptRegradeRequest struct {
+	Score    *float64                 `json:"score" validate:"required_without=Rubric,omitempty,min=0"`
+	Rubric   []RubricSelectionRequest `json:"rubric" validate:"omitempty,dive"`
+	Response string                   `json:"response" validate:"max=5000"`
+}
+
+// RejectRegradeRequest represents the data needed to reject a regrade request
+type RejectRegradeRequest struct {
+	Response string `json:"response" validate:"required,max=5000"`
+}
@@ -0,0 +1,121 @@
+package models
+
+import (
+	"time"
+)
+
+// Rubric represents a reusable grading rubric of a course
+type Rubric struct {
+	ID          string             `json:"id"`
+	CourseID    string             `json:"course_id"`
+	CreatedBy   string             `json:"created_by"`
+	Title       string             `json:"title"`
+	Description string             `json:"description"`
+	Criteria    []*RubricCriterion `json:"criteria"`
+	CreatedAt   time.Time          `json:"created_at"`
+	UpdatedAt   time.Time          `json:"updated_at"`
+}
+
+// MaxPoints returns the points a submission earns with the best level of every criterion
+func (r *Rubric) MaxPoints() float64 {
+	var total float64
+	for _, criterion := range r.Criteria {
+		total += criterion.MaxPoints()
+	}
+	return total
+}
+
+// RubricCriterion represents one criterion of a rubric with its performance levels
+type RubricCriterion struct {
+	ID          string         `json:"id"`
+	RubricID    string         `json:"rubric_id"`
+	Position    int            `json:"position"`
+	Title       string         `json:"title"`
+	Description string         `json:"description"`
+	Levels      []*RubricLevel `json:"levels"`
+}
+
+// MaxPoints returns the points of the criterion's best level
+func (c *RubricCriterion) MaxPoints() float64 {
+	var best float64
+	for _, level := range c.Levels {
+		if level.Points > best {
+			best = level.Points
+		}
+	}
+	return best
+}
+
+// RubricLevel represents a performance level of a rubric criterion
+type RubricLevel struct {
+	ID          string  `json:"id"`
+	CriterionID string  `json:"criterion_id"`
+	Position    int     `json:"position"`
+	Title       string  `json:"title"`
+	Descriptor  string  `json:"descriptor"`
+	Points      float64 `json:"points"`
+}
+
+// RubricScore represents the level chosen for one criterion when a submission was graded
+type RubricScore struct {
+	CriterionID    string  `json:"criterion_id"`
+	CriterionTitle string  `json:"criterion_title"`
+	LevelID        string  `json:"level_id"`
+	LevelTitle     string  `json:"level_title"`
+	Descriptor     string  `json:"descriptor"`
+	Points         float64 `json:"points"`
+	MaxPoints      float64 `json:"max_points"`
+	Comment        string  `json:"comment,omitempty"`
+}
+
+// CriterionStats summarizes the scores given for one rubric criterion
+type CriterionStats struct {
+	RubricID       string  `json:"rubric_id"`
+	RubricTitle    string  `json:"rubric_title"`
+	CriterionID    string  `json:"criterion_id"`
+	CriterionTitle string  `json:"criterion_title"`
+	MaxPoints      float64 `json:"max_points"`
+	AveragePoints  float64 `json:"average_points"`
+	GradedCount    int     `json:"graded_count"`
+}
+
+// RubricLevelRequest represents a performance level in a rubric request
+type RubricLevelRequest struct {
+	Title      string  `json:"title" validate:"required,max=255"`
+	Descriptor string  `json:"descriptor"`
+	Points     float64 `json:"points" validate:"min=0"`
+}
+
+// RubricCriterionRequest represents a criterion in a rubric request
+type RubricCriterionRequest struct {
+	Title       string               `json:"title" validate:"required,max=255"`
+	Description string               `json:"description"`
+	Levels      []RubricLevelRequest `json:"levels" validate:"required,min=2,dive"`
+}
+
+// CreateRubricRequest represents the data needed to create a rubric
+type CreateRubricRequest struct {
+	Title       string                   `json:"title" validate:"required,min=3,max=255"`
+	Description string                   `json:"description"`
+	Criteria    []RubricCriterionRequest `json:"criteria" validate:"required,min=1,dive"`
+}
+
+// UpdateRubricRequest represents the data needed to update a rubric. Criteria, when given,
+// replace the existing criteria entirely.
+type UpdateRubricRequest struct {
+	Title       *string                  `json:"title" validate:"omitempty,min=3,max=255"`
+	Description *string                  `json:"description"`
+	Criteria    []RubricCriterionRequest `json:"criteria" validate:"omitempty,min=1,dive"`
+}
+
+// AttachRubricRequest represents the rubric to grade an assessment with
+type AttachRubricRequest struct {
+	RubricID string `json:"rubric_id" validate:"required"`
+}
+
+// RubricSelectionRequest represents the level chosen for a criterion when grading
+type RubricSelectionRequest struct {
+	CriterionID string `json:"criterion_id" validate:"required"`
+	LevelID     string `json:"level_id" validate:"required"`
+	Comment     string `json:"comment"`
+}
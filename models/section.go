@@ -0,0 +1,32 @@
+package models
+
+import (
+	"time"
+)
+
+// CourseSection is a section of a course. Students enroll into one section, and teachers assigned to sections only
+// see and grade the work of their sections' students.
+type CourseSection struct {
+	ID           string    `json:"id"`
+	CourseID     string    `json:"course_id"`
+	Name         string    `json:"name"`
+	Teachers     []*User   `json:"teachers"`
+	StudentCount int       `json:"student_count"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// CreateSectionRequest represents the data needed to create a section in a course
+type CreateSectionRequest struct {
+	Name string `json:"name" validate:"required,min=1,max=255"`
+}
+
+// UpdateSectionRequest represents the data needed to rename a section
+type UpdateSectionRequest struct {
+	Name *string `json:"name" validate:"omitempty,min=1,max=255"`
+}
+
+// ChangeSectionRequest represents the data needed to move an enrolled student to another section
+type ChangeSectionRequest struct {
+	SectionID string `json:"section_id" validate:"required"`
+}
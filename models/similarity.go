@@ -0,0 +1,64 @@
+package models
+
+import "time"
+
+// SimilarityCheckStatus is the state of a similarity check
+type SimilarityCheckStatus string
+
+const (
+	// SimilarityCheckRunning is still comparing submissions
+	SimilarityCheckRunning SimilarityCheckStatus = "running"
+	// SimilarityCheckCompleted has its matches ready
+	SimilarityCheckCompleted SimilarityCheckStatus = "completed"
+	// SimilarityCheckFailed stopped with an error
+	SimilarityCheckFailed SimilarityCheckStatus = "failed"
+)
+
+// SimilarityCheck compares the submissions of an assessment with each other, and optionally with earlier submissions
+// to the same course, for copied work. Checks run in the background.
+type SimilarityCheck struct {
+	ID                 string                `json:"id"`
+	AssessmentID       string                `json:"assessment_id"`
+	IncludePrevious    bool                  `json:"include_previous"`
+	Threshold          float64               `json:"threshold"`
+	Status             SimilarityCheckStatus `json:"status"`
+	Error              string                `json:"error,omitempty"`
+	SubmissionsChecked int                   `json:"submissions_checked"`
+	RequestedBy        string                `json:"requested_by"`
+	CreatedAt          time.Time             `json:"created_at"`
+	CompletedAt        *time.Time            `json:"completed_at"`
+	Matches            []*SimilarityMatch    `json:"matches,omitempty"`
+}
+
+// SimilarityMatch is a pair of submissions whose texts share enough fingerprints. Similarity is the share of the
+// shorter text's fingerprints found in the other text, in percent.
+type SimilarityMatch struct {
+	ID                string              `json:"id"`
+	CheckID           string              `json:"check_id"`
+	SubmissionID      string              `json:"submission_id"`
+	StudentID         string              `json:"student_id,omitempty"`
+	Pseudonym         string              `json:"pseudonym,omitempty"`
+	OtherSubmissionID string              `json:"other_submission_id"`
+	OtherAssessmentID string              `json:"other_assessment_id"`
+	OtherStudentID    string              `json:"other_student_id,omitempty"`
+	OtherPseudonym    string              `json:"other_pseudonym,omitempty"`
+	Similarity        float64             `json:"similarity"`
+	Regions           []*SimilarityRegion `json:"regions"`
+	Text              string              `json:"text,omitempty"`       // The compared text of the submission, in the report
+	OtherText         string              `json:"other_text,omitempty"` // The compared text of the other submission, in the report
+}
+
+// SimilarityRegion is a stretch of text found in both submissions of a match, as character offsets into each
+// submission's compared text. End offsets are exclusive.
+type SimilarityRegion struct {
+	Start      int `json:"start"`
+	End        int `json:"end"`
+	OtherStart int `json:"other_start"`
+	OtherEnd   int `json:"other_end"`
+}
+
+// StartSimilarityCheckRequest represents starting a similarity check. Pairs below Threshold percent are not reported.
+type StartSimilarityCheckRequest struct {
+	IncludePrevious bool     `json:"include_previous"`
+	Threshold       *float64 `json:"threshold" validate:"omitempty,gt=0,max=100"`
+}
@@ -0,0 +1,39 @@
+package models
+
+import "time"
+
+// Term is an academic term of an organization. Courses are offered in a term, and once it has ended they become
+// read-only archives.
+type Term struct {
+	ID             string    `json:"id"`
+	OrganizationID string    `json:"organization_id"`
+	Name           string    `json:"name"`
+	StartDate      time.Time `json:"start_date"`
+	EndDate        time.Time `json:"end_date"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+// Contains reports whether a point in time falls within the term
+func (t *Term) Contains(at time.Time) bool {
+	return !at.Before(t.StartDate) && !at.After(t.EndDate)
+}
+
+// HasEnded reports whether the term is over
+func (t *Term) HasEnded(now time.Time) bool {
+	return now.After(t.EndDate)
+}
+
+// CreateTermRequest represents the data needed to create an academic term
+type CreateTermRequest struct {
+	Name      string    `json:"name" validate:"required,min=2,max=255"`
+	StartDate time.Time `json:"start_date" validate:"required"`
+	EndDate   time.Time `json:"end_date" validate:"required,gtfield=StartDate"`
+}
+
+// UpdateTermRequest represents the data needed to update an academic term
+type UpdateTermRequest struct {
+	Name      *string    `json:"name" validate:"omitempty,min=2,max=255"`
+	StartDate *time.Time `json:"start_date"`
+	EndDate   *time.Time `json:"end_date"`
+}
@@ -34,19 +34,21 @@ type UserWithOrgName struct {
 
 // TeacherStats represents statistics for a teacher
 type TeacherStats struct {
-	User               *User `json:"user"`
-	AssignedCourses    int   `json:"assigned_courses"`
-	CreatedAssessments int   `json:"created_assessments"`
-	PendingGrading     int   `json:"pending_grading"`
+	User               *User             `json:"user"`
+	AssignedCourses    int               `json:"assigned_courses"`
+	CreatedAssessments int               `json:"created_assessments"`
+	PendingGrading     int               `json:"pending_grading"`
+	RubricCriteria     []*CriterionStats `json:"rubric_criteria"`
 }
 
 // StudentStats represents statistics for a student
 type StudentStats struct {
-	User                 *User   `json:"user"`
-	EnrolledCourses      int     `json:"enrolled_courses"`
-	CompletedAssessments int     `json:"completed_assessments"`
-	PendingAssessments   int     `json:"pending_assessments"`
-	AverageGrade         float64 `json:"average_grade"`
+	User                 *User             `json:"user"`
+	EnrolledCourses      int               `json:"enrolled_courses"`
+	CompletedAssessments int               `json:"completed_assessments"`
+	PendingAssessments   int               `json:"pending_assessments"`
+	AverageGrade         float64           `json:"average_grade"`
+	RubricCriteria       []*CriterionStats `json:"rubric_criteria"`
 }
 
 // CreateUserRequest represents the data needed to create a new user
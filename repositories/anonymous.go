@@ -0,0 +1,50 @@
+package repositories
+
+import (
+	"context"
+
+	"assessment-management-system/models"
+)
+
+// LogUnmask records that an admin revealed the students of an assessment graded anonymously
+func (r *AssessmentRepository) LogUnmask(ctx context.Context, assessmentID, unmaskedBy, reason string) (*models.UnmaskLogEntry, error) {
+	var entry models.UnmaskLogEntry
+	err := r.db.Pool.QueryRow(ctx,
+		`INSERT INTO anonymous_unmask_log (assessment_id, unmasked_by, reason)
+                VALUES ($1, $2, $3)
+                RETURNING id, assessment_id, unmasked_by, reason, created_at`,
+		assessmentID, unmaskedBy, reason).Scan(&entry.ID, &entry.AssessmentID, &entry.UnmaskedBy, &entry.Reason, &entry.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+// FindUnmaskLog retrieves every time the students of an assessment were revealed, newest first
+func (r *AssessmentRepository) FindUnmaskLog(ctx context.Context, assessmentID string) ([]*models.UnmaskLogEntry, error) {
+	rows, err := r.db.Pool.Query(ctx,
+		`SELECT id, assessment_id, unmasked_by, reason, created_at
+                FROM anonymous_unmask_log
+                WHERE assessment_id = $1
+                ORDER BY created_at DESC`,
+		assessmentID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries := []*models.UnmaskLogEntry{}
+	for rows.Next() {
+		var entry models.UnmaskLogEntry
+		if err := rows.Scan(&entry.ID, &entry.AssessmentID, &entry.UnmaskedBy, &entry.Reason, &entry.CreatedAt); err != nil {
+			return nil, err
+		}
+		entries = append(entries, &entry)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
@@ -2,6 +2,7 @@ package repositories
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"time"
 
@@ -23,33 +24,208 @@ func NewAssessmentRepository(db *db.DB) *AssessmentRepository {
 	}
 }
 
-// Create creates a new assessment
-func (r *AssessmentRepository) Create(ctx context.Context, courseID, teacherID, title, description, assessmentType string, maxScore int, dueDate *time.Time) (*models.Assessment, error) {
+// defaultMaxAttachmentSizeMB is the upload limit of assessments that do not set their own
+const defaultMaxAttachmentSizeMB = 10
+
+// defaultGracePeriodSeconds is how long after a timed attempt ends its submission is still accepted
+const defaultGracePeriodSeconds = 60
+
+// assessmentStatusExpr is the current status of an assessment: scheduled assessments count as published
+// once their available_from date has passed
+const assessmentStatusExpr = `CASE WHEN status = 'scheduled' AND available_from <= CURRENT_TIMESTAMP THEN 'published' ELSE status END`
+
+// assessmentSectionsExpr lists the IDs of the sections an assessment is for
+const assessmentSectionsExpr = `ARRAY(SELECT section_id::text FROM assessment_sections WHERE assessment_sections.assessment_id = assessments.id ORDER BY section_id)`
+
+const assessmentColumns = `id, course_id, teacher_id, title, description, type, ` + assessmentStatusExpr + `, available_from, max_score, due_date, cutoff_date, late_penalty_type, late_penalty_value, rubric_id, max_attachment_size_mb, allowed_file_types, max_attempts, attempt_policy, time_limit_minutes, grace_period_seconds, grade_release_mode, grades_release_at, category_id, extra_credit, anonymous_grading, moderation_threshold, peer_reviews_per_submission, peer_review_due_date, peer_review_weight, group_submission, previous_offering_id, ` + assessmentSectionsExpr + `, anonymous_key, created_at, updated_at`
+
+const gradeColumns = `submission_id, score, raw_score, late_penalty, feedback, graded_by, graded_at, auto_graded, released_at`
+
+const submissionColumns = `id, assessment_id, student_id, group_id, attempt_number, content, submitted_at`
+
+// scanAssessment scans an assessment row selected with assessmentColumns
+func scanAssessment(row pgx.Row) (*models.Assessment, error) {
 	var assessment models.Assessment
-	err := r.db.Pool.QueryRow(ctx,
-		`INSERT INTO assessments (course_id, teacher_id, title, description, type, max_score, due_date) 
-                VALUES ($1, $2, $3, $4, $5, $6, $7) 
-                RETURNING id, course_id, teacher_id, title, description, type, max_score, due_date, created_at, updated_at`,
-		courseID, teacherID, title, description, assessmentType, maxScore, dueDate).Scan(
-		&assessment.ID, &assessment.CourseID, &assessment.TeacherID, &assessment.Title, &assessment.Description,
-		&assessment.Type, &assessment.MaxScore, &assessment.DueDate, &assessment.CreatedAt, &assessment.UpdatedAt)
+	if err := row.Scan(&assessment.ID, &assessment.CourseID, &assessment.TeacherID, &assessment.Title, &assessment.Description,
+		&assessment.Type, &assessment.Status, &assessment.AvailableFrom, &assessment.MaxScore, &assessment.DueDate, &assessment.CutoffDate, &assessment.LatePenaltyType,
+		&assessment.LatePenaltyValue, &assessment.RubricID, &assessment.MaxAttachmentSizeMB,
+		&assessment.AllowedFileTypes, &assessment.MaxAttempts, &assessment.AttemptPolicy, &assessment.TimeLimitMinutes, &assessment.GracePeriodSeconds,
+		&assessment.GradeReleaseMode, &assessment.GradesReleaseAt, &assessment.CategoryID, &assessment.ExtraCredit,
+		&assessment.AnonymousGrading, &assessment.ModerationThreshold,
+		&assessment.PeerReviewsPerSubmission, &assessment.PeerReviewDueDate, &assessment.PeerReviewWeight, &assessment.GroupSubmission,
+		&assessment.PreviousOfferingID, &assessment.SectionIDs, &assessment.AnonymousKey,
+		&assessment.CreatedAt, &assessment.UpdatedAt); err != nil {
+		return nil, err
+	}
+	return &assessment, nil
+}
+
+// scanGrade scans a grade row selected with gradeColumns
+func scanGrade(row pgx.Row) (*models.Grade, error) {
+	var grade models.Grade
+	if err := row.Scan(&grade.SubmissionID, &grade.Score, &grade.RawScore, &grade.LatePenalty, &grade.Feedback,
+		&grade.GradedBy, &grade.GradedAt, &grade.AutoGraded, &grade.ReleasedAt); err != nil {
+		return nil, err
+	}
+	return &grade, nil
+}
+
+// scanSubmission scans a submission row selected with submissionColumns
+func scanSubmission(row pgx.Row) (*models.AssessmentSubmission, error) {
+	var submission models.AssessmentSubmission
+	if err := row.Scan(&submission.ID, &submission.AssessmentID, &submission.StudentID, &submission.GroupID, &submission.AttemptNumber,
+		&submission.Content, &submission.SubmittedAt); err != nil {
+		return nil, err
+	}
+	return &submission, nil
+}
+
+// scanSubmissions scans all submission rows selected with submissionColumns
+func scanSubmissions(rows pgx.Rows) ([]*models.AssessmentSubmission, error) {
+	defer rows.Close()
+
+	var submissions []*models.AssessmentSubmission
+	for rows.Next() {
+		submission, err := scanSubmission(rows)
+		if err != nil {
+			return nil, err
+		}
+		submissions = append(submissions, submission)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return submissions, nil
+}
+
+// scanAssessments scans all assessment rows selected with assessmentColumns
+func scanAssessments(rows pgx.Rows) ([]*models.Assessment, error) {
+	defer rows.Close()
+
+	var assessments []*models.Assessment
+	for rows.Next() {
+		assessment, err := scanAssessment(rows)
+		if err != nil {
+			return nil, err
+		}
+		assessments = append(assessments, assessment)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return assessments, nil
+}
+
+// Create creates a new assessment
+func (r *AssessmentRepository) Create(ctx context.Context, teacherID string, req models.CreateAssessmentRequest) (*models.Assessment, error) {
+	maxAttachmentSize := defaultMaxAttachmentSizeMB
+	if req.MaxAttachmentSizeMB != nil {
+		maxAttachmentSize = *req.MaxAttachmentSizeMB
+	}
+
+	allowedFileTypes := req.AllowedFileTypes
+	if allowedFileTypes == nil {
+		allowedFileTypes = []string{}
+	}
 
+	maxAttempts := 1
+	if req.MaxAttempts != nil {
+		maxAttempts = *req.MaxAttempts
+	}
+
+	attemptPolicy := req.AttemptPolicy
+	if attemptPolicy == "" {
+		attemptPolicy = models.AttemptPolicyLatest
+	}
+
+	latePenaltyType := req.LatePenaltyType
+	if latePenaltyType == "" {
+		latePenaltyType = models.LatePenaltyNone
+	}
+
+	status := req.Status
+	if status == "" {
+		status = models.AssessmentStatusDraft
+	}
+
+	gradeReleaseMode := req.GradeReleaseMode
+	if gradeReleaseMode == "" {
+		gradeReleaseMode = models.GradeReleaseImmediate
+	}
+
+	gracePeriod := defaultGracePeriodSeconds
+	if req.GracePeriodSeconds != nil {
+		gracePeriod = *req.GracePeriodSeconds
+	}
+
+	var assessment *models.Assessment
+	err := r.db.ExecuteTransaction(ctx, func(tx pgx.Tx) error {
+		var id string
+		err := tx.QueryRow(ctx,
+			`INSERT INTO assessments (course_id, teacher_id, title, description, type, max_score, due_date, cutoff_date, late_penalty_type,
+                        late_penalty_value, max_attachment_size_mb, allowed_file_types, max_attempts, attempt_policy, time_limit_minutes,
+                        grace_period_seconds, status, available_from, grade_release_mode, grades_release_at, category_id,
+                        extra_credit, anonymous_grading, moderation_threshold, peer_reviews_per_submission, peer_review_due_date, group_submission,
+                        previous_offering_id) 
+                VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23, $24,
+                        $25, $26, $27, $28) 
+                RETURNING id`,
+			req.CourseID, teacherID, req.Title, req.Description, req.Type, req.MaxScore, req.DueDate, req.CutoffDate, latePenaltyType,
+			req.LatePenaltyValue, maxAttachmentSize, allowedFileTypes, maxAttempts, attemptPolicy, req.TimeLimitMinutes, gracePeriod,
+			status, req.AvailableFrom, gradeReleaseMode, req.GradesReleaseAt, req.CategoryID, req.ExtraCredit, req.AnonymousGrading, req.ModerationThreshold,
+			req.PeerReviewsPerSubmission, req.PeerReviewDueDate, req.GroupSubmission, req.PreviousOfferingID).Scan(&id)
+		if err != nil {
+			return err
+		}
+
+		if err := setAssessmentSections(ctx, tx, id, req.SectionIDs); err != nil {
+			return err
+		}
+
+		assessment, err = scanAssessment(tx.QueryRow(ctx,
+			`SELECT `+assessmentColumns+` 
+                        FROM assessments 
+                        WHERE id = $1`,
+			id))
+		return err
+	})
 	if err != nil {
 		return nil, err
 	}
-	return &assessment, nil
+
+	return assessment, nil
+}
+
+// setAssessmentSections replaces the sections an assessment is for
+func setAssessmentSections(ctx context.Context, tx pgx.Tx, assessmentID string, sectionIDs []string) error {
+	if _, err := tx.Exec(ctx,
+		`DELETE FROM assessment_sections WHERE assessment_id = $1`,
+		assessmentID); err != nil {
+		return err
+	}
+
+	for _, sectionID := range sectionIDs {
+		if _, err := tx.Exec(ctx,
+			`INSERT INTO assessment_sections (assessment_id, section_id)
+                        VALUES ($1, $2)`,
+			assessmentID, sectionID); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 // FindByID retrieves an assessment by ID
 func (r *AssessmentRepository) FindByID(ctx context.Context, id string) (*models.Assessment, error) {
-	var assessment models.Assessment
-	var dueDate *time.Time
-	err := r.db.Pool.QueryRow(ctx,
-		`SELECT id, course_id, teacher_id, title, description, type, max_score, due_date, created_at, updated_at 
+	assessment, err := scanAssessment(r.db.Pool.QueryRow(ctx,
+		`SELECT `+assessmentColumns+` 
                 FROM assessments 
                 WHERE id = $1`,
-		id).Scan(&assessment.ID, &assessment.CourseID, &assessment.TeacherID, &assessment.Title, &assessment.Description,
-		&assessment.Type, &assessment.MaxScore, &dueDate, &assessment.CreatedAt, &assessment.UpdatedAt)
+		id))
 
 	if err != nil {
 		if err == pgx.ErrNoRows {
@@ -57,14 +233,13 @@ func (r *AssessmentRepository) FindByID(ctx context.Context, id string) (*models
 		}
 		return nil, err
 	}
-	assessment.DueDate = dueDate
-	return &assessment, nil
+	return assessment, nil
 }
 
 // FindByCourse retrieves all assessments for a course
 func (r *AssessmentRepository) FindByCourse(ctx context.Context, courseID string) ([]*models.Assessment, error) {
 	rows, err := r.db.Pool.Query(ctx,
-		`SELECT id, course_id, teacher_id, title, description, type, max_score, due_date, created_at, updated_at 
+		`SELECT `+assessmentColumns+` 
                 FROM assessments 
                 WHERE course_id = $1
                 ORDER BY due_date NULLS LAST, created_at DESC`,
@@ -72,77 +247,28 @@ func (r *AssessmentRepository) FindByCourse(ctx context.Context, courseID string
 	if err != nil {
 		return nil, err
 	}
-	defer rows.Close()
-
-	var assessments []*models.Assessment
-	for rows.Next() {
-		var assessment models.Assessment
-		var dueDate *time.Time
-		if err := rows.Scan(&assessment.ID, &assessment.CourseID, &assessment.TeacherID, &assessment.Title, &assessment.Description,
-			&assessment.Type, &assessment.MaxScore, &dueDate, &assessment.CreatedAt, &assessment.UpdatedAt); err != nil {
-			return nil, err
-		}
-		assessment.DueDate = dueDate
-		assessments = append(assessments, &assessment)
-	}
-
-	if err := rows.Err(); err != nil {
-		return nil, err
-	}
 
-	return assessments, nil
+	return scanAssessments(rows)
 }
 
 // FindByOrganization retrieves all assessments for an organization
 func (r *AssessmentRepository) FindByOrganization(ctx context.Context, organizationID, courseID string) ([]*models.Assessment, error) {
-	var query string
-	var args []interface{}
-
-	if courseID != "" {
-		query = `
-                        SELECT a.id, a.course_id, a.teacher_id, a.title, a.description, a.type, a.max_score, a.due_date, a.created_at, a.updated_at 
-                        FROM assessments a
-                        JOIN courses c ON a.course_id = c.id
-                        WHERE c.organization_id = $1 AND a.course_id = $2
-                        ORDER BY a.due_date NULLS LAST, a.created_at DESC`
-		args = append(args, organizationID, courseID)
-	} else {
-		query = `
-                        SELECT a.id, a.course_id, a.teacher_id, a.title, a.description, a.type, a.max_score, a.due_date, a.created_at, a.updated_at 
-                        FROM assessments a
-                        JOIN courses c ON a.course_id = c.id
-                        WHERE c.organization_id = $1
-                        ORDER BY a.due_date NULLS LAST, a.created_at DESC`
-		args = append(args, organizationID)
-	}
-
-	rows, err := r.db.Pool.Query(ctx, query, args...)
+	rows, err := r.db.Pool.Query(ctx,
+		`SELECT `+assessmentColumns+` 
+                FROM assessments
+                WHERE course_id IN (SELECT id FROM courses WHERE organization_id = $1)
+                AND ($2 = '' OR course_id::text = $2)
+                ORDER BY due_date NULLS LAST, created_at DESC`,
+		organizationID, courseID)
 	if err != nil {
 		return nil, err
 	}
-	defer rows.Close()
 
-	var assessments []*models.Assessment
-	for rows.Next() {
-		var assessment models.Assessment
-		var dueDate *time.Time
-		if err := rows.Scan(&assessment.ID, &assessment.CourseID, &assessment.TeacherID, &assessment.Title, &assessment.Description,
-			&assessment.Type, &assessment.MaxScore, &dueDate, &assessment.CreatedAt, &assessment.UpdatedAt); err != nil {
-			return nil, err
-		}
-		assessment.DueDate = dueDate
-		assessments = append(assessments, &assessment)
-	}
-
-	if err := rows.Err(); err != nil {
-		return nil, err
-	}
-
-	return assessments, nil
+	return scanAssessments(rows)
 }
 
 // Update updates an assessment
-func (r *AssessmentRepository) Update(ctx context.Context, id string, title *string, description *string, assessmentType *models.AssessmentType, maxScore *int, dueDate *time.Time) (*models.Assessment, error) {
+func (r *AssessmentRepository) Update(ctx context.Context, id string, req models.UpdateAssessmentRequest) (*models.Assessment, error) {
 	tx, err := r.db.Pool.Begin(ctx)
 	if err != nil {
 		return nil, err
@@ -150,14 +276,11 @@ func (r *AssessmentRepository) Update(ctx context.Context, id string, title *str
 	defer tx.Rollback(ctx)
 
 	// Get current assessment
-	var assessment models.Assessment
-	var currentDueDate *time.Time
-	err = tx.QueryRow(ctx,
-		`SELECT id, course_id, teacher_id, title, description, type, max_score, due_date, created_at, updated_at 
+	assessment, err := scanAssessment(tx.QueryRow(ctx,
+		`SELECT `+assessmentColumns+` 
                 FROM assessments 
                 WHERE id = $1`,
-		id).Scan(&assessment.ID, &assessment.CourseID, &assessment.TeacherID, &assessment.Title, &assessment.Description,
-		&assessment.Type, &assessment.MaxScore, &currentDueDate, &assessment.CreatedAt, &assessment.UpdatedAt)
+		id))
 
 	if err != nil {
 		if err == pgx.ErrNoRows {
@@ -166,48 +289,148 @@ func (r *AssessmentRepository) Update(ctx context.Context, id string, title *str
 		return nil, err
 	}
 
-	assessment.DueDate = currentDueDate
-
 	// Update fields that are provided
-	if title != nil {
-		assessment.Title = *title
+	if req.Title != nil {
+		assessment.Title = *req.Title
+	}
+	if req.Description != nil {
+		assessment.Description = *req.Description
 	}
-	if description != nil {
-		assessment.Description = *description
+	if req.Type != nil {
+		assessment.Type = *req.Type
 	}
-	if assessmentType != nil {
-		assessment.Type = *assessmentType
+	if req.Status != nil {
+		assessment.Status = *req.Status
 	}
-	if maxScore != nil {
-		assessment.MaxScore = *maxScore
+	if req.AvailableFrom != nil {
+		assessment.AvailableFrom = req.AvailableFrom
+	}
+	if req.MaxScore != nil {
+		assessment.MaxScore = *req.MaxScore
 	}
 	// Due date handling is special because we need to distinguish between setting to null and not changing
-	if dueDate != nil {
-		assessment.DueDate = dueDate
+	if req.DueDate != nil {
+		assessment.DueDate = req.DueDate
+	}
+	if req.CutoffDate != nil {
+		assessment.CutoffDate = req.CutoffDate
+	}
+	if req.LatePenaltyType != nil {
+		assessment.LatePenaltyType = *req.LatePenaltyType
+	}
+	if req.LatePenaltyValue != nil {
+		assessment.LatePenaltyValue = *req.LatePenaltyValue
+	}
+	if req.MaxAttachmentSizeMB != nil {
+		assessment.MaxAttachmentSizeMB = *req.MaxAttachmentSizeMB
+	}
+	if req.AllowedFileTypes != nil {
+		assessment.AllowedFileTypes = req.AllowedFileTypes
+	}
+	if req.MaxAttempts != nil {
+		assessment.MaxAttempts = *req.MaxAttempts
+	}
+	if req.AttemptPolicy != nil {
+		assessment.AttemptPolicy = *req.AttemptPolicy
+	}
+	if req.TimeLimitMinutes != nil {
+		assessment.TimeLimitMinutes = req.TimeLimitMinutes
+	}
+	if req.GracePeriodSeconds != nil {
+		assessment.GracePeriodSeconds = *req.GracePeriodSeconds
+	}
+	if req.GradeReleaseMode != nil {
+		assessment.GradeReleaseMode = *req.GradeReleaseMode
+	}
+	if req.GradesReleaseAt != nil {
+		assessment.GradesReleaseAt = req.GradesReleaseAt
+	}
+	// An empty category ID takes the assessment out of its category
+	if req.CategoryID != nil {
+		assessment.CategoryID = req.CategoryID
+		if *req.CategoryID == "" {
+			assessment.CategoryID = nil
+		}
+	}
+	if req.ExtraCredit != nil {
+		assessment.ExtraCredit = *req.ExtraCredit
+	}
+	if req.AnonymousGrading != nil {
+		assessment.AnonymousGrading = *req.AnonymousGrading
+	}
+	if req.ModerationThreshold != nil {
+		assessment.ModerationThreshold = *req.ModerationThreshold
+	}
+	if req.PeerReviewsPerSubmission != nil {
+		assessment.PeerReviewsPerSubmission = *req.PeerReviewsPerSubmission
+	}
+	if req.PeerReviewDueDate != nil {
+		assessment.PeerReviewDueDate = req.PeerReviewDueDate
+	}
+	if req.GroupSubmission != nil {
+		assessment.GroupSubmission = *req.GroupSubmission
+	}
+	// An empty previous offering ID unlinks the assessment from its earlier offering
+	if req.PreviousOfferingID != nil {
+		assessment.PreviousOfferingID = req.PreviousOfferingID
+		if *req.PreviousOfferingID == "" {
+			assessment.PreviousOfferingID = nil
+		}
+	}
+
+	if req.SectionIDs != nil {
+		if err := setAssessmentSections(ctx, tx, id, *req.SectionIDs); err != nil {
+			return nil, err
+		}
 	}
 
 	// Update in database
-	var updatedDueDate *time.Time
-	err = tx.QueryRow(ctx,
+	assessment, err = scanAssessment(tx.QueryRow(ctx,
 		`UPDATE assessments 
-                SET title = $2, description = $3, type = $4, max_score = $5, due_date = $6, updated_at = $7
+                SET title = $2, description = $3, type = $4, max_score = $5, due_date = $6, max_attachment_size_mb = $7,
+                    allowed_file_types = $8, max_attempts = $9, attempt_policy = $10, cutoff_date = $11,
+                    late_penalty_type = $12, late_penalty_value = $13, time_limit_minutes = $14, grace_period_seconds = $15,
+                    status = $16, available_from = $17, grade_release_mode = $18, grades_release_at = $19,
+                    category_id = $20, extra_credit = $21, anonymous_grading = $22, moderation_threshold = $23,
+                    peer_reviews_per_submission = $24, peer_review_due_date = $25, group_submission = $26, previous_offering_id = $27,
+                    updated_at = $28
                 WHERE id = $1 
-                RETURNING id, course_id, teacher_id, title, description, type, max_score, due_date, created_at, updated_at`,
-		id, assessment.Title, assessment.Description, assessment.Type, assessment.MaxScore, assessment.DueDate, time.Now()).Scan(
-		&assessment.ID, &assessment.CourseID, &assessment.TeacherID, &assessment.Title, &assessment.Description,
-		&assessment.Type, &assessment.MaxScore, &updatedDueDate, &assessment.CreatedAt, &assessment.UpdatedAt)
+                RETURNING `+assessmentColumns,
+		id, assessment.Title, assessment.Description, assessment.Type, assessment.MaxScore, assessment.DueDate,
+		assessment.MaxAttachmentSizeMB, assessment.AllowedFileTypes, assessment.MaxAttempts, assessment.AttemptPolicy,
+		assessment.CutoffDate, assessment.LatePenaltyType, assessment.LatePenaltyValue, assessment.TimeLimitMinutes,
+		assessment.GracePeriodSeconds, assessment.Status, assessment.AvailableFrom, assessment.GradeReleaseMode,
+		assessment.GradesReleaseAt, assessment.CategoryID, assessment.ExtraCredit, assessment.AnonymousGrading, assessment.ModerationThreshold,
+		assessment.PeerReviewsPerSubmission, assessment.PeerReviewDueDate, assessment.GroupSubmission, assessment.PreviousOfferingID,
+		time.Now()))
 
 	if err != nil {
 		return nil, err
 	}
 
-	assessment.DueDate = updatedDueDate
-
 	if err := tx.Commit(ctx); err != nil {
 		return nil, err
 	}
 
-	return &assessment, nil
+	return assessment, nil
+}
+
+// SetRubric attaches a rubric to an assessment, or detaches it when rubricID is nil
+func (r *AssessmentRepository) SetRubric(ctx context.Context, id string, rubricID *string) (*models.Assessment, error) {
+	assessment, err := scanAssessment(r.db.Pool.QueryRow(ctx,
+		`UPDATE assessments 
+                SET rubric_id = $2, updated_at = $3
+                WHERE id = $1 
+                RETURNING `+assessmentColumns,
+		id, rubricID, time.Now()))
+
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, errors.New("assessment not found")
+		}
+		return nil, err
+	}
+	return assessment, nil
 }
 
 // Delete deletes an assessment
@@ -224,10 +447,10 @@ func (r *AssessmentRepository) Delete(ctx context.Context, id string) error {
 	return nil
 }
 
-// FindSubmissionsByAssessment retrieves all submissions for an assessment
+// FindSubmissionsByAssessment retrieves all submissions for an assessment, every attempt included
 func (r *AssessmentRepository) FindSubmissionsByAssessment(ctx context.Context, assessmentID string) ([]*models.AssessmentSubmission, error) {
 	rows, err := r.db.Pool.Query(ctx,
-		`SELECT id, assessment_id, student_id, content, submitted_at 
+		`SELECT `+submissionColumns+` 
                 FROM assessment_submissions 
                 WHERE assessment_id = $1
                 ORDER BY submitted_at DESC`,
@@ -235,32 +458,41 @@ func (r *AssessmentRepository) FindSubmissionsByAssessment(ctx context.Context,
 	if err != nil {
 		return nil, err
 	}
-	defer rows.Close()
 
-	var submissions []*models.AssessmentSubmission
-	for rows.Next() {
-		var submission models.AssessmentSubmission
-		if err := rows.Scan(&submission.ID, &submission.AssessmentID, &submission.StudentID, &submission.Content, &submission.SubmittedAt); err != nil {
-			return nil, err
-		}
-		submissions = append(submissions, &submission)
-	}
+	return scanSubmissions(rows)
+}
 
-	if err := rows.Err(); err != nil {
+// FindPreviousOfferingSubmissions retrieves the submissions made to the earlier offerings of an assessment, following
+// its previous offerings back term by term, every attempt included
+func (r *AssessmentRepository) FindPreviousOfferingSubmissions(ctx context.Context, assessmentID string) ([]*models.AssessmentSubmission, error) {
+	rows, err := r.db.Pool.Query(ctx,
+		`WITH RECURSIVE offerings AS (
+                        SELECT previous_offering_id AS id FROM assessments
+                        WHERE id = $1 AND previous_offering_id IS NOT NULL
+                        UNION
+                        SELECT a.previous_offering_id FROM assessments a
+                        JOIN offerings o ON a.id = o.id
+                        WHERE a.previous_offering_id IS NOT NULL
+                )
+                SELECT `+submissionColumns+`
+                FROM assessment_submissions
+                WHERE assessment_id IN (SELECT id FROM offerings)
+                ORDER BY submitted_at DESC`,
+		assessmentID)
+	if err != nil {
 		return nil, err
 	}
 
-	return submissions, nil
+	return scanSubmissions(rows)
 }
 
 // FindSubmissionByID retrieves a submission by ID
 func (r *AssessmentRepository) FindSubmissionByID(ctx context.Context, id string) (*models.AssessmentSubmission, error) {
-	var submission models.AssessmentSubmission
-	err := r.db.Pool.QueryRow(ctx,
-		`SELECT id, assessment_id, student_id, content, submitted_at 
+	submission, err := scanSubmission(r.db.Pool.QueryRow(ctx,
+		`SELECT `+submissionColumns+` 
                 FROM assessment_submissions 
                 WHERE id = $1`,
-		id).Scan(&submission.ID, &submission.AssessmentID, &submission.StudentID, &submission.Content, &submission.SubmittedAt)
+		id))
 
 	if err != nil {
 		if err == pgx.ErrNoRows {
@@ -268,17 +500,32 @@ func (r *AssessmentRepository) FindSubmissionByID(ctx context.Context, id string
 		}
 		return nil, err
 	}
-	return &submission, nil
+	return submission, nil
 }
 
-// FindSubmissionByStudentAndAssessment retrieves a student's submission for an assessment
-func (r *AssessmentRepository) FindSubmissionByStudentAndAssessment(ctx context.Context, assessmentID, studentID string) (*models.AssessmentSubmission, error) {
-	var submission models.AssessmentSubmission
+// studentSubmissionsExpr holds for the submissions that count for the student $2: their own, and those made by a
+// group they were in when it submitted
+const studentSubmissionsExpr = `id IN (SELECT submission_id FROM submission_students WHERE student_id = $2)`
+
+// IsSubmissionStudent reports whether a submission counts for a student, as its author or a member of the group
+// that made it
+func (r *AssessmentRepository) IsSubmissionStudent(ctx context.Context, submissionID, studentID string) (bool, error) {
+	var exists bool
 	err := r.db.Pool.QueryRow(ctx,
-		`SELECT id, assessment_id, student_id, content, submitted_at 
+		`SELECT EXISTS (SELECT 1 FROM submission_students WHERE submission_id = $1 AND student_id = $2)`,
+		submissionID, studentID).Scan(&exists)
+	return exists, err
+}
+
+// FindSubmissionByStudentAndAssessment retrieves a student's latest attempt at an assessment
+func (r *AssessmentRepository) FindSubmissionByStudentAndAssessment(ctx context.Context, assessmentID, studentID string) (*models.AssessmentSubmission, error) {
+	submission, err := scanSubmission(r.db.Pool.QueryRow(ctx,
+		`SELECT `+submissionColumns+` 
                 FROM assessment_submissions 
-                WHERE assessment_id = $1 AND student_id = $2`,
-		assessmentID, studentID).Scan(&submission.ID, &submission.AssessmentID, &submission.StudentID, &submission.Content, &submission.SubmittedAt)
+                WHERE assessment_id = $1 AND `+studentSubmissionsExpr+`
+                ORDER BY attempt_number DESC
+                LIMIT 1`,
+		assessmentID, studentID))
 
 	if err != nil {
 		if err == pgx.ErrNoRows {
@@ -286,32 +533,282 @@ func (r *AssessmentRepository) FindSubmissionByStudentAndAssessment(ctx context.
 		}
 		return nil, err
 	}
-	return &submission, nil
+	return submission, nil
+}
+
+// FindAttemptsByStudentAndAssessment retrieves every attempt of a student at an assessment, oldest first
+func (r *AssessmentRepository) FindAttemptsByStudentAndAssessment(ctx context.Context, assessmentID, studentID string) ([]*models.AssessmentSubmission, error) {
+	rows, err := r.db.Pool.Query(ctx,
+		`SELECT `+submissionColumns+` 
+                FROM assessment_submissions 
+                WHERE assessment_id = $1 AND `+studentSubmissionsExpr+`
+                ORDER BY attempt_number`,
+		assessmentID, studentID)
+	if err != nil {
+		return nil, err
+	}
+
+	return scanSubmissions(rows)
+}
+
+// FindAttempt retrieves a single numbered attempt of a student at an assessment
+func (r *AssessmentRepository) FindAttempt(ctx context.Context, assessmentID, studentID string, attemptNumber int) (*models.AssessmentSubmission, error) {
+	submission, err := scanSubmission(r.db.Pool.QueryRow(ctx,
+		`SELECT `+submissionColumns+` 
+                FROM assessment_submissions 
+                WHERE assessment_id = $1 AND `+studentSubmissionsExpr+` AND attempt_number = $3`,
+		assessmentID, studentID, attemptNumber))
+
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return submission, nil
+}
+
+// CountAttempts counts the attempts a student has made at an assessment
+func (r *AssessmentRepository) CountAttempts(ctx context.Context, assessmentID, studentID string) (int, error) {
+	var count int
+	err := r.db.Pool.QueryRow(ctx,
+		`SELECT COUNT(*) 
+                FROM assessment_submissions 
+                WHERE assessment_id = $1 AND `+studentSubmissionsExpr,
+		assessmentID, studentID).Scan(&count)
+	return count, err
 }
 
 // CreateSubmission creates a new submission
 func (r *AssessmentRepository) CreateSubmission(ctx context.Context, assessmentID, studentID, content string) (*models.AssessmentSubmission, error) {
-	var submission models.AssessmentSubmission
-	err := r.db.Pool.QueryRow(ctx,
+	return scanSubmission(r.db.Pool.QueryRow(ctx,
 		`INSERT INTO assessment_submissions (assessment_id, student_id, content) 
                 VALUES ($1, $2, $3) 
-                RETURNING id, assessment_id, student_id, content, submitted_at`,
-		assessmentID, studentID, content).Scan(&submission.ID, &submission.AssessmentID, &submission.StudentID, &submission.Content, &submission.SubmittedAt)
+                RETURNING `+submissionColumns,
+		assessmentID, studentID, content))
+}
+
+// CreateSubmissionWithDetails records the student's next attempt together with its per-question answers, its
+// attachments and, when a grade is provided, the automatic grade, all in a single transaction. Two concurrent
+// attempts get the same number, so the unique attempt constraint lets only one of them through. Attempts at
+// timed assessments pass the ID of their attempt session, which is closed with the submission. Group attempts
+// are numbered per group and record the group's current members, who all share the submission.
+func (r *AssessmentRepository) CreateSubmissionWithDetails(ctx context.Context, assessmentID, studentID string, groupID *string, content string, answers []*models.SubmissionAnswer, attachments []*models.Attachment, grade *models.Grade, sessionID *string) (*models.AssessmentSubmission, error) {
+	var submission *models.AssessmentSubmission
+	err := r.db.ExecuteTransaction(ctx, func(tx pgx.Tx) error {
+		var err error
+		submission, err = scanSubmission(tx.QueryRow(ctx,
+			`INSERT INTO assessment_submissions (assessment_id, student_id, group_id, attempt_number, content) 
+                        VALUES ($1, $2, $3, (
+                                SELECT COALESCE(MAX(attempt_number), 0) + 1
+                                FROM assessment_submissions
+                                WHERE assessment_id = $1 AND (CASE WHEN $3::uuid IS NULL THEN student_id = $2 ELSE group_id = $3 END)
+                        ), $4) 
+                        RETURNING `+submissionColumns,
+			assessmentID, studentID, groupID, content))
+		if err != nil {
+			return err
+		}
+
+		if groupID != nil {
+			_, err := tx.Exec(ctx,
+				`INSERT INTO submission_group_members (submission_id, student_id)
+                                SELECT $1, student_id FROM course_group_members WHERE group_id = $2`,
+				submission.ID, *groupID)
+			if err != nil {
+				return err
+			}
+		}
+
+		if sessionID != nil {
+			commandTag, err := tx.Exec(ctx,
+				`UPDATE attempt_sessions SET submission_id = $2 WHERE id = $1 AND submission_id IS NULL`,
+				*sessionID, submission.ID)
+			if err != nil {
+				return err
+			}
+			if commandTag.RowsAffected() == 0 {
+				return errors.New("this attempt has already been submitted")
+			}
+		}
+
+		for _, answer := range answers {
+			var rawAnswer interface{}
+			if answer.Answer != nil {
+				encoded, err := json.Marshal(answer.Answer)
+				if err != nil {
+					return err
+				}
+				rawAnswer = string(encoded)
+			}
+
+			_, err = tx.Exec(ctx,
+				`INSERT INTO submission_answers (submission_id, question_id, position, answer, is_correct, points_awarded)
+                                VALUES ($1, $2, $3, $4, $5, $6)`,
+				submission.ID, answer.QuestionID, answer.Position, rawAnswer, answer.IsCorrect, answer.PointsAwarded)
+			if err != nil {
+				return err
+			}
+			answer.SubmissionID = submission.ID
+		}
+
+		for _, attachment := range attachments {
+			attachment.SubmissionID = submission.ID
+			err := tx.QueryRow(ctx,
+				`INSERT INTO submission_attachments (submission_id, file_name, content_type, size_bytes, storage_key)
+                                VALUES ($1, $2, $3, $4, $5)
+                                RETURNING id, created_at`,
+				submission.ID, attachment.FileName, attachment.ContentType, attachment.SizeBytes, attachment.StorageKey).Scan(
+				&attachment.ID, &attachment.CreatedAt)
+			if err != nil {
+				return err
+			}
+		}
+
+		if grade == nil {
+			return nil
+		}
+
+		grade.SubmissionID = submission.ID
+		err = tx.QueryRow(ctx,
+			`INSERT INTO grades (submission_id, score, raw_score, late_penalty, feedback, graded_by, auto_graded) 
+                        VALUES ($1, $2, $3, $4, $5, $6, true) 
+                        RETURNING graded_at, auto_graded`,
+			submission.ID, grade.Score, grade.RawScore, grade.LatePenalty, grade.Feedback, grade.GradedBy).Scan(&grade.GradedAt, &grade.AutoGraded)
+		if err != nil {
+			return err
+		}
+
+		return recordGradeVersion(ctx, tx, nil, grade, grade.GradedBy, "Graded automatically")
+	})
 
 	if err != nil {
 		return nil, err
 	}
-	return &submission, nil
+
+	submission.Answers = answers
+	submission.Attachments = attachments
+	return submission, nil
+}
+
+const attemptSessionColumns = `id, assessment_id, student_id, started_at, ends_at, submission_id`
+
+// scanAttemptSession scans an attempt session row selected with attemptSessionColumns
+func scanAttemptSession(row pgx.Row) (*models.AttemptSession, error) {
+	var session models.AttemptSession
+	if err := row.Scan(&session.ID, &session.AssessmentID, &session.StudentID, &session.StartedAt, &session.EndsAt,
+		&session.SubmissionID); err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+// CreateAttemptSession records that a student started a timed attempt
+func (r *AssessmentRepository) CreateAttemptSession(ctx context.Context, assessmentID, studentID string, startedAt, endsAt time.Time) (*models.AttemptSession, error) {
+	return scanAttemptSession(r.db.Pool.QueryRow(ctx,
+		`INSERT INTO attempt_sessions (assessment_id, student_id, started_at, ends_at)
+                VALUES ($1, $2, $3, $4)
+                RETURNING `+attemptSessionColumns,
+		assessmentID, studentID, startedAt, endsAt))
+}
+
+// FindOpenAttemptSession retrieves the student's latest attempt session that has no submission yet
+func (r *AssessmentRepository) FindOpenAttemptSession(ctx context.Context, assessmentID, studentID string) (*models.AttemptSession, error) {
+	session, err := scanAttemptSession(r.db.Pool.QueryRow(ctx,
+		`SELECT `+attemptSessionColumns+`
+                FROM attempt_sessions
+                WHERE assessment_id = $1 AND student_id = $2 AND submission_id IS NULL
+                ORDER BY started_at DESC
+                LIMIT 1`,
+		assessmentID, studentID))
+
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return session, nil
+}
+
+// CountAttemptSessions counts the timed attempts a student has started at an assessment
+func (r *AssessmentRepository) CountAttemptSessions(ctx context.Context, assessmentID, studentID string) (int, error) {
+	var count int
+	err := r.db.Pool.QueryRow(ctx,
+		`SELECT COUNT(*)
+                FROM attempt_sessions
+                WHERE assessment_id = $1 AND student_id = $2`,
+		assessmentID, studentID).Scan(&count)
+	return count, err
+}
+
+// FindAttachmentsBySubmission retrieves the files handed in with a submission
+func (r *AssessmentRepository) FindAttachmentsBySubmission(ctx context.Context, submissionID string) ([]*models.Attachment, error) {
+	rows, err := r.db.Pool.Query(ctx,
+		`SELECT id, submission_id, file_name, content_type, size_bytes, storage_key, created_at
+                FROM submission_attachments
+                WHERE submission_id = $1
+                ORDER BY created_at, file_name`,
+		submissionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var attachments []*models.Attachment
+	for rows.Next() {
+		var attachment models.Attachment
+		if err := rows.Scan(&attachment.ID, &attachment.SubmissionID, &attachment.FileName, &attachment.ContentType,
+			&attachment.SizeBytes, &attachment.StorageKey, &attachment.CreatedAt); err != nil {
+			return nil, err
+		}
+		attachments = append(attachments, &attachment)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return attachments, nil
+}
+
+// FindAttachmentByID retrieves an attachment by ID
+func (r *AssessmentRepository) FindAttachmentByID(ctx context.Context, id string) (*models.Attachment, error) {
+	var attachment models.Attachment
+	err := r.db.Pool.QueryRow(ctx,
+		`SELECT id, submission_id, file_name, content_type, size_bytes, storage_key, created_at
+                FROM submission_attachments
+                WHERE id = $1`,
+		id).Scan(&attachment.ID, &attachment.SubmissionID, &attachment.FileName, &attachment.ContentType,
+		&attachment.SizeBytes, &attachment.StorageKey, &attachment.CreatedAt)
+
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &attachment, nil
+}
+
+// CountSubmissionsByAssessment counts the submissions made for an assessment
+func (r *AssessmentRepository) CountSubmissionsByAssessment(ctx context.Context, assessmentID string) (int, error) {
+	var count int
+	err := r.db.Pool.QueryRow(ctx,
+		`SELECT COUNT(*) 
+                FROM assessment_submissions 
+                WHERE assessment_id = $1`,
+		assessmentID).Scan(&count)
+	return count, err
 }
 
 // FindGradeBySubmission retrieves the grade for a submission
 func (r *AssessmentRepository) FindGradeBySubmission(ctx context.Context, submissionID string) (*models.Grade, error) {
-	var grade models.Grade
-	err := r.db.Pool.QueryRow(ctx,
-		`SELECT submission_id, score, feedback, graded_by, graded_at 
+	grade, err := scanGrade(r.db.Pool.QueryRow(ctx,
+		`SELECT `+gradeColumns+` 
                 FROM grades 
                 WHERE submission_id = $1`,
-		submissionID).Scan(&grade.SubmissionID, &grade.Score, &grade.Feedback, &grade.GradedBy, &grade.GradedAt)
+		submissionID))
 
 	if err != nil {
 		if err == pgx.ErrNoRows {
@@ -319,38 +816,162 @@ func (r *AssessmentRepository) FindGradeBySubmission(ctx context.Context, submis
 		}
 		return nil, err
 	}
-	return &grade, nil
+	return grade, nil
 }
 
-// CreateGrade creates a new grade
-func (r *AssessmentRepository) CreateGrade(ctx context.Context, submissionID string, score float64, feedback, gradedBy string) (*models.Grade, error) {
-	var grade models.Grade
-	err := r.db.Pool.QueryRow(ctx,
-		`INSERT INTO grades (submission_id, score, feedback, graded_by) 
-                VALUES ($1, $2, $3, $4) 
-                RETURNING submission_id, score, feedback, graded_by, graded_at`,
-		submissionID, score, feedback, gradedBy).Scan(&grade.SubmissionID, &grade.Score, &grade.Feedback, &grade.GradedBy, &grade.GradedAt)
+// CreateGrade creates a new grade and starts its history. The stored score is the raw score minus the late penalty.
+func (r *AssessmentRepository) CreateGrade(ctx context.Context, submissionID string, rawScore, latePenalty float64, feedback, gradedBy, reason string) (*models.Grade, error) {
+	var grade *models.Grade
+	err := r.db.ExecuteTransaction(ctx, func(tx pgx.Tx) error {
+		var err error
+		grade, err = scanGrade(tx.QueryRow(ctx,
+			`INSERT INTO grades (submission_id, score, raw_score, late_penalty, feedback, graded_by) 
+                        VALUES ($1, $2, $3, $4, $5, $6) 
+                        RETURNING `+gradeColumns,
+			submissionID, rawScore-latePenalty, rawScore, latePenalty, feedback, gradedBy))
+		if err != nil {
+			return err
+		}
+
+		return recordGradeVersion(ctx, tx, nil, grade, gradedBy, reason)
+	})
 
 	if err != nil {
 		return nil, err
 	}
-	return &grade, nil
+	return grade, nil
 }
 
-// UpdateGrade updates a grade
-func (r *AssessmentRepository) UpdateGrade(ctx context.Context, submissionID string, score float64, feedback, gradedBy string) (*models.Grade, error) {
-	var grade models.Grade
-	err := r.db.Pool.QueryRow(ctx,
-		`UPDATE grades 
-                SET score = $2, feedback = $3, graded_by = $4, graded_at = $5
-                WHERE submission_id = $1 
-                RETURNING submission_id, score, feedback, graded_by, graded_at`,
-		submissionID, score, feedback, gradedBy, time.Now()).Scan(&grade.SubmissionID, &grade.Score, &grade.Feedback, &grade.GradedBy, &grade.GradedAt)
+// UpdateGrade updates a grade and records the change in its history. The stored score is the raw score minus the late penalty.
+func (r *AssessmentRepository) UpdateGrade(ctx context.Context, submissionID string, rawScore, latePenalty float64, feedback, gradedBy, reason string) (*models.Grade, error) {
+	var grade *models.Grade
+	err := r.db.ExecuteTransaction(ctx, func(tx pgx.Tx) error {
+		previous, err := lockGrade(ctx, tx, submissionID)
+		if err != nil {
+			return err
+		}
+		if previous == nil {
+			return errors.New("grade not found")
+		}
+
+		grade, err = scanGrade(tx.QueryRow(ctx,
+			`UPDATE grades 
+                        SET score = $2, raw_score = $3, late_penalty = $4, feedback = $5, graded_by = $6, graded_at = $7, auto_graded = false
+                        WHERE submission_id = $1 
+                        RETURNING `+gradeColumns,
+			submissionID, rawScore-latePenalty, rawScore, latePenalty, feedback, gradedBy, time.Now()))
+		if err != nil {
+			return err
+		}
+
+		return recordGradeVersion(ctx, tx, previous, grade, gradedBy, reason)
+	})
 
 	if err != nil {
 		return nil, err
 	}
-	return &grade, nil
+	return grade, nil
+}
+
+// SaveGrades creates or replaces many grades at once, recording every change in the grade's history. Either all
+// grades are saved or none.
+func (r *AssessmentRepository) SaveGrades(ctx context.Context, grades []*models.Grade, gradedBy, reason string) error {
+	return r.db.ExecuteTransaction(ctx, func(tx pgx.Tx) error {
+		for _, next := range grades {
+			previous, err := lockGrade(ctx, tx, next.SubmissionID)
+			if err != nil {
+				return err
+			}
+
+			grade, err := scanGrade(tx.QueryRow(ctx,
+				`INSERT INTO grades (submission_id, score, raw_score, late_penalty, feedback, graded_by)
+                        VALUES ($1, $2, $3, $4, $5, $6)
+                        ON CONFLICT (submission_id) DO UPDATE
+                        SET score = EXCLUDED.score, raw_score = EXCLUDED.raw_score, late_penalty = EXCLUDED.late_penalty,
+                            feedback = EXCLUDED.feedback, graded_by = EXCLUDED.graded_by, graded_at = CURRENT_TIMESTAMP,
+                            auto_graded = false
+                        RETURNING `+gradeColumns,
+				next.SubmissionID, next.RawScore-next.LatePenalty, next.RawScore, next.LatePenalty, next.Feedback, gradedBy))
+			if err != nil {
+				return err
+			}
+
+			if err := recordGradeVersion(ctx, tx, previous, grade, gradedBy, reason); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// SaveRubricGrade creates or replaces a grade given with a rubric, together with the level chosen per criterion,
+// and records the change in the grade's history
+func (r *AssessmentRepository) SaveRubricGrade(ctx context.Context, submissionID string, rawScore, latePenalty float64, feedback, gradedBy, reason string, scores []*models.RubricScore) (*models.Grade, error) {
+	var grade *models.Grade
+	err := r.db.ExecuteTransaction(ctx, func(tx pgx.Tx) error {
+		previous, err := lockGrade(ctx, tx, submissionID)
+		if err != nil {
+			return err
+		}
+
+		grade, err = scanGrade(tx.QueryRow(ctx,
+			`INSERT INTO grades (submission_id, score, raw_score, late_penalty, feedback, graded_by) 
+                        VALUES ($1, $2, $3, $4, $5, $6) 
+                        ON CONFLICT (submission_id) DO UPDATE
+                        SET score = EXCLUDED.score, raw_score = EXCLUDED.raw_score, late_penalty = EXCLUDED.late_penalty,
+                            feedback = EXCLUDED.feedback, graded_by = EXCLUDED.graded_by,
+                            graded_at = CURRENT_TIMESTAMP, auto_graded = false
+                        RETURNING `+gradeColumns,
+			submissionID, rawScore-latePenalty, rawScore, latePenalty, feedback, gradedBy))
+		if err != nil {
+			return err
+		}
+
+		if err := replaceRubricScores(ctx, tx, submissionID, scores); err != nil {
+			return err
+		}
+
+		return recordGradeVersion(ctx, tx, previous, grade, gradedBy, reason)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	grade.Rubric = scores
+	return grade, nil
+}
+
+// replaceRubricScores replaces the level chosen per criterion for the grade of a submission
+func replaceRubricScores(ctx context.Context, tx pgx.Tx, submissionID string, scores []*models.RubricScore) error {
+	if _, err := tx.Exec(ctx, `DELETE FROM grade_rubric_scores WHERE submission_id = $1`, submissionID); err != nil {
+		return err
+	}
+
+	for _, item := range scores {
+		_, err := tx.Exec(ctx,
+			`INSERT INTO grade_rubric_scores (submission_id, criterion_id, level_id, points, comment)
+                        VALUES ($1, $2, $3, $4, $5)`,
+			submissionID, item.CriterionID, item.LevelID, item.Points, item.Comment)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ReleaseGrades releases every grade of an assessment that has not been released yet and returns how many were released
+func (r *AssessmentRepository) ReleaseGrades(ctx context.Context, assessmentID string) (int, error) {
+	commandTag, err := r.db.Pool.Exec(ctx,
+		`UPDATE grades 
+                SET released_at = CURRENT_TIMESTAMP
+                WHERE released_at IS NULL
+                AND submission_id IN (SELECT id FROM assessment_submissions WHERE assessment_id = $1)`,
+		assessmentID)
+	if err != nil {
+		return 0, err
+	}
+	return int(commandTag.RowsAffected()), nil
 }
 
 // CountByTeacher counts assessments created by a teacher
@@ -364,13 +985,14 @@ func (r *AssessmentRepository) CountByTeacher(ctx context.Context, teacherID str
 	return count, err
 }
 
-// CountSubmissionsByStudent counts submissions by a student
+// CountSubmissionsByStudent counts the assessments a student has submitted, however many attempts each took
 func (r *AssessmentRepository) CountSubmissionsByStudent(ctx context.Context, studentID string) (int, error) {
 	var count int
 	err := r.db.Pool.QueryRow(ctx,
-		`SELECT COUNT(*) 
-                FROM assessment_submissions 
-                WHERE student_id = $1`,
+		`SELECT COUNT(DISTINCT s.assessment_id) 
+                FROM assessment_submissions s
+                JOIN submission_students ss ON s.id = ss.submission_id
+                WHERE ss.student_id = $1`,
 		studentID).Scan(&count)
 	return count, err
 }
@@ -397,23 +1019,44 @@ func (r *AssessmentRepository) CountPendingAssessmentsByStudent(ctx context.Cont
                 JOIN courses c ON a.course_id = c.id
                 JOIN course_enrollments ce ON c.id = ce.course_id
                 WHERE ce.student_id = $1
+                AND (a.status = 'published' OR (a.status = 'scheduled' AND a.available_from <= CURRENT_TIMESTAMP))
                 AND NOT EXISTS (
                         SELECT 1 FROM assessment_submissions s
-                        WHERE s.assessment_id = a.id AND s.student_id = $1
+                        JOIN submission_students ss ON s.id = ss.submission_id
+                        WHERE s.assessment_id = a.id AND ss.student_id = $1
                 )
                 AND (a.due_date IS NULL OR a.due_date > CURRENT_TIMESTAMP)`,
 		studentID).Scan(&count)
 	return count, err
 }
 
-// GetAverageGradeForStudent calculates the average grade for a student
+// finalScoreExpr aggregates the graded attempts of one student at one assessment, grouped by assessment,
+// into the score that counts under the assessment's attempt policy. The attempts are joined with the students
+// ss they count for, whose score may be adjusted from the group's.
+const finalScoreExpr = `CASE a.attempt_policy
+                        WHEN 'highest' THEN MAX(COALESCE(ss.adjusted_score, g.score))
+                        WHEN 'average' THEN AVG(COALESCE(ss.adjusted_score, g.score))
+                        ELSE (ARRAY_AGG(COALESCE(ss.adjusted_score, g.score) ORDER BY s.attempt_number DESC))[1]
+                END`
+
+// gradeReleasedExpr holds for the grades g of an assessment a that students can see, following the assessment's release mode
+const gradeReleasedExpr = `(a.grade_release_mode = 'immediate' OR g.released_at IS NOT NULL
+                        OR (a.grade_release_mode = 'scheduled' AND a.grades_release_at <= CURRENT_TIMESTAMP))`
+
+// GetAverageGradeForStudent calculates the average released grade for a student, counting one score per assessment
 func (r *AssessmentRepository) GetAverageGradeForStudent(ctx context.Context, studentID string) (float64, error) {
 	var avgGrade float64
 	err := r.db.Pool.QueryRow(ctx,
-		`SELECT COALESCE(AVG(g.score), 0)
-                FROM grades g
-                JOIN assessment_submissions s ON g.submission_id = s.id
-                WHERE s.student_id = $1`,
+		`SELECT COALESCE(AVG(final_score), 0)
+                FROM (
+                        SELECT `+finalScoreExpr+` AS final_score
+                        FROM grades g
+                        JOIN assessment_submissions s ON g.submission_id = s.id
+                        JOIN submission_students ss ON s.id = ss.submission_id
+                        JOIN assessments a ON s.assessment_id = a.id
+                        WHERE ss.student_id = $1 AND `+gradeReleasedExpr+`
+                        GROUP BY a.id, a.attempt_policy
+                ) final_scores`,
 		studentID).Scan(&avgGrade)
 	return avgGrade, err
 }
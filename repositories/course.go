@@ -23,30 +23,79 @@ func NewCourseRepository(db *db.DB) *CourseRepository {
 	}
 }
 
-// Create creates a new course
-func (r *CourseRepository) Create(ctx context.Context, organizationID, name, description string, enrollmentOpen bool) (*models.Course, error) {
+// courseColumns selects a course c joined with its term t, if it has one
+const courseColumns = `c.id, c.organization_id, c.name, c.description, c.enrollment_open, c.max_students, c.prerequisite_mode, c.term_id, c.created_at, c.updated_at,
+                t.id, t.organization_id, t.name, t.start_date, t.end_date, t.created_at, t.updated_at`
+
+const courseFrom = `courses c LEFT JOIN academic_terms t ON c.term_id = t.id`
+
+// scanCourse scans a course row selected with courseColumns
+func scanCourse(row pgx.Row) (*models.Course, error) {
 	var course models.Course
+	var termID, termOrganizationID, termName *string
+	var termStart, termEnd, termCreated, termUpdated *time.Time
+	if err := row.Scan(&course.ID, &course.OrganizationID, &course.Name, &course.Description, &course.EnrollmentOpen,
+		&course.MaxStudents, &course.PrerequisiteMode, &course.TermID, &course.CreatedAt, &course.UpdatedAt,
+		&termID, &termOrganizationID, &termName, &termStart, &termEnd, &termCreated, &termUpdated); err != nil {
+		return nil, err
+	}
+
+	if termID != nil {
+		course.Term = &models.Term{
+			ID:             *termID,
+			OrganizationID: *termOrganizationID,
+			Name:           *termName,
+			StartDate:      *termStart,
+			EndDate:        *termEnd,
+			CreatedAt:      *termCreated,
+			UpdatedAt:      *termUpdated,
+		}
+	}
+	return &course, nil
+}
+
+// scanCourses scans all course rows selected with courseColumns
+func scanCourses(rows pgx.Rows) ([]*models.Course, error) {
+	defer rows.Close()
+
+	var courses []*models.Course
+	for rows.Next() {
+		course, err := scanCourse(rows)
+		if err != nil {
+			return nil, err
+		}
+		courses = append(courses, course)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return courses, nil
+}
+
+// Create creates a new course offered in a term. A nil maximum takes any number of students.
+func (r *CourseRepository) Create(ctx context.Context, organizationID, termID, name, description string, enrollmentOpen bool, maxStudents *int) (*models.Course, error) {
+	var id string
 	err := r.db.Pool.QueryRow(ctx,
-		`INSERT INTO courses (organization_id, name, description, enrollment_open) 
-                VALUES ($1, $2, $3, $4) 
-                RETURNING id, organization_id, name, description, enrollment_open, created_at, updated_at`,
-		organizationID, name, description, enrollmentOpen).Scan(
-		&course.ID, &course.OrganizationID, &course.Name, &course.Description, &course.EnrollmentOpen, &course.CreatedAt, &course.UpdatedAt)
+		`INSERT INTO courses (organization_id, term_id, name, description, enrollment_open, max_students) 
+                VALUES ($1, $2, $3, $4, $5, $6) 
+                RETURNING id`,
+		organizationID, termID, name, description, enrollmentOpen, maxStudents).Scan(&id)
 
 	if err != nil {
 		return nil, err
 	}
-	return &course, nil
+	return r.FindByID(ctx, id)
 }
 
 // FindByID retrieves a course by ID
 func (r *CourseRepository) FindByID(ctx context.Context, id string) (*models.Course, error) {
-	var course models.Course
-	err := r.db.Pool.QueryRow(ctx,
-		`SELECT id, organization_id, name, description, enrollment_open, created_at, updated_at 
-                FROM courses 
-                WHERE id = $1`,
-		id).Scan(&course.ID, &course.OrganizationID, &course.Name, &course.Description, &course.EnrollmentOpen, &course.CreatedAt, &course.UpdatedAt)
+	course, err := scanCourse(r.db.Pool.QueryRow(ctx,
+		`SELECT `+courseColumns+` 
+                FROM `+courseFrom+` 
+                WHERE c.id = $1`,
+		id))
 
 	if err != nil {
 		if err == pgx.ErrNoRows {
@@ -54,17 +103,17 @@ func (r *CourseRepository) FindByID(ctx context.Context, id string) (*models.Cou
 		}
 		return nil, err
 	}
-	return &course, nil
+	return course, nil
 }
 
-// FindByNameAndOrganization retrieves a course by name and organization
-func (r *CourseRepository) FindByNameAndOrganization(ctx context.Context, name, organizationID string) (*models.Course, error) {
-	var course models.Course
-	err := r.db.Pool.QueryRow(ctx,
-		`SELECT id, organization_id, name, description, enrollment_open, created_at, updated_at 
-                FROM courses 
-                WHERE name = $1 AND organization_id = $2`,
-		name, organizationID).Scan(&course.ID, &course.OrganizationID, &course.Name, &course.Description, &course.EnrollmentOpen, &course.CreatedAt, &course.UpdatedAt)
+// FindByNameAndTerm retrieves a course by name among the courses an organization offers in a term. A nil term
+// looks among the courses without a term.
+func (r *CourseRepository) FindByNameAndTerm(ctx context.Context, name, organizationID string, termID *string) (*models.Course, error) {
+	course, err := scanCourse(r.db.Pool.QueryRow(ctx,
+		`SELECT `+courseColumns+` 
+                FROM `+courseFrom+` 
+                WHERE c.name = $1 AND c.organization_id = $2 AND c.term_id IS NOT DISTINCT FROM $3`,
+		name, organizationID, termID))
 
 	if err != nil {
 		if err == pgx.ErrNoRows {
@@ -72,40 +121,27 @@ func (r *CourseRepository) FindByNameAndOrganization(ctx context.Context, name,
 		}
 		return nil, err
 	}
-	return &course, nil
+	return course, nil
 }
 
 // FindByOrganization retrieves all courses in an organization
 func (r *CourseRepository) FindByOrganization(ctx context.Context, organizationID string) ([]*models.Course, error) {
 	rows, err := r.db.Pool.Query(ctx,
-		`SELECT id, organization_id, name, description, enrollment_open, created_at, updated_at 
-                FROM courses 
-                WHERE organization_id = $1
-                ORDER BY name`,
+		`SELECT `+courseColumns+` 
+                FROM `+courseFrom+` 
+                WHERE c.organization_id = $1
+                ORDER BY c.name, t.start_date`,
 		organizationID)
 	if err != nil {
 		return nil, err
 	}
-	defer rows.Close()
 
-	var courses []*models.Course
-	for rows.Next() {
-		var course models.Course
-		if err := rows.Scan(&course.ID, &course.OrganizationID, &course.Name, &course.Description, &course.EnrollmentOpen, &course.CreatedAt, &course.UpdatedAt); err != nil {
-			return nil, err
-		}
-		courses = append(courses, &course)
-	}
-
-	if err := rows.Err(); err != nil {
-		return nil, err
-	}
-
-	return courses, nil
+	return scanCourses(rows)
 }
 
-// Update updates a course
-func (r *CourseRepository) Update(ctx context.Context, id string, name *string, description *string, enrollmentOpen *bool) (*models.Course, error) {
+// Update updates a course. A maximum of 0 students removes the limit; raising or removing it enrolls students
+// from the waitlist into the new seats.
+func (r *CourseRepository) Update(ctx context.Context, id string, name *string, description *string, enrollmentOpen *bool, maxStudents *int, termID *string) (*models.Course, error) {
 	tx, err := r.db.Pool.Begin(ctx)
 	if err != nil {
 		return nil, err
@@ -115,10 +151,11 @@ func (r *CourseRepository) Update(ctx context.Context, id string, name *string,
 	// Get current course
 	var course models.Course
 	err = tx.QueryRow(ctx,
-		`SELECT id, organization_id, name, description, enrollment_open, created_at, updated_at 
+		`SELECT id, name, description, enrollment_open, max_students, term_id 
                 FROM courses 
-                WHERE id = $1`,
-		id).Scan(&course.ID, &course.OrganizationID, &course.Name, &course.Description, &course.EnrollmentOpen, &course.CreatedAt, &course.UpdatedAt)
+                WHERE id = $1
+                FOR UPDATE`,
+		id).Scan(&course.ID, &course.Name, &course.Description, &course.EnrollmentOpen, &course.MaxStudents, &course.TermID)
 
 	if err != nil {
 		if err == pgx.ErrNoRows {
@@ -137,25 +174,36 @@ func (r *CourseRepository) Update(ctx context.Context, id string, name *string,
 	if enrollmentOpen != nil {
 		course.EnrollmentOpen = *enrollmentOpen
 	}
+	if termID != nil {
+		course.TermID = termID
+	}
+	if maxStudents != nil {
+		course.MaxStudents = maxStudents
+		if *maxStudents == 0 {
+			course.MaxStudents = nil
+		}
+	}
 
 	// Update in database
-	err = tx.QueryRow(ctx,
+	_, err = tx.Exec(ctx,
 		`UPDATE courses 
-                SET name = $2, description = $3, enrollment_open = $4, updated_at = $5
-                WHERE id = $1 
-                RETURNING id, organization_id, name, description, enrollment_open, created_at, updated_at`,
-		id, course.Name, course.Description, course.EnrollmentOpen, time.Now()).Scan(
-		&course.ID, &course.OrganizationID, &course.Name, &course.Description, &course.EnrollmentOpen, &course.CreatedAt, &course.UpdatedAt)
+                SET name = $2, description = $3, enrollment_open = $4, max_students = $5, term_id = $6, updated_at = $7
+                WHERE id = $1`,
+		id, course.Name, course.Description, course.EnrollmentOpen, course.MaxStudents, course.TermID, time.Now())
 
 	if err != nil {
 		return nil, err
 	}
 
+	if err := promoteWaitlisted(ctx, tx, id); err != nil {
+		return nil, err
+	}
+
 	if err := tx.Commit(ctx); err != nil {
 		return nil, err
 	}
 
-	return &course, nil
+	return r.FindByID(ctx, id)
 }
 
 // UpdateEnrollmentStatus updates a course's enrollment status
@@ -207,19 +255,28 @@ func (r *CourseRepository) AssignTeacher(ctx context.Context, courseID, teacherI
 	return err
 }
 
-// RemoveTeacher removes a teacher from a course
+// RemoveTeacher removes a teacher from a course and its sections
 func (r *CourseRepository) RemoveTeacher(ctx context.Context, courseID, teacherID string) error {
-	commandTag, err := r.db.Pool.Exec(ctx,
-		`DELETE FROM course_teachers 
-                WHERE course_id = $1 AND teacher_id = $2`,
-		courseID, teacherID)
-	if err != nil {
+	return r.db.ExecuteTransaction(ctx, func(tx pgx.Tx) error {
+		commandTag, err := tx.Exec(ctx,
+			`DELETE FROM course_teachers 
+                        WHERE course_id = $1 AND teacher_id = $2`,
+			courseID, teacherID)
+		if err != nil {
+			return err
+		}
+		if commandTag.RowsAffected() == 0 {
+			return errors.New("teacher not assigned to course")
+		}
+
+		// The teacher no longer teaches any section of the course either
+		_, err = tx.Exec(ctx,
+			`DELETE FROM section_teachers
+                        WHERE teacher_id = $2
+                        AND section_id IN (SELECT id FROM course_sections WHERE course_id = $1)`,
+			courseID, teacherID)
 		return err
-	}
-	if commandTag.RowsAffected() == 0 {
-		return errors.New("teacher not assigned to course")
-	}
-	return nil
+	})
 }
 
 // IsTeacherAssigned checks if a teacher is assigned to a course
@@ -275,26 +332,231 @@ func (r *CourseRepository) CountTeachersByCourse(ctx context.Context, courseID s
 	return count, err
 }
 
-// EnrollStudent enrolls a student in a course
-func (r *CourseRepository) EnrollStudent(ctx context.Context, courseID, studentID string) error {
-	_, err := r.db.Pool.Exec(ctx,
-		`INSERT INTO course_enrollments (course_id, student_id) 
-                VALUES ($1, $2)`,
-		courseID, studentID)
+// EnrollStudent enrolls a student in a course, into a section when the course has sections. When the course is
+// full, or others are already waiting, the student joins the end of its waitlist instead and their waitlist entry
+// is returned. A join code the student enrolls with is recorded with the enrollment, and used up by one once the
+// student takes a seat.
+func (r *CourseRepository) EnrollStudent(ctx context.Context, courseID, studentID string, sectionID, joinCodeID *string) (*models.WaitlistEntry, error) {
+	waitlisted := false
+	err := r.db.ExecuteTransaction(ctx, func(tx pgx.Tx) error {
+		// Lock the course so that concurrent enrollments cannot take the same seat
+		free, err := freeSeats(ctx, tx, courseID)
+		if err != nil {
+			return err
+		}
+
+		if joinCodeID != nil {
+			// Lock the code so that concurrent enrollments cannot take its last use
+			var valid bool
+			err = tx.QueryRow(ctx,
+				`SELECT revoked_at IS NULL AND expires_at > CURRENT_TIMESTAMP AND uses < max_uses
+                                FROM course_join_codes
+                                WHERE id = $1
+                                FOR UPDATE`,
+				*joinCodeID).Scan(&valid)
+			if err != nil && err != pgx.ErrNoRows {
+				return err
+			}
+			if !valid {
+				return errors.New("join code is no longer valid")
+			}
+		}
+
+		var waiting bool
+		err = tx.QueryRow(ctx,
+			`SELECT EXISTS(SELECT 1 FROM course_waitlist WHERE course_id = $1)`,
+			courseID).Scan(&waiting)
+		if err != nil {
+			return err
+		}
+
+		if free == 0 || (free > 0 && waiting) {
+			waitlisted = true
+			_, err = tx.Exec(ctx,
+				`INSERT INTO course_waitlist (course_id, student_id, section_id, join_code_id)
+                                VALUES ($1, $2, $3, $4)`,
+				courseID, studentID, sectionID, joinCodeID)
+			return err
+		}
+
+		_, err = tx.Exec(ctx,
+			`INSERT INTO course_enrollments (course_id, student_id, section_id, join_code_id) 
+                        VALUES ($1, $2, $3, $4)`,
+			courseID, studentID, sectionID, joinCodeID)
+		if err != nil || joinCodeID == nil {
+			return err
+		}
+
+		_, err = tx.Exec(ctx,
+			`UPDATE course_join_codes SET uses = uses + 1 WHERE id = $1`,
+			*joinCodeID)
+		return err
+	})
+	if err != nil || !waitlisted {
+		return nil, err
+	}
+
+	return r.FindWaitlistEntry(ctx, courseID, studentID)
+}
+
+// freeSeats locks a course and counts its free seats, -1 when it takes any number of students
+func freeSeats(ctx context.Context, tx pgx.Tx, courseID string) (int, error) {
+	var maxStudents *int
+	err := tx.QueryRow(ctx,
+		`SELECT max_students FROM courses WHERE id = $1 FOR UPDATE`,
+		courseID).Scan(&maxStudents)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return 0, errors.New("course not found")
+		}
+		return 0, err
+	}
+
+	if maxStudents == nil {
+		return -1, nil
+	}
+
+	var enrolled int
+	err = tx.QueryRow(ctx,
+		`SELECT COUNT(*) FROM course_enrollments WHERE course_id = $1`,
+		courseID).Scan(&enrolled)
+	if err != nil {
+		return 0, err
+	}
+
+	if enrolled >= *maxStudents {
+		return 0, nil
+	}
+	return *maxStudents - enrolled, nil
+}
+
+// promoteWaitlisted enrolls students from the waitlist of a course into its free seats, in the order they joined.
+// Students who waited with a join code use it up now; they were let in by the code, so a code that ran out of uses
+// meanwhile stays at its limit.
+func promoteWaitlisted(ctx context.Context, tx pgx.Tx, courseID string) error {
+	free, err := freeSeats(ctx, tx, courseID)
+	if err != nil || free == 0 {
+		return err
+	}
+
+	limit := &free
+	if free < 0 {
+		limit = nil
+	}
+
+	_, err = tx.Exec(ctx,
+		`WITH promoted AS (
+                        DELETE FROM course_waitlist
+                        WHERE (course_id, student_id) IN (
+                                SELECT course_id, student_id FROM course_waitlist
+                                WHERE course_id = $1
+                                ORDER BY created_at, student_id
+                                LIMIT $2
+                        )
+                        RETURNING course_id, student_id, section_id, join_code_id
+                ),
+                enrolled AS (
+                        INSERT INTO course_enrollments (course_id, student_id, section_id, join_code_id)
+                        SELECT course_id, student_id, section_id, join_code_id FROM promoted
+                        ON CONFLICT DO NOTHING
+                        RETURNING join_code_id
+                )
+                UPDATE course_join_codes j
+                SET uses = LEAST(j.uses + u.used, j.max_uses)
+                FROM (SELECT join_code_id, COUNT(*) AS used FROM enrolled
+                      WHERE join_code_id IS NOT NULL
+                      GROUP BY join_code_id) u
+                WHERE j.id = u.join_code_id`,
+		courseID, limit)
 	return err
 }
 
-// UnenrollStudent removes a student from a course
+// UnenrollStudent removes a student from a course and gives the freed seat to the first student on the waitlist
 func (r *CourseRepository) UnenrollStudent(ctx context.Context, courseID, studentID string) error {
+	return r.db.ExecuteTransaction(ctx, func(tx pgx.Tx) error {
+		commandTag, err := tx.Exec(ctx,
+			`DELETE FROM course_enrollments 
+                        WHERE course_id = $1 AND student_id = $2`,
+			courseID, studentID)
+		if err != nil {
+			return err
+		}
+		if commandTag.RowsAffected() == 0 {
+			return errors.New("student not enrolled in course")
+		}
+
+		return promoteWaitlisted(ctx, tx, courseID)
+	})
+}
+
+// waitlistColumns selects a waitlist entry w with its position, counted from 1 in the order students joined
+const waitlistColumns = `w.course_id, w.student_id, w.section_id, w.created_at,
+                (SELECT COUNT(*) FROM course_waitlist o
+                 WHERE o.course_id = w.course_id AND (o.created_at, o.student_id) <= (w.created_at, w.student_id))`
+
+// FindWaitlistEntry retrieves a student's entry on the waitlist of a course
+func (r *CourseRepository) FindWaitlistEntry(ctx context.Context, courseID, studentID string) (*models.WaitlistEntry, error) {
+	var entry models.WaitlistEntry
+	err := r.db.Pool.QueryRow(ctx,
+		`SELECT `+waitlistColumns+`
+                FROM course_waitlist w
+                WHERE w.course_id = $1 AND w.student_id = $2`,
+		courseID, studentID).Scan(&entry.CourseID, &entry.StudentID, &entry.SectionID, &entry.CreatedAt, &entry.Position)
+
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &entry, nil
+}
+
+// FindWaitlist retrieves the waitlist of a course in order, with the students
+func (r *CourseRepository) FindWaitlist(ctx context.Context, courseID string) ([]*models.WaitlistEntry, error) {
+	rows, err := r.db.Pool.Query(ctx,
+		`SELECT `+waitlistColumns+`,
+                        u.id, u.organization_id, u.email, u.first_name, u.last_name, u.role, u.created_at, u.updated_at
+                FROM course_waitlist w
+                JOIN users u ON u.id = w.student_id
+                WHERE w.course_id = $1
+                ORDER BY w.created_at, w.student_id`,
+		courseID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries := []*models.WaitlistEntry{}
+	for rows.Next() {
+		var entry models.WaitlistEntry
+		var student models.User
+		if err := rows.Scan(&entry.CourseID, &entry.StudentID, &entry.SectionID, &entry.CreatedAt, &entry.Position,
+			&student.ID, &student.OrganizationID, &student.Email, &student.FirstName, &student.LastName, &student.Role, &student.CreatedAt, &student.UpdatedAt); err != nil {
+			return nil, err
+		}
+		entry.Student = &student
+		entries = append(entries, &entry)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+// RemoveFromWaitlist takes a student off the waitlist of a course
+func (r *CourseRepository) RemoveFromWaitlist(ctx context.Context, courseID, studentID string) error {
 	commandTag, err := r.db.Pool.Exec(ctx,
-		`DELETE FROM course_enrollments 
+		`DELETE FROM course_waitlist
                 WHERE course_id = $1 AND student_id = $2`,
 		courseID, studentID)
 	if err != nil {
 		return err
 	}
 	if commandTag.RowsAffected() == 0 {
-		return errors.New("student not enrolled in course")
+		return errors.New("student not on the waitlist of the course")
 	}
 	return nil
 }
@@ -355,8 +617,8 @@ func (r *CourseRepository) CountStudentsByCourse(ctx context.Context, courseID s
 // FindByTeacher retrieves all courses assigned to a teacher
 func (r *CourseRepository) FindByTeacher(ctx context.Context, teacherID string) ([]*models.Course, error) {
 	rows, err := r.db.Pool.Query(ctx,
-		`SELECT c.id, c.organization_id, c.name, c.description, c.enrollment_open, c.created_at, c.updated_at
-                FROM courses c
+		`SELECT `+courseColumns+`
+                FROM `+courseFrom+`
                 JOIN course_teachers ct ON c.id = ct.course_id
                 WHERE ct.teacher_id = $1
                 ORDER BY c.name`,
@@ -364,29 +626,15 @@ func (r *CourseRepository) FindByTeacher(ctx context.Context, teacherID string)
 	if err != nil {
 		return nil, err
 	}
-	defer rows.Close()
-
-	var courses []*models.Course
-	for rows.Next() {
-		var course models.Course
-		if err := rows.Scan(&course.ID, &course.OrganizationID, &course.Name, &course.Description, &course.EnrollmentOpen, &course.CreatedAt, &course.UpdatedAt); err != nil {
-			return nil, err
-		}
-		courses = append(courses, &course)
-	}
-
-	if err := rows.Err(); err != nil {
-		return nil, err
-	}
 
-	return courses, nil
+	return scanCourses(rows)
 }
 
 // FindByStudent retrieves all courses a student is enrolled in
 func (r *CourseRepository) FindByStudent(ctx context.Context, studentID string) ([]*models.Course, error) {
 	rows, err := r.db.Pool.Query(ctx,
-		`SELECT c.id, c.organization_id, c.name, c.description, c.enrollment_open, c.created_at, c.updated_at
-                FROM courses c
+		`SELECT `+courseColumns+`
+                FROM `+courseFrom+`
                 JOIN course_enrollments ce ON c.id = ce.course_id
                 WHERE ce.student_id = $1
                 ORDER BY c.name`,
@@ -394,31 +642,35 @@ func (r *CourseRepository) FindByStudent(ctx context.Context, studentID string)
 	if err != nil {
 		return nil, err
 	}
-	defer rows.Close()
 
-	var courses []*models.Course
-	for rows.Next() {
-		var course models.Course
-		if err := rows.Scan(&course.ID, &course.OrganizationID, &course.Name, &course.Description, &course.EnrollmentOpen, &course.CreatedAt, &course.UpdatedAt); err != nil {
-			return nil, err
-		}
-		courses = append(courses, &course)
-	}
+	return scanCourses(rows)
+}
 
-	if err := rows.Err(); err != nil {
+// FindOfferingsByStudent retrieves the offerings of a course a student was enrolled in: the organization's courses
+// with the same name, one per term, most recent term first
+func (r *CourseRepository) FindOfferingsByStudent(ctx context.Context, organizationID, name, studentID string) ([]*models.Course, error) {
+	rows, err := r.db.Pool.Query(ctx,
+		`SELECT `+courseColumns+`
+                FROM `+courseFrom+`
+                JOIN course_enrollments ce ON c.id = ce.course_id
+                WHERE c.organization_id = $1 AND c.name = $2 AND ce.student_id = $3
+                ORDER BY t.start_date DESC NULLS LAST`,
+		organizationID, name, studentID)
+	if err != nil {
 		return nil, err
 	}
 
-	return courses, nil
+	return scanCourses(rows)
 }
 
 // FindAvailableCourses retrieves all courses available for enrollment
 func (r *CourseRepository) FindAvailableCourses(ctx context.Context, organizationID, studentID string) ([]*models.Course, error) {
 	rows, err := r.db.Pool.Query(ctx,
-		`SELECT c.id, c.organization_id, c.name, c.description, c.enrollment_open, c.created_at, c.updated_at
-                FROM courses c
+		`SELECT `+courseColumns+`
+                FROM `+courseFrom+`
                 WHERE c.organization_id = $1
                 AND c.enrollment_open = true
+                AND (t.end_date IS NULL OR t.end_date >= CURRENT_TIMESTAMP)
                 AND NOT EXISTS (
                         SELECT 1 FROM course_enrollments ce 
                         WHERE ce.course_id = c.id AND ce.student_id = $2
@@ -432,22 +684,8 @@ func (r *CourseRepository) FindAvailableCourses(ctx context.Context, organizatio
 	if err != nil {
 		return nil, err
 	}
-	defer rows.Close()
-
-	var courses []*models.Course
-	for rows.Next() {
-		var course models.Course
-		if err := rows.Scan(&course.ID, &course.OrganizationID, &course.Name, &course.Description, &course.EnrollmentOpen, &course.CreatedAt, &course.UpdatedAt); err != nil {
-			return nil, err
-		}
-		courses = append(courses, &course)
-	}
-
-	if err := rows.Err(); err != nil {
-		return nil, err
-	}
 
-	return courses, nil
+	return scanCourses(rows)
 }
 
 // CountByTeacher counts courses assigned to a teacher
@@ -0,0 +1,152 @@
+package repositories_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"assessment-management-system/db"
+	"assessment-management-system/db/dbtest"
+	"assessment-management-system/models"
+	"assessment-management-system/repositories"
+)
+
+// waitlistFixture is a course with one seat, taken by a student, and a join code of the course
+type waitlistFixture struct {
+	courses   *repositories.CourseRepository
+	joinCodes *repositories.JoinCodeRepository
+	course    *models.Course
+	code      *models.JoinCode
+	seated    *models.User
+	orgID     string
+}
+
+func newWaitlistFixture(t *testing.T, database *db.DB, maxUses int) *waitlistFixture {
+	t.Helper()
+	ctx := context.Background()
+
+	org := dbtest.Organization(t, database)
+	teacher := dbtest.User(t, database, org.ID, models.RoleTeacher)
+	term := dbtest.CurrentTerm(t, database, org.ID)
+	seats := 1
+	course := dbtest.Course(t, database, org.ID, term.ID, "Programming 1", &seats)
+
+	f := &waitlistFixture{
+		courses:   repositories.NewCourseRepository(database),
+		joinCodes: repositories.NewJoinCodeRepository(database),
+		course:    course,
+		seated:    dbtest.User(t, database, org.ID, models.RoleStudent),
+		orgID:     org.ID,
+	}
+
+	code, err := f.joinCodes.Create(ctx, course.ID, fmt.Sprintf("T%d", time.Now().UnixNano()%1e14), teacher.ID,
+		models.CreateJoinCodeRequest{ExpiresAt: time.Now().Add(24 * time.Hour), MaxUses: maxUses})
+	if err != nil {
+		t.Fatalf("failed to create join code: %v", err)
+	}
+	f.code = code
+
+	entry, err := f.courses.EnrollStudent(ctx, course.ID, f.seated.ID, nil, nil)
+	if err != nil || entry != nil {
+		t.Fatalf("expected the first student to take the seat, got %v, %v", entry, err)
+	}
+	return f
+}
+
+// waitWithCode puts a new student on the waitlist with the fixture's join code
+func (f *waitlistFixture) waitWithCode(t *testing.T, database *db.DB) *models.User {
+	t.Helper()
+
+	student := dbtest.User(t, database, f.orgID, models.RoleStudent)
+	entry, err := f.courses.EnrollStudent(context.Background(), f.course.ID, student.ID, nil, &f.code.ID)
+	if err != nil {
+		t.Fatalf("failed to enroll with join code: %v", err)
+	}
+	if entry == nil {
+		t.Fatalf("expected the student to wait for a seat")
+	}
+	return student
+}
+
+// uses reports how often the fixture's join code was used
+func (f *waitlistFixture) uses(t *testing.T) int {
+	t.Helper()
+
+	code, err := f.joinCodes.FindByID(context.Background(), f.code.ID)
+	if err != nil {
+		t.Fatalf("failed to find join code: %v", err)
+	}
+	return code.Uses
+}
+
+func TestUnenrollStudentPromotesWaitlistedStudent(t *testing.T) {
+	database := dbtest.Open(t)
+	ctx := context.Background()
+	f := newWaitlistFixture(t, database, 5)
+
+	waiting := f.waitWithCode(t, database)
+	if uses := f.uses(t); uses != 0 {
+		t.Fatalf("waiting for a seat used the join code: uses = %d", uses)
+	}
+
+	if err := f.courses.UnenrollStudent(ctx, f.course.ID, f.seated.ID); err != nil {
+		t.Fatalf("failed to unenroll: %v", err)
+	}
+
+	enrolled, err := f.courses.IsStudentEnrolled(ctx, f.course.ID, waiting.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !enrolled {
+		t.Fatalf("the waitlisted student was not given the freed seat")
+	}
+
+	entry, err := f.courses.FindWaitlistEntry(ctx, f.course.ID, waiting.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if entry != nil {
+		t.Fatalf("the promoted student is still on the waitlist")
+	}
+
+	if uses := f.uses(t); uses != 1 {
+		t.Fatalf("taking the seat did not use the join code: uses = %d", uses)
+	}
+}
+
+func TestEnrollStudentWithJoinCodeUsesItOnlyForSeats(t *testing.T) {
+	database := dbtest.Open(t)
+	ctx := context.Background()
+	f := newWaitlistFixture(t, database, 1)
+
+	// The code has one use left while both students wait
+	first := f.waitWithCode(t, database)
+	second := f.waitWithCode(t, database)
+
+	// Both get a seat; the second student keeps theirs although the code ran out meanwhile
+	seats := 3
+	if _, err := f.courses.Update(ctx, f.course.ID, nil, nil, nil, &seats, nil); err != nil {
+		t.Fatalf("failed to add seats: %v", err)
+	}
+
+	for _, student := range []*models.User{first, second} {
+		enrolled, err := f.courses.IsStudentEnrolled(ctx, f.course.ID, student.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !enrolled {
+			t.Fatalf("student %s was not promoted", student.ID)
+		}
+	}
+
+	if uses := f.uses(t); uses != 1 {
+		t.Fatalf("expected the join code to stop at its limit, uses = %d", uses)
+	}
+
+	// With the code used up, nobody else gets in with it
+	late := dbtest.User(t, database, f.orgID, models.RoleStudent)
+	if _, err := f.courses.EnrollStudent(ctx, f.course.ID, late.ID, nil, &f.code.ID); err == nil {
+		t.Fatalf("expected a used up join code to be refused")
+	}
+}
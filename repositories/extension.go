@@ -0,0 +1,109 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v4"
+
+	"assessment-management-system/db"
+	"assessment-management-system/models"
+)
+
+// ExtensionRepository handles database operations for per-student assessment extensions
+type ExtensionRepository struct {
+	db *db.DB
+}
+
+// NewExtensionRepository creates a new ExtensionRepository
+func NewExtensionRepository(db *db.DB) *ExtensionRepository {
+	return &ExtensionRepository{
+		db: db,
+	}
+}
+
+const extensionColumns = `id, assessment_id, student_id, due_date, cutoff_date, time_limit_minutes, max_attempts, COALESCE(reason, ''), granted_by, created_at, updated_at`
+
+// scanExtension scans an extension row selected with extensionColumns
+func scanExtension(row pgx.Row) (*models.Extension, error) {
+	var extension models.Extension
+	if err := row.Scan(&extension.ID, &extension.AssessmentID, &extension.StudentID, &extension.DueDate, &extension.CutoffDate,
+		&extension.TimeLimitMinutes, &extension.MaxAttempts, &extension.Reason, &extension.GrantedBy,
+		&extension.CreatedAt, &extension.UpdatedAt); err != nil {
+		return nil, err
+	}
+	return &extension, nil
+}
+
+// Save creates a student's extension for an assessment or replaces the existing one
+func (r *ExtensionRepository) Save(ctx context.Context, extension *models.Extension) (*models.Extension, error) {
+	return scanExtension(r.db.Pool.QueryRow(ctx,
+		`INSERT INTO assessment_extensions (assessment_id, student_id, due_date, cutoff_date, time_limit_minutes, max_attempts, reason, granted_by)
+                VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+                ON CONFLICT (assessment_id, student_id) DO UPDATE
+                SET due_date = EXCLUDED.due_date, cutoff_date = EXCLUDED.cutoff_date, time_limit_minutes = EXCLUDED.time_limit_minutes,
+                    max_attempts = EXCLUDED.max_attempts, reason = EXCLUDED.reason, granted_by = EXCLUDED.granted_by
+                RETURNING `+extensionColumns,
+		extension.AssessmentID, extension.StudentID, extension.DueDate, extension.CutoffDate, extension.TimeLimitMinutes,
+		extension.MaxAttempts, extension.Reason, extension.GrantedBy))
+}
+
+// FindByStudentAndAssessment retrieves a student's extension for an assessment
+func (r *ExtensionRepository) FindByStudentAndAssessment(ctx context.Context, assessmentID, studentID string) (*models.Extension, error) {
+	extension, err := scanExtension(r.db.Pool.QueryRow(ctx,
+		`SELECT `+extensionColumns+`
+                FROM assessment_extensions
+                WHERE assessment_id = $1 AND student_id = $2`,
+		assessmentID, studentID))
+
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return extension, nil
+}
+
+// FindByAssessment retrieves all extensions granted for an assessment
+func (r *ExtensionRepository) FindByAssessment(ctx context.Context, assessmentID string) ([]*models.Extension, error) {
+	rows, err := r.db.Pool.Query(ctx,
+		`SELECT `+extensionColumns+`
+                FROM assessment_extensions
+                WHERE assessment_id = $1
+                ORDER BY created_at`,
+		assessmentID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var extensions []*models.Extension
+	for rows.Next() {
+		extension, err := scanExtension(rows)
+		if err != nil {
+			return nil, err
+		}
+		extensions = append(extensions, extension)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return extensions, nil
+}
+
+// Delete revokes a student's extension for an assessment
+func (r *ExtensionRepository) Delete(ctx context.Context, assessmentID, studentID string) error {
+	commandTag, err := r.db.Pool.Exec(ctx,
+		`DELETE FROM assessment_extensions WHERE assessment_id = $1 AND student_id = $2`,
+		assessmentID, studentID)
+	if err != nil {
+		return err
+	}
+	if commandTag.RowsAffected() == 0 {
+		return errors.New("extension not found")
+	}
+	return nil
+}
@@ -0,0 +1,128 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v4"
+
+	"assessment-management-system/models"
+)
+
+const gradeVersionColumns = `id, submission_id, student_id, version, score, raw_score, late_penalty, COALESCE(feedback, ''), previous_score,
+                previous_raw_score, previous_late_penalty, previous_feedback, changed_by, reason, created_at`
+
+// scanGradeVersion scans a grade version row selected with gradeVersionColumns
+func scanGradeVersion(row pgx.Row) (*models.GradeVersion, error) {
+	var version models.GradeVersion
+	var previousScore, previousRawScore, previousLatePenalty *float64
+	var previousFeedback *string
+	if err := row.Scan(&version.ID, &version.SubmissionID, &version.StudentID, &version.Version, &version.New.Score, &version.New.RawScore,
+		&version.New.LatePenalty, &version.New.Feedback, &previousScore, &previousRawScore, &previousLatePenalty,
+		&previousFeedback, &version.ChangedBy, &version.Reason, &version.CreatedAt); err != nil {
+		return nil, err
+	}
+
+	if previousScore != nil {
+		version.Previous = &models.GradeValues{Score: *previousScore}
+		if previousRawScore != nil {
+			version.Previous.RawScore = *previousRawScore
+		}
+		if previousLatePenalty != nil {
+			version.Previous.LatePenalty = *previousLatePenalty
+		}
+		if previousFeedback != nil {
+			version.Previous.Feedback = *previousFeedback
+		}
+	}
+	return &version, nil
+}
+
+// lockGrade retrieves the grade of a submission within a transaction and locks it until the transaction ends,
+// so that its versions are numbered in order. It returns nil when the submission has no grade.
+func lockGrade(ctx context.Context, tx pgx.Tx, submissionID string) (*models.Grade, error) {
+	grade, err := scanGrade(tx.QueryRow(ctx,
+		`SELECT `+gradeColumns+`
+                FROM grades
+                WHERE submission_id = $1
+                FOR UPDATE`,
+		submissionID))
+
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return grade, nil
+}
+
+// recordGradeVersion appends the next version to the history of a grade. previous is the grade before the
+// change, nil when the change created it.
+func recordGradeVersion(ctx context.Context, tx pgx.Tx, previous, grade *models.Grade, changedBy, reason string) error {
+	var previousScore, previousRawScore, previousLatePenalty *float64
+	var previousFeedback *string
+	if previous != nil {
+		previousScore, previousRawScore, previousLatePenalty = &previous.Score, &previous.RawScore, &previous.LatePenalty
+		previousFeedback = &previous.Feedback
+	}
+
+	_, err := tx.Exec(ctx,
+		`INSERT INTO grade_versions (submission_id, version, score, raw_score, late_penalty, feedback,
+                        previous_score, previous_raw_score, previous_late_penalty, previous_feedback, changed_by, reason)
+                VALUES ($1, (SELECT COALESCE(MAX(version), 0) + 1 FROM grade_versions WHERE submission_id = $1),
+                        $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)`,
+		grade.SubmissionID, grade.Score, grade.RawScore, grade.LatePenalty, grade.Feedback,
+		previousScore, previousRawScore, previousLatePenalty, previousFeedback, changedBy, reason)
+	return err
+}
+
+// recordMemberGradeVersion appends the next version to the history of a grade for an adjustment of one member's
+// grade. The member's grade is the adjusted score, late penalty included, or else the group's grade.
+func recordMemberGradeVersion(ctx context.Context, tx pgx.Tx, grade *models.Grade, studentID string, previousAdjustment, adjustment *float64, changedBy, reason string) error {
+	memberValues := func(adjusted *float64) (float64, float64, float64) {
+		if adjusted == nil {
+			return grade.Score, grade.RawScore, grade.LatePenalty
+		}
+		return *adjusted, *adjusted, 0
+	}
+	score, rawScore, latePenalty := memberValues(adjustment)
+	previousScore, previousRawScore, previousLatePenalty := memberValues(previousAdjustment)
+
+	_, err := tx.Exec(ctx,
+		`INSERT INTO grade_versions (submission_id, student_id, version, score, raw_score, late_penalty, feedback,
+                        previous_score, previous_raw_score, previous_late_penalty, previous_feedback, changed_by, reason)
+                VALUES ($1, $2, (SELECT COALESCE(MAX(version), 0) + 1 FROM grade_versions WHERE submission_id = $1),
+                        $3, $4, $5, $6, $7, $8, $9, $6, $10, $11)`,
+		grade.SubmissionID, studentID, score, rawScore, latePenalty, grade.Feedback,
+		previousScore, previousRawScore, previousLatePenalty, changedBy, reason)
+	return err
+}
+
+// FindGradeVersions retrieves the history of a submission's grade, oldest version first
+func (r *AssessmentRepository) FindGradeVersions(ctx context.Context, submissionID string) ([]*models.GradeVersion, error) {
+	rows, err := r.db.Pool.Query(ctx,
+		`SELECT `+gradeVersionColumns+`
+                FROM grade_versions
+                WHERE submission_id = $1
+                ORDER BY version`,
+		submissionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	versions := []*models.GradeVersion{}
+	for rows.Next() {
+		version, err := scanGradeVersion(rows)
+		if err != nil {
+			return nil, err
+		}
+		versions = append(versions, version)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return versions, nil
+}
@@ -0,0 +1,159 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+
+	"assessment-management-system/db"
+	"assessment-management-system/models"
+)
+
+// GradebookRepository handles database operations for course gradebooks
+type GradebookRepository struct {
+	db *db.DB
+}
+
+// NewGradebookRepository creates a new GradebookRepository
+func NewGradebookRepository(db *db.DB) *GradebookRepository {
+	return &GradebookRepository{
+		db: db,
+	}
+}
+
+const gradeCategoryColumns = `id, course_id, name, weight, drop_lowest, created_at, updated_at`
+
+// scanGradeCategory scans a grade category row selected with gradeCategoryColumns
+func scanGradeCategory(row pgx.Row) (*models.GradeCategory, error) {
+	var category models.GradeCategory
+	if err := row.Scan(&category.ID, &category.CourseID, &category.Name, &category.Weight, &category.DropLowest,
+		&category.CreatedAt, &category.UpdatedAt); err != nil {
+		return nil, err
+	}
+	return &category, nil
+}
+
+// CreateCategory adds a grade category to a course
+func (r *GradebookRepository) CreateCategory(ctx context.Context, courseID string, req models.CreateGradeCategoryRequest) (*models.GradeCategory, error) {
+	return scanGradeCategory(r.db.Pool.QueryRow(ctx,
+		`INSERT INTO grade_categories (course_id, name, weight, drop_lowest)
+                VALUES ($1, $2, $3, $4)
+                RETURNING `+gradeCategoryColumns,
+		courseID, req.Name, req.Weight, req.DropLowest))
+}
+
+// FindCategoryByID retrieves a grade category by ID
+func (r *GradebookRepository) FindCategoryByID(ctx context.Context, id string) (*models.GradeCategory, error) {
+	category, err := scanGradeCategory(r.db.Pool.QueryRow(ctx,
+		`SELECT `+gradeCategoryColumns+`
+                FROM grade_categories
+                WHERE id = $1`,
+		id))
+
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return category, nil
+}
+
+// FindCategoriesByCourse retrieves the grade categories of a course
+func (r *GradebookRepository) FindCategoriesByCourse(ctx context.Context, courseID string) ([]*models.GradeCategory, error) {
+	rows, err := r.db.Pool.Query(ctx,
+		`SELECT `+gradeCategoryColumns+`
+                FROM grade_categories
+                WHERE course_id = $1
+                ORDER BY created_at`,
+		courseID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	categories := []*models.GradeCategory{}
+	for rows.Next() {
+		category, err := scanGradeCategory(rows)
+		if err != nil {
+			return nil, err
+		}
+		categories = append(categories, category)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return categories, nil
+}
+
+// UpdateCategory saves the name, weight and drop count of a grade category
+func (r *GradebookRepository) UpdateCategory(ctx context.Context, category *models.GradeCategory) (*models.GradeCategory, error) {
+	updated, err := scanGradeCategory(r.db.Pool.QueryRow(ctx,
+		`UPDATE grade_categories
+                SET name = $2, weight = $3, drop_lowest = $4, updated_at = $5
+                WHERE id = $1
+                RETURNING `+gradeCategoryColumns,
+		category.ID, category.Name, category.Weight, category.DropLowest, time.Now()))
+
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, errors.New("grade category not found")
+		}
+		return nil, err
+	}
+	return updated, nil
+}
+
+// DeleteCategory deletes a grade category, its assessments are left without a category
+func (r *GradebookRepository) DeleteCategory(ctx context.Context, id string) error {
+	commandTag, err := r.db.Pool.Exec(ctx,
+		`DELETE FROM grade_categories WHERE id = $1`,
+		id)
+	if err != nil {
+		return err
+	}
+	if commandTag.RowsAffected() == 0 {
+		return errors.New("grade category not found")
+	}
+	return nil
+}
+
+// FindFinalScores retrieves the score that counts for every graded student and assessment of a course, under each
+// assessment's attempt policy. An empty studentID selects all students. With releasedOnly, only the grades students
+// can see are counted.
+func (r *GradebookRepository) FindFinalScores(ctx context.Context, courseID, studentID string, releasedOnly bool) ([]*models.StudentAssessmentScore, error) {
+	rows, err := r.db.Pool.Query(ctx,
+		`SELECT ss.student_id, a.id, `+finalScoreExpr+`
+                FROM grades g
+                JOIN assessment_submissions s ON g.submission_id = s.id
+                JOIN submission_students ss ON s.id = ss.submission_id
+                JOIN assessments a ON s.assessment_id = a.id
+                WHERE a.course_id = $1
+                AND ($2 = '' OR ss.student_id::text = $2)
+                AND (NOT $3 OR `+gradeReleasedExpr+`)
+                GROUP BY ss.student_id, a.id, a.attempt_policy`,
+		courseID, studentID, releasedOnly)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var scores []*models.StudentAssessmentScore
+	for rows.Next() {
+		var score models.StudentAssessmentScore
+		if err := rows.Scan(&score.StudentID, &score.AssessmentID, &score.Score); err != nil {
+			return nil, err
+		}
+		scores = append(scores, &score)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return scores, nil
+}
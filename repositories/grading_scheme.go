@@ -0,0 +1,128 @@
+package repositories
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/jackc/pgx/v4"
+
+	"assessment-management-system/db"
+	"assessment-management-system/models"
+)
+
+// GradingSchemeRepository handles database operations for letter-grade schemes
+type GradingSchemeRepository struct {
+	db *db.DB
+}
+
+// NewGradingSchemeRepository creates a new GradingSchemeRepository
+func NewGradingSchemeRepository(db *db.DB) *GradingSchemeRepository {
+	return &GradingSchemeRepository{
+		db: db,
+	}
+}
+
+const gradingSchemeColumns = `id, organization_id, course_id, name, bands, created_at, updated_at`
+
+// scanGradingScheme scans a grading scheme row, decoding the JSON bands
+func scanGradingScheme(row pgx.Row) (*models.GradingScheme, error) {
+	var scheme models.GradingScheme
+	var bands []byte
+	if err := row.Scan(&scheme.ID, &scheme.OrganizationID, &scheme.CourseID, &scheme.Name, &bands,
+		&scheme.CreatedAt, &scheme.UpdatedAt); err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(bands, &scheme.Bands); err != nil {
+		return nil, err
+	}
+	return &scheme, nil
+}
+
+// findOne runs a query selecting at most one grading scheme, a missing scheme is returned as nil
+func (r *GradingSchemeRepository) findOne(ctx context.Context, query string, args ...interface{}) (*models.GradingScheme, error) {
+	scheme, err := scanGradingScheme(r.db.Pool.QueryRow(ctx, query, args...))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return scheme, nil
+}
+
+// SaveForOrganization defines the grading scheme of an organization or replaces the existing one
+func (r *GradingSchemeRepository) SaveForOrganization(ctx context.Context, organizationID, name string, bands []models.GradeBand) (*models.GradingScheme, error) {
+	encoded, err := json.Marshal(bands)
+	if err != nil {
+		return nil, err
+	}
+
+	return scanGradingScheme(r.db.Pool.QueryRow(ctx,
+		`INSERT INTO grading_schemes (organization_id, name, bands)
+                VALUES ($1, $2, $3)
+                ON CONFLICT (organization_id) WHERE organization_id IS NOT NULL DO UPDATE
+                SET name = EXCLUDED.name, bands = EXCLUDED.bands
+                RETURNING `+gradingSchemeColumns,
+		organizationID, name, encoded))
+}
+
+// SaveForCourse defines the grading scheme of a course or replaces the existing one
+func (r *GradingSchemeRepository) SaveForCourse(ctx context.Context, courseID, name string, bands []models.GradeBand) (*models.GradingScheme, error) {
+	encoded, err := json.Marshal(bands)
+	if err != nil {
+		return nil, err
+	}
+
+	return scanGradingScheme(r.db.Pool.QueryRow(ctx,
+		`INSERT INTO grading_schemes (course_id, name, bands)
+                VALUES ($1, $2, $3)
+                ON CONFLICT (course_id) WHERE course_id IS NOT NULL DO UPDATE
+                SET name = EXCLUDED.name, bands = EXCLUDED.bands
+                RETURNING `+gradingSchemeColumns,
+		courseID, name, encoded))
+}
+
+// FindByOrganization retrieves the grading scheme of an organization
+func (r *GradingSchemeRepository) FindByOrganization(ctx context.Context, organizationID string) (*models.GradingScheme, error) {
+	return r.findOne(ctx,
+		`SELECT `+gradingSchemeColumns+`
+                FROM grading_schemes
+                WHERE organization_id = $1`,
+		organizationID)
+}
+
+// FindForCourse retrieves the grading scheme that applies to a course: its own, or else its organization's
+func (r *GradingSchemeRepository) FindForCourse(ctx context.Context, courseID string) (*models.GradingScheme, error) {
+	return r.findOne(ctx,
+		`SELECT `+gradingSchemeColumns+`
+                FROM grading_schemes
+                WHERE course_id = $1
+                OR organization_id = (SELECT organization_id FROM courses WHERE id = $1)
+                ORDER BY course_id IS NULL
+                LIMIT 1`,
+		courseID)
+}
+
+// DeleteForOrganization deletes the grading scheme of an organization
+func (r *GradingSchemeRepository) DeleteForOrganization(ctx context.Context, organizationID string) error {
+	return r.delete(ctx, `DELETE FROM grading_schemes WHERE organization_id = $1`, organizationID)
+}
+
+// DeleteForCourse deletes the grading scheme of a course, which then follows its organization's scheme again
+func (r *GradingSchemeRepository) DeleteForCourse(ctx context.Context, courseID string) error {
+	return r.delete(ctx, `DELETE FROM grading_schemes WHERE course_id = $1`, courseID)
+}
+
+// delete runs a statement deleting one grading scheme
+func (r *GradingSchemeRepository) delete(ctx context.Context, query, ownerID string) error {
+	commandTag, err := r.db.Pool.Exec(ctx, query, ownerID)
+	if err != nil {
+		return err
+	}
+	if commandTag.RowsAffected() == 0 {
+		return errors.New("grading scheme not found")
+	}
+	return nil
+}
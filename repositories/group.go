@@ -0,0 +1,374 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v4"
+
+	"assessment-management-system/db"
+	"assessment-management-system/models"
+)
+
+// GroupRepository handles database operations for course groups and group submissions
+type GroupRepository struct {
+	db *db.DB
+}
+
+// NewGroupRepository creates a new GroupRepository
+func NewGroupRepository(db *db.DB) *GroupRepository {
+	return &GroupRepository{
+		db: db,
+	}
+}
+
+const groupColumns = `id, course_id, name, max_size, self_signup, created_by, created_at, updated_at`
+
+// scanGroup scans a group row selected with groupColumns
+func scanGroup(row pgx.Row) (*models.CourseGroup, error) {
+	var group models.CourseGroup
+	if err := row.Scan(&group.ID, &group.CourseID, &group.Name, &group.MaxSize, &group.SelfSignup, &group.CreatedBy,
+		&group.CreatedAt, &group.UpdatedAt); err != nil {
+		return nil, err
+	}
+	group.Members = []*models.GroupMember{}
+	return &group, nil
+}
+
+// loadMembers loads the members of the groups, in the order they joined
+func (r *GroupRepository) loadMembers(ctx context.Context, groups ...*models.CourseGroup) error {
+	for _, group := range groups {
+		rows, err := r.db.Pool.Query(ctx,
+			`SELECT m.student_id, u.first_name, u.last_name, u.email, m.joined_at
+                        FROM course_group_members m
+                        JOIN users u ON m.student_id = u.id
+                        WHERE m.group_id = $1
+                        ORDER BY m.joined_at, u.last_name`,
+			group.ID)
+		if err != nil {
+			return err
+		}
+
+		for rows.Next() {
+			var member models.GroupMember
+			if err := rows.Scan(&member.StudentID, &member.FirstName, &member.LastName, &member.Email, &member.JoinedAt); err != nil {
+				rows.Close()
+				return err
+			}
+			group.Members = append(group.Members, &member)
+		}
+		rows.Close()
+
+		if err := rows.Err(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Create creates a group in a course
+func (r *GroupRepository) Create(ctx context.Context, courseID, createdBy string, req models.CreateGroupRequest) (*models.CourseGroup, error) {
+	return scanGroup(r.db.Pool.QueryRow(ctx,
+		`INSERT INTO course_groups (course_id, name, max_size, self_signup, created_by)
+                VALUES ($1, $2, $3, $4, $5)
+                RETURNING `+groupColumns,
+		courseID, req.Name, req.MaxSize, req.SelfSignup, createdBy))
+}
+
+// FindByID retrieves a group by ID with its members
+func (r *GroupRepository) FindByID(ctx context.Context, id string) (*models.CourseGroup, error) {
+	group, err := scanGroup(r.db.Pool.QueryRow(ctx,
+		`SELECT `+groupColumns+`
+                FROM course_groups
+                WHERE id = $1`,
+		id))
+
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	if err := r.loadMembers(ctx, group); err != nil {
+		return nil, err
+	}
+	return group, nil
+}
+
+// FindByCourse retrieves the groups of a course with their members, by name
+func (r *GroupRepository) FindByCourse(ctx context.Context, courseID string) ([]*models.CourseGroup, error) {
+	rows, err := r.db.Pool.Query(ctx,
+		`SELECT `+groupColumns+`
+                FROM course_groups
+                WHERE course_id = $1
+                ORDER BY name`,
+		courseID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	groups := []*models.CourseGroup{}
+	for rows.Next() {
+		group, err := scanGroup(rows)
+		if err != nil {
+			return nil, err
+		}
+		groups = append(groups, group)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	rows.Close()
+
+	if err := r.loadMembers(ctx, groups...); err != nil {
+		return nil, err
+	}
+	return groups, nil
+}
+
+// FindByStudent retrieves the group a student belongs to in a course, if any
+func (r *GroupRepository) FindByStudent(ctx context.Context, courseID, studentID string) (*models.CourseGroup, error) {
+	var groupID string
+	err := r.db.Pool.QueryRow(ctx,
+		`SELECT group_id FROM course_group_members WHERE course_id = $1 AND student_id = $2`,
+		courseID, studentID).Scan(&groupID)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return r.FindByID(ctx, groupID)
+}
+
+// Update updates the name and settings of a group
+func (r *GroupRepository) Update(ctx context.Context, id string, req models.UpdateGroupRequest) (*models.CourseGroup, error) {
+	group, err := r.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if group == nil {
+		return nil, errors.New("group not found")
+	}
+
+	if req.Name != nil {
+		group.Name = *req.Name
+	}
+	if req.MaxSize != nil {
+		group.MaxSize = req.MaxSize
+	}
+	if req.SelfSignup != nil {
+		group.SelfSignup = *req.SelfSignup
+	}
+
+	_, err = r.db.Pool.Exec(ctx,
+		`UPDATE course_groups SET name = $2, max_size = $3, self_signup = $4 WHERE id = $1`,
+		id, group.Name, group.MaxSize, group.SelfSignup)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.FindByID(ctx, id)
+}
+
+// Delete deletes a group. Submissions the group made keep the members they were made by.
+func (r *GroupRepository) Delete(ctx context.Context, id string) error {
+	commandTag, err := r.db.Pool.Exec(ctx,
+		`DELETE FROM course_groups WHERE id = $1`,
+		id)
+	if err != nil {
+		return err
+	}
+	if commandTag.RowsAffected() == 0 {
+		return errors.New("group not found")
+	}
+	return nil
+}
+
+// AddMember adds a student to a group within the group's size limit. A student belongs to one group per course.
+func (r *GroupRepository) AddMember(ctx context.Context, groupID, studentID string) (*models.CourseGroup, error) {
+	err := r.db.ExecuteTransaction(ctx, func(tx pgx.Tx) error {
+		var courseID string
+		var maxSize *int
+		err := tx.QueryRow(ctx,
+			`SELECT course_id, max_size FROM course_groups WHERE id = $1 FOR UPDATE`,
+			groupID).Scan(&courseID, &maxSize)
+		if err != nil {
+			if err == pgx.ErrNoRows {
+				return errors.New("group not found")
+			}
+			return err
+		}
+
+		var inGroup bool
+		err = tx.QueryRow(ctx,
+			`SELECT EXISTS (SELECT 1 FROM course_group_members WHERE course_id = $1 AND student_id = $2)`,
+			courseID, studentID).Scan(&inGroup)
+		if err != nil {
+			return err
+		}
+		if inGroup {
+			return errors.New("the student already belongs to a group in this course")
+		}
+
+		if maxSize != nil {
+			var members int
+			err := tx.QueryRow(ctx,
+				`SELECT COUNT(*) FROM course_group_members WHERE group_id = $1`,
+				groupID).Scan(&members)
+			if err != nil {
+				return err
+			}
+			if members >= *maxSize {
+				return errors.New("the group is full")
+			}
+		}
+
+		_, err = tx.Exec(ctx,
+			`INSERT INTO course_group_members (group_id, course_id, student_id) VALUES ($1, $2, $3)`,
+			groupID, courseID, studentID)
+		return err
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return r.FindByID(ctx, groupID)
+}
+
+// RemoveMember removes a student from a group
+func (r *GroupRepository) RemoveMember(ctx context.Context, groupID, studentID string) error {
+	commandTag, err := r.db.Pool.Exec(ctx,
+		`DELETE FROM course_group_members WHERE group_id = $1 AND student_id = $2`,
+		groupID, studentID)
+	if err != nil {
+		return err
+	}
+	if commandTag.RowsAffected() == 0 {
+		return errors.New("the student is not a member of this group")
+	}
+	return nil
+}
+
+// CountAttempts counts the attempts a group has made at an assessment
+func (r *GroupRepository) CountAttempts(ctx context.Context, assessmentID, groupID string) (int, error) {
+	var count int
+	err := r.db.Pool.QueryRow(ctx,
+		`SELECT COUNT(*) FROM assessment_submissions WHERE assessment_id = $1 AND group_id = $2`,
+		assessmentID, groupID).Scan(&count)
+	return count, err
+}
+
+const submissionMemberColumns = `submission_id, student_id, adjusted_score, COALESCE(adjustment_reason, ''), adjusted_by, adjusted_at`
+
+// scanSubmissionMember scans a group submission member row selected with submissionMemberColumns
+func scanSubmissionMember(row pgx.Row) (*models.SubmissionMember, error) {
+	var member models.SubmissionMember
+	if err := row.Scan(&member.SubmissionID, &member.StudentID, &member.AdjustedScore, &member.AdjustmentReason,
+		&member.AdjustedBy, &member.AdjustedAt); err != nil {
+		return nil, err
+	}
+	return &member, nil
+}
+
+// FindSubmissionMembers retrieves the members a group submission was made by
+func (r *GroupRepository) FindSubmissionMembers(ctx context.Context, submissionID string) ([]*models.SubmissionMember, error) {
+	rows, err := r.db.Pool.Query(ctx,
+		`SELECT `+submissionMemberColumns+`
+                FROM submission_group_members
+                WHERE submission_id = $1`,
+		submissionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	members := []*models.SubmissionMember{}
+	for rows.Next() {
+		member, err := scanSubmissionMember(rows)
+		if err != nil {
+			return nil, err
+		}
+		members = append(members, member)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return members, nil
+}
+
+// FindSubmissionMember retrieves one member of a group submission, if they were in the group
+func (r *GroupRepository) FindSubmissionMember(ctx context.Context, submissionID, studentID string) (*models.SubmissionMember, error) {
+	member, err := scanSubmissionMember(r.db.Pool.QueryRow(ctx,
+		`SELECT `+submissionMemberColumns+`
+                FROM submission_group_members
+                WHERE submission_id = $1 AND student_id = $2`,
+		submissionID, studentID))
+
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return member, nil
+}
+
+// AdjustMemberScore sets the score a member of a group submission gets in place of the group's, and records the
+// change in the history of the submission's grade. A nil score gives the member the group's score again.
+func (r *GroupRepository) AdjustMemberScore(ctx context.Context, submissionID, studentID string, score *float64, reason, adjustedBy string) (*models.SubmissionMember, error) {
+	var member *models.SubmissionMember
+	err := r.db.ExecuteTransaction(ctx, func(tx pgx.Tx) error {
+		grade, err := lockGrade(ctx, tx, submissionID)
+		if err != nil {
+			return err
+		}
+		if grade == nil {
+			return errors.New("grade the group submission before adjusting it for a member")
+		}
+
+		var previous *float64
+		err = tx.QueryRow(ctx,
+			`SELECT adjusted_score FROM submission_group_members
+                        WHERE submission_id = $1 AND student_id = $2
+                        FOR UPDATE`,
+			submissionID, studentID).Scan(&previous)
+		if err != nil {
+			if err == pgx.ErrNoRows {
+				return errors.New("the student is not a member of the group that made this submission")
+			}
+			return err
+		}
+
+		// The member keeps the reason for an adjustment only while it stands; the history keeps every reason
+		adjustmentReason := reason
+		if score == nil {
+			adjustmentReason = ""
+		}
+
+		member, err = scanSubmissionMember(tx.QueryRow(ctx,
+			`UPDATE submission_group_members
+                        SET adjusted_score = $3, adjustment_reason = NULLIF($4, ''), adjusted_by = $5, adjusted_at = CURRENT_TIMESTAMP
+                        WHERE submission_id = $1 AND student_id = $2
+                        RETURNING `+submissionMemberColumns,
+			submissionID, studentID, score, adjustmentReason, adjustedBy))
+		if err != nil {
+			return err
+		}
+
+		return recordMemberGradeVersion(ctx, tx, grade, studentID, previous, score, adjustedBy, reason)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+	return member, nil
+}
@@ -0,0 +1,154 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+
+	"assessment-management-system/db"
+	"assessment-management-system/models"
+)
+
+// JoinCodeRepository handles database operations for course join codes
+type JoinCodeRepository struct {
+	db *db.DB
+}
+
+// NewJoinCodeRepository creates a new JoinCodeRepository
+func NewJoinCodeRepository(db *db.DB) *JoinCodeRepository {
+	return &JoinCodeRepository{
+		db: db,
+	}
+}
+
+const joinCodeColumns = `id, course_id, section_id, code, created_by, expires_at, max_uses, uses, revoked_at, created_at`
+
+// scanJoinCode scans a join code row selected with joinCodeColumns
+func scanJoinCode(row pgx.Row) (*models.JoinCode, error) {
+	var code models.JoinCode
+	if err := row.Scan(&code.ID, &code.CourseID, &code.SectionID, &code.Code, &code.CreatedBy, &code.ExpiresAt,
+		&code.MaxUses, &code.Uses, &code.RevokedAt, &code.CreatedAt); err != nil {
+		return nil, err
+	}
+	return &code, nil
+}
+
+// Create creates a join code for a course
+func (r *JoinCodeRepository) Create(ctx context.Context, courseID, code, createdBy string, req models.CreateJoinCodeRequest) (*models.JoinCode, error) {
+	return scanJoinCode(r.db.Pool.QueryRow(ctx,
+		`INSERT INTO course_join_codes (course_id, section_id, code, created_by, expires_at, max_uses)
+                VALUES ($1, $2, $3, $4, $5, $6)
+                RETURNING `+joinCodeColumns,
+		courseID, req.SectionID, code, createdBy, req.ExpiresAt, req.MaxUses))
+}
+
+// FindByID retrieves a join code by ID
+func (r *JoinCodeRepository) FindByID(ctx context.Context, id string) (*models.JoinCode, error) {
+	code, err := scanJoinCode(r.db.Pool.QueryRow(ctx,
+		`SELECT `+joinCodeColumns+`
+                FROM course_join_codes
+                WHERE id = $1`,
+		id))
+
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return code, nil
+}
+
+// FindByCode retrieves a join code by the code students type
+func (r *JoinCodeRepository) FindByCode(ctx context.Context, code string) (*models.JoinCode, error) {
+	joinCode, err := scanJoinCode(r.db.Pool.QueryRow(ctx,
+		`SELECT `+joinCodeColumns+`
+                FROM course_join_codes
+                WHERE code = $1`,
+		code))
+
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return joinCode, nil
+}
+
+// FindByCourse retrieves the join codes of a course, newest first
+func (r *JoinCodeRepository) FindByCourse(ctx context.Context, courseID string) ([]*models.JoinCode, error) {
+	rows, err := r.db.Pool.Query(ctx,
+		`SELECT `+joinCodeColumns+`
+                FROM course_join_codes
+                WHERE course_id = $1
+                ORDER BY created_at DESC`,
+		courseID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	codes := []*models.JoinCode{}
+	for rows.Next() {
+		code, err := scanJoinCode(rows)
+		if err != nil {
+			return nil, err
+		}
+		codes = append(codes, code)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return codes, nil
+}
+
+// Revoke stops a join code from being used. Enrollments made with it are kept.
+func (r *JoinCodeRepository) Revoke(ctx context.Context, id string) error {
+	commandTag, err := r.db.Pool.Exec(ctx,
+		`UPDATE course_join_codes
+                SET revoked_at = $2
+                WHERE id = $1 AND revoked_at IS NULL`,
+		id, time.Now())
+	if err != nil {
+		return err
+	}
+	if commandTag.RowsAffected() == 0 {
+		return errors.New("join code not found or already revoked")
+	}
+	return nil
+}
+
+// FindStudents retrieves the students enrolled in a course with a join code
+func (r *JoinCodeRepository) FindStudents(ctx context.Context, id string) ([]*models.User, error) {
+	rows, err := r.db.Pool.Query(ctx,
+		`SELECT u.id, u.organization_id, u.email, u.first_name, u.last_name, u.role, u.created_at, u.updated_at
+                FROM users u
+                JOIN course_enrollments ce ON u.id = ce.student_id
+                WHERE ce.join_code_id = $1
+                ORDER BY u.first_name, u.last_name`,
+		id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	students := []*models.User{}
+	for rows.Next() {
+		var student models.User
+		if err := rows.Scan(&student.ID, &student.OrganizationID, &student.Email, &student.FirstName, &student.LastName, &student.Role, &student.CreatedAt, &student.UpdatedAt); err != nil {
+			return nil, err
+		}
+		students = append(students, &student)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return students, nil
+}
@@ -0,0 +1,229 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v4"
+
+	"assessment-management-system/db"
+	"assessment-management-system/models"
+)
+
+// ModerationRepository handles database operations for the double marking of submissions
+type ModerationRepository struct {
+	db *db.DB
+}
+
+// NewModerationRepository creates a new ModerationRepository
+func NewModerationRepository(db *db.DB) *ModerationRepository {
+	return &ModerationRepository{
+		db: db,
+	}
+}
+
+// moderationColumns selects a moderation m joined with its submission s
+const moderationColumns = `m.submission_id, s.assessment_id, m.first_marker_id, m.first_score, COALESCE(m.first_feedback, ''),
+                m.second_marker_id, m.second_score, COALESCE(m.second_feedback, ''), m.status, m.moderator_id, m.final_score,
+                m.agreed_at, m.assigned_by, m.created_at, m.updated_at`
+
+const moderationFrom = `submission_moderations m JOIN assessment_submissions s ON m.submission_id = s.id`
+
+// scanModeration scans a moderation row selected with moderationColumns
+func scanModeration(row pgx.Row) (*models.Moderation, error) {
+	var moderation models.Moderation
+	if err := row.Scan(&moderation.SubmissionID, &moderation.AssessmentID, &moderation.FirstMarkerID, &moderation.FirstScore,
+		&moderation.FirstFeedback, &moderation.SecondMarkerID, &moderation.SecondScore, &moderation.SecondFeedback,
+		&moderation.Status, &moderation.ModeratorID, &moderation.FinalScore, &moderation.AgreedAt, &moderation.AssignedBy,
+		&moderation.CreatedAt, &moderation.UpdatedAt); err != nil {
+		return nil, err
+	}
+	return &moderation, nil
+}
+
+// Assign puts a submission up for double marking with a second marker. The second marker can be replaced until
+// they have marked the submission.
+func (r *ModerationRepository) Assign(ctx context.Context, submissionID, secondMarkerID, assignedBy string) (*models.Moderation, error) {
+	var id string
+	err := r.db.Pool.QueryRow(ctx,
+		`INSERT INTO submission_moderations (submission_id, second_marker_id, assigned_by)
+                VALUES ($1, $2, $3)
+                ON CONFLICT (submission_id) DO UPDATE
+                SET second_marker_id = EXCLUDED.second_marker_id, assigned_by = EXCLUDED.assigned_by
+                WHERE submission_moderations.second_score IS NULL
+                RETURNING submission_id`,
+		submissionID, secondMarkerID, assignedBy).Scan(&id)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, errors.New("the second marker has already marked this submission")
+		}
+		return nil, err
+	}
+
+	return r.FindBySubmission(ctx, id)
+}
+
+// FindBySubmission retrieves the double marking of a submission, if any
+func (r *ModerationRepository) FindBySubmission(ctx context.Context, submissionID string) (*models.Moderation, error) {
+	moderation, err := scanModeration(r.db.Pool.QueryRow(ctx,
+		`SELECT `+moderationColumns+`
+                FROM `+moderationFrom+`
+                WHERE m.submission_id = $1`,
+		submissionID))
+
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return moderation, nil
+}
+
+// FindForTeacher retrieves the double-marked submissions of the assessments a teacher created or whose course they
+// are assigned to, oldest first. Teachers of particular sections of a course only get their own students'
+// submissions, and those they were asked to second mark. An empty status selects submissions in any status.
+func (r *ModerationRepository) FindForTeacher(ctx context.Context, teacherID string, status models.ModerationStatus) ([]*models.Moderation, error) {
+	rows, err := r.db.Pool.Query(ctx,
+		`SELECT `+moderationColumns+`
+                FROM `+moderationFrom+`
+                JOIN assessments a ON s.assessment_id = a.id
+                WHERE (a.teacher_id = $1 OR a.course_id IN (SELECT course_id FROM course_teachers WHERE teacher_id = $1))
+                AND (m.second_marker_id = $1
+                     OR NOT EXISTS (SELECT 1 FROM section_teachers st
+                                    JOIN course_sections cs ON cs.id = st.section_id
+                                    WHERE cs.course_id = a.course_id AND st.teacher_id = $1)
+                     OR EXISTS (SELECT 1 FROM course_enrollments ce
+                                JOIN section_teachers st ON st.section_id = ce.section_id
+                                WHERE ce.course_id = a.course_id AND ce.student_id = s.student_id AND st.teacher_id = $1))
+                AND ($2 = '' OR m.status::text = $2)
+                ORDER BY m.created_at`,
+		teacherID, string(status))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	moderations := []*models.Moderation{}
+	for rows.Next() {
+		moderation, err := scanModeration(rows)
+		if err != nil {
+			return nil, err
+		}
+		moderations = append(moderations, moderation)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return moderations, nil
+}
+
+// RecordMark records a teacher's mark for a double marking that is not agreed yet and re-evaluates its status
+// against the assessment's threshold. The moderation is locked while the mark is recorded, so that the two markers
+// cannot overwrite each other's marks, nor two teachers both become the first marker.
+func (r *ModerationRepository) RecordMark(ctx context.Context, submissionID, teacherID string, score float64, feedback string, threshold float64) (*models.Moderation, error) {
+	err := r.db.ExecuteTransaction(ctx, func(tx pgx.Tx) error {
+		moderation, err := scanModeration(tx.QueryRow(ctx,
+			`SELECT `+moderationColumns+`
+                        FROM `+moderationFrom+`
+                        WHERE m.submission_id = $1
+                        FOR UPDATE OF m`,
+			submissionID))
+		if err != nil {
+			if err == pgx.ErrNoRows {
+				return errors.New("this submission is not double marked")
+			}
+			return err
+		}
+
+		if err := moderation.RecordMark(teacherID, score, feedback); err != nil {
+			return err
+		}
+		moderation.Evaluate(threshold)
+
+		_, err = tx.Exec(ctx,
+			`UPDATE submission_moderations
+                        SET first_marker_id = $2, first_score = $3, first_feedback = $4, second_score = $5, second_feedback = $6, status = $7
+                        WHERE submission_id = $1`,
+			submissionID, moderation.FirstMarkerID, moderation.FirstScore, moderation.FirstFeedback,
+			moderation.SecondScore, moderation.SecondFeedback, moderation.Status)
+		return err
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return r.FindBySubmission(ctx, submissionID)
+}
+
+// Agree sets the agreed final mark of a double-marked submission as its grade and records it in the grade's
+// history. The stored score is the raw score minus the late penalty. Rubric scores, when given, replace the grade's
+// levels per criterion.
+func (r *ModerationRepository) Agree(ctx context.Context, submissionID, moderatorID string, rawScore, latePenalty float64, feedback, reason string, rubricScores []*models.RubricScore) (*models.Moderation, error) {
+	err := r.db.ExecuteTransaction(ctx, func(tx pgx.Tx) error {
+		var status models.ModerationStatus
+		var firstMarkerID *string
+		var secondMarkerID string
+		err := tx.QueryRow(ctx,
+			`SELECT status, first_marker_id, second_marker_id FROM submission_moderations
+                        WHERE submission_id = $1 FOR UPDATE`,
+			submissionID).Scan(&status, &firstMarkerID, &secondMarkerID)
+		if err != nil {
+			if err == pgx.ErrNoRows {
+				return errors.New("this submission is not double marked")
+			}
+			return err
+		}
+		if status != models.ModerationStatusReady && status != models.ModerationStatusFlagged {
+			return errors.New("both marks are needed before the final mark can be agreed")
+		}
+		// The moderator settles between the two marks, so neither marker may agree the final mark
+		if moderatorID == secondMarkerID || (firstMarkerID != nil && moderatorID == *firstMarkerID) {
+			return errors.New("a marker of this submission cannot agree its final mark")
+		}
+
+		previous, err := lockGrade(ctx, tx, submissionID)
+		if err != nil {
+			return err
+		}
+
+		grade, err := scanGrade(tx.QueryRow(ctx,
+			`INSERT INTO grades (submission_id, score, raw_score, late_penalty, feedback, graded_by)
+                        VALUES ($1, $2, $3, $4, $5, $6)
+                        ON CONFLICT (submission_id) DO UPDATE
+                        SET score = EXCLUDED.score, raw_score = EXCLUDED.raw_score, late_penalty = EXCLUDED.late_penalty,
+                            feedback = EXCLUDED.feedback, graded_by = EXCLUDED.graded_by,
+                            graded_at = CURRENT_TIMESTAMP, auto_graded = false
+                        RETURNING `+gradeColumns,
+			submissionID, rawScore-latePenalty, rawScore, latePenalty, feedback, moderatorID))
+		if err != nil {
+			return err
+		}
+
+		if err := recordGradeVersion(ctx, tx, previous, grade, moderatorID, reason); err != nil {
+			return err
+		}
+
+		if rubricScores != nil {
+			if err := replaceRubricScores(ctx, tx, submissionID, rubricScores); err != nil {
+				return err
+			}
+		}
+
+		_, err = tx.Exec(ctx,
+			`UPDATE submission_moderations
+                        SET status = 'agreed', moderator_id = $2, final_score = $3, agreed_at = CURRENT_TIMESTAMP
+                        WHERE submission_id = $1`,
+			submissionID, moderatorID, rawScore)
+		return err
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return r.FindBySubmission(ctx, submissionID)
+}
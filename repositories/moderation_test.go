@@ -0,0 +1,161 @@
+package repositories_test
+
+import (
+	"context"
+	"testing"
+
+	"assessment-management-system/db/dbtest"
+	"assessment-management-system/models"
+	"assessment-management-system/repositories"
+)
+
+func TestModerationMarkerTransitions(t *testing.T) {
+	database := dbtest.Open(t)
+	ctx := context.Background()
+	moderations := repositories.NewModerationRepository(database)
+
+	org := dbtest.Organization(t, database)
+	term := dbtest.CurrentTerm(t, database, org.ID)
+	course := dbtest.Course(t, database, org.ID, term.ID, "Algorithms", nil)
+	first := dbtest.User(t, database, org.ID, models.RoleTeacher)
+	second := dbtest.User(t, database, org.ID, models.RoleTeacher)
+	third := dbtest.User(t, database, org.ID, models.RoleTeacher)
+	moderator := dbtest.User(t, database, org.ID, models.RoleTeacher)
+	student := dbtest.User(t, database, org.ID, models.RoleStudent)
+	dbtest.Enroll(t, database, course.ID, student.ID, nil)
+
+	assessment := dbtest.Assessment(t, database, first.ID, models.CreateAssessmentRequest{
+		CourseID:            course.ID,
+		Type:                models.AssessmentTypeExam,
+		ModerationThreshold: 5,
+	})
+	submission := dbtest.Submission(t, database, assessment.ID, student.ID, "Answers")
+
+	if _, err := moderations.Assign(ctx, submission.ID, second.ID, first.ID); err != nil {
+		t.Fatalf("failed to assign second marker: %v", err)
+	}
+
+	moderation, err := moderations.RecordMark(ctx, submission.ID, first.ID, 70, "", assessment.ModerationThreshold)
+	if err != nil {
+		t.Fatalf("failed to record first mark: %v", err)
+	}
+	if moderation.FirstMarkerID == nil || *moderation.FirstMarkerID != first.ID {
+		t.Fatalf("the first teacher to mark did not become the first marker: %+v", moderation)
+	}
+
+	if _, err := moderations.RecordMark(ctx, submission.ID, third.ID, 10, "", assessment.ModerationThreshold); err == nil {
+		t.Fatalf("a third teacher replaced the first mark")
+	}
+
+	if _, err := moderations.Agree(ctx, submission.ID, moderator.ID, 70, 0, "", "", nil); err == nil {
+		t.Fatalf("the final mark was agreed before both marks were in")
+	}
+
+	moderation, err = moderations.RecordMark(ctx, submission.ID, second.ID, 80, "", assessment.ModerationThreshold)
+	if err != nil {
+		t.Fatalf("failed to record second mark: %v", err)
+	}
+	if moderation.Status != models.ModerationStatusFlagged {
+		t.Fatalf("marks 10 apart with a threshold of 5 should be flagged, status = %s", moderation.Status)
+	}
+	if *moderation.FirstMarkerID != first.ID || *moderation.FirstScore != 70 {
+		t.Fatalf("the first mark changed: %+v", moderation)
+	}
+
+	for _, marker := range []string{first.ID, second.ID} {
+		if _, err := moderations.Agree(ctx, submission.ID, marker, 75, 0, "", "", nil); err == nil {
+			t.Fatalf("marker %s agreed their own submission's final mark", marker)
+		}
+	}
+
+	moderation, err = moderations.Agree(ctx, submission.ID, moderator.ID, 75, 0, "Agreed", "Moderated mark", nil)
+	if err != nil {
+		t.Fatalf("the moderator could not agree the final mark: %v", err)
+	}
+	if moderation.Status != models.ModerationStatusAgreed || moderation.FinalScore == nil || *moderation.FinalScore != 75 {
+		t.Fatalf("final mark not agreed: %+v", moderation)
+	}
+
+	grade, err := repositories.NewAssessmentRepository(database).FindGradeBySubmission(ctx, submission.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if grade == nil || grade.RawScore != 75 {
+		t.Fatalf("the agreed mark did not become the grade: %+v", grade)
+	}
+
+	if _, err := moderations.RecordMark(ctx, submission.ID, second.ID, 90, "", assessment.ModerationThreshold); err == nil {
+		t.Fatalf("a mark was recorded after the final mark was agreed")
+	}
+}
+
+func TestModerationFindForTeacherKeepsToSections(t *testing.T) {
+	database := dbtest.Open(t)
+	ctx := context.Background()
+	moderations := repositories.NewModerationRepository(database)
+
+	org := dbtest.Organization(t, database)
+	term := dbtest.CurrentTerm(t, database, org.ID)
+	course := dbtest.Course(t, database, org.ID, term.ID, "Statistics", nil)
+	lead := dbtest.User(t, database, org.ID, models.RoleTeacher)
+	tutor := dbtest.User(t, database, org.ID, models.RoleTeacher)
+	other := dbtest.User(t, database, org.ID, models.RoleTeacher)
+	dbtest.Teach(t, database, course.ID, lead.ID)
+	dbtest.Teach(t, database, course.ID, tutor.ID)
+	dbtest.Teach(t, database, course.ID, other.ID)
+
+	ownSection := dbtest.Section(t, database, course.ID, tutor.ID)
+	otherSection := dbtest.Section(t, database, course.ID, other.ID)
+	ownStudent := dbtest.User(t, database, org.ID, models.RoleStudent)
+	otherStudent := dbtest.User(t, database, org.ID, models.RoleStudent)
+	secondMarked := dbtest.User(t, database, org.ID, models.RoleStudent)
+	dbtest.Enroll(t, database, course.ID, ownStudent.ID, &ownSection.ID)
+	dbtest.Enroll(t, database, course.ID, otherStudent.ID, &otherSection.ID)
+	dbtest.Enroll(t, database, course.ID, secondMarked.ID, &otherSection.ID)
+
+	assessment := dbtest.Assessment(t, database, lead.ID, models.CreateAssessmentRequest{
+		CourseID: course.ID,
+		Type:     models.AssessmentTypeExam,
+	})
+
+	assign := func(student *models.User, marker *models.User) string {
+		submission := dbtest.Submission(t, database, assessment.ID, student.ID, "Answers")
+		if _, err := moderations.Assign(ctx, submission.ID, marker.ID, lead.ID); err != nil {
+			t.Fatalf("failed to assign second marker: %v", err)
+		}
+		return submission.ID
+	}
+	own := assign(ownStudent, lead)
+	hidden := assign(otherStudent, lead)
+	asked := assign(secondMarked, tutor)
+
+	found := func(teacherID string) map[string]bool {
+		queue, err := moderations.FindForTeacher(ctx, teacherID, "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		submissions := map[string]bool{}
+		for _, moderation := range queue {
+			submissions[moderation.SubmissionID] = true
+		}
+		return submissions
+	}
+
+	tutorQueue := found(tutor.ID)
+	if !tutorQueue[own] {
+		t.Errorf("the section teacher does not see their own student's submission")
+	}
+	if tutorQueue[hidden] {
+		t.Errorf("the section teacher sees a submission of a section they do not teach")
+	}
+	if !tutorQueue[asked] {
+		t.Errorf("the section teacher does not see the submission they were asked to second mark")
+	}
+
+	leadQueue := found(lead.ID)
+	for _, submissionID := range []string{own, hidden, asked} {
+		if !leadQueue[submissionID] {
+			t.Errorf("the teacher of the whole course does not see submission %s", submissionID)
+		}
+	}
+}
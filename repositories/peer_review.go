@@ -0,0 +1,223 @@
+package repositories
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/jackc/pgx/v4"
+
+	"assessment-management-system/db"
+	"assessment-management-system/models"
+)
+
+// PeerReviewRepository handles database operations for peer reviews
+type PeerReviewRepository struct {
+	db *db.DB
+}
+
+// NewPeerReviewRepository creates a new PeerReviewRepository
+func NewPeerReviewRepository(db *db.DB) *PeerReviewRepository {
+	return &PeerReviewRepository{
+		db: db,
+	}
+}
+
+// peerReviewColumns selects a peer review p joined with its submission s
+const peerReviewColumns = `p.id, p.submission_id, s.assessment_id, p.reviewer_id, p.score, COALESCE(p.comments, ''), p.rubric,
+                p.submitted_at, p.created_at`
+
+const peerReviewFrom = `peer_reviews p JOIN assessment_submissions s ON p.submission_id = s.id`
+
+// scanPeerReview scans a peer review row selected with peerReviewColumns
+func scanPeerReview(row pgx.Row) (*models.PeerReview, error) {
+	var review models.PeerReview
+	var rubric []byte
+	if err := row.Scan(&review.ID, &review.SubmissionID, &review.AssessmentID, &review.ReviewerID, &review.Score,
+		&review.Comments, &rubric, &review.SubmittedAt, &review.CreatedAt); err != nil {
+		return nil, err
+	}
+
+	if rubric != nil {
+		if err := json.Unmarshal(rubric, &review.Rubric); err != nil {
+			return nil, err
+		}
+	}
+	return &review, nil
+}
+
+// queryPeerReviews runs a query selecting peerReviewColumns and collects the reviews
+func (r *PeerReviewRepository) queryPeerReviews(ctx context.Context, query string, args ...interface{}) ([]*models.PeerReview, error) {
+	rows, err := r.db.Pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	reviews := []*models.PeerReview{}
+	for rows.Next() {
+		review, err := scanPeerReview(rows)
+		if err != nil {
+			return nil, err
+		}
+		reviews = append(reviews, review)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return reviews, nil
+}
+
+// Distribute creates the peer reviews of an assessment. An assessment's submissions are distributed only once.
+func (r *PeerReviewRepository) Distribute(ctx context.Context, assessmentID string, assignments []models.PeerReviewAssignment) error {
+	return r.db.ExecuteTransaction(ctx, func(tx pgx.Tx) error {
+		// Locking the assessment keeps two distributions from running side by side
+		if _, err := tx.Exec(ctx, `SELECT id FROM assessments WHERE id = $1 FOR UPDATE`, assessmentID); err != nil {
+			return err
+		}
+
+		var distributed bool
+		err := tx.QueryRow(ctx,
+			`SELECT EXISTS (SELECT 1 FROM `+peerReviewFrom+` WHERE s.assessment_id = $1)`,
+			assessmentID).Scan(&distributed)
+		if err != nil {
+			return err
+		}
+		if distributed {
+			return errors.New("the submissions of this assessment are already distributed for peer review")
+		}
+
+		for _, assignment := range assignments {
+			_, err := tx.Exec(ctx,
+				`INSERT INTO peer_reviews (submission_id, reviewer_id) VALUES ($1, $2)`,
+				assignment.SubmissionID, assignment.ReviewerID)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// FindByID retrieves a peer review by ID
+func (r *PeerReviewRepository) FindByID(ctx context.Context, id string) (*models.PeerReview, error) {
+	review, err := scanPeerReview(r.db.Pool.QueryRow(ctx,
+		`SELECT `+peerReviewColumns+`
+                FROM `+peerReviewFrom+`
+                WHERE p.id = $1`,
+		id))
+
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return review, nil
+}
+
+// FindByReviewer retrieves the peer reviews assigned to a student, newest first
+func (r *PeerReviewRepository) FindByReviewer(ctx context.Context, reviewerID string) ([]*models.PeerReview, error) {
+	return r.queryPeerReviews(ctx,
+		`SELECT `+peerReviewColumns+`
+                FROM `+peerReviewFrom+`
+                WHERE p.reviewer_id = $1
+                ORDER BY p.created_at DESC`,
+		reviewerID)
+}
+
+// FindBySubmission retrieves the peer reviews of a submission, oldest first
+func (r *PeerReviewRepository) FindBySubmission(ctx context.Context, submissionID string) ([]*models.PeerReview, error) {
+	return r.queryPeerReviews(ctx,
+		`SELECT `+peerReviewColumns+`
+                FROM `+peerReviewFrom+`
+                WHERE p.submission_id = $1
+                ORDER BY p.created_at, p.id`,
+		submissionID)
+}
+
+// FindByAssessment retrieves the peer reviews of every submission of an assessment
+func (r *PeerReviewRepository) FindByAssessment(ctx context.Context, assessmentID string) ([]*models.PeerReview, error) {
+	return r.queryPeerReviews(ctx,
+		`SELECT `+peerReviewColumns+`
+                FROM `+peerReviewFrom+`
+                WHERE s.assessment_id = $1
+                ORDER BY p.submission_id, p.created_at, p.id`,
+		assessmentID)
+}
+
+// Submit stores a reviewer's score, comments and rubric levels. A review can be revised until the peer-review
+// phase ends.
+func (r *PeerReviewRepository) Submit(ctx context.Context, id string, score float64, comments string, rubric []*models.RubricScore) (*models.PeerReview, error) {
+	var encoded []byte
+	if len(rubric) > 0 {
+		var err error
+		if encoded, err = json.Marshal(rubric); err != nil {
+			return nil, err
+		}
+	}
+
+	commandTag, err := r.db.Pool.Exec(ctx,
+		`UPDATE peer_reviews
+                SET score = $2, comments = $3, rubric = $4, submitted_at = CURRENT_TIMESTAMP
+                WHERE id = $1`,
+		id, score, comments, encoded)
+	if err != nil {
+		return nil, err
+	}
+	if commandTag.RowsAffected() == 0 {
+		return nil, errors.New("peer review not found")
+	}
+
+	return r.FindByID(ctx, id)
+}
+
+// ApplyScores folds peer scores into the grades of an assessment and records the weight used, all at once. Every
+// change is recorded in the grade's history. Peer scores are folded in only once per assessment.
+func (r *PeerReviewRepository) ApplyScores(ctx context.Context, assessmentID string, weight float64, grades []*models.Grade, appliedBy, reason string) error {
+	return r.db.ExecuteTransaction(ctx, func(tx pgx.Tx) error {
+		var applied *float64
+		err := tx.QueryRow(ctx,
+			`SELECT peer_review_weight FROM assessments WHERE id = $1 FOR UPDATE`,
+			assessmentID).Scan(&applied)
+		if err != nil {
+			if err == pgx.ErrNoRows {
+				return errors.New("assessment not found")
+			}
+			return err
+		}
+		if applied != nil {
+			return errors.New("the peer scores of this assessment are already folded into its grades")
+		}
+
+		for _, next := range grades {
+			previous, err := lockGrade(ctx, tx, next.SubmissionID)
+			if err != nil {
+				return err
+			}
+
+			grade, err := scanGrade(tx.QueryRow(ctx,
+				`INSERT INTO grades (submission_id, score, raw_score, late_penalty, feedback, graded_by)
+                        VALUES ($1, $2, $3, $4, '', $5)
+                        ON CONFLICT (submission_id) DO UPDATE
+                        SET score = EXCLUDED.score, raw_score = EXCLUDED.raw_score, late_penalty = EXCLUDED.late_penalty,
+                            graded_by = EXCLUDED.graded_by, graded_at = CURRENT_TIMESTAMP, auto_graded = false
+                        RETURNING `+gradeColumns,
+				next.SubmissionID, next.RawScore-next.LatePenalty, next.RawScore, next.LatePenalty, appliedBy))
+			if err != nil {
+				return err
+			}
+
+			if err := recordGradeVersion(ctx, tx, previous, grade, appliedBy, reason); err != nil {
+				return err
+			}
+		}
+
+		_, err = tx.Exec(ctx,
+			`UPDATE assessments SET peer_review_weight = $2 WHERE id = $1`,
+			assessmentID, weight)
+		return err
+	})
+}
@@ -0,0 +1,80 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v4"
+
+	"assessment-management-system/db"
+	"assessment-management-system/models"
+)
+
+// PrerequisiteRepository handles database operations for course prerequisites
+type PrerequisiteRepository struct {
+	db *db.DB
+}
+
+// NewPrerequisiteRepository creates a new PrerequisiteRepository
+func NewPrerequisiteRepository(db *db.DB) *PrerequisiteRepository {
+	return &PrerequisiteRepository{
+		db: db,
+	}
+}
+
+// FindByCourse retrieves the prerequisites of a course with the names of the required courses
+func (r *PrerequisiteRepository) FindByCourse(ctx context.Context, courseID string) ([]*models.CoursePrerequisite, error) {
+	rows, err := r.db.Pool.Query(ctx,
+		`SELECT p.course_id, p.required_course_id, c.name, p.min_grade::float8, p.created_at
+                FROM course_prerequisites p
+                JOIN courses c ON c.id = p.required_course_id
+                WHERE p.course_id = $1
+                ORDER BY c.name`,
+		courseID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	prerequisites := []*models.CoursePrerequisite{}
+	for rows.Next() {
+		var prerequisite models.CoursePrerequisite
+		if err := rows.Scan(&prerequisite.CourseID, &prerequisite.RequiredCourseID, &prerequisite.RequiredCourseName,
+			&prerequisite.MinGrade, &prerequisite.CreatedAt); err != nil {
+			return nil, err
+		}
+		prerequisites = append(prerequisites, &prerequisite)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return prerequisites, nil
+}
+
+// Set replaces the prerequisites of a course and how they combine
+func (r *PrerequisiteRepository) Set(ctx context.Context, courseID string, mode models.PrerequisiteMode, rules []models.PrerequisiteRule) error {
+	return r.db.ExecuteTransaction(ctx, func(tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx,
+			`UPDATE courses SET prerequisite_mode = $2, updated_at = CURRENT_TIMESTAMP WHERE id = $1`,
+			courseID, mode); err != nil {
+			return err
+		}
+
+		if _, err := tx.Exec(ctx,
+			`DELETE FROM course_prerequisites WHERE course_id = $1`,
+			courseID); err != nil {
+			return err
+		}
+
+		for _, rule := range rules {
+			if _, err := tx.Exec(ctx,
+				`INSERT INTO course_prerequisites (course_id, required_course_id, min_grade)
+                                VALUES ($1, $2, $3)`,
+				courseID, rule.RequiredCourseID, rule.MinGrade); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,248 @@
+package repositories
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/jackc/pgx/v4"
+
+	"assessment-management-system/db"
+	"assessment-management-system/models"
+)
+
+// QuestionRepository handles database operations for assessment questions and answers
+type QuestionRepository struct {
+	db *db.DB
+}
+
+// NewQuestionRepository creates a new QuestionRepository
+func NewQuestionRepository(db *db.DB) *QuestionRepository {
+	return &QuestionRepository{
+		db: db,
+	}
+}
+
+const questionColumns = `id, assessment_id, position, type, prompt, options, points, correct_answer, tolerance, case_sensitive, created_at, updated_at`
+
+// scanQuestion scans a question row, decoding the JSON answer key
+func scanQuestion(row pgx.Row) (*models.Question, error) {
+	var question models.Question
+	var correctAnswer []byte
+	if err := row.Scan(&question.ID, &question.AssessmentID, &question.Position, &question.Type, &question.Prompt,
+		&question.Options, &question.Points, &correctAnswer, &question.Tolerance, &question.CaseSensitive,
+		&question.CreatedAt, &question.UpdatedAt); err != nil {
+		return nil, err
+	}
+
+	if len(correctAnswer) > 0 {
+		question.CorrectAnswer = &models.QuestionAnswer{}
+		if err := json.Unmarshal(correctAnswer, question.CorrectAnswer); err != nil {
+			return nil, err
+		}
+	}
+	return &question, nil
+}
+
+// Create inserts a question at its position, shifting later questions down
+func (r *QuestionRepository) Create(ctx context.Context, question *models.Question) (*models.Question, error) {
+	correctAnswer, err := json.Marshal(question.CorrectAnswer)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	// Make room for the new question
+	_, err = tx.Exec(ctx,
+		`UPDATE assessment_questions
+                SET position = position + 1
+                WHERE assessment_id = $1 AND position >= $2`,
+		question.AssessmentID, question.Position)
+	if err != nil {
+		return nil, err
+	}
+
+	created, err := scanQuestion(tx.QueryRow(ctx,
+		`INSERT INTO assessment_questions (assessment_id, position, type, prompt, options, points, correct_answer, tolerance, case_sensitive)
+                VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+                RETURNING `+questionColumns,
+		question.AssessmentID, question.Position, question.Type, question.Prompt, question.Options, question.Points,
+		string(correctAnswer), question.Tolerance, question.CaseSensitive))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return created, nil
+}
+
+// FindByID retrieves a question by ID
+func (r *QuestionRepository) FindByID(ctx context.Context, id string) (*models.Question, error) {
+	question, err := scanQuestion(r.db.Pool.QueryRow(ctx,
+		`SELECT `+questionColumns+`
+                FROM assessment_questions
+                WHERE id = $1`,
+		id))
+
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return question, nil
+}
+
+// FindByAssessment retrieves all questions of an assessment in order
+func (r *QuestionRepository) FindByAssessment(ctx context.Context, assessmentID string) ([]*models.Question, error) {
+	rows, err := r.db.Pool.Query(ctx,
+		`SELECT `+questionColumns+`
+                FROM assessment_questions
+                WHERE assessment_id = $1
+                ORDER BY position`,
+		assessmentID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var questions []*models.Question
+	for rows.Next() {
+		question, err := scanQuestion(rows)
+		if err != nil {
+			return nil, err
+		}
+		questions = append(questions, question)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return questions, nil
+}
+
+// CountByAssessment counts the questions of an assessment
+func (r *QuestionRepository) CountByAssessment(ctx context.Context, assessmentID string) (int, error) {
+	var count int
+	err := r.db.Pool.QueryRow(ctx,
+		`SELECT COUNT(*)
+                FROM assessment_questions
+                WHERE assessment_id = $1`,
+		assessmentID).Scan(&count)
+	return count, err
+}
+
+// Update updates the content of a question
+func (r *QuestionRepository) Update(ctx context.Context, question *models.Question) (*models.Question, error) {
+	correctAnswer, err := json.Marshal(question.CorrectAnswer)
+	if err != nil {
+		return nil, err
+	}
+
+	updated, err := scanQuestion(r.db.Pool.QueryRow(ctx,
+		`UPDATE assessment_questions
+                SET type = $2, prompt = $3, options = $4, points = $5, correct_answer = $6, tolerance = $7, case_sensitive = $8
+                WHERE id = $1
+                RETURNING `+questionColumns,
+		question.ID, question.Type, question.Prompt, question.Options, question.Points, string(correctAnswer),
+		question.Tolerance, question.CaseSensitive))
+
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, errors.New("question not found")
+		}
+		return nil, err
+	}
+	return updated, nil
+}
+
+// Delete deletes a question and closes the gap it leaves in the ordering
+func (r *QuestionRepository) Delete(ctx context.Context, id string) error {
+	return r.db.ExecuteTransaction(ctx, func(tx pgx.Tx) error {
+		var assessmentID string
+		var position int
+		err := tx.QueryRow(ctx,
+			`DELETE FROM assessment_questions
+                        WHERE id = $1
+                        RETURNING assessment_id, position`,
+			id).Scan(&assessmentID, &position)
+		if err != nil {
+			if err == pgx.ErrNoRows {
+				return errors.New("question not found")
+			}
+			return err
+		}
+
+		_, err = tx.Exec(ctx,
+			`UPDATE assessment_questions
+                        SET position = position - 1
+                        WHERE assessment_id = $1 AND position > $2`,
+			assessmentID, position)
+		return err
+	})
+}
+
+// Reorder assigns positions to an assessment's questions following the given order
+func (r *QuestionRepository) Reorder(ctx context.Context, assessmentID string, questionIDs []string) error {
+	return r.db.ExecuteTransaction(ctx, func(tx pgx.Tx) error {
+		for i, questionID := range questionIDs {
+			commandTag, err := tx.Exec(ctx,
+				`UPDATE assessment_questions
+                                SET position = $3
+                                WHERE id = $1 AND assessment_id = $2`,
+				questionID, assessmentID, i+1)
+			if err != nil {
+				return err
+			}
+			if commandTag.RowsAffected() == 0 {
+				return errors.New("question " + questionID + " does not belong to this assessment")
+			}
+		}
+		return nil
+	})
+}
+
+// FindAnswersBySubmission retrieves the per-question answers of a submission in question order
+func (r *QuestionRepository) FindAnswersBySubmission(ctx context.Context, submissionID string) ([]*models.SubmissionAnswer, error) {
+	rows, err := r.db.Pool.Query(ctx,
+		`SELECT submission_id, question_id, position, answer, is_correct, points_awarded
+                FROM submission_answers
+                WHERE submission_id = $1
+                ORDER BY position`,
+		submissionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var answers []*models.SubmissionAnswer
+	for rows.Next() {
+		var answer models.SubmissionAnswer
+		var rawAnswer []byte
+		if err := rows.Scan(&answer.SubmissionID, &answer.QuestionID, &answer.Position, &rawAnswer, &answer.IsCorrect, &answer.PointsAwarded); err != nil {
+			return nil, err
+		}
+		if len(rawAnswer) > 0 {
+			answer.Answer = &models.QuestionAnswer{}
+			if err := json.Unmarshal(rawAnswer, answer.Answer); err != nil {
+				return nil, err
+			}
+		}
+		answers = append(answers, &answer)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return answers, nil
+}
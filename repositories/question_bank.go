@@ -0,0 +1,305 @@
+package repositories
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/jackc/pgx/v4"
+
+	"assessment-management-system/db"
+	"assessment-management-system/models"
+)
+
+// QuestionBankRepository handles database operations for course question banks and random draws
+type QuestionBankRepository struct {
+	db *db.DB
+}
+
+// NewQuestionBankRepository creates a new QuestionBankRepository
+func NewQuestionBankRepository(db *db.DB) *QuestionBankRepository {
+	return &QuestionBankRepository{
+		db: db,
+	}
+}
+
+const bankQuestionColumns = `id, course_id, created_by, type, prompt, options, points, correct_answer, tolerance, case_sensitive, tags, difficulty, created_at, updated_at`
+
+// scanBankQuestion scans a bank question row, decoding the JSON answer key
+func scanBankQuestion(row pgx.Row) (*models.BankQuestion, error) {
+	var question models.BankQuestion
+	var correctAnswer []byte
+	if err := row.Scan(&question.ID, &question.CourseID, &question.CreatedBy, &question.Type, &question.Prompt,
+		&question.Options, &question.Points, &correctAnswer, &question.Tolerance, &question.CaseSensitive,
+		&question.Tags, &question.Difficulty, &question.CreatedAt, &question.UpdatedAt); err != nil {
+		return nil, err
+	}
+
+	if len(correctAnswer) > 0 {
+		question.CorrectAnswer = &models.QuestionAnswer{}
+		if err := json.Unmarshal(correctAnswer, question.CorrectAnswer); err != nil {
+			return nil, err
+		}
+	}
+	return &question, nil
+}
+
+// Create creates a new bank question
+func (r *QuestionBankRepository) Create(ctx context.Context, question *models.BankQuestion) (*models.BankQuestion, error) {
+	correctAnswer, err := json.Marshal(question.CorrectAnswer)
+	if err != nil {
+		return nil, err
+	}
+
+	return scanBankQuestion(r.db.Pool.QueryRow(ctx,
+		`INSERT INTO question_bank (course_id, created_by, type, prompt, options, points, correct_answer, tolerance, case_sensitive, tags, difficulty)
+                VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+                RETURNING `+bankQuestionColumns,
+		question.CourseID, question.CreatedBy, question.Type, question.Prompt, question.Options, question.Points,
+		string(correctAnswer), question.Tolerance, question.CaseSensitive, question.Tags, question.Difficulty))
+}
+
+// FindByID retrieves a bank question by ID
+func (r *QuestionBankRepository) FindByID(ctx context.Context, id string) (*models.BankQuestion, error) {
+	question, err := scanBankQuestion(r.db.Pool.QueryRow(ctx,
+		`SELECT `+bankQuestionColumns+`
+                FROM question_bank
+                WHERE id = $1`,
+		id))
+
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return question, nil
+}
+
+// FindByCourse retrieves the bank questions of a course, optionally filtered by tags and difficulty.
+// A question matches when it carries every requested tag.
+func (r *QuestionBankRepository) FindByCourse(ctx context.Context, courseID string, tags []string, difficulty string) ([]*models.BankQuestion, error) {
+	if tags == nil {
+		tags = []string{}
+	}
+
+	rows, err := r.db.Pool.Query(ctx,
+		`SELECT `+bankQuestionColumns+`
+                FROM question_bank
+                WHERE course_id = $1
+                AND tags @> $2
+                AND ($3 = '' OR difficulty::text = $3)
+                ORDER BY id`,
+		courseID, tags, difficulty)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var questions []*models.BankQuestion
+	for rows.Next() {
+		question, err := scanBankQuestion(rows)
+		if err != nil {
+			return nil, err
+		}
+		questions = append(questions, question)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return questions, nil
+}
+
+// Update updates a bank question
+func (r *QuestionBankRepository) Update(ctx context.Context, question *models.BankQuestion) (*models.BankQuestion, error) {
+	correctAnswer, err := json.Marshal(question.CorrectAnswer)
+	if err != nil {
+		return nil, err
+	}
+
+	updated, err := scanBankQuestion(r.db.Pool.QueryRow(ctx,
+		`UPDATE question_bank
+                SET type = $2, prompt = $3, options = $4, points = $5, correct_answer = $6, tolerance = $7,
+                    case_sensitive = $8, tags = $9, difficulty = $10
+                WHERE id = $1
+                RETURNING `+bankQuestionColumns,
+		question.ID, question.Type, question.Prompt, question.Options, question.Points, string(correctAnswer),
+		question.Tolerance, question.CaseSensitive, question.Tags, question.Difficulty))
+
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, errors.New("question not found")
+		}
+		return nil, err
+	}
+	return updated, nil
+}
+
+// Delete deletes a bank question
+func (r *QuestionBankRepository) Delete(ctx context.Context, id string) error {
+	commandTag, err := r.db.Pool.Exec(ctx,
+		`DELETE FROM question_bank WHERE id = $1`,
+		id)
+	if err != nil {
+		return err
+	}
+	if commandTag.RowsAffected() == 0 {
+		return errors.New("question not found")
+	}
+	return nil
+}
+
+// IsDrawn checks if a bank question has been drawn for any student
+func (r *QuestionBankRepository) IsDrawn(ctx context.Context, questionID string) (bool, error) {
+	var exists bool
+	err := r.db.Pool.QueryRow(ctx,
+		`SELECT EXISTS(
+                        SELECT 1 FROM student_drawn_questions
+                        WHERE question_id = $1
+                )`,
+		questionID).Scan(&exists)
+	return exists, err
+}
+
+// CreateDrawRule creates a new random draw rule for an assessment
+func (r *QuestionBankRepository) CreateDrawRule(ctx context.Context, rule *models.QuestionDrawRule) (*models.QuestionDrawRule, error) {
+	var created models.QuestionDrawRule
+	err := r.db.Pool.QueryRow(ctx,
+		`INSERT INTO assessment_question_draws (assessment_id, tags, difficulty, question_count, points_per_question)
+                VALUES ($1, $2, $3, $4, $5)
+                RETURNING id, assessment_id, tags, difficulty, question_count, points_per_question, created_at`,
+		rule.AssessmentID, rule.Tags, rule.Difficulty, rule.QuestionCount, rule.PointsPerQuestion).Scan(
+		&created.ID, &created.AssessmentID, &created.Tags, &created.Difficulty, &created.QuestionCount,
+		&created.PointsPerQuestion, &created.CreatedAt)
+
+	if err != nil {
+		return nil, err
+	}
+	return &created, nil
+}
+
+// FindDrawRulesByAssessment retrieves the draw rules of an assessment in creation order
+func (r *QuestionBankRepository) FindDrawRulesByAssessment(ctx context.Context, assessmentID string) ([]*models.QuestionDrawRule, error) {
+	rows, err := r.db.Pool.Query(ctx,
+		`SELECT id, assessment_id, tags, difficulty, question_count, points_per_question, created_at
+                FROM assessment_question_draws
+                WHERE assessment_id = $1
+                ORDER BY created_at, id`,
+		assessmentID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var rules []*models.QuestionDrawRule
+	for rows.Next() {
+		var rule models.QuestionDrawRule
+		if err := rows.Scan(&rule.ID, &rule.AssessmentID, &rule.Tags, &rule.Difficulty, &rule.QuestionCount,
+			&rule.PointsPerQuestion, &rule.CreatedAt); err != nil {
+			return nil, err
+		}
+		rules = append(rules, &rule)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return rules, nil
+}
+
+// DeleteDrawRule deletes a draw rule of an assessment
+func (r *QuestionBankRepository) DeleteDrawRule(ctx context.Context, assessmentID, ruleID string) error {
+	commandTag, err := r.db.Pool.Exec(ctx,
+		`DELETE FROM assessment_question_draws WHERE id = $1 AND assessment_id = $2`,
+		ruleID, assessmentID)
+	if err != nil {
+		return err
+	}
+	if commandTag.RowsAffected() == 0 {
+		return errors.New("draw rule not found")
+	}
+	return nil
+}
+
+// FindDrawnQuestions retrieves the bank questions drawn for a student, in the order they were drawn
+func (r *QuestionBankRepository) FindDrawnQuestions(ctx context.Context, assessmentID, studentID string) ([]*models.Question, error) {
+	rows, err := r.db.Pool.Query(ctx,
+		`SELECT qb.id, qb.course_id, qb.created_by, qb.type, qb.prompt, qb.options, qb.points, qb.correct_answer,
+                        qb.tolerance, qb.case_sensitive, qb.tags, qb.difficulty, qb.created_at, qb.updated_at,
+                        sdq.position, sdq.points
+                FROM student_drawn_questions sdq
+                JOIN question_bank qb ON sdq.question_id = qb.id
+                WHERE sdq.assessment_id = $1 AND sdq.student_id = $2
+                ORDER BY sdq.position`,
+		assessmentID, studentID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var questions []*models.Question
+	for rows.Next() {
+		var question models.BankQuestion
+		var correctAnswer []byte
+		var position int
+		var points float64
+		if err := rows.Scan(&question.ID, &question.CourseID, &question.CreatedBy, &question.Type, &question.Prompt,
+			&question.Options, &question.Points, &correctAnswer, &question.Tolerance, &question.CaseSensitive,
+			&question.Tags, &question.Difficulty, &question.CreatedAt, &question.UpdatedAt, &position, &points); err != nil {
+			return nil, err
+		}
+		if len(correctAnswer) > 0 {
+			question.CorrectAnswer = &models.QuestionAnswer{}
+			if err := json.Unmarshal(correctAnswer, question.CorrectAnswer); err != nil {
+				return nil, err
+			}
+		}
+		questions = append(questions, question.AsQuestion(assessmentID, position, points))
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return questions, nil
+}
+
+// SaveDraw persists the questions drawn for a student. If another request persisted a draw
+// for the same student first, that draw is kept.
+func (r *QuestionBankRepository) SaveDraw(ctx context.Context, assessmentID, studentID string, drawn []*models.DrawnQuestion) error {
+	return r.db.ExecuteTransaction(ctx, func(tx pgx.Tx) error {
+		var exists bool
+		err := tx.QueryRow(ctx,
+			`SELECT EXISTS(
+                                SELECT 1 FROM student_drawn_questions
+                                WHERE assessment_id = $1 AND student_id = $2
+                        )`,
+			assessmentID, studentID).Scan(&exists)
+		if err != nil || exists {
+			return err
+		}
+
+		for _, item := range drawn {
+			_, err := tx.Exec(ctx,
+				`INSERT INTO student_drawn_questions (assessment_id, student_id, draw_id, question_id, position, points)
+                                VALUES ($1, $2, $3, $4, $5, $6)
+                                ON CONFLICT DO NOTHING`,
+				assessmentID, studentID, item.DrawID, item.QuestionID, item.Position, item.Points)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// DeleteDraws discards the questions drawn for every student of an assessment
+func (r *QuestionBankRepository) DeleteDraws(ctx context.Context, assessmentID string) error {
+	_, err := r.db.Pool.Exec(ctx,
+		`DELETE FROM student_drawn_questions WHERE assessment_id = $1`,
+		assessmentID)
+	return err
+}
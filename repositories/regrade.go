@@ -0,0 +1,237 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v4"
+
+	"assessment-management-system/db"
+	"assessment-management-system/models"
+)
+
+// RegradeRepository handles database operations for regrade requests
+type RegradeRepository struct {
+	db *db.DB
+}
+
+// NewRegradeRepository creates a new RegradeRepository
+func NewRegradeRepository(db *db.DB) *RegradeRepository {
+	return &RegradeRepository{
+		db: db,
+	}
+}
+
+// regradeColumns selects a regrade request r joined with its submission s
+const regradeColumns = `r.id, r.submission_id, s.assessment_id, r.student_id, r.criterion_id, r.question_id, r.reason, r.status,
+                r.previous_score, r.new_score, COALESCE(r.response, ''), r.resolved_by, r.resolved_at, r.created_at, r.updated_at`
+
+const regradeFrom = `regrade_requests r JOIN assessment_submissions s ON r.submission_id = s.id`
+
+// scanRegrade scans a regrade request row selected with regradeColumns
+func scanRegrade(row pgx.Row) (*models.RegradeRequest, error) {
+	var request models.RegradeRequest
+	if err := row.Scan(&request.ID, &request.SubmissionID, &request.AssessmentID, &request.StudentID, &request.CriterionID,
+		&request.QuestionID, &request.Reason, &request.Status, &request.PreviousScore, &request.NewScore, &request.Response,
+		&request.ResolvedBy, &request.ResolvedAt, &request.CreatedAt, &request.UpdatedAt); err != nil {
+		return nil, err
+	}
+	return &request, nil
+}
+
+// queryRegrades runs a query selecting regradeColumns and collects the requests
+func (r *RegradeRepository) queryRegrades(ctx context.Context, query string, args ...interface{}) ([]*models.RegradeRequest, error) {
+	rows, err := r.db.Pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	requests := []*models.RegradeRequest{}
+	for rows.Next() {
+		request, err := scanRegrade(rows)
+		if err != nil {
+			return nil, err
+		}
+		requests = append(requests, request)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return requests, nil
+}
+
+// Create opens a regrade request for a submission
+func (r *RegradeRepository) Create(ctx context.Context, submissionID, studentID string, req models.CreateRegradeRequest) (*models.RegradeRequest, error) {
+	var id string
+	err := r.db.Pool.QueryRow(ctx,
+		`INSERT INTO regrade_requests (submission_id, student_id, criterion_id, question_id, reason)
+                VALUES ($1, $2, $3, $4, $5)
+                RETURNING id`,
+		submissionID, studentID, req.CriterionID, req.QuestionID, req.Reason).Scan(&id)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.FindByID(ctx, id)
+}
+
+// FindByID retrieves a regrade request by ID
+func (r *RegradeRepository) FindByID(ctx context.Context, id string) (*models.RegradeRequest, error) {
+	request, err := scanRegrade(r.db.Pool.QueryRow(ctx,
+		`SELECT `+regradeColumns+`
+                FROM `+regradeFrom+`
+                WHERE r.id = $1`,
+		id))
+
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return request, nil
+}
+
+// FindOpenBySubmission retrieves the open regrade request of a submission, if any
+func (r *RegradeRepository) FindOpenBySubmission(ctx context.Context, submissionID string) (*models.RegradeRequest, error) {
+	request, err := scanRegrade(r.db.Pool.QueryRow(ctx,
+		`SELECT `+regradeColumns+`
+                FROM `+regradeFrom+`
+                WHERE r.submission_id = $1 AND r.status = 'open'`,
+		submissionID))
+
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return request, nil
+}
+
+// FindBySubmission retrieves every regrade request of a submission, oldest first
+func (r *RegradeRepository) FindBySubmission(ctx context.Context, submissionID string) ([]*models.RegradeRequest, error) {
+	return r.queryRegrades(ctx,
+		`SELECT `+regradeColumns+`
+                FROM `+regradeFrom+`
+                WHERE r.submission_id = $1
+                ORDER BY r.created_at`,
+		submissionID)
+}
+
+// FindByStudent retrieves the regrade requests a student opened, newest first
+func (r *RegradeRepository) FindByStudent(ctx context.Context, studentID string) ([]*models.RegradeRequest, error) {
+	return r.queryRegrades(ctx,
+		`SELECT `+regradeColumns+`
+                FROM `+regradeFrom+`
+                WHERE r.student_id = $1
+                ORDER BY r.created_at DESC`,
+		studentID)
+}
+
+// FindForTeacher retrieves the regrade requests of the assessments a teacher created or whose course they are
+// assigned to, oldest first. Teachers of particular sections of a course only get their own students' requests. An
+// empty status selects requests in any status.
+func (r *RegradeRepository) FindForTeacher(ctx context.Context, teacherID string, status models.RegradeStatus) ([]*models.RegradeRequest, error) {
+	return r.queryRegrades(ctx,
+		`SELECT `+regradeColumns+`
+                FROM `+regradeFrom+`
+                JOIN assessments a ON s.assessment_id = a.id
+                WHERE (a.teacher_id = $1 OR a.course_id IN (SELECT course_id FROM course_teachers WHERE teacher_id = $1))
+                AND (NOT EXISTS (SELECT 1 FROM section_teachers st
+                                 JOIN course_sections cs ON cs.id = st.section_id
+                                 WHERE cs.course_id = a.course_id AND st.teacher_id = $1)
+                     OR EXISTS (SELECT 1 FROM course_enrollments ce
+                                JOIN section_teachers st ON st.section_id = ce.section_id
+                                WHERE ce.course_id = a.course_id AND ce.student_id = r.student_id AND st.teacher_id = $1))
+                AND ($2 = '' OR r.status::text = $2)
+                ORDER BY r.created_at`,
+		teacherID, string(status))
+}
+
+// Accept resolves an open regrade request with a new grade and records the change in the grade's history. The stored
+// score is the raw score minus the late penalty, and the request keeps the score from before the regrade. Grades
+// given with a rubric get the new level per criterion with it.
+func (r *RegradeRepository) Accept(ctx context.Context, id string, rawScore, latePenalty float64, response, resolvedBy string, rubricScores []*models.RubricScore) (*models.RegradeRequest, error) {
+	err := r.db.ExecuteTransaction(ctx, func(tx pgx.Tx) error {
+		var submissionID string
+		err := tx.QueryRow(ctx,
+			`SELECT submission_id
+                        FROM regrade_requests
+                        WHERE id = $1 AND status = 'open'
+                        FOR UPDATE`,
+			id).Scan(&submissionID)
+		if err != nil {
+			if err == pgx.ErrNoRows {
+				return errors.New("open regrade request not found")
+			}
+			return err
+		}
+
+		previous, err := lockGrade(ctx, tx, submissionID)
+		if err != nil {
+			return err
+		}
+		if previous == nil {
+			return errors.New("grade not found")
+		}
+
+		grade, err := scanGrade(tx.QueryRow(ctx,
+			`UPDATE grades
+                        SET score = $2, raw_score = $3, late_penalty = $4, graded_by = $5, graded_at = CURRENT_TIMESTAMP, auto_graded = false
+                        WHERE submission_id = $1
+                        RETURNING `+gradeColumns,
+			submissionID, rawScore-latePenalty, rawScore, latePenalty, resolvedBy))
+		if err != nil {
+			return err
+		}
+
+		if rubricScores != nil {
+			if err := replaceRubricScores(ctx, tx, submissionID, rubricScores); err != nil {
+				return err
+			}
+		}
+
+		reason := "Regrade request accepted"
+		if response != "" {
+			reason += ": " + response
+		}
+		if err := recordGradeVersion(ctx, tx, previous, grade, resolvedBy, reason); err != nil {
+			return err
+		}
+
+		_, err = tx.Exec(ctx,
+			`UPDATE regrade_requests
+                        SET status = 'accepted', previous_score = $2, new_score = $3, response = $4, resolved_by = $5, resolved_at = CURRENT_TIMESTAMP
+                        WHERE id = $1`,
+			id, previous.Score, grade.Score, response, resolvedBy)
+		return err
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return r.FindByID(ctx, id)
+}
+
+// Reject resolves an open regrade request without changing the grade
+func (r *RegradeRepository) Reject(ctx context.Context, id, response, resolvedBy string) (*models.RegradeRequest, error) {
+	commandTag, err := r.db.Pool.Exec(ctx,
+		`UPDATE regrade_requests
+                SET status = 'rejected', previous_score = (SELECT score FROM grades WHERE submission_id = regrade_requests.submission_id),
+                    response = $2, resolved_by = $3, resolved_at = CURRENT_TIMESTAMP
+                WHERE id = $1 AND status = 'open'`,
+		id, response, resolvedBy)
+	if err != nil {
+		return nil, err
+	}
+	if commandTag.RowsAffected() == 0 {
+		return nil, errors.New("open regrade request not found")
+	}
+
+	return r.FindByID(ctx, id)
+}
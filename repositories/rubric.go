@@ -0,0 +1,353 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v4"
+
+	"assessment-management-system/db"
+	"assessment-management-system/models"
+)
+
+// RubricRepository handles database operations for grading rubrics
+type RubricRepository struct {
+	db *db.DB
+}
+
+// NewRubricRepository creates a new RubricRepository
+func NewRubricRepository(db *db.DB) *RubricRepository {
+	return &RubricRepository{
+		db: db,
+	}
+}
+
+// insertCriteria inserts the criteria of a rubric and their levels in order
+func insertCriteria(ctx context.Context, tx pgx.Tx, rubric *models.Rubric) error {
+	for i, criterion := range rubric.Criteria {
+		criterion.RubricID = rubric.ID
+		criterion.Position = i + 1
+		err := tx.QueryRow(ctx,
+			`INSERT INTO rubric_criteria (rubric_id, position, title, description)
+                        VALUES ($1, $2, $3, $4)
+                        RETURNING id`,
+			rubric.ID, criterion.Position, criterion.Title, criterion.Description).Scan(&criterion.ID)
+		if err != nil {
+			return err
+		}
+
+		for j, level := range criterion.Levels {
+			level.CriterionID = criterion.ID
+			level.Position = j + 1
+			err := tx.QueryRow(ctx,
+				`INSERT INTO rubric_levels (criterion_id, position, title, descriptor, points)
+                                VALUES ($1, $2, $3, $4, $5)
+                                RETURNING id`,
+				criterion.ID, level.Position, level.Title, level.Descriptor, level.Points).Scan(&level.ID)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Create creates a rubric together with its criteria and levels
+func (r *RubricRepository) Create(ctx context.Context, rubric *models.Rubric) (*models.Rubric, error) {
+	err := r.db.ExecuteTransaction(ctx, func(tx pgx.Tx) error {
+		err := tx.QueryRow(ctx,
+			`INSERT INTO rubrics (course_id, created_by, title, description)
+                        VALUES ($1, $2, $3, $4)
+                        RETURNING id, created_at, updated_at`,
+			rubric.CourseID, rubric.CreatedBy, rubric.Title, rubric.Description).Scan(&rubric.ID, &rubric.CreatedAt, &rubric.UpdatedAt)
+		if err != nil {
+			return err
+		}
+
+		return insertCriteria(ctx, tx, rubric)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+	return rubric, nil
+}
+
+// FindByID retrieves a rubric with its criteria and levels
+func (r *RubricRepository) FindByID(ctx context.Context, id string) (*models.Rubric, error) {
+	var rubric models.Rubric
+	var description *string
+	err := r.db.Pool.QueryRow(ctx,
+		`SELECT id, course_id, created_by, title, description, created_at, updated_at
+                FROM rubrics
+                WHERE id = $1`,
+		id).Scan(&rubric.ID, &rubric.CourseID, &rubric.CreatedBy, &rubric.Title, &description, &rubric.CreatedAt, &rubric.UpdatedAt)
+
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	if description != nil {
+		rubric.Description = *description
+	}
+
+	if err := r.loadCriteria(ctx, []*models.Rubric{&rubric}); err != nil {
+		return nil, err
+	}
+	return &rubric, nil
+}
+
+// FindByCourse retrieves all rubrics of a course with their criteria and levels
+func (r *RubricRepository) FindByCourse(ctx context.Context, courseID string) ([]*models.Rubric, error) {
+	rows, err := r.db.Pool.Query(ctx,
+		`SELECT id, course_id, created_by, title, description, created_at, updated_at
+                FROM rubrics
+                WHERE course_id = $1
+                ORDER BY title`,
+		courseID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var rubrics []*models.Rubric
+	for rows.Next() {
+		var rubric models.Rubric
+		var description *string
+		if err := rows.Scan(&rubric.ID, &rubric.CourseID, &rubric.CreatedBy, &rubric.Title, &description, &rubric.CreatedAt, &rubric.UpdatedAt); err != nil {
+			return nil, err
+		}
+		if description != nil {
+			rubric.Description = *description
+		}
+		rubrics = append(rubrics, &rubric)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if err := r.loadCriteria(ctx, rubrics); err != nil {
+		return nil, err
+	}
+	return rubrics, nil
+}
+
+// loadCriteria fills in the criteria and levels of the given rubrics
+func (r *RubricRepository) loadCriteria(ctx context.Context, rubrics []*models.Rubric) error {
+	if len(rubrics) == 0 {
+		return nil
+	}
+
+	byID := make(map[string]*models.Rubric, len(rubrics))
+	ids := make([]string, 0, len(rubrics))
+	for _, rubric := range rubrics {
+		rubric.Criteria = []*models.RubricCriterion{}
+		byID[rubric.ID] = rubric
+		ids = append(ids, rubric.ID)
+	}
+
+	rows, err := r.db.Pool.Query(ctx,
+		`SELECT c.id, c.rubric_id, c.position, c.title, COALESCE(c.description, ''),
+                        l.id, l.position, l.title, COALESCE(l.descriptor, ''), l.points
+                FROM rubric_criteria c
+                JOIN rubric_levels l ON l.criterion_id = c.id
+                WHERE c.rubric_id = ANY($1)
+                ORDER BY c.rubric_id, c.position, l.position`,
+		ids)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	var current *models.RubricCriterion
+	for rows.Next() {
+		var criterion models.RubricCriterion
+		var level models.RubricLevel
+		if err := rows.Scan(&criterion.ID, &criterion.RubricID, &criterion.Position, &criterion.Title, &criterion.Description,
+			&level.ID, &level.Position, &level.Title, &level.Descriptor, &level.Points); err != nil {
+			return err
+		}
+
+		if current == nil || current.ID != criterion.ID {
+			current = &criterion
+			byID[criterion.RubricID].Criteria = append(byID[criterion.RubricID].Criteria, current)
+		}
+		level.CriterionID = current.ID
+		current.Levels = append(current.Levels, &level)
+	}
+
+	return rows.Err()
+}
+
+// Update updates a rubric. When criteria are given they replace the existing criteria.
+func (r *RubricRepository) Update(ctx context.Context, rubric *models.Rubric, replaceCriteria bool) (*models.Rubric, error) {
+	err := r.db.ExecuteTransaction(ctx, func(tx pgx.Tx) error {
+		err := tx.QueryRow(ctx,
+			`UPDATE rubrics
+                        SET title = $2, description = $3
+                        WHERE id = $1
+                        RETURNING updated_at`,
+			rubric.ID, rubric.Title, rubric.Description).Scan(&rubric.UpdatedAt)
+		if err != nil {
+			if err == pgx.ErrNoRows {
+				return errors.New("rubric not found")
+			}
+			return err
+		}
+
+		if !replaceCriteria {
+			return nil
+		}
+
+		if _, err := tx.Exec(ctx, `DELETE FROM rubric_criteria WHERE rubric_id = $1`, rubric.ID); err != nil {
+			return err
+		}
+
+		return insertCriteria(ctx, tx, rubric)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+	return rubric, nil
+}
+
+// Delete deletes a rubric
+func (r *RubricRepository) Delete(ctx context.Context, id string) error {
+	commandTag, err := r.db.Pool.Exec(ctx,
+		`DELETE FROM rubrics WHERE id = $1`,
+		id)
+	if err != nil {
+		return err
+	}
+	if commandTag.RowsAffected() == 0 {
+		return errors.New("rubric not found")
+	}
+	return nil
+}
+
+// IsAttached checks if a rubric is attached to any assessment
+func (r *RubricRepository) IsAttached(ctx context.Context, id string) (bool, error) {
+	var exists bool
+	err := r.db.Pool.QueryRow(ctx,
+		`SELECT EXISTS(
+                        SELECT 1 FROM assessments
+                        WHERE rubric_id = $1
+                )`,
+		id).Scan(&exists)
+	return exists, err
+}
+
+// IsUsedForGrading checks if any grade was given with the rubric
+func (r *RubricRepository) IsUsedForGrading(ctx context.Context, id string) (bool, error) {
+	var exists bool
+	err := r.db.Pool.QueryRow(ctx,
+		`SELECT EXISTS(
+                        SELECT 1 FROM grade_rubric_scores grs
+                        JOIN rubric_criteria c ON grs.criterion_id = c.id
+                        WHERE c.rubric_id = $1
+                )`,
+		id).Scan(&exists)
+	return exists, err
+}
+
+// HasRubricGrades checks if any submission of an assessment was graded with a rubric
+func (r *RubricRepository) HasRubricGrades(ctx context.Context, assessmentID string) (bool, error) {
+	var exists bool
+	err := r.db.Pool.QueryRow(ctx,
+		`SELECT EXISTS(
+                        SELECT 1 FROM grade_rubric_scores grs
+                        JOIN assessment_submissions s ON grs.submission_id = s.id
+                        WHERE s.assessment_id = $1
+                )`,
+		assessmentID).Scan(&exists)
+	return exists, err
+}
+
+// FindScoresBySubmission retrieves the filled-in rubric of a graded submission in criterion order
+func (r *RubricRepository) FindScoresBySubmission(ctx context.Context, submissionID string) ([]*models.RubricScore, error) {
+	rows, err := r.db.Pool.Query(ctx,
+		`SELECT c.id, c.title, l.id, l.title, COALESCE(l.descriptor, ''), grs.points,
+                        (SELECT MAX(points) FROM rubric_levels WHERE criterion_id = c.id),
+                        COALESCE(grs.comment, '')
+                FROM grade_rubric_scores grs
+                JOIN rubric_criteria c ON grs.criterion_id = c.id
+                JOIN rubric_levels l ON grs.level_id = l.id
+                WHERE grs.submission_id = $1
+                ORDER BY c.position`,
+		submissionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var scores []*models.RubricScore
+	for rows.Next() {
+		var score models.RubricScore
+		if err := rows.Scan(&score.CriterionID, &score.CriterionTitle, &score.LevelID, &score.LevelTitle, &score.Descriptor,
+			&score.Points, &score.MaxPoints, &score.Comment); err != nil {
+			return nil, err
+		}
+		scores = append(scores, &score)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return scores, nil
+}
+
+// criterionStatsQuery aggregates rubric scores per criterion for the submissions matched by the filter
+const criterionStatsQuery = `
+        SELECT r.id, r.title, c.id, c.title,
+                (SELECT MAX(points) FROM rubric_levels WHERE criterion_id = c.id),
+                AVG(grs.points), COUNT(*)
+        FROM grade_rubric_scores grs
+        JOIN rubric_criteria c ON grs.criterion_id = c.id
+        JOIN rubrics r ON c.rubric_id = r.id
+        JOIN assessment_submissions s ON grs.submission_id = s.id
+        JOIN assessments a ON s.assessment_id = a.id
+        WHERE %s
+        GROUP BY r.id, r.title, c.id, c.title, c.position
+        ORDER BY r.title, c.position`
+
+// findCriterionStats runs the criterion statistics query with the given filter
+func (r *RubricRepository) findCriterionStats(ctx context.Context, filter string, args ...interface{}) ([]*models.CriterionStats, error) {
+	rows, err := r.db.Pool.Query(ctx, fmt.Sprintf(criterionStatsQuery, filter), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var stats []*models.CriterionStats
+	for rows.Next() {
+		var item models.CriterionStats
+		if err := rows.Scan(&item.RubricID, &item.RubricTitle, &item.CriterionID, &item.CriterionTitle,
+			&item.MaxPoints, &item.AveragePoints, &item.GradedCount); err != nil {
+			return nil, err
+		}
+		stats = append(stats, &item)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return stats, nil
+}
+
+// FindCriterionStatsByTeacher summarizes rubric scores on the assessments a teacher created
+func (r *RubricRepository) FindCriterionStatsByTeacher(ctx context.Context, teacherID string) ([]*models.CriterionStats, error) {
+	return r.findCriterionStats(ctx, "a.teacher_id = $1", teacherID)
+}
+
+// FindCriterionStatsByStudent summarizes the rubric scores a student received
+func (r *RubricRepository) FindCriterionStatsByStudent(ctx context.Context, studentID string) ([]*models.CriterionStats, error) {
+	return r.findCriterionStats(ctx, "s.student_id = $1", studentID)
+}
@@ -0,0 +1,370 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v4"
+
+	"assessment-management-system/db"
+	"assessment-management-system/models"
+)
+
+// SectionRepository handles database operations for course sections
+type SectionRepository struct {
+	db *db.DB
+}
+
+// NewSectionRepository creates a new SectionRepository
+func NewSectionRepository(db *db.DB) *SectionRepository {
+	return &SectionRepository{
+		db: db,
+	}
+}
+
+const sectionColumns = `id, course_id, name, created_at, updated_at`
+
+// scanSection scans a section row selected with sectionColumns
+func scanSection(row pgx.Row) (*models.CourseSection, error) {
+	var section models.CourseSection
+	if err := row.Scan(&section.ID, &section.CourseID, &section.Name, &section.CreatedAt, &section.UpdatedAt); err != nil {
+		return nil, err
+	}
+	return &section, nil
+}
+
+// Create creates a section in a course
+func (r *SectionRepository) Create(ctx context.Context, courseID, name string) (*models.CourseSection, error) {
+	return scanSection(r.db.Pool.QueryRow(ctx,
+		`INSERT INTO course_sections (course_id, name)
+                VALUES ($1, $2)
+                RETURNING `+sectionColumns,
+		courseID, name))
+}
+
+// FindByID retrieves a section by ID
+func (r *SectionRepository) FindByID(ctx context.Context, id string) (*models.CourseSection, error) {
+	section, err := scanSection(r.db.Pool.QueryRow(ctx,
+		`SELECT `+sectionColumns+`
+                FROM course_sections
+                WHERE id = $1`,
+		id))
+
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return section, nil
+}
+
+// FindByName retrieves a section of a course by name
+func (r *SectionRepository) FindByName(ctx context.Context, courseID, name string) (*models.CourseSection, error) {
+	section, err := scanSection(r.db.Pool.QueryRow(ctx,
+		`SELECT `+sectionColumns+`
+                FROM course_sections
+                WHERE course_id = $1 AND name = $2`,
+		courseID, name))
+
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return section, nil
+}
+
+// FindByCourse retrieves the sections of a course by name
+func (r *SectionRepository) FindByCourse(ctx context.Context, courseID string) ([]*models.CourseSection, error) {
+	rows, err := r.db.Pool.Query(ctx,
+		`SELECT `+sectionColumns+`
+                FROM course_sections
+                WHERE course_id = $1
+                ORDER BY name`,
+		courseID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	sections := []*models.CourseSection{}
+	for rows.Next() {
+		section, err := scanSection(rows)
+		if err != nil {
+			return nil, err
+		}
+		sections = append(sections, section)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return sections, nil
+}
+
+// CountByCourse counts the sections of a course
+func (r *SectionRepository) CountByCourse(ctx context.Context, courseID string) (int, error) {
+	var count int
+	err := r.db.Pool.QueryRow(ctx,
+		`SELECT COUNT(*) FROM course_sections WHERE course_id = $1`,
+		courseID).Scan(&count)
+	return count, err
+}
+
+// Update renames a section
+func (r *SectionRepository) Update(ctx context.Context, id, name string) (*models.CourseSection, error) {
+	section, err := scanSection(r.db.Pool.QueryRow(ctx,
+		`UPDATE course_sections
+                SET name = $2
+                WHERE id = $1
+                RETURNING `+sectionColumns,
+		id, name))
+
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, errors.New("section not found")
+		}
+		return nil, err
+	}
+	return section, nil
+}
+
+// Delete deletes a section together with its teacher assignments
+func (r *SectionRepository) Delete(ctx context.Context, id string) error {
+	commandTag, err := r.db.Pool.Exec(ctx,
+		`DELETE FROM course_sections WHERE id = $1`,
+		id)
+	if err != nil {
+		return err
+	}
+	if commandTag.RowsAffected() == 0 {
+		return errors.New("section not found")
+	}
+	return nil
+}
+
+// CountStudents counts the students enrolled in a section
+func (r *SectionRepository) CountStudents(ctx context.Context, sectionID string) (int, error) {
+	var count int
+	err := r.db.Pool.QueryRow(ctx,
+		`SELECT COUNT(*) FROM course_enrollments WHERE section_id = $1`,
+		sectionID).Scan(&count)
+	return count, err
+}
+
+// CountAssessments counts the assessments for a section
+func (r *SectionRepository) CountAssessments(ctx context.Context, sectionID string) (int, error) {
+	var count int
+	err := r.db.Pool.QueryRow(ctx,
+		`SELECT COUNT(*) FROM assessment_sections WHERE section_id = $1`,
+		sectionID).Scan(&count)
+	return count, err
+}
+
+// AssignTeacher assigns a teacher to a section
+func (r *SectionRepository) AssignTeacher(ctx context.Context, sectionID, teacherID string) error {
+	_, err := r.db.Pool.Exec(ctx,
+		`INSERT INTO section_teachers (section_id, teacher_id)
+                VALUES ($1, $2)`,
+		sectionID, teacherID)
+	return err
+}
+
+// RemoveTeacher removes a teacher from a section
+func (r *SectionRepository) RemoveTeacher(ctx context.Context, sectionID, teacherID string) error {
+	commandTag, err := r.db.Pool.Exec(ctx,
+		`DELETE FROM section_teachers
+                WHERE section_id = $1 AND teacher_id = $2`,
+		sectionID, teacherID)
+	if err != nil {
+		return err
+	}
+	if commandTag.RowsAffected() == 0 {
+		return errors.New("teacher not assigned to section")
+	}
+	return nil
+}
+
+// IsTeacherAssigned checks if a teacher is assigned to a section
+func (r *SectionRepository) IsTeacherAssigned(ctx context.Context, sectionID, teacherID string) (bool, error) {
+	var exists bool
+	err := r.db.Pool.QueryRow(ctx,
+		`SELECT EXISTS(
+                        SELECT 1 FROM section_teachers
+                        WHERE section_id = $1 AND teacher_id = $2
+                )`,
+		sectionID, teacherID).Scan(&exists)
+	return exists, err
+}
+
+// FindTeachers retrieves the teachers assigned to a section
+func (r *SectionRepository) FindTeachers(ctx context.Context, sectionID string) ([]*models.User, error) {
+	rows, err := r.db.Pool.Query(ctx,
+		`SELECT u.id, u.organization_id, u.email, u.first_name, u.last_name, u.role, u.created_at, u.updated_at
+                FROM users u
+                JOIN section_teachers st ON u.id = st.teacher_id
+                WHERE st.section_id = $1
+                ORDER BY u.first_name, u.last_name`,
+		sectionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	teachers := []*models.User{}
+	for rows.Next() {
+		var teacher models.User
+		if err := rows.Scan(&teacher.ID, &teacher.OrganizationID, &teacher.Email, &teacher.FirstName, &teacher.LastName, &teacher.Role, &teacher.CreatedAt, &teacher.UpdatedAt); err != nil {
+			return nil, err
+		}
+		teachers = append(teachers, &teacher)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return teachers, nil
+}
+
+// FindStudents retrieves the students enrolled in a section
+func (r *SectionRepository) FindStudents(ctx context.Context, sectionID string) ([]*models.User, error) {
+	rows, err := r.db.Pool.Query(ctx,
+		`SELECT u.id, u.organization_id, u.email, u.first_name, u.last_name, u.role, u.created_at, u.updated_at
+                FROM users u
+                JOIN course_enrollments ce ON u.id = ce.student_id
+                WHERE ce.section_id = $1
+                ORDER BY u.first_name, u.last_name`,
+		sectionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	students := []*models.User{}
+	for rows.Next() {
+		var student models.User
+		if err := rows.Scan(&student.ID, &student.OrganizationID, &student.Email, &student.FirstName, &student.LastName, &student.Role, &student.CreatedAt, &student.UpdatedAt); err != nil {
+			return nil, err
+		}
+		students = append(students, &student)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return students, nil
+}
+
+// FindTeacherSectionIDs retrieves the IDs of the sections of a course a teacher is assigned to
+func (r *SectionRepository) FindTeacherSectionIDs(ctx context.Context, courseID, teacherID string) ([]string, error) {
+	rows, err := r.db.Pool.Query(ctx,
+		`SELECT cs.id
+                FROM course_sections cs
+                JOIN section_teachers st ON cs.id = st.section_id
+                WHERE cs.course_id = $1 AND st.teacher_id = $2`,
+		courseID, teacherID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	sectionIDs := []string{}
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		sectionIDs = append(sectionIDs, id)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return sectionIDs, nil
+}
+
+// FindStudentSections retrieves the section of every student enrolled in a course, nil for students without one
+func (r *SectionRepository) FindStudentSections(ctx context.Context, courseID string) (map[string]*string, error) {
+	rows, err := r.db.Pool.Query(ctx,
+		`SELECT student_id, section_id
+                FROM course_enrollments
+                WHERE course_id = $1`,
+		courseID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	sections := map[string]*string{}
+	for rows.Next() {
+		var studentID string
+		var sectionID *string
+		if err := rows.Scan(&studentID, &sectionID); err != nil {
+			return nil, err
+		}
+		sections[studentID] = sectionID
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return sections, nil
+}
+
+// FindStudentSectionID retrieves the section a student is enrolled in, nil when they have none
+func (r *SectionRepository) FindStudentSectionID(ctx context.Context, courseID, studentID string) (*string, error) {
+	var sectionID *string
+	err := r.db.Pool.QueryRow(ctx,
+		`SELECT section_id
+                FROM course_enrollments
+                WHERE course_id = $1 AND student_id = $2`,
+		courseID, studentID).Scan(&sectionID)
+
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return sectionID, nil
+}
+
+// MoveStudent moves an enrolled student to another section
+func (r *SectionRepository) MoveStudent(ctx context.Context, courseID, studentID, sectionID string) error {
+	commandTag, err := r.db.Pool.Exec(ctx,
+		`UPDATE course_enrollments
+                SET section_id = $3
+                WHERE course_id = $1 AND student_id = $2`,
+		courseID, studentID, sectionID)
+	if err != nil {
+		return err
+	}
+	if commandTag.RowsAffected() == 0 {
+		return errors.New("student not enrolled in course")
+	}
+	return nil
+}
+
+// IsSubmissionInSections checks if the student of a submission, or any member of its group, is enrolled in one of
+// the sections
+func (r *SectionRepository) IsSubmissionInSections(ctx context.Context, submissionID string, sectionIDs []string) (bool, error) {
+	var exists bool
+	err := r.db.Pool.QueryRow(ctx,
+		`SELECT EXISTS(
+                        SELECT 1 FROM assessment_submissions s
+                        JOIN assessments a ON a.id = s.assessment_id
+                        JOIN course_enrollments ce ON ce.course_id = a.course_id
+                        WHERE s.id = $1 AND ce.section_id::text = ANY($2)
+                        AND (ce.student_id = s.student_id
+                             OR ce.student_id IN (SELECT student_id FROM course_group_members WHERE group_id = s.group_id))
+                )`,
+		submissionID, sectionIDs).Scan(&exists)
+	return exists, err
+}
@@ -0,0 +1,221 @@
+package repositories
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/jackc/pgx/v4"
+
+	"assessment-management-system/db"
+	"assessment-management-system/models"
+)
+
+// SimilarityRepository handles database operations for similarity checks
+type SimilarityRepository struct {
+	db *db.DB
+}
+
+// NewSimilarityRepository creates a new SimilarityRepository
+func NewSimilarityRepository(db *db.DB) *SimilarityRepository {
+	return &SimilarityRepository{
+		db: db,
+	}
+}
+
+const similarityCheckColumns = `id, assessment_id, include_previous, threshold, status, COALESCE(error, ''), submissions_checked,
+                requested_by, created_at, completed_at`
+
+// scanSimilarityCheck scans a similarity check row selected with similarityCheckColumns
+func scanSimilarityCheck(row pgx.Row) (*models.SimilarityCheck, error) {
+	var check models.SimilarityCheck
+	if err := row.Scan(&check.ID, &check.AssessmentID, &check.IncludePrevious, &check.Threshold, &check.Status,
+		&check.Error, &check.SubmissionsChecked, &check.RequestedBy, &check.CreatedAt, &check.CompletedAt); err != nil {
+		return nil, err
+	}
+	return &check, nil
+}
+
+// similarityMatchColumns selects a match m joined with its other submission o
+const similarityMatchColumns = `m.id, m.check_id, m.submission_id, m.other_submission_id, o.assessment_id, m.similarity, m.regions`
+
+const similarityMatchFrom = `similarity_matches m JOIN assessment_submissions o ON m.other_submission_id = o.id`
+
+// scanSimilarityMatch scans a match row selected with similarityMatchColumns
+func scanSimilarityMatch(row pgx.Row) (*models.SimilarityMatch, error) {
+	var match models.SimilarityMatch
+	var regions []byte
+	if err := row.Scan(&match.ID, &match.CheckID, &match.SubmissionID, &match.OtherSubmissionID, &match.OtherAssessmentID,
+		&match.Similarity, &regions); err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(regions, &match.Regions); err != nil {
+		return nil, err
+	}
+	return &match, nil
+}
+
+// Create records a similarity check that has started running
+func (r *SimilarityRepository) Create(ctx context.Context, assessmentID string, includePrevious bool, threshold float64, requestedBy string) (*models.SimilarityCheck, error) {
+	return scanSimilarityCheck(r.db.Pool.QueryRow(ctx,
+		`INSERT INTO similarity_checks (assessment_id, include_previous, threshold, requested_by)
+                VALUES ($1, $2, $3, $4)
+                RETURNING `+similarityCheckColumns,
+		assessmentID, includePrevious, threshold, requestedBy))
+}
+
+// Complete stores the matches of a check and marks it completed, all at once
+func (r *SimilarityRepository) Complete(ctx context.Context, id string, submissionsChecked int, matches []*models.SimilarityMatch) error {
+	return r.db.ExecuteTransaction(ctx, func(tx pgx.Tx) error {
+		for _, match := range matches {
+			regions, err := json.Marshal(match.Regions)
+			if err != nil {
+				return err
+			}
+
+			_, err = tx.Exec(ctx,
+				`INSERT INTO similarity_matches (check_id, submission_id, other_submission_id, similarity, regions)
+                        VALUES ($1, $2, $3, $4, $5)`,
+				id, match.SubmissionID, match.OtherSubmissionID, match.Similarity, regions)
+			if err != nil {
+				return err
+			}
+		}
+
+		commandTag, err := tx.Exec(ctx,
+			`UPDATE similarity_checks
+                        SET status = 'completed', submissions_checked = $2, completed_at = CURRENT_TIMESTAMP
+                        WHERE id = $1`,
+			id, submissionsChecked)
+		if err != nil {
+			return err
+		}
+		if commandTag.RowsAffected() == 0 {
+			return errors.New("similarity check not found")
+		}
+		return nil
+	})
+}
+
+// Fail marks a check failed with the error that stopped it
+func (r *SimilarityRepository) Fail(ctx context.Context, id, message string) error {
+	commandTag, err := r.db.Pool.Exec(ctx,
+		`UPDATE similarity_checks
+                SET status = 'failed', error = $2, completed_at = CURRENT_TIMESTAMP
+                WHERE id = $1`,
+		id, message)
+	if err != nil {
+		return err
+	}
+	if commandTag.RowsAffected() == 0 {
+		return errors.New("similarity check not found")
+	}
+	return nil
+}
+
+// FailRunning marks every running check failed with the given message and returns how many there were. Checks run
+// in the background of the server, so any still running when it starts were stopped by a restart.
+func (r *SimilarityRepository) FailRunning(ctx context.Context, message string) (int64, error) {
+	commandTag, err := r.db.Pool.Exec(ctx,
+		`UPDATE similarity_checks
+                SET status = 'failed', error = $1, completed_at = CURRENT_TIMESTAMP
+                WHERE status = 'running'`,
+		message)
+	if err != nil {
+		return 0, err
+	}
+	return commandTag.RowsAffected(), nil
+}
+
+// FindByID retrieves a similarity check by ID, without its matches
+func (r *SimilarityRepository) FindByID(ctx context.Context, id string) (*models.SimilarityCheck, error) {
+	check, err := scanSimilarityCheck(r.db.Pool.QueryRow(ctx,
+		`SELECT `+similarityCheckColumns+`
+                FROM similarity_checks
+                WHERE id = $1`,
+		id))
+
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return check, nil
+}
+
+// FindByAssessment retrieves the similarity checks of an assessment, newest first, without their matches
+func (r *SimilarityRepository) FindByAssessment(ctx context.Context, assessmentID string) ([]*models.SimilarityCheck, error) {
+	rows, err := r.db.Pool.Query(ctx,
+		`SELECT `+similarityCheckColumns+`
+                FROM similarity_checks
+                WHERE assessment_id = $1
+                ORDER BY created_at DESC`,
+		assessmentID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	checks := []*models.SimilarityCheck{}
+	for rows.Next() {
+		check, err := scanSimilarityCheck(rows)
+		if err != nil {
+			return nil, err
+		}
+		checks = append(checks, check)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return checks, nil
+}
+
+// FindMatches retrieves the matches of a check, most similar first
+func (r *SimilarityRepository) FindMatches(ctx context.Context, checkID string) ([]*models.SimilarityMatch, error) {
+	rows, err := r.db.Pool.Query(ctx,
+		`SELECT `+similarityMatchColumns+`
+                FROM `+similarityMatchFrom+`
+                WHERE m.check_id = $1
+                ORDER BY m.similarity DESC, m.id`,
+		checkID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	matches := []*models.SimilarityMatch{}
+	for rows.Next() {
+		match, err := scanSimilarityMatch(rows)
+		if err != nil {
+			return nil, err
+		}
+		matches = append(matches, match)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return matches, nil
+}
+
+// FindMatch retrieves a match by ID
+func (r *SimilarityRepository) FindMatch(ctx context.Context, id string) (*models.SimilarityMatch, error) {
+	match, err := scanSimilarityMatch(r.db.Pool.QueryRow(ctx,
+		`SELECT `+similarityMatchColumns+`
+                FROM `+similarityMatchFrom+`
+                WHERE m.id = $1`,
+		id))
+
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return match, nil
+}
@@ -0,0 +1,177 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+
+	"assessment-management-system/db"
+	"assessment-management-system/models"
+)
+
+// TermRepository handles database operations for academic terms
+type TermRepository struct {
+	db *db.DB
+}
+
+// NewTermRepository creates a new TermRepository
+func NewTermRepository(db *db.DB) *TermRepository {
+	return &TermRepository{
+		db: db,
+	}
+}
+
+const termColumns = `id, organization_id, name, start_date, end_date, created_at, updated_at`
+
+// scanTerm scans a term row selected with termColumns
+func scanTerm(row pgx.Row) (*models.Term, error) {
+	var term models.Term
+	if err := row.Scan(&term.ID, &term.OrganizationID, &term.Name, &term.StartDate, &term.EndDate,
+		&term.CreatedAt, &term.UpdatedAt); err != nil {
+		return nil, err
+	}
+	return &term, nil
+}
+
+// assessmentOutsideWindowExpr matches assessments a with a date before $2 or after $3
+const assessmentOutsideWindowExpr = `(a.available_from < $2 OR a.available_from > $3
+                        OR a.due_date < $2 OR a.due_date > $3
+                        OR a.cutoff_date < $2 OR a.cutoff_date > $3
+                        OR a.peer_review_due_date < $2 OR a.peer_review_due_date > $3)`
+
+// Create creates an academic term in an organization
+func (r *TermRepository) Create(ctx context.Context, organizationID string, req models.CreateTermRequest) (*models.Term, error) {
+	return scanTerm(r.db.Pool.QueryRow(ctx,
+		`INSERT INTO academic_terms (organization_id, name, start_date, end_date)
+                VALUES ($1, $2, $3, $4)
+                RETURNING `+termColumns,
+		organizationID, req.Name, req.StartDate, req.EndDate))
+}
+
+// FindByID retrieves a term by ID
+func (r *TermRepository) FindByID(ctx context.Context, id string) (*models.Term, error) {
+	term, err := scanTerm(r.db.Pool.QueryRow(ctx,
+		`SELECT `+termColumns+`
+                FROM academic_terms
+                WHERE id = $1`,
+		id))
+
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return term, nil
+}
+
+// FindByName retrieves a term of an organization by name
+func (r *TermRepository) FindByName(ctx context.Context, organizationID, name string) (*models.Term, error) {
+	term, err := scanTerm(r.db.Pool.QueryRow(ctx,
+		`SELECT `+termColumns+`
+                FROM academic_terms
+                WHERE organization_id = $1 AND name = $2`,
+		organizationID, name))
+
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return term, nil
+}
+
+// FindByOrganization retrieves the terms of an organization, latest first
+func (r *TermRepository) FindByOrganization(ctx context.Context, organizationID string) ([]*models.Term, error) {
+	rows, err := r.db.Pool.Query(ctx,
+		`SELECT `+termColumns+`
+                FROM academic_terms
+                WHERE organization_id = $1
+                ORDER BY start_date DESC`,
+		organizationID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	terms := []*models.Term{}
+	for rows.Next() {
+		term, err := scanTerm(rows)
+		if err != nil {
+			return nil, err
+		}
+		terms = append(terms, term)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return terms, nil
+}
+
+// Update updates the name and dates of a term
+func (r *TermRepository) Update(ctx context.Context, id, name string, startDate, endDate time.Time) (*models.Term, error) {
+	term, err := scanTerm(r.db.Pool.QueryRow(ctx,
+		`UPDATE academic_terms
+                SET name = $2, start_date = $3, end_date = $4
+                WHERE id = $1
+                RETURNING `+termColumns,
+		id, name, startDate, endDate))
+
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, errors.New("term not found")
+		}
+		return nil, err
+	}
+	return term, nil
+}
+
+// Delete deletes a term
+func (r *TermRepository) Delete(ctx context.Context, id string) error {
+	commandTag, err := r.db.Pool.Exec(ctx,
+		`DELETE FROM academic_terms WHERE id = $1`,
+		id)
+	if err != nil {
+		return err
+	}
+	if commandTag.RowsAffected() == 0 {
+		return errors.New("term not found")
+	}
+	return nil
+}
+
+// CountCourses counts the courses offered in a term
+func (r *TermRepository) CountCourses(ctx context.Context, termID string) (int, error) {
+	var count int
+	err := r.db.Pool.QueryRow(ctx,
+		`SELECT COUNT(*) FROM courses WHERE term_id = $1`,
+		termID).Scan(&count)
+	return count, err
+}
+
+// CountAssessmentsOutside counts the assessments of a term's courses with a date outside a window
+func (r *TermRepository) CountAssessmentsOutside(ctx context.Context, termID string, start, end time.Time) (int, error) {
+	var count int
+	err := r.db.Pool.QueryRow(ctx,
+		`SELECT COUNT(*) FROM assessments a
+                WHERE a.course_id IN (SELECT id FROM courses WHERE term_id = $1)
+                AND `+assessmentOutsideWindowExpr,
+		termID, start, end).Scan(&count)
+	return count, err
+}
+
+// CountCourseAssessmentsOutside counts the assessments of a course with a date outside a window
+func (r *TermRepository) CountCourseAssessmentsOutside(ctx context.Context, courseID string, start, end time.Time) (int, error) {
+	var count int
+	err := r.db.Pool.QueryRow(ctx,
+		`SELECT COUNT(*) FROM assessments a
+                WHERE a.course_id = $1
+                AND `+assessmentOutsideWindowExpr,
+		courseID, start, end).Scan(&count)
+	return count, err
+}
@@ -12,23 +12,48 @@ import (
 	customMiddleware "assessment-management-system/middleware"
 	"assessment-management-system/repositories"
 	"assessment-management-system/services"
+	"assessment-management-system/storage"
 )
 
 // SetupRoutes configures all the routes for the application
-func SetupRoutes(e *echo.Echo, db *db.DB, cfg *config.AppConfig) {
+func SetupRoutes(e *echo.Echo, db *db.DB, cfg *config.AppConfig, blobStorage storage.Storage) {
 	// Create repositories
 	orgRepo := repositories.NewOrganizationRepository(db)
 	userRepo := repositories.NewUserRepository(db)
 	courseRepo := repositories.NewCourseRepository(db)
 	assessmentRepo := repositories.NewAssessmentRepository(db)
 	refreshTokenRepo := repositories.NewRefreshTokenRepository(db)
+	questionRepo := repositories.NewQuestionRepository(db)
+	questionBankRepo := repositories.NewQuestionBankRepository(db)
+	rubricRepo := repositories.NewRubricRepository(db)
+	extensionRepo := repositories.NewExtensionRepository(db)
+	gradebookRepo := repositories.NewGradebookRepository(db)
+	schemeRepo := repositories.NewGradingSchemeRepository(db)
+	regradeRepo := repositories.NewRegradeRepository(db)
+	moderationRepo := repositories.NewModerationRepository(db)
+	peerReviewRepo := repositories.NewPeerReviewRepository(db)
+	groupRepo := repositories.NewGroupRepository(db)
+	similarityRepo := repositories.NewSimilarityRepository(db)
+	termRepo := repositories.NewTermRepository(db)
+	sectionRepo := repositories.NewSectionRepository(db)
+	prerequisiteRepo := repositories.NewPrerequisiteRepository(db)
+	joinCodeRepo := repositories.NewJoinCodeRepository(db)
 
 	// Create services
 	authService := services.NewAuthService(userRepo, refreshTokenRepo)
 	orgService := services.NewOrganizationService(orgRepo, userRepo, courseRepo)
-	userService := services.NewUserService(userRepo, orgRepo, courseRepo, assessmentRepo)
-	courseService := services.NewCourseService(courseRepo, userRepo, orgRepo)
-	assessmentService := services.NewAssessmentService(assessmentRepo, courseRepo, userRepo)
+	userService := services.NewUserService(userRepo, orgRepo, courseRepo, assessmentRepo, rubricRepo)
+	assessmentService := services.NewAssessmentService(assessmentRepo, courseRepo, userRepo, questionRepo, questionBankRepo, rubricRepo, extensionRepo, gradebookRepo, regradeRepo, moderationRepo, peerReviewRepo, groupRepo, similarityRepo, sectionRepo, blobStorage)
+	questionService := services.NewQuestionService(questionRepo, assessmentRepo, questionBankRepo)
+	questionBankService := services.NewQuestionBankService(questionBankRepo, courseRepo)
+	rubricService := services.NewRubricService(rubricRepo, courseRepo, assessmentRepo)
+	extensionService := services.NewExtensionService(extensionRepo, assessmentRepo, courseRepo)
+	gradebookService := services.NewGradebookService(gradebookRepo, assessmentRepo, courseRepo, schemeRepo, sectionRepo)
+	courseService := services.NewCourseService(courseRepo, userRepo, orgRepo, termRepo, sectionRepo, prerequisiteRepo, joinCodeRepo, gradebookService)
+	schemeService := services.NewGradingSchemeService(schemeRepo, orgRepo, courseRepo)
+	regradeService := services.NewRegradeService(regradeRepo, assessmentRepo, extensionRepo, rubricRepo, questionRepo, courseRepo)
+	groupService := services.NewGroupService(groupRepo, courseRepo)
+	termService := services.NewTermService(termRepo, orgRepo)
 
 	// Create handlers
 	authHandler := handlers.NewAuthHandler(authService, userService, cfg.JWT.Secret, cfg.JWT.Expiration)
@@ -38,14 +63,30 @@ func SetupRoutes(e *echo.Echo, db *db.DB, cfg *config.AppConfig) {
 	adminUserHandler := admin.NewUserHandler(userService)
 	adminCourseHandler := admin.NewCourseHandler(courseService)
 	adminAssessmentHandler := admin.NewAssessmentHandler(assessmentService, courseService)
+	adminExtensionHandler := admin.NewExtensionHandler(extensionService, assessmentService, courseService)
+	adminSchemeHandler := admin.NewGradingSchemeHandler(schemeService)
+	adminAnonymousHandler := admin.NewAnonymousGradingHandler(assessmentService, courseService)
+	adminTermHandler := admin.NewTermHandler(termService)
 
 	// Teacher handlers
 	teacherCourseHandler := teacher.NewCourseHandler(courseService)
 	teacherAssessmentHandler := teacher.NewAssessmentHandler(assessmentService, courseService)
+	teacherQuestionHandler := teacher.NewQuestionHandler(questionService, assessmentService, courseService)
+	teacherQuestionBankHandler := teacher.NewQuestionBankHandler(questionBankService, courseService)
+	teacherRubricHandler := teacher.NewRubricHandler(rubricService, assessmentService, courseService)
+	teacherExtensionHandler := teacher.NewExtensionHandler(extensionService, assessmentService, courseService)
+	teacherGradebookHandler := teacher.NewGradebookHandler(gradebookService, schemeService, courseService)
+	teacherRegradeHandler := teacher.NewRegradeHandler(regradeService, assessmentService, courseService)
+	teacherGroupHandler := teacher.NewGroupHandler(groupService, courseService)
+	teacherJoinCodeHandler := teacher.NewJoinCodeHandler(courseService, cfg.JWT.Secret)
 
 	// Student handlers
 	studentCourseHandler := student.NewCourseHandler(courseService)
-	studentAssessmentHandler := student.NewAssessmentHandler(assessmentService, courseService)
+	studentAssessmentHandler := student.NewAssessmentHandler(assessmentService, courseService, questionService)
+	studentGradebookHandler := student.NewGradebookHandler(gradebookService, courseService)
+	studentRegradeHandler := student.NewRegradeHandler(regradeService)
+	studentGroupHandler := student.NewGroupHandler(groupService, courseService)
+	studentJoinHandler := student.NewJoinHandler(courseService, cfg.JWT.Secret)
 
 	// Auth middleware
 	authMiddleware := customMiddleware.AuthMiddleware(cfg.JWT.Secret)
@@ -79,6 +120,9 @@ func SetupRoutes(e *echo.Echo, db *db.DB, cfg *config.AppConfig) {
 	adminRoutes.PUT("/organizations/:id", adminOrgHandler.HandleUpdateOrganization)
 	adminRoutes.DELETE("/organizations/:id", adminOrgHandler.HandleDeleteOrganization)
 	adminRoutes.GET("/organizations/:id/stats", adminOrgHandler.HandleGetOrganizationStats)
+	adminRoutes.PUT("/organizations/:id/grading-scheme", adminSchemeHandler.HandleSaveGradingScheme)
+	adminRoutes.GET("/organizations/:id/grading-scheme", adminSchemeHandler.HandleGetGradingScheme)
+	adminRoutes.DELETE("/organizations/:id/grading-scheme", adminSchemeHandler.HandleDeleteGradingScheme)
 
 	// User management
 	adminRoutes.POST("/users", adminUserHandler.HandleCreateUser)
@@ -90,6 +134,13 @@ func SetupRoutes(e *echo.Echo, db *db.DB, cfg *config.AppConfig) {
 	adminRoutes.GET("/users/teachers/:id/stats", adminUserHandler.HandleGetTeacherStats)
 	adminRoutes.GET("/users/students/:id/stats", adminUserHandler.HandleGetStudentStats)
 
+	// Academic terms
+	adminRoutes.POST("/terms", adminTermHandler.HandleCreateTerm)
+	adminRoutes.GET("/terms", adminTermHandler.HandleGetTerms)
+	adminRoutes.GET("/terms/:id", adminTermHandler.HandleGetTerm)
+	adminRoutes.PUT("/terms/:id", adminTermHandler.HandleUpdateTerm)
+	adminRoutes.DELETE("/terms/:id", adminTermHandler.HandleDeleteTerm)
+
 	// Course management
 	adminRoutes.POST("/courses", adminCourseHandler.HandleCreateCourse)
 	adminRoutes.GET("/courses", adminCourseHandler.HandleGetAllCourses)
@@ -103,12 +154,31 @@ func SetupRoutes(e *echo.Echo, db *db.DB, cfg *config.AppConfig) {
 	adminRoutes.POST("/courses/:id/students", adminCourseHandler.HandleManageStudentEnrollment)
 	adminRoutes.POST("/courses/:id/students/bulk", adminCourseHandler.HandleBulkEnrollStudents)
 	adminRoutes.GET("/courses/:id/students", adminCourseHandler.HandleGetCourseStudents)
+	adminRoutes.GET("/courses/:id/waitlist", adminCourseHandler.HandleGetWaitlist)
+	adminRoutes.GET("/courses/:id/prerequisites", adminCourseHandler.HandleGetPrerequisites)
+	adminRoutes.PUT("/courses/:id/prerequisites", adminCourseHandler.HandleSetPrerequisites)
+	adminRoutes.PUT("/courses/:id/students/:studentId/section", adminCourseHandler.HandleChangeStudentSection)
+	adminRoutes.POST("/courses/:id/sections", adminCourseHandler.HandleCreateSection)
+	adminRoutes.GET("/courses/:id/sections", adminCourseHandler.HandleGetSections)
+	adminRoutes.PUT("/courses/:id/sections/:sectionId", adminCourseHandler.HandleUpdateSection)
+	adminRoutes.DELETE("/courses/:id/sections/:sectionId", adminCourseHandler.HandleDeleteSection)
+	adminRoutes.GET("/courses/:id/sections/:sectionId/students", adminCourseHandler.HandleGetSectionStudents)
+	adminRoutes.POST("/courses/:id/sections/:sectionId/teachers", adminCourseHandler.HandleAssignSectionTeacher)
+	adminRoutes.DELETE("/courses/:id/sections/:sectionId/teachers/:teacherId", adminCourseHandler.HandleRemoveSectionTeacher)
 
 	// Assessment management (read-only for admin)
 	adminRoutes.GET("/assessments", adminAssessmentHandler.HandleGetAllAssessments)
 	adminRoutes.GET("/assessments/:id", adminAssessmentHandler.HandleGetAssessmentByID)
 	adminRoutes.GET("/assessments/:id/submissions", adminAssessmentHandler.HandleGetAssessmentSubmissions)
 	adminRoutes.GET("/submissions/:submissionId/grade", adminAssessmentHandler.HandleGetSubmissionGrades)
+	adminRoutes.GET("/submissions/:submissionId/grade/history", adminAssessmentHandler.HandleGetGradeHistory)
+	adminRoutes.POST("/assessments/:id/unmask", adminAnonymousHandler.HandleUnmask)
+	adminRoutes.GET("/assessments/:id/unmask-log", adminAnonymousHandler.HandleGetUnmaskLog)
+
+	// Per-student extensions
+	adminRoutes.GET("/assessments/:id/extensions", adminExtensionHandler.HandleGetExtensions)
+	adminRoutes.PUT("/assessments/:id/extensions/:studentId", adminExtensionHandler.HandleGrantExtension)
+	adminRoutes.DELETE("/assessments/:id/extensions/:studentId", adminExtensionHandler.HandleRevokeExtension)
 
 	// Teacher routes
 	teacherRoutes := apiAuth.Group("/teacher", teacherOnly)
@@ -117,6 +187,7 @@ func SetupRoutes(e *echo.Echo, db *db.DB, cfg *config.AppConfig) {
 	teacherRoutes.GET("/courses", teacherCourseHandler.HandleGetAssignedCourses)
 	teacherRoutes.GET("/courses/:id", teacherCourseHandler.HandleGetCourseByID)
 	teacherRoutes.GET("/courses/:id/students", teacherCourseHandler.HandleGetCourseStudents)
+	teacherRoutes.GET("/courses/:id/sections", teacherCourseHandler.HandleGetCourseSections)
 	teacherRoutes.GET("/organization", teacherCourseHandler.HandleGetOrganizationDetails)
 
 	// Assessment management for teachers
@@ -127,6 +198,87 @@ func SetupRoutes(e *echo.Echo, db *db.DB, cfg *config.AppConfig) {
 	teacherRoutes.DELETE("/assessments/:id", teacherAssessmentHandler.HandleDeleteAssessment)
 	teacherRoutes.GET("/assessments/:id/submissions", teacherAssessmentHandler.HandleGetSubmissions)
 	teacherRoutes.POST("/submissions/:submissionId/grade", teacherAssessmentHandler.HandleGradeSubmission)
+	teacherRoutes.GET("/submissions/:submissionId/grade/history", teacherAssessmentHandler.HandleGetGradeHistory)
+	teacherRoutes.PUT("/submissions/:submissionId/moderation", teacherAssessmentHandler.HandleAssignSecondMarker)
+	teacherRoutes.GET("/submissions/:submissionId/moderation", teacherAssessmentHandler.HandleGetModeration)
+	teacherRoutes.POST("/submissions/:submissionId/moderation/agree", teacherAssessmentHandler.HandleAgreeMark)
+	teacherRoutes.GET("/moderations", teacherAssessmentHandler.HandleGetModerationQueue)
+	teacherRoutes.POST("/assessments/:id/peer-reviews/distribute", teacherAssessmentHandler.HandleDistributePeerReviews)
+	teacherRoutes.GET("/assessments/:id/peer-reviews", teacherAssessmentHandler.HandleGetPeerReviews)
+	teacherRoutes.POST("/assessments/:id/peer-reviews/apply", teacherAssessmentHandler.HandleApplyPeerScores)
+	teacherRoutes.POST("/assessments/:id/similarity-checks", teacherAssessmentHandler.HandleStartSimilarityCheck)
+	teacherRoutes.GET("/assessments/:id/similarity-checks", teacherAssessmentHandler.HandleGetSimilarityChecks)
+	teacherRoutes.GET("/assessments/:id/similarity-checks/:checkId", teacherAssessmentHandler.HandleGetSimilarityCheck)
+	teacherRoutes.GET("/assessments/:id/similarity-checks/:checkId/matches/:matchId", teacherAssessmentHandler.HandleGetSimilarityReport)
+	teacherRoutes.GET("/submissions/:submissionId/members", teacherAssessmentHandler.HandleGetSubmissionMembers)
+	teacherRoutes.PUT("/submissions/:submissionId/members/:studentId/grade", teacherAssessmentHandler.HandleAdjustMemberGrade)
+	teacherRoutes.DELETE("/submissions/:submissionId/members/:studentId/grade", teacherAssessmentHandler.HandleClearMemberGrade)
+	teacherRoutes.POST("/assessments/:id/grades/release", teacherAssessmentHandler.HandleReleaseGrades)
+	teacherRoutes.GET("/assessments/:id/grades/export", teacherAssessmentHandler.HandleExportGrades)
+	teacherRoutes.POST("/assessments/:id/grades/import", teacherAssessmentHandler.HandleImportGrades)
+	teacherRoutes.GET("/submissions/:submissionId/attachments/:attachmentId", teacherAssessmentHandler.HandleDownloadAttachment)
+	teacherRoutes.GET("/assessments/:id/students/:studentId/attempts", teacherAssessmentHandler.HandleGetStudentAttempts)
+	teacherRoutes.GET("/assessments/:id/students/:studentId/attempts/diff", teacherAssessmentHandler.HandleDiffAttempts)
+
+	// Assessment questions for teachers
+	teacherRoutes.POST("/assessments/:id/questions", teacherQuestionHandler.HandleCreateQuestion)
+	teacherRoutes.GET("/assessments/:id/questions", teacherQuestionHandler.HandleGetQuestions)
+	teacherRoutes.PUT("/assessments/:id/questions/order", teacherQuestionHandler.HandleReorderQuestions)
+	teacherRoutes.PUT("/assessments/:id/questions/:questionId", teacherQuestionHandler.HandleUpdateQuestion)
+	teacherRoutes.DELETE("/assessments/:id/questions/:questionId", teacherQuestionHandler.HandleDeleteQuestion)
+	teacherRoutes.POST("/assessments/:id/question-draws", teacherQuestionHandler.HandleCreateDrawRule)
+	teacherRoutes.GET("/assessments/:id/question-draws", teacherQuestionHandler.HandleGetDrawRules)
+	teacherRoutes.DELETE("/assessments/:id/question-draws/:drawId", teacherQuestionHandler.HandleDeleteDrawRule)
+
+	// Course question banks for teachers
+	teacherRoutes.POST("/courses/:courseId/questions", teacherQuestionBankHandler.HandleCreateQuestion)
+	teacherRoutes.GET("/courses/:courseId/questions", teacherQuestionBankHandler.HandleGetQuestions)
+	teacherRoutes.GET("/courses/:courseId/questions/:questionId", teacherQuestionBankHandler.HandleGetQuestion)
+	teacherRoutes.PUT("/courses/:courseId/questions/:questionId", teacherQuestionBankHandler.HandleUpdateQuestion)
+	teacherRoutes.DELETE("/courses/:courseId/questions/:questionId", teacherQuestionBankHandler.HandleDeleteQuestion)
+
+	// Grading rubrics for teachers
+	teacherRoutes.POST("/courses/:courseId/rubrics", teacherRubricHandler.HandleCreateRubric)
+	teacherRoutes.GET("/courses/:courseId/rubrics", teacherRubricHandler.HandleGetRubrics)
+	teacherRoutes.GET("/courses/:courseId/rubrics/:rubricId", teacherRubricHandler.HandleGetRubric)
+	teacherRoutes.PUT("/courses/:courseId/rubrics/:rubricId", teacherRubricHandler.HandleUpdateRubric)
+	teacherRoutes.DELETE("/courses/:courseId/rubrics/:rubricId", teacherRubricHandler.HandleDeleteRubric)
+	teacherRoutes.PUT("/assessments/:id/rubric", teacherRubricHandler.HandleAttachRubric)
+	teacherRoutes.DELETE("/assessments/:id/rubric", teacherRubricHandler.HandleDetachRubric)
+
+	// Per-student extensions for teachers
+	teacherRoutes.GET("/assessments/:id/extensions", teacherExtensionHandler.HandleGetExtensions)
+	teacherRoutes.PUT("/assessments/:id/extensions/:studentId", teacherExtensionHandler.HandleGrantExtension)
+	teacherRoutes.DELETE("/assessments/:id/extensions/:studentId", teacherExtensionHandler.HandleRevokeExtension)
+
+	// Course gradebook for teachers
+	teacherRoutes.POST("/courses/:courseId/grade-categories", teacherGradebookHandler.HandleCreateCategory)
+	teacherRoutes.GET("/courses/:courseId/grade-categories", teacherGradebookHandler.HandleGetCategories)
+	teacherRoutes.PUT("/courses/:courseId/grade-categories/:categoryId", teacherGradebookHandler.HandleUpdateCategory)
+	teacherRoutes.DELETE("/courses/:courseId/grade-categories/:categoryId", teacherGradebookHandler.HandleDeleteCategory)
+	teacherRoutes.GET("/courses/:courseId/gradebook", teacherGradebookHandler.HandleGetGradebook)
+	teacherRoutes.PUT("/courses/:courseId/grading-scheme", teacherGradebookHandler.HandleSaveGradingScheme)
+	teacherRoutes.GET("/courses/:courseId/grading-scheme", teacherGradebookHandler.HandleGetGradingScheme)
+	teacherRoutes.DELETE("/courses/:courseId/grading-scheme", teacherGradebookHandler.HandleDeleteGradingScheme)
+
+	// Course groups for teachers
+	teacherRoutes.POST("/courses/:courseId/groups", teacherGroupHandler.HandleCreateGroup)
+	teacherRoutes.GET("/courses/:courseId/groups", teacherGroupHandler.HandleGetGroups)
+	teacherRoutes.PUT("/groups/:groupId", teacherGroupHandler.HandleUpdateGroup)
+	teacherRoutes.DELETE("/groups/:groupId", teacherGroupHandler.HandleDeleteGroup)
+	teacherRoutes.POST("/groups/:groupId/members", teacherGroupHandler.HandleAddMember)
+	teacherRoutes.DELETE("/groups/:groupId/members/:studentId", teacherGroupHandler.HandleRemoveMember)
+
+	// Join codes and invite links for teachers
+	teacherRoutes.POST("/courses/:courseId/join-codes", teacherJoinCodeHandler.HandleCreateJoinCode)
+	teacherRoutes.GET("/courses/:courseId/join-codes", teacherJoinCodeHandler.HandleGetJoinCodes)
+	teacherRoutes.DELETE("/join-codes/:codeId", teacherJoinCodeHandler.HandleRevokeJoinCode)
+	teacherRoutes.GET("/join-codes/:codeId/students", teacherJoinCodeHandler.HandleGetJoinCodeStudents)
+
+	// Regrade requests for teachers
+	teacherRoutes.GET("/regrade-requests", teacherRegradeHandler.HandleGetQueue)
+	teacherRoutes.POST("/regrade-requests/:id/accept", teacherRegradeHandler.HandleAcceptRequest)
+	teacherRoutes.POST("/regrade-requests/:id/reject", teacherRegradeHandler.HandleRejectRequest)
 
 	// Student routes
 	studentRoutes := apiAuth.Group("/student", studentOnly)
@@ -136,12 +288,39 @@ func SetupRoutes(e *echo.Echo, db *db.DB, cfg *config.AppConfig) {
 	studentRoutes.GET("/courses/:id", studentCourseHandler.HandleGetCourseByID)
 	studentRoutes.GET("/courses/available", studentCourseHandler.HandleGetAvailableCourses)
 	studentRoutes.POST("/courses/:id/enroll", studentCourseHandler.HandleEnrollInCourse)
+	studentRoutes.DELETE("/courses/:id/waitlist", studentCourseHandler.HandleLeaveWaitlist)
+	studentRoutes.POST("/courses/join", studentJoinHandler.HandleJoinWithCode)
+	studentRoutes.POST("/invites/:token", studentJoinHandler.HandleJoinWithInvite)
 	studentRoutes.GET("/organization", studentCourseHandler.HandleGetOrganizationDetails)
 
 	// Assessment management for students
 	studentRoutes.GET("/courses/:courseId/assessments", studentAssessmentHandler.HandleGetCourseAssessments)
 	studentRoutes.GET("/assessments/:id", studentAssessmentHandler.HandleGetAssessmentByID)
+	studentRoutes.GET("/assessments/:id/questions", studentAssessmentHandler.HandleGetQuestions)
+	studentRoutes.POST("/assessments/:id/start", studentAssessmentHandler.HandleStartAttempt)
 	studentRoutes.POST("/assessments/:id/submit", studentAssessmentHandler.HandleSubmitAssessment)
 	studentRoutes.GET("/assessments/:id/submission", studentAssessmentHandler.HandleViewSubmission)
+	studentRoutes.GET("/assessments/:id/attempts", studentAssessmentHandler.HandleGetAttempts)
+	studentRoutes.GET("/assessments/:id/submission/attachments/:attachmentId", studentAssessmentHandler.HandleDownloadAttachment)
 	studentRoutes.GET("/assessments/:id/grade", studentAssessmentHandler.HandleViewGrade)
+
+	// Course gradebook for students
+	studentRoutes.GET("/courses/:courseId/gradebook", studentGradebookHandler.HandleGetStanding)
+	studentRoutes.GET("/transcript", studentGradebookHandler.HandleGetTranscript)
+
+	// Regrade requests for students
+	studentRoutes.POST("/submissions/:submissionId/regrade-requests", studentRegradeHandler.HandleOpenRequest)
+	studentRoutes.GET("/regrade-requests", studentRegradeHandler.HandleGetRequests)
+
+	// Course groups for students
+	studentRoutes.GET("/courses/:courseId/groups", studentGroupHandler.HandleGetGroups)
+	studentRoutes.GET("/courses/:courseId/group", studentGroupHandler.HandleGetMyGroup)
+	studentRoutes.POST("/groups/:groupId/join", studentGroupHandler.HandleJoinGroup)
+	studentRoutes.POST("/groups/:groupId/leave", studentGroupHandler.HandleLeaveGroup)
+
+	// Peer review for students
+	studentRoutes.GET("/peer-reviews", studentAssessmentHandler.HandleGetPeerReviews)
+	studentRoutes.POST("/peer-reviews/:id", studentAssessmentHandler.HandleSubmitPeerReview)
+	studentRoutes.GET("/peer-reviews/:id/attachments/:attachmentId", studentAssessmentHandler.HandleDownloadPeerReviewAttachment)
+	studentRoutes.GET("/assessments/:id/peer-feedback", studentAssessmentHandler.HandleGetPeerFeedback)
 }
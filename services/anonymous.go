@@ -0,0 +1,154 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"assessment-management-system/models"
+)
+
+// isMasked reports whether a submission's student is hidden from staff: at assessments graded anonymously,
+// until the submission's grade is released
+func (s *AssessmentService) isMasked(ctx context.Context, assessment *models.Assessment, submission *models.AssessmentSubmission) (bool, error) {
+	if !assessment.AnonymousGrading {
+		return false, nil
+	}
+
+	grade, err := s.assessmentRepo.FindGradeBySubmission(ctx, submission.ID)
+	if err != nil {
+		return false, err
+	}
+
+	return grade == nil || !assessment.IsGradeReleased(grade, time.Now()), nil
+}
+
+// maskSubmission replaces the student of a submission with their pseudonym while the student is hidden from staff
+func (s *AssessmentService) maskSubmission(ctx context.Context, assessment *models.Assessment, submission *models.AssessmentSubmission) (bool, error) {
+	masked, err := s.isMasked(ctx, assessment, submission)
+	if err != nil || !masked {
+		return false, err
+	}
+
+	submission.Pseudonym = assessment.Pseudonym(submission.StudentID)
+	submission.StudentID = ""
+	return true, nil
+}
+
+// MaskAttempts replaces the student of the attempts whose grade is not released with their pseudonym, for staff
+// viewing an assessment graded anonymously
+func (s *AssessmentService) MaskAttempts(ctx context.Context, assessment *models.Assessment, attempts []*models.SubmissionAttempt) error {
+	for _, attempt := range attempts {
+		if _, err := s.maskSubmission(ctx, assessment, attempt.Submission); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// MaskDiff replaces the student of an attempt comparison with their pseudonym while either attempt is masked
+func (s *AssessmentService) MaskDiff(ctx context.Context, assessment *models.Assessment, diff *models.AttemptDiff) error {
+	for _, number := range []int{diff.FromAttempt, diff.ToAttempt} {
+		submission, err := s.assessmentRepo.FindAttempt(ctx, assessment.ID, diff.StudentID, number)
+		if err != nil {
+			return err
+		}
+
+		masked, err := s.isMasked(ctx, assessment, submission)
+		if err != nil {
+			return err
+		}
+
+		if masked {
+			diff.StudentID = assessment.Pseudonym(diff.StudentID)
+			return nil
+		}
+	}
+	return nil
+}
+
+// maskGradeHistory replaces the members named by adjustments in the grade history of a group submission with
+// their pseudonym while the submission is masked
+func (s *AssessmentService) maskGradeHistory(ctx context.Context, assessment *models.Assessment, submission *models.AssessmentSubmission, versions []*models.GradeVersion) error {
+	masked, err := s.isMasked(ctx, assessment, submission)
+	if err != nil || !masked {
+		return err
+	}
+
+	for _, version := range versions {
+		if version.StudentID != nil {
+			version.Pseudonym = assessment.Pseudonym(*version.StudentID)
+			version.StudentID = nil
+		}
+	}
+	return nil
+}
+
+// ResolveStudent finds the student staff refer to at an assessment. At assessments graded anonymously students are
+// referred to by pseudonym only, so that their work cannot be looked up by name.
+func (s *AssessmentService) ResolveStudent(ctx context.Context, assessment *models.Assessment, ref string) (string, error) {
+	if !assessment.AnonymousGrading {
+		return ref, nil
+	}
+
+	submissions, err := s.assessmentRepo.FindSubmissionsByAssessment(ctx, assessment.ID)
+	if err != nil {
+		return "", err
+	}
+
+	for _, submission := range submissions {
+		if assessment.Pseudonym(submission.StudentID) == ref {
+			return submission.StudentID, nil
+		}
+	}
+	return "", errors.New("this assessment is graded anonymously, refer to the student by pseudonym")
+}
+
+// UnmaskStudents reveals the students behind the pseudonyms of an assessment graded anonymously and logs who did so and why
+func (s *AssessmentService) UnmaskStudents(ctx context.Context, assessment *models.Assessment, adminID, reason string) ([]*models.AnonymousIdentity, error) {
+	if !assessment.AnonymousGrading {
+		return nil, errors.New("this assessment is not graded anonymously")
+	}
+
+	submissions, err := s.assessmentRepo.FindSubmissionsByAssessment(ctx, assessment.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := s.assessmentRepo.LogUnmask(ctx, assessment.ID, adminID, reason); err != nil {
+		return nil, err
+	}
+
+	identities := []*models.AnonymousIdentity{}
+	seen := make(map[string]bool)
+	for _, submission := range submissions {
+		if seen[submission.StudentID] {
+			continue
+		}
+		seen[submission.StudentID] = true
+
+		identity := &models.AnonymousIdentity{
+			Pseudonym: assessment.Pseudonym(submission.StudentID),
+			StudentID: submission.StudentID,
+		}
+
+		student, err := s.userRepo.FindByID(ctx, submission.StudentID)
+		if err != nil {
+			return nil, err
+		}
+		if student != nil {
+			identity.FirstName = student.FirstName
+			identity.LastName = student.LastName
+			identity.Email = student.Email
+		}
+
+		identities = append(identities, identity)
+	}
+
+	return identities, nil
+}
+
+// GetUnmaskLog retrieves every time the students of an assessment were revealed
+func (s *AssessmentService) GetUnmaskLog(ctx context.Context, assessmentID string) ([]*models.UnmaskLogEntry, error) {
+	return s.assessmentRepo.FindUnmaskLog(ctx, assessmentID)
+}
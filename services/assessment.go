@@ -3,10 +3,14 @@ package services
 import (
 	"context"
 	"errors"
+	"fmt"
+	"mime/multipart"
+	"strings"
 	"time"
 
 	"assessment-management-system/models"
 	"assessment-management-system/repositories"
+	"assessment-management-system/storage"
 )
 
 // AssessmentService handles assessment-related business logic
@@ -14,6 +18,18 @@ type AssessmentService struct {
 	assessmentRepo *repositories.AssessmentRepository
 	courseRepo     *repositories.CourseRepository
 	userRepo       *repositories.UserRepository
+	questionRepo   *repositories.QuestionRepository
+	bankRepo       *repositories.QuestionBankRepository
+	rubricRepo     *repositories.RubricRepository
+	extensionRepo  *repositories.ExtensionRepository
+	gradebookRepo  *repositories.GradebookRepository
+	regradeRepo    *repositories.RegradeRepository
+	moderationRepo *repositories.ModerationRepository
+	peerReviewRepo *repositories.PeerReviewRepository
+	groupRepo      *repositories.GroupRepository
+	similarityRepo *repositories.SimilarityRepository
+	sectionRepo    *repositories.SectionRepository
+	storage        storage.Storage
 }
 
 // NewAssessmentService creates a new AssessmentService
@@ -21,11 +37,35 @@ func NewAssessmentService(
 	assessmentRepo *repositories.AssessmentRepository,
 	courseRepo *repositories.CourseRepository,
 	userRepo *repositories.UserRepository,
+	questionRepo *repositories.QuestionRepository,
+	bankRepo *repositories.QuestionBankRepository,
+	rubricRepo *repositories.RubricRepository,
+	extensionRepo *repositories.ExtensionRepository,
+	gradebookRepo *repositories.GradebookRepository,
+	regradeRepo *repositories.RegradeRepository,
+	moderationRepo *repositories.ModerationRepository,
+	peerReviewRepo *repositories.PeerReviewRepository,
+	groupRepo *repositories.GroupRepository,
+	similarityRepo *repositories.SimilarityRepository,
+	sectionRepo *repositories.SectionRepository,
+	storage storage.Storage,
 ) *AssessmentService {
 	return &AssessmentService{
 		assessmentRepo: assessmentRepo,
 		courseRepo:     courseRepo,
 		userRepo:       userRepo,
+		questionRepo:   questionRepo,
+		bankRepo:       bankRepo,
+		rubricRepo:     rubricRepo,
+		extensionRepo:  extensionRepo,
+		gradebookRepo:  gradebookRepo,
+		regradeRepo:    regradeRepo,
+		moderationRepo: moderationRepo,
+		peerReviewRepo: peerReviewRepo,
+		groupRepo:      groupRepo,
+		similarityRepo: similarityRepo,
+		sectionRepo:    sectionRepo,
+		storage:        storage,
 	}
 }
 
@@ -61,8 +101,60 @@ func (s *AssessmentService) CreateAssessment(ctx context.Context, teacherID stri
 		return nil, errors.New("teacher is not assigned to this course")
 	}
 
+	// Courses of past terms are read-only
+	now := time.Now()
+	if course.IsArchived(now) {
+		return nil, ErrCourseArchived
+	}
+
+	if err := validateTermDates(course, req.AvailableFrom, req.DueDate, req.CutoffDate, req.PeerReviewDueDate); err != nil {
+		return nil, err
+	}
+
+	if err := validateLatePolicy(req.DueDate, req.CutoffDate, req.LatePenaltyType, req.LatePenaltyValue); err != nil {
+		return nil, err
+	}
+
+	// New assessments start as drafts unless they are published straight away or scheduled
+	if req.Status == "" && req.AvailableFrom != nil && req.AvailableFrom.After(now) {
+		req.Status = models.AssessmentStatusScheduled
+	}
+
+	if err := validateStatus(req.Status, req.AvailableFrom, now); err != nil {
+		return nil, err
+	}
+
+	if err := validateGradeRelease(req.GradeReleaseMode, req.GradesReleaseAt); err != nil {
+		return nil, err
+	}
+
+	if req.CategoryID != nil && *req.CategoryID == "" {
+		req.CategoryID = nil
+	}
+	if req.CategoryID != nil {
+		if err := s.checkCategory(ctx, req.CourseID, *req.CategoryID); err != nil {
+			return nil, err
+		}
+	}
+
+	if req.PreviousOfferingID != nil && *req.PreviousOfferingID == "" {
+		req.PreviousOfferingID = nil
+	}
+	if req.PreviousOfferingID != nil {
+		if err := s.checkPreviousOffering(ctx, course, *req.PreviousOfferingID); err != nil {
+			return nil, err
+		}
+	}
+
+	req.SectionIDs, err = s.checkAssessmentSections(ctx, req.CourseID, teacherID, req.SectionIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	req.AllowedFileTypes = normalizeFileTypes(req.AllowedFileTypes)
+
 	// Create assessment
-	assessment, err := s.assessmentRepo.Create(ctx, req.CourseID, teacherID, req.Title, req.Description, string(req.Type), req.MaxScore, req.DueDate)
+	assessment, err := s.assessmentRepo.Create(ctx, teacherID, req)
 	if err != nil {
 		return nil, err
 	}
@@ -110,6 +202,46 @@ func (s *AssessmentService) GetAssessmentsByOrganization(ctx context.Context, or
 	return assessmentsWithDetails, nil
 }
 
+// checkCategory checks that a grade category belongs to the assessment's course
+func (s *AssessmentService) checkCategory(ctx context.Context, courseID, categoryID string) error {
+	category, err := s.gradebookRepo.FindCategoryByID(ctx, categoryID)
+	if err != nil {
+		return err
+	}
+
+	if category == nil || category.CourseID != courseID {
+		return errors.New("grade category not found in this course")
+	}
+	return nil
+}
+
+// checkPreviousOffering checks that an assessment can be the earlier offering of an assessment of the course: it
+// must belong to a course of the same organization in an earlier term
+func (s *AssessmentService) checkPreviousOffering(ctx context.Context, course *models.Course, previousID string) error {
+	previous, err := s.assessmentRepo.FindByID(ctx, previousID)
+	if err != nil {
+		return err
+	}
+
+	var previousCourse *models.Course
+	if previous != nil {
+		previousCourse, err = s.courseRepo.FindByID(ctx, previous.CourseID)
+		if err != nil {
+			return err
+		}
+	}
+
+	if previousCourse == nil || previousCourse.OrganizationID != course.OrganizationID {
+		return errors.New("previous offering not found in this organization")
+	}
+
+	// Earlier terms only, so that following previous offerings always ends
+	if course.Term == nil || previousCourse.Term == nil || !previousCourse.Term.StartDate.Before(course.Term.StartDate) {
+		return errors.New("the previous offering must belong to a course of an earlier term")
+	}
+	return nil
+}
+
 // GetAssessmentsByCourse retrieves all assessments for a course
 func (s *AssessmentService) GetAssessmentsByCourse(ctx context.Context, courseID string) ([]*models.Assessment, error) {
 	// Validate course
@@ -126,11 +258,61 @@ func (s *AssessmentService) GetAssessmentsByCourse(ctx context.Context, courseID
 	return s.assessmentRepo.FindByCourse(ctx, courseID)
 }
 
+// GetVisibleAssessmentsByCourse retrieves the assessments of a course that a student can see, those for the
+// student's section
+func (s *AssessmentService) GetVisibleAssessmentsByCourse(ctx context.Context, courseID, studentID string) ([]*models.Assessment, error) {
+	assessments, err := s.GetAssessmentsByCourse(ctx, courseID)
+	if err != nil {
+		return nil, err
+	}
+
+	sectionID, err := s.sectionRepo.FindStudentSectionID(ctx, courseID, studentID)
+	if err != nil {
+		return nil, err
+	}
+
+	visible := make([]*models.Assessment, 0, len(assessments))
+	for _, assessment := range assessments {
+		if assessment.IsVisibleToStudents() && assessment.IsForSection(sectionID) {
+			visible = append(visible, assessment)
+		}
+	}
+	return visible, nil
+}
+
 // GetAssessmentByID retrieves an assessment by ID
 func (s *AssessmentService) GetAssessmentByID(ctx context.Context, id string) (*models.Assessment, error) {
 	return s.assessmentRepo.FindByID(ctx, id)
 }
 
+// GetAssessmentForStudent retrieves an assessment as it applies to a student, with the student's extension in place.
+// Assessments for other sections than the student's are not found.
+func (s *AssessmentService) GetAssessmentForStudent(ctx context.Context, id, studentID string) (*models.Assessment, error) {
+	assessment, err := s.assessmentRepo.FindByID(ctx, id)
+	if err != nil || assessment == nil {
+		return assessment, err
+	}
+
+	isForStudent, err := s.isForStudent(ctx, assessment, studentID)
+	if err != nil || !isForStudent {
+		return nil, err
+	}
+
+	assessment, _, err = s.applyExtension(ctx, assessment, studentID)
+	return assessment, err
+}
+
+// applyExtension overrides the assessment's deadlines and limits with the student's extension, if any.
+// The returned assessment is a copy, the extension is nil when the student has none.
+func (s *AssessmentService) applyExtension(ctx context.Context, assessment *models.Assessment, studentID string) (*models.Assessment, *models.Extension, error) {
+	extension, err := s.extensionRepo.FindByStudentAndAssessment(ctx, assessment.ID, studentID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return extension.Apply(assessment), extension, nil
+}
+
 // UpdateAssessment updates an assessment
 func (s *AssessmentService) UpdateAssessment(ctx context.Context, id string, req models.UpdateAssessmentRequest) (*models.Assessment, error) {
 	// Check if assessment exists
@@ -143,8 +325,112 @@ func (s *AssessmentService) UpdateAssessment(ctx context.Context, id string, req
 		return nil, errors.New("assessment not found")
 	}
 
+	if req.Type != nil && assessment.RubricID != nil && !req.Type.SupportsRubric() {
+		return nil, errors.New("only assignments and projects can have a rubric, detach the rubric first")
+	}
+
+	submissionCount, err := s.assessmentRepo.CountSubmissionsByAssessment(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	// Changing the maximum score would misrepresent the grades already given
+	if req.MaxScore != nil && *req.MaxScore != assessment.MaxScore && submissionCount > 0 {
+		return nil, errors.New("the maximum score cannot be changed once there are submissions")
+	}
+
+	if req.GroupSubmission != nil && *req.GroupSubmission != assessment.GroupSubmission && submissionCount > 0 {
+		return nil, errors.New("group submission cannot be turned on or off once there are submissions")
+	}
+
+	// Check the status as it will be after the update
+	status, availableFrom := assessment.Status, assessment.AvailableFrom
+	if req.Status != nil {
+		status = *req.Status
+	}
+	if req.AvailableFrom != nil {
+		availableFrom = req.AvailableFrom
+	}
+	if err := ensureStatusChangeAllowed(assessment.Status, status, submissionCount); err != nil {
+		return nil, err
+	}
+	if err := validateStatus(status, availableFrom, time.Now()); err != nil {
+		return nil, err
+	}
+
+	// Check the late policy as it will be after the update
+	dueDate, cutoffDate := assessment.DueDate, assessment.CutoffDate
+	if req.DueDate != nil {
+		dueDate = req.DueDate
+	}
+	if req.CutoffDate != nil {
+		cutoffDate = req.CutoffDate
+	}
+	penaltyType, penaltyValue := assessment.LatePenaltyType, assessment.LatePenaltyValue
+	if req.LatePenaltyType != nil {
+		penaltyType = *req.LatePenaltyType
+	}
+	if req.LatePenaltyValue != nil {
+		penaltyValue = *req.LatePenaltyValue
+	}
+	if err := validateLatePolicy(dueDate, cutoffDate, penaltyType, penaltyValue); err != nil {
+		return nil, err
+	}
+
+	// Check the dates as they will be after the update against the course's term
+	course, err := s.courseRepo.FindByID(ctx, assessment.CourseID)
+	if err != nil {
+		return nil, err
+	}
+
+	if course == nil {
+		return nil, errors.New("course not found")
+	}
+
+	peerReviewDueDate := assessment.PeerReviewDueDate
+	if req.PeerReviewDueDate != nil {
+		peerReviewDueDate = req.PeerReviewDueDate
+	}
+	if err := validateTermDates(course, availableFrom, dueDate, cutoffDate, peerReviewDueDate); err != nil {
+		return nil, err
+	}
+
+	// Check the grade release as it will be after the update
+	releaseMode, releaseAt := assessment.GradeReleaseMode, assessment.GradesReleaseAt
+	if req.GradeReleaseMode != nil {
+		releaseMode = *req.GradeReleaseMode
+	}
+	if req.GradesReleaseAt != nil {
+		releaseAt = req.GradesReleaseAt
+	}
+	if err := validateGradeRelease(releaseMode, releaseAt); err != nil {
+		return nil, err
+	}
+
+	if req.CategoryID != nil && *req.CategoryID != "" {
+		if err := s.checkCategory(ctx, assessment.CourseID, *req.CategoryID); err != nil {
+			return nil, err
+		}
+	}
+
+	if req.PreviousOfferingID != nil && *req.PreviousOfferingID != "" {
+		if err := s.checkPreviousOffering(ctx, course, *req.PreviousOfferingID); err != nil {
+			return nil, err
+		}
+	}
+
+	if req.SectionIDs != nil {
+		sectionIDs, err := s.checkAssessmentSections(ctx, assessment.CourseID, assessment.TeacherID, *req.SectionIDs)
+		if err != nil {
+			return nil, err
+		}
+		req.SectionIDs = &sectionIDs
+	}
+
+	req.AllowedFileTypes = normalizeFileTypes(req.AllowedFileTypes)
+
 	// Update assessment
-	updatedAssessment, err := s.assessmentRepo.Update(ctx, id, req.Title, req.Description, req.Type, req.MaxScore, req.DueDate)
+	updatedAssessment, err := s.assessmentRepo.Update(ctx, id, req)
 	if err != nil {
 		return nil, err
 	}
@@ -168,7 +454,8 @@ func (s *AssessmentService) DeleteAssessment(ctx context.Context, id string) err
 	return s.assessmentRepo.Delete(ctx, id)
 }
 
-// GetAssessmentSubmissions retrieves all submissions for an assessment
+// GetAssessmentSubmissions retrieves all submissions for an assessment as staff see them, with pseudonyms for the
+// students hidden by anonymous grading
 func (s *AssessmentService) GetAssessmentSubmissions(ctx context.Context, assessmentID string) ([]*models.AssessmentSubmission, error) {
 	// Check if assessment exists
 	assessment, err := s.assessmentRepo.FindByID(ctx, assessmentID)
@@ -181,17 +468,71 @@ func (s *AssessmentService) GetAssessmentSubmissions(ctx context.Context, assess
 	}
 
 	// Get submissions
-	return s.assessmentRepo.FindSubmissionsByAssessment(ctx, assessmentID)
+	submissions, err := s.assessmentRepo.FindSubmissionsByAssessment(ctx, assessmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, submission := range submissions {
+		if err := s.loadSubmissionDetails(ctx, submission); err != nil {
+			return nil, err
+		}
+
+		if _, err := s.maskSubmission(ctx, assessment, submission); err != nil {
+			return nil, err
+		}
+	}
+
+	return submissions, nil
 }
 
 // GetSubmissionByID retrieves a submission by ID
 func (s *AssessmentService) GetSubmissionByID(ctx context.Context, id string) (*models.AssessmentSubmission, error) {
-	return s.assessmentRepo.FindSubmissionByID(ctx, id)
+	submission, err := s.assessmentRepo.FindSubmissionByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.loadSubmissionDetails(ctx, submission); err != nil {
+		return nil, err
+	}
+
+	return submission, nil
 }
 
 // GetStudentSubmission retrieves a student's submission for an assessment
 func (s *AssessmentService) GetStudentSubmission(ctx context.Context, assessmentID, studentID string) (*models.AssessmentSubmission, error) {
-	return s.assessmentRepo.FindSubmissionByStudentAndAssessment(ctx, assessmentID, studentID)
+	submission, err := s.assessmentRepo.FindSubmissionByStudentAndAssessment(ctx, assessmentID, studentID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.loadSubmissionDetails(ctx, submission); err != nil {
+		return nil, err
+	}
+
+	return submission, nil
+}
+
+// loadSubmissionDetails loads the per-question answers and the attachments of a submission, if any
+func (s *AssessmentService) loadSubmissionDetails(ctx context.Context, submission *models.AssessmentSubmission) error {
+	if submission == nil {
+		return nil
+	}
+
+	answers, err := s.questionRepo.FindAnswersBySubmission(ctx, submission.ID)
+	if err != nil {
+		return err
+	}
+
+	attachments, err := s.assessmentRepo.FindAttachmentsBySubmission(ctx, submission.ID)
+	if err != nil {
+		return err
+	}
+
+	submission.Answers = answers
+	submission.Attachments = attachments
+	return nil
 }
 
 // HasStudentSubmitted checks if a student has submitted an assessment
@@ -204,10 +545,17 @@ func (s *AssessmentService) HasStudentSubmitted(ctx context.Context, assessmentI
 	return submission != nil, nil
 }
 
-// SubmitAssessment submits an assessment
-func (s *AssessmentService) SubmitAssessment(ctx context.Context, assessmentID, studentID, content string) (*models.AssessmentSubmission, error) {
-	// Check if assessment exists
-	assessment, err := s.assessmentRepo.FindByID(ctx, assessmentID)
+// CountAttempts counts the attempts a student has made at an assessment
+func (s *AssessmentService) CountAttempts(ctx context.Context, assessmentID, studentID string) (int, error) {
+	return s.assessmentRepo.CountAttempts(ctx, assessmentID, studentID)
+}
+
+// SubmitAssessment submits an attempt at an assessment together with any uploaded files. Assessments with
+// questions are scored automatically and receive a grade without any teacher action.
+func (s *AssessmentService) SubmitAssessment(ctx context.Context, assessmentID, studentID string, req models.CreateSubmissionRequest, files []*multipart.FileHeader) (*models.AssessmentSubmission, error) {
+	// Deadlines and limits are the student's own when they have an extension. Assessments for other sections are
+	// not found.
+	assessment, err := s.GetAssessmentForStudent(ctx, assessmentID, studentID)
 	if err != nil {
 		return nil, err
 	}
@@ -216,6 +564,10 @@ func (s *AssessmentService) SubmitAssessment(ctx context.Context, assessmentID,
 		return nil, errors.New("assessment not found")
 	}
 
+	if assessment.Status != models.AssessmentStatusPublished {
+		return nil, errors.New("assessment is not open for submissions")
+	}
+
 	// Check if student exists
 	student, err := s.userRepo.FindByID(ctx, studentID)
 	if err != nil {
@@ -236,37 +588,152 @@ func (s *AssessmentService) SubmitAssessment(ctx context.Context, assessmentID,
 		return nil, errors.New("student is not enrolled in this course")
 	}
 
-	// Check if student has already submitted
-	hasSubmitted, err := s.HasStudentSubmitted(ctx, assessmentID, studentID)
+	// Check if the student has attempts left
+	attempts, err := s.assessmentRepo.CountAttempts(ctx, assessmentID, studentID)
 	if err != nil {
 		return nil, err
 	}
 
-	if hasSubmitted {
-		return nil, errors.New("student has already submitted this assessment")
+	// Any member submits for the whole group, the group shares its attempts
+	var groupID *string
+	if assessment.GroupSubmission {
+		group, groupAttempts, err := s.findSubmittingGroup(ctx, assessment, studentID)
+		if err != nil {
+			return nil, err
+		}
+
+		if group == nil {
+			return nil, errors.New("join a group before submitting this group assessment")
+		}
+
+		attempts = max(attempts, groupAttempts)
+		groupID = &group.ID
+	}
+
+	if attempts >= assessment.MaxAttempts {
+		if assessment.MaxAttempts == 1 {
+			if groupID != nil {
+				return nil, errors.New("your group has already submitted this assessment")
+			}
+			return nil, errors.New("student has already submitted this assessment")
+		}
+		return nil, fmt.Errorf("all %d attempts at this assessment have been used", assessment.MaxAttempts)
 	}
 
-	// Check if assessment is past due
-	if assessment.DueDate != nil && assessment.DueDate.Before(time.Now()) {
+	// Timed attempts are accepted until their time window plus the grace period ends, the window never
+	// runs past the submission deadline
+	submittedAt := time.Now()
+	var sessionID *string
+	if assessment.IsTimed() {
+		session, err := s.assessmentRepo.FindOpenAttemptSession(ctx, assessmentID, studentID)
+		if err != nil {
+			return nil, err
+		}
+
+		if session == nil {
+			return nil, errors.New("start the attempt before submitting")
+		}
+
+		if !session.AcceptsSubmissionAt(submittedAt, assessment.GracePeriodSeconds) {
+			return nil, errors.New("the time limit for this attempt has passed")
+		}
+		sessionID = &session.ID
+	} else if deadline := assessment.SubmissionDeadline(); deadline != nil && deadline.Before(submittedAt) {
+		// Check if submissions are still accepted, late work is accepted until the cutoff date
+		if assessment.CutoffDate != nil {
+			return nil, errors.New("the cutoff date for late submissions has passed")
+		}
 		return nil, errors.New("assessment is past due")
 	}
 
-	// Create submission
-	submission, err := s.assessmentRepo.CreateSubmission(ctx, assessmentID, studentID, content)
+	questions, err := resolveStudentQuestions(ctx, s.questionRepo, s.bankRepo, assessment, studentID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check the files against the assessment's limits before storing anything
+	if err := validateAttachments(assessment, files); err != nil {
+		return nil, err
+	}
+
+	var answers []*models.SubmissionAnswer
+	var grade *models.Grade
+
+	if len(questions) == 0 {
+		// Free-text assessments keep the manual grading flow
+		if len(req.Answers) > 0 {
+			return nil, errors.New("this assessment has no questions to answer")
+		}
+
+		if strings.TrimSpace(req.Content) == "" && len(files) == 0 {
+			return nil, errors.New("content or an attached file is required")
+		}
+	} else {
+		// Score the answers and record the submission together with its grade
+		var earned, total float64
+		answers, earned, total, err = gradeAnswers(questions, req.Answers)
+		if err != nil {
+			return nil, err
+		}
+
+		rawScore := scaleScore(earned, total, assessment.MaxScore)
+		latePenalty := calculateLatePenalty(assessment, submittedAt, rawScore)
+		grade = &models.Grade{
+			Score:       rawScore - latePenalty,
+			RawScore:    rawScore,
+			LatePenalty: latePenalty,
+			Feedback:    fmt.Sprintf("Automatically graded: %.2f of %.2f points", earned, total),
+			GradedBy:    assessment.TeacherID,
+		}
+	}
+
+	attachments, err := s.storeAttachments(ctx, assessment, studentID, files)
 	if err != nil {
 		return nil, err
 	}
 
+	submission, err := s.assessmentRepo.CreateSubmissionWithDetails(ctx, assessmentID, studentID, groupID, req.Content, answers, attachments, grade, sessionID)
+	if err != nil {
+		// The submission was not recorded, so its files are unreachable
+		s.removeAttachments(attachments)
+		return nil, err
+	}
+
+	// Automatic scores are part of the grade, which may not be released yet
+	if !assessment.AreGradesReleased(time.Now()) {
+		hideAnswerScores(submission)
+	}
+
 	return submission, nil
 }
 
-// GetSubmissionGrade retrieves the grade for a submission
+// GetSubmissionGrade retrieves the grade for a submission, including the filled-in rubric if it was graded with one
+// and the regrade requests opened against it
 func (s *AssessmentService) GetSubmissionGrade(ctx context.Context, submissionID string) (*models.Grade, error) {
-	return s.assessmentRepo.FindGradeBySubmission(ctx, submissionID)
+	grade, err := s.assessmentRepo.FindGradeBySubmission(ctx, submissionID)
+	if err != nil || grade == nil {
+		return grade, err
+	}
+
+	scores, err := s.rubricRepo.FindScoresBySubmission(ctx, submissionID)
+	if err != nil {
+		return nil, err
+	}
+
+	regrades, err := s.regradeRepo.FindBySubmission(ctx, submissionID)
+	if err != nil {
+		return nil, err
+	}
+
+	grade.Rubric = scores
+	grade.Regrades = regrades
+	return grade, nil
 }
 
-// GradeSubmission grades a submission
-func (s *AssessmentService) GradeSubmission(ctx context.Context, submissionID, teacherID string, score float64, feedback string) (*models.Grade, error) {
+// GradeSubmission grades a submission. Assessments with a rubric are graded by choosing a level per
+// criterion and the score is scaled from the rubric points to the assessment's maximum score. Late
+// submissions are penalized automatically, the grade keeps both the raw and the penalized score.
+func (s *AssessmentService) GradeSubmission(ctx context.Context, submissionID, teacherID string, req models.GradeSubmissionRequest) (*models.Grade, error) {
 	// Check if submission exists
 	submission, err := s.assessmentRepo.FindSubmissionByID(ctx, submissionID)
 	if err != nil {
@@ -287,37 +754,152 @@ func (s *AssessmentService) GradeSubmission(ctx context.Context, submissionID, t
 		return nil, errors.New("invalid teacher")
 	}
 
+	// Double-marked submissions are graded with the mark the moderator agrees on
+	moderation, err := s.moderationRepo.FindBySubmission(ctx, submissionID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkModeratedGrade(moderation); err != nil {
+		return nil, err
+	}
+
 	// Get assessment to check max score
 	assessment, err := s.assessmentRepo.FindByID(ctx, submission.AssessmentID)
 	if err != nil {
 		return nil, err
 	}
 
-	// Validate score
-	if score < 0 || score > float64(assessment.MaxScore) {
-		return nil, errors.New("score must be between 0 and the maximum score")
+	if assessment == nil {
+		return nil, errors.New("assessment not found")
+	}
+
+	// The late penalty is measured against the student's own due date
+	assessment, _, err = s.applyExtension(ctx, assessment, submission.StudentID)
+	if err != nil {
+		return nil, err
+	}
+
+	score, scores, err := s.scoreSubmission(ctx, assessment, req)
+	if err != nil {
+		return nil, err
 	}
 
+	// Late submissions lose points according to the assessment's late policy
+	latePenalty := calculateLatePenalty(assessment, submission.SubmittedAt, score)
+
 	// Check if submission is already graded
 	existingGrade, err := s.assessmentRepo.FindGradeBySubmission(ctx, submissionID)
 	if err != nil {
 		return nil, err
 	}
 
-	var grade *models.Grade
+	reason := gradeChangeReason(req.Reason, existingGrade != nil)
+	if assessment.RubricID != nil {
+		return s.assessmentRepo.SaveRubricGrade(ctx, submission.ID, score, latePenalty, req.Feedback, teacherID, reason, scores)
+	}
+
 	if existingGrade == nil {
 		// Create grade
-		grade, err = s.assessmentRepo.CreateGrade(ctx, submissionID, score, feedback, teacherID)
-	} else {
-		// Update grade
-		grade, err = s.assessmentRepo.UpdateGrade(ctx, submissionID, score, feedback, teacherID)
+		return s.assessmentRepo.CreateGrade(ctx, submissionID, score, latePenalty, req.Feedback, teacherID, reason)
+	}
+
+	// Update grade
+	return s.assessmentRepo.UpdateGrade(ctx, submissionID, score, latePenalty, req.Feedback, teacherID, reason)
+}
+
+// scoreSubmission computes the raw score a grading request gives, before any late penalty. Assessments with a
+// rubric are scored from the level chosen for every criterion, which are returned as well.
+func (s *AssessmentService) scoreSubmission(ctx context.Context, assessment *models.Assessment, req models.GradeSubmissionRequest) (float64, []*models.RubricScore, error) {
+	if assessment.RubricID != nil {
+		return s.scoreWithRubric(ctx, assessment, req)
+	}
+
+	if len(req.Rubric) > 0 {
+		return 0, nil, errors.New("this assessment has no rubric")
+	}
+
+	if req.Score == nil {
+		return 0, nil, errors.New("score is required")
+	}
+	score := *req.Score
+
+	// Validate score
+	if score < 0 || score > float64(assessment.MaxScore) {
+		return 0, nil, errors.New("score must be between 0 and the maximum score")
+	}
+
+	return score, nil, nil
+}
+
+// scoreWithRubric computes a score from the level chosen for every criterion of the assessment's rubric
+func (s *AssessmentService) scoreWithRubric(ctx context.Context, assessment *models.Assessment, req models.GradeSubmissionRequest) (float64, []*models.RubricScore, error) {
+	if req.Score != nil {
+		return 0, nil, errors.New("this assessment is graded with a rubric, choose a level per criterion instead of a score")
 	}
 
+	rubric, err := s.rubricRepo.FindByID(ctx, *assessment.RubricID)
 	if err != nil {
-		return nil, err
+		return 0, nil, err
 	}
 
-	return grade, nil
+	if rubric == nil {
+		return 0, nil, errors.New("rubric not found")
+	}
+
+	return scoreRubric(rubric, assessment, req.Rubric)
+}
+
+// scoreRubric computes a score from the level chosen for every criterion of a rubric, scaled to the assessment's
+// maximum score
+func scoreRubric(rubric *models.Rubric, assessment *models.Assessment, selections []models.RubricSelectionRequest) (float64, []*models.RubricScore, error) {
+	selected := make(map[string]models.RubricSelectionRequest, len(selections))
+	for _, selection := range selections {
+		if _, duplicate := selected[selection.CriterionID]; duplicate {
+			return 0, nil, fmt.Errorf("criterion %s is graded more than once", selection.CriterionID)
+		}
+		selected[selection.CriterionID] = selection
+	}
+
+	var earned float64
+	scores := make([]*models.RubricScore, 0, len(rubric.Criteria))
+	for _, criterion := range rubric.Criteria {
+		selection, ok := selected[criterion.ID]
+		if !ok {
+			return 0, nil, fmt.Errorf("a level must be chosen for criterion %q", criterion.Title)
+		}
+		delete(selected, criterion.ID)
+
+		var level *models.RubricLevel
+		for _, candidate := range criterion.Levels {
+			if candidate.ID == selection.LevelID {
+				level = candidate
+				break
+			}
+		}
+
+		if level == nil {
+			return 0, nil, fmt.Errorf("level %s does not belong to criterion %q", selection.LevelID, criterion.Title)
+		}
+
+		earned += level.Points
+		scores = append(scores, &models.RubricScore{
+			CriterionID:    criterion.ID,
+			CriterionTitle: criterion.Title,
+			LevelID:        level.ID,
+			LevelTitle:     level.Title,
+			Descriptor:     level.Descriptor,
+			Points:         level.Points,
+			MaxPoints:      criterion.MaxPoints(),
+			Comment:        selection.Comment,
+		})
+	}
+
+	for criterionID := range selected {
+		return 0, nil, fmt.Errorf("criterion %s does not belong to this rubric", criterionID)
+	}
+
+	return scaleScore(earned, rubric.MaxPoints(), assessment.MaxScore), scores, nil
 }
 
 // GetStudentAssessmentStatus retrieves a student's status for an assessment
@@ -332,24 +914,52 @@ func (s *AssessmentService) GetStudentAssessmentStatus(ctx context.Context, asse
 		return nil, errors.New("assessment not found")
 	}
 
-	// Get student's submission
-	submission, err := s.assessmentRepo.FindSubmissionByStudentAndAssessment(ctx, assessmentID, studentID)
+	// Show the student their own deadlines when they have an extension
+	assessment, extension, err := s.applyExtension(ctx, assessment, studentID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Get the student's attempts, the latest one is shown as the submission. Grades are only shown once released.
+	attempts, err := s.GetReleasedAttempts(ctx, assessment, studentID)
 	if err != nil {
 		return nil, err
 	}
 
 	// Check submission status
-	hasSubmitted := submission != nil
+	hasSubmitted := len(attempts) > 0
 
 	// Get grade if submitted
+	var submission *models.AssessmentSubmission
 	var grade *models.Grade
-	var isGraded bool
+	var finalScore *float64
 	if hasSubmitted {
-		grade, err = s.assessmentRepo.FindGradeBySubmission(ctx, submission.ID)
+		latest := attempts[len(attempts)-1]
+		submission, grade = latest.Submission, latest.Grade
+		if final, _ := computeFinalGrade(assessment, attempts); final != nil {
+			finalScore = &final.Score
+		}
+	}
+	isGraded := grade != nil
+
+	attemptsUsed, err := s.countAttemptsUsed(ctx, assessment, studentID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Expose the running timed attempt so clients can show a countdown
+	var activeAttempt *models.AttemptSession
+	var timeRemaining *int
+	if assessment.IsTimed() {
+		activeAttempt, err = s.GetActiveAttempt(ctx, assessment, studentID)
 		if err != nil {
 			return nil, err
 		}
-		isGraded = grade != nil
+
+		if activeAttempt != nil {
+			remaining := max(int(time.Until(activeAttempt.EndsAt).Seconds()), 0)
+			timeRemaining = &remaining
+		}
 	}
 
 	// Calculate days until due
@@ -365,13 +975,19 @@ func (s *AssessmentService) GetStudentAssessmentStatus(ctx context.Context, asse
 
 	// Create status object
 	status := &models.StudentAssessmentStatus{
-		Assessment:   assessment,
-		Submission:   submission,
-		Grade:        grade,
-		HasSubmitted: hasSubmitted,
-		IsGraded:     isGraded,
-		DaysUntilDue: daysUntilDue,
-		IsOverdue:    isOverdue,
+		Assessment:           assessment,
+		Submission:           submission,
+		Grade:                grade,
+		FinalScore:           finalScore,
+		Extension:            extension,
+		HasSubmitted:         hasSubmitted,
+		IsGraded:             isGraded,
+		AttemptsUsed:         attemptsUsed,
+		AttemptsLeft:         max(assessment.MaxAttempts-attemptsUsed, 0),
+		ActiveAttempt:        activeAttempt,
+		TimeRemainingSeconds: timeRemaining,
+		DaysUntilDue:         daysUntilDue,
+		IsOverdue:            isOverdue,
 	}
 
 	return status, nil
@@ -0,0 +1,79 @@
+package services
+
+import (
+	"testing"
+
+	"assessment-management-system/models"
+)
+
+// testRubric has a criterion worth up to 5 points and one worth up to 15
+func testRubric() *models.Rubric {
+	return &models.Rubric{
+		Criteria: []*models.RubricCriterion{
+			{ID: "structure", Title: "Structure", Levels: []*models.RubricLevel{
+				{ID: "structure-weak", Title: "Weak", Points: 0},
+				{ID: "structure-good", Title: "Good", Points: 5},
+			}},
+			{ID: "content", Title: "Content", Levels: []*models.RubricLevel{
+				{ID: "content-weak", Title: "Weak", Points: 0},
+				{ID: "content-fair", Title: "Fair", Points: 10},
+				{ID: "content-good", Title: "Good", Points: 15},
+			}},
+		},
+	}
+}
+
+func TestScoreRubric(t *testing.T) {
+	assessment := &models.Assessment{MaxScore: 100}
+
+	score, scores, err := scoreRubric(testRubric(), assessment, []models.RubricSelectionRequest{
+		{CriterionID: "structure", LevelID: "structure-good"},
+		{CriterionID: "content", LevelID: "content-fair", Comment: "Needs sources"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 15 of 20 rubric points, scaled to the assessment's 100
+	if score != 75 {
+		t.Errorf("score = %g, want 75", score)
+	}
+	if len(scores) != 2 || scores[1].Points != 10 || scores[1].MaxPoints != 15 || scores[1].Comment != "Needs sources" {
+		t.Errorf("unexpected criterion scores: %+v", scores)
+	}
+}
+
+func TestScoreRubricRejectsIncompleteSelections(t *testing.T) {
+	assessment := &models.Assessment{MaxScore: 100}
+
+	tests := []struct {
+		name       string
+		selections []models.RubricSelectionRequest
+	}{
+		{"missing criterion", []models.RubricSelectionRequest{
+			{CriterionID: "structure", LevelID: "structure-good"},
+		}},
+		{"level of another criterion", []models.RubricSelectionRequest{
+			{CriterionID: "structure", LevelID: "content-good"},
+			{CriterionID: "content", LevelID: "content-good"},
+		}},
+		{"criterion graded twice", []models.RubricSelectionRequest{
+			{CriterionID: "structure", LevelID: "structure-good"},
+			{CriterionID: "structure", LevelID: "structure-weak"},
+			{CriterionID: "content", LevelID: "content-good"},
+		}},
+		{"unknown criterion", []models.RubricSelectionRequest{
+			{CriterionID: "structure", LevelID: "structure-good"},
+			{CriterionID: "content", LevelID: "content-good"},
+			{CriterionID: "style", LevelID: "style-good"},
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, err := scoreRubric(testRubric(), assessment, tt.selections); err == nil {
+				t.Errorf("expected the selections to be rejected")
+			}
+		})
+	}
+}
@@ -0,0 +1,148 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"mime/multipart"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"assessment-management-system/models"
+)
+
+// maxAttachmentsPerSubmission caps the number of files handed in with one submission
+const maxAttachmentsPerSubmission = 10
+
+// normalizeFileTypes lower-cases file extensions and makes sure they start with a dot
+func normalizeFileTypes(fileTypes []string) []string {
+	if fileTypes == nil {
+		return nil
+	}
+
+	normalized := make([]string, 0, len(fileTypes))
+	for _, fileType := range fileTypes {
+		fileType = strings.ToLower(strings.TrimSpace(fileType))
+		if !strings.HasPrefix(fileType, ".") {
+			fileType = "." + fileType
+		}
+		normalized = append(normalized, fileType)
+	}
+	return normalized
+}
+
+// validateAttachments checks uploaded files against the assessment's size and file type limits
+func validateAttachments(assessment *models.Assessment, files []*multipart.FileHeader) error {
+	if len(files) > maxAttachmentsPerSubmission {
+		return fmt.Errorf("at most %d files can be attached to a submission", maxAttachmentsPerSubmission)
+	}
+
+	maxBytes := int64(assessment.MaxAttachmentSizeMB) * 1024 * 1024
+	for _, file := range files {
+		if file.Size > maxBytes {
+			return fmt.Errorf("%s exceeds the maximum file size of %d MB", file.Filename, assessment.MaxAttachmentSizeMB)
+		}
+
+		if len(assessment.AllowedFileTypes) == 0 {
+			continue
+		}
+
+		extension := strings.ToLower(filepath.Ext(file.Filename))
+		allowed := false
+		for _, fileType := range assessment.AllowedFileTypes {
+			if extension == fileType {
+				allowed = true
+				break
+			}
+		}
+
+		if !allowed {
+			return fmt.Errorf("%s is not an allowed file type, allowed types are %s", file.Filename, strings.Join(assessment.AllowedFileTypes, ", "))
+		}
+	}
+
+	return nil
+}
+
+// storeAttachments uploads the files of a submission to blob storage. If one upload fails,
+// the files stored so far are removed again.
+func (s *AssessmentService) storeAttachments(ctx context.Context, assessment *models.Assessment, studentID string, files []*multipart.FileHeader) ([]*models.Attachment, error) {
+	attachments := make([]*models.Attachment, 0, len(files))
+	for _, file := range files {
+		attachment, err := s.storeAttachment(ctx, assessment, studentID, file)
+		if err != nil {
+			s.removeAttachments(attachments)
+			return nil, err
+		}
+		attachments = append(attachments, attachment)
+	}
+	return attachments, nil
+}
+
+// storeAttachment uploads a single file under a unique key
+func (s *AssessmentService) storeAttachment(ctx context.Context, assessment *models.Assessment, studentID string, file *multipart.FileHeader) (*models.Attachment, error) {
+	suffix := make([]byte, 16)
+	if _, err := rand.Read(suffix); err != nil {
+		return nil, err
+	}
+
+	// Only the base name is kept, clients may send full paths
+	fileName := path.Base(strings.ReplaceAll(file.Filename, "\\", "/"))
+	contentType := file.Header.Get("Content-Type")
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+
+	attachment := &models.Attachment{
+		FileName:    fileName,
+		ContentType: contentType,
+		SizeBytes:   file.Size,
+		StorageKey:  path.Join("submissions", assessment.ID, studentID, hex.EncodeToString(suffix)+strings.ToLower(filepath.Ext(fileName))),
+	}
+
+	content, err := file.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer content.Close()
+
+	if err := s.storage.Put(ctx, attachment.StorageKey, content, file.Size, contentType); err != nil {
+		return nil, fmt.Errorf("failed to store %s: %w", fileName, err)
+	}
+
+	return attachment, nil
+}
+
+// removeAttachments deletes stored files that are no longer referenced. Failures are only logged,
+// the caller is already handling another error.
+func (s *AssessmentService) removeAttachments(attachments []*models.Attachment) {
+	for _, attachment := range attachments {
+		if err := s.storage.Delete(context.Background(), attachment.StorageKey); err != nil {
+			log.Printf("Warning: failed to remove attachment %s: %v", attachment.StorageKey, err)
+		}
+	}
+}
+
+// OpenAttachment opens the content of an attachment of a submission. The caller must close the returned reader.
+func (s *AssessmentService) OpenAttachment(ctx context.Context, submissionID, attachmentID string) (*models.Attachment, io.ReadCloser, error) {
+	attachment, err := s.assessmentRepo.FindAttachmentByID(ctx, attachmentID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if attachment == nil || attachment.SubmissionID != submissionID {
+		return nil, nil, errors.New("attachment not found")
+	}
+
+	content, err := s.storage.Get(ctx, attachment.StorageKey)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return attachment, content, nil
+}
@@ -0,0 +1,271 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"assessment-management-system/models"
+)
+
+// getAttempts loads every attempt of a student at an assessment, oldest first, with details and grades
+func (s *AssessmentService) getAttempts(ctx context.Context, assessmentID, studentID string) ([]*models.SubmissionAttempt, error) {
+	submissions, err := s.assessmentRepo.FindAttemptsByStudentAndAssessment(ctx, assessmentID, studentID)
+	if err != nil {
+		return nil, err
+	}
+
+	attempts := make([]*models.SubmissionAttempt, 0, len(submissions))
+	for _, submission := range submissions {
+		if err := s.loadSubmissionDetails(ctx, submission); err != nil {
+			return nil, err
+		}
+
+		grade, err := s.GetSubmissionGrade(ctx, submission.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := s.applyMemberAdjustment(ctx, submission, grade, studentID); err != nil {
+			return nil, err
+		}
+
+		attempts = append(attempts, &models.SubmissionAttempt{Submission: submission, Grade: grade})
+	}
+
+	return attempts, nil
+}
+
+// GetStudentAttempts retrieves every attempt of a student at an assessment, oldest first
+func (s *AssessmentService) GetStudentAttempts(ctx context.Context, assessmentID, studentID string) ([]*models.SubmissionAttempt, error) {
+	assessment, err := s.assessmentRepo.FindByID(ctx, assessmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	if assessment == nil {
+		return nil, errors.New("assessment not found")
+	}
+
+	return s.getAttempts(ctx, assessmentID, studentID)
+}
+
+// GetStudentGrade retrieves the grade that counts for a student under the assessment's attempt policy, from the
+// grades released to the student. It returns nil when none of the student's attempts has a released grade yet.
+func (s *AssessmentService) GetStudentGrade(ctx context.Context, assessment *models.Assessment, studentID string) (*models.StudentGrade, error) {
+	attempts, err := s.GetReleasedAttempts(ctx, assessment, studentID)
+	if err != nil {
+		return nil, err
+	}
+
+	final, counted := computeFinalGrade(assessment, attempts)
+	if final == nil {
+		return nil, nil
+	}
+
+	return &models.StudentGrade{
+		Grade:         counted.Grade,
+		AttemptNumber: counted.Submission.AttemptNumber,
+		Final:         final,
+	}, nil
+}
+
+// computeFinalGrade applies the assessment's attempt policy to the graded attempts. Besides the final grade
+// it returns the attempt that counts, which is the latest graded attempt when scores are averaged.
+func computeFinalGrade(assessment *models.Assessment, attempts []*models.SubmissionAttempt) (*models.FinalGrade, *models.SubmissionAttempt) {
+	var graded []*models.SubmissionAttempt
+	for _, attempt := range attempts {
+		if attempt.Grade != nil {
+			graded = append(graded, attempt)
+		}
+	}
+
+	if len(graded) == 0 {
+		return nil, nil
+	}
+
+	final := &models.FinalGrade{
+		Policy:         assessment.AttemptPolicy,
+		MaxScore:       assessment.MaxScore,
+		GradedAttempts: len(graded),
+	}
+
+	counted := graded[len(graded)-1]
+	switch assessment.AttemptPolicy {
+	case models.AttemptPolicyHighest:
+		for _, attempt := range graded {
+			if attempt.Grade.Score > counted.Grade.Score {
+				counted = attempt
+			}
+		}
+		final.Score = counted.Grade.Score
+		final.CountedAttempt = counted.Submission.AttemptNumber
+	case models.AttemptPolicyAverage:
+		var total float64
+		for _, attempt := range graded {
+			total += attempt.Grade.Score
+		}
+		final.Score = total / float64(len(graded))
+	default:
+		final.Score = counted.Grade.Score
+		final.CountedAttempt = counted.Submission.AttemptNumber
+	}
+
+	return final, counted
+}
+
+// DiffAttempts compares two attempts of a student at an assessment: the text content line by line,
+// the answers that changed and the files that were added or removed
+func (s *AssessmentService) DiffAttempts(ctx context.Context, assessmentID, studentID string, fromAttempt, toAttempt int) (*models.AttemptDiff, error) {
+	from, err := s.getAttempt(ctx, assessmentID, studentID, fromAttempt)
+	if err != nil {
+		return nil, err
+	}
+
+	to, err := s.getAttempt(ctx, assessmentID, studentID, toAttempt)
+	if err != nil {
+		return nil, err
+	}
+
+	diff := &models.AttemptDiff{
+		StudentID:          studentID,
+		FromAttempt:        fromAttempt,
+		ToAttempt:          toAttempt,
+		Content:            diffLines(from.Submission.Content, to.Submission.Content),
+		Answers:            []*models.AnswerChange{},
+		AttachmentsAdded:   []string{},
+		AttachmentsRemoved: []string{},
+	}
+
+	if from.Grade != nil {
+		diff.ScoreBefore = &from.Grade.Score
+	}
+	if to.Grade != nil {
+		diff.ScoreAfter = &to.Grade.Score
+	}
+
+	diff.Answers, err = diffAnswers(from.Submission.Answers, to.Submission.Answers)
+	if err != nil {
+		return nil, err
+	}
+
+	diff.AttachmentsAdded, diff.AttachmentsRemoved = diffAttachments(from.Submission.Attachments, to.Submission.Attachments)
+	return diff, nil
+}
+
+// getAttempt loads a single numbered attempt with its details and grade
+func (s *AssessmentService) getAttempt(ctx context.Context, assessmentID, studentID string, attemptNumber int) (*models.SubmissionAttempt, error) {
+	submission, err := s.assessmentRepo.FindAttempt(ctx, assessmentID, studentID, attemptNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	if submission == nil {
+		return nil, fmt.Errorf("attempt %d not found", attemptNumber)
+	}
+
+	if err := s.loadSubmissionDetails(ctx, submission); err != nil {
+		return nil, err
+	}
+
+	grade, err := s.GetSubmissionGrade(ctx, submission.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.applyMemberAdjustment(ctx, submission, grade, studentID); err != nil {
+		return nil, err
+	}
+
+	return &models.SubmissionAttempt{Submission: submission, Grade: grade}, nil
+}
+
+// diffAnswers lists the questions whose answer differs between two attempts, matched by question
+func diffAnswers(before, after []*models.SubmissionAnswer) ([]*models.AnswerChange, error) {
+	previous := make(map[string]*models.SubmissionAnswer, len(before))
+	for _, answer := range before {
+		previous[answer.QuestionID] = answer
+	}
+
+	changes := []*models.AnswerChange{}
+	for _, answer := range after {
+		change := &models.AnswerChange{
+			QuestionID:  answer.QuestionID,
+			Position:    answer.Position,
+			After:       answer.Answer,
+			PointsAfter: answer.PointsAwarded,
+		}
+
+		if old, ok := previous[answer.QuestionID]; ok {
+			delete(previous, answer.QuestionID)
+			same, err := sameAnswer(old.Answer, answer.Answer)
+			if err != nil {
+				return nil, err
+			}
+			if same {
+				continue
+			}
+			change.Before = old.Answer
+			change.PointsBefore = old.PointsAwarded
+		}
+
+		changes = append(changes, change)
+	}
+
+	// Questions only answered in the earlier attempt
+	for _, answer := range before {
+		if _, ok := previous[answer.QuestionID]; ok {
+			changes = append(changes, &models.AnswerChange{
+				QuestionID:   answer.QuestionID,
+				Position:     answer.Position,
+				Before:       answer.Answer,
+				PointsBefore: answer.PointsAwarded,
+			})
+		}
+	}
+
+	return changes, nil
+}
+
+// sameAnswer compares two answers by their JSON encoding
+func sameAnswer(a, b *models.QuestionAnswer) (bool, error) {
+	encodedA, err := json.Marshal(a)
+	if err != nil {
+		return false, err
+	}
+
+	encodedB, err := json.Marshal(b)
+	if err != nil {
+		return false, err
+	}
+
+	return string(encodedA) == string(encodedB), nil
+}
+
+// diffAttachments lists the file names only present in the later attempt and those only present in the earlier one
+func diffAttachments(before, after []*models.Attachment) (added, removed []string) {
+	count := make(map[string]int, len(before))
+	for _, attachment := range before {
+		count[attachment.FileName]++
+	}
+
+	added = []string{}
+	for _, attachment := range after {
+		if count[attachment.FileName] > 0 {
+			count[attachment.FileName]--
+			continue
+		}
+		added = append(added, attachment.FileName)
+	}
+
+	removed = []string{}
+	for _, attachment := range before {
+		if count[attachment.FileName] > 0 {
+			count[attachment.FileName]--
+			removed = append(removed, attachment.FileName)
+		}
+	}
+
+	return added, removed
+}
@@ -6,13 +6,23 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 )
 
+// ErrCourseArchived is returned for changes to a course whose term has ended
+var ErrCourseArchived = errors.New("the course's term has ended, the course is read-only")
+
 // CourseService handles course-related business logic
 type CourseService struct {
-	courseRepo *repositories.CourseRepository
-	userRepo   *repositories.UserRepository
-	orgRepo    *repositories.OrganizationRepository
+	courseRepo       *repositories.CourseRepository
+	userRepo         *repositories.UserRepository
+	orgRepo          *repositories.OrganizationRepository
+	termRepo         *repositories.TermRepository
+	sectionRepo      *repositories.SectionRepository
+	prerequisiteRepo *repositories.PrerequisiteRepository
+	joinCodeRepo     *repositories.JoinCodeRepository
+	// gradebookService computes the final grades prerequisites are checked against
+	gradebookService *GradebookService
 }
 
 // NewCourseService creates a new CourseService
@@ -20,14 +30,66 @@ func NewCourseService(
 	courseRepo *repositories.CourseRepository,
 	userRepo *repositories.UserRepository,
 	orgRepo *repositories.OrganizationRepository,
+	termRepo *repositories.TermRepository,
+	sectionRepo *repositories.SectionRepository,
+	prerequisiteRepo *repositories.PrerequisiteRepository,
+	joinCodeRepo *repositories.JoinCodeRepository,
+	gradebookService *GradebookService,
 ) *CourseService {
 	return &CourseService{
-		courseRepo: courseRepo,
-		userRepo:   userRepo,
-		orgRepo:    orgRepo,
+		courseRepo:       courseRepo,
+		userRepo:         userRepo,
+		orgRepo:          orgRepo,
+		termRepo:         termRepo,
+		sectionRepo:      sectionRepo,
+		prerequisiteRepo: prerequisiteRepo,
+		joinCodeRepo:     joinCodeRepo,
+		gradebookService: gradebookService,
 	}
 }
 
+// findWritableCourse retrieves a course that may be changed. Courses of past terms are read-only archives.
+func (s *CourseService) findWritableCourse(ctx context.Context, id string) (*models.Course, error) {
+	course, err := s.courseRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if course == nil {
+		return nil, errors.New("course not found")
+	}
+
+	if course.IsArchived(time.Now()) {
+		return nil, ErrCourseArchived
+	}
+
+	return course, nil
+}
+
+// CheckCourseWritable returns ErrCourseArchived when a course's term has ended
+func (s *CourseService) CheckCourseWritable(ctx context.Context, courseID string) error {
+	_, err := s.findWritableCourse(ctx, courseID)
+	return err
+}
+
+// findOpenTerm retrieves a term of an organization that courses can still be offered in
+func (s *CourseService) findOpenTerm(ctx context.Context, organizationID, termID string) (*models.Term, error) {
+	term, err := s.termRepo.FindByID(ctx, termID)
+	if err != nil {
+		return nil, err
+	}
+
+	if term == nil || term.OrganizationID != organizationID {
+		return nil, errors.New("term not found")
+	}
+
+	if term.HasEnded(time.Now()) {
+		return nil, errors.New("the term has ended")
+	}
+
+	return term, nil
+}
+
 // CreateCourse creates a new course
 func (s *CourseService) CreateCourse(ctx context.Context, organizationID string, req models.CreateCourseRequest) (*models.Course, error) {
 	// Validate organization
@@ -40,22 +102,33 @@ func (s *CourseService) CreateCourse(ctx context.Context, organizationID string,
 		return nil, errors.New("organization not found")
 	}
 
-	// Check if course name already exists in the organization
-	existingCourse, err := s.courseRepo.FindByNameAndOrganization(ctx, req.Name, organizationID)
+	// Courses are offered in a term that has not ended
+	if _, err := s.findOpenTerm(ctx, organizationID, req.TermID); err != nil {
+		return nil, err
+	}
+
+	// Check if course name already exists in the term
+	existingCourse, err := s.courseRepo.FindByNameAndTerm(ctx, req.Name, organizationID, &req.TermID)
 	if err != nil {
 		return nil, err
 	}
 
 	if existingCourse != nil {
-		return nil, errors.New("course name already exists in this organization")
+		return nil, errors.New("course name already exists in this term")
 	}
 
 	if req.EnrollmentOpen {
 		req.EnrollmentOpen = false
 	}
 
+	// A maximum of 0 students is the same as no limit
+	maxStudents := req.MaxStudents
+	if maxStudents != nil && *maxStudents == 0 {
+		maxStudents = nil
+	}
+
 	// Create course
-	course, err := s.courseRepo.Create(ctx, organizationID, req.Name, req.Description, req.EnrollmentOpen)
+	course, err := s.courseRepo.Create(ctx, organizationID, req.TermID, req.Name, req.Description, req.EnrollmentOpen, maxStudents)
 	if err != nil {
 		return nil, err
 	}
@@ -111,30 +184,48 @@ func (s *CourseService) GetCourseByID(ctx context.Context, id string) (*models.C
 
 // UpdateCourse updates a course
 func (s *CourseService) UpdateCourse(ctx context.Context, id string, req models.UpdateCourseRequest) (*models.Course, error) {
-	// Check if course exists
-	course, err := s.courseRepo.FindByID(ctx, id)
+	// Courses of past terms are read-only
+	course, err := s.findWritableCourse(ctx, id)
 	if err != nil {
 		return nil, err
 	}
 
-	if course == nil {
-		return nil, errors.New("course not found")
-	}
+	// Moving the course to another term keeps its assessment dates within the new term
+	termID := course.TermID
+	if req.TermID != nil && (course.TermID == nil || *req.TermID != *course.TermID) {
+		term, err := s.findOpenTerm(ctx, course.OrganizationID, *req.TermID)
+		if err != nil {
+			return nil, err
+		}
 
-	// Check if name is being changed and is already taken
-	if req.Name != nil && *req.Name != course.Name {
-		existingCourse, err := s.courseRepo.FindByNameAndOrganization(ctx, *req.Name, course.OrganizationID)
+		outside, err := s.termRepo.CountCourseAssessmentsOutside(ctx, id, term.StartDate, term.EndDate)
 		if err != nil {
 			return nil, err
 		}
 
-		if existingCourse != nil && existingCourse.ID != id {
-			return nil, errors.New("course name already exists in this organization")
+		if outside > 0 {
+			return nil, fmt.Errorf("%d assessments of the course have dates outside the term", outside)
 		}
+		termID = req.TermID
+	}
+
+	// Check if name or term is being changed and the name is already taken in the term
+	name := course.Name
+	if req.Name != nil {
+		name = *req.Name
+	}
+
+	existingCourse, err := s.courseRepo.FindByNameAndTerm(ctx, name, course.OrganizationID, termID)
+	if err != nil {
+		return nil, err
+	}
+
+	if existingCourse != nil && existingCourse.ID != id {
+		return nil, errors.New("course name already exists in this term")
 	}
 
 	// Update course
-	updatedCourse, err := s.courseRepo.Update(ctx, id, req.Name, req.Description, req.EnrollmentOpen)
+	updatedCourse, err := s.courseRepo.Update(ctx, id, req.Name, req.Description, req.EnrollmentOpen, req.MaxStudents, req.TermID)
 	if err != nil {
 		return nil, err
 	}
@@ -144,16 +235,11 @@ func (s *CourseService) UpdateCourse(ctx context.Context, id string, req models.
 
 // DeleteCourse deletes a course
 func (s *CourseService) DeleteCourse(ctx context.Context, id string) error {
-	// Check if course exists
-	course, err := s.courseRepo.FindByID(ctx, id)
-	if err != nil {
+	// Courses of past terms are read-only
+	if _, err := s.findWritableCourse(ctx, id); err != nil {
 		return err
 	}
 
-	if course == nil {
-		return errors.New("course not found")
-	}
-
 	// Check if students are enrolled
 	studentCount, err := s.courseRepo.CountStudentsByCourse(ctx, id)
 	if err != nil {
@@ -170,16 +256,12 @@ func (s *CourseService) DeleteCourse(ctx context.Context, id string) error {
 
 // AssignTeacherToCourse assigns a teacher to a course
 func (s *CourseService) AssignTeacherToCourse(ctx context.Context, courseID, teacherID string) error {
-	// Check if course exists
-	course, err := s.courseRepo.FindByID(ctx, courseID)
+	// Courses of past terms are read-only
+	course, err := s.findWritableCourse(ctx, courseID)
 	if err != nil {
 		return err
 	}
 
-	if course == nil {
-		return errors.New("course not found")
-	}
-
 	// Check if teacher exists and has role 'teacher'
 	teacher, err := s.userRepo.FindByID(ctx, teacherID)
 	if err != nil {
@@ -215,16 +297,11 @@ func (s *CourseService) AssignTeacherToCourse(ctx context.Context, courseID, tea
 
 // RemoveTeacherFromCourse removes a teacher from a course
 func (s *CourseService) RemoveTeacherFromCourse(ctx context.Context, courseID, teacherID string) error {
-	// Check if course exists
-	course, err := s.courseRepo.FindByID(ctx, courseID)
-	if err != nil {
+	// Courses of past terms are read-only
+	if _, err := s.findWritableCourse(ctx, courseID); err != nil {
 		return err
 	}
 
-	if course == nil {
-		return errors.New("course not found")
-	}
-
 	// Check if teacher is assigned to the course
 	isAssigned, err := s.courseRepo.IsTeacherAssigned(ctx, courseID, teacherID)
 	if err != nil {
@@ -267,16 +344,11 @@ func (s *CourseService) GetCourseTeachers(ctx context.Context, courseID string)
 
 // ToggleCourseEnrollment toggles a course's enrollment status
 func (s *CourseService) ToggleCourseEnrollment(ctx context.Context, courseID string, enrollmentOpen bool) error {
-	// Check if course exists
-	course, err := s.courseRepo.FindByID(ctx, courseID)
-	if err != nil {
+	// Courses of past terms are read-only
+	if _, err := s.findWritableCourse(ctx, courseID); err != nil {
 		return err
 	}
 
-	if course == nil {
-		return errors.New("course not found")
-	}
-
 	// If trying to open enrollment, ensure at least one teacher is assigned
 	if enrollmentOpen {
 		hasTeachers, err := s.CourseHasTeachers(ctx, courseID)
@@ -293,78 +365,106 @@ func (s *CourseService) ToggleCourseEnrollment(ctx context.Context, courseID str
 	return s.courseRepo.UpdateEnrollmentStatus(ctx, courseID, enrollmentOpen)
 }
 
-// EnrollStudentInCourse enrolls a student in a course
-func (s *CourseService) EnrollStudentInCourse(ctx context.Context, courseID, studentID string) error {
-	// Check if course exists
-	course, err := s.courseRepo.FindByID(ctx, courseID)
-	if err != nil {
-		return err
-	}
+// EnrollStudentInCourse enrolls a student in a course, into one of its sections when it has any. When the course is
+// full the student is put on its waitlist instead, and their waitlist entry is returned.
+func (s *CourseService) EnrollStudentInCourse(ctx context.Context, courseID, studentID string, sectionID *string) (*models.WaitlistEntry, error) {
+	return s.enrollStudent(ctx, courseID, studentID, sectionID, nil)
+}
 
-	if course == nil {
-		return errors.New("course not found")
+// enrollStudent enrolls a student in a course, or puts them on its waitlist when it is full. Students enrolling
+// with a join code may enroll while the course's enrollment is closed.
+func (s *CourseService) enrollStudent(ctx context.Context, courseID, studentID string, sectionID *string, joinCode *models.JoinCode) (*models.WaitlistEntry, error) {
+	// Courses of past terms are read-only
+	course, err := s.findWritableCourse(ctx, courseID)
+	if err != nil {
+		return nil, err
 	}
 
 	// Check if student exists and has role 'student'
 	student, err := s.userRepo.FindByID(ctx, studentID)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	if student == nil {
-		return errors.New("student not found")
+		return nil, errors.New("student not found")
 	}
 
 	if student.Role != models.RoleStudent {
-		return errors.New("user is not a student")
+		return nil, errors.New("user is not a student")
 	}
 
 	// Check if student is in the same organization as the course
 	if student.OrganizationID != course.OrganizationID {
-		return errors.New("student and course must be in the same organization")
+		return nil, errors.New("student and course must be in the same organization")
 	}
 
 	// Check if course is open for enrollment
-	if !course.EnrollmentOpen {
-		return errors.New("course is not open for enrollment")
+	if !course.EnrollmentOpen && joinCode == nil {
+		return nil, errors.New("course is not open for enrollment")
 	}
 
 	// Check if student is already enrolled
 	isEnrolled, err := s.courseRepo.IsStudentEnrolled(ctx, courseID, studentID)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	if isEnrolled {
-		return errors.New("student is already enrolled in this course")
+		return nil, errors.New("student is already enrolled in this course")
+	}
+
+	// Check if the student meets the course's prerequisites
+	locked, err := s.checkPrerequisites(ctx, course, studentID)
+	if err != nil {
+		return nil, err
+	}
+
+	if locked != "" {
+		return nil, errors.New("prerequisites not met: " + locked)
+	}
+
+	// Check if student is already waiting for a seat
+	waiting, err := s.courseRepo.FindWaitlistEntry(ctx, courseID, studentID)
+	if err != nil {
+		return nil, err
+	}
+
+	if waiting != nil {
+		return nil, errors.New("student is already on the waitlist of this course")
 	}
 
 	// Check if course has teachers
 	hasTeachers, err := s.CourseHasTeachers(ctx, courseID)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	if !hasTeachers {
-		return errors.New("cannot enroll in a course without teachers")
+		return nil, errors.New("cannot enroll in a course without teachers")
+	}
+
+	sectionID, err = s.checkEnrollmentSection(ctx, courseID, sectionID)
+	if err != nil {
+		return nil, err
+	}
+
+	var joinCodeID *string
+	if joinCode != nil {
+		joinCodeID = &joinCode.ID
 	}
 
 	// Enroll student
-	return s.courseRepo.EnrollStudent(ctx, courseID, studentID)
+	return s.courseRepo.EnrollStudent(ctx, courseID, studentID, sectionID, joinCodeID)
 }
 
 // UnenrollStudentFromCourse removes a student from a course
 func (s *CourseService) UnenrollStudentFromCourse(ctx context.Context, courseID, studentID string) error {
-	// Check if course exists
-	course, err := s.courseRepo.FindByID(ctx, courseID)
-	if err != nil {
+	// Courses of past terms are read-only
+	if _, err := s.findWritableCourse(ctx, courseID); err != nil {
 		return err
 	}
 
-	if course == nil {
-		return errors.New("course not found")
-	}
-
 	// Check if student is enrolled
 	isEnrolled, err := s.courseRepo.IsStudentEnrolled(ctx, courseID, studentID)
 	if err != nil {
@@ -379,6 +479,16 @@ func (s *CourseService) UnenrollStudentFromCourse(ctx context.Context, courseID,
 	return s.courseRepo.UnenrollStudent(ctx, courseID, studentID)
 }
 
+// LeaveWaitlist takes a student off the waitlist of a course
+func (s *CourseService) LeaveWaitlist(ctx context.Context, courseID, studentID string) error {
+	return s.courseRepo.RemoveFromWaitlist(ctx, courseID, studentID)
+}
+
+// GetWaitlist retrieves the students waiting for a seat in a course, in the order they will be enrolled
+func (s *CourseService) GetWaitlist(ctx context.Context, courseID string) ([]*models.WaitlistEntry, error) {
+	return s.courseRepo.FindWaitlist(ctx, courseID)
+}
+
 // GetCourseStudents retrieves all students enrolled in a course
 func (s *CourseService) GetCourseStudents(ctx context.Context, courseID string) ([]*models.User, error) {
 	// Check if course exists
@@ -395,18 +505,14 @@ func (s *CourseService) GetCourseStudents(ctx context.Context, courseID string)
 	return s.courseRepo.FindStudentsByCourse(ctx, courseID)
 }
 
-// BulkEnrollStudents enrolls multiple students in a course
-func (s *CourseService) BulkEnrollStudents(ctx context.Context, courseID string, studentIDs []string) (map[string]interface{}, error) {
-	// Check if course exists
-	course, err := s.courseRepo.FindByID(ctx, courseID)
+// BulkEnrollStudents enrolls multiple students in a course, into the same section when it has any
+func (s *CourseService) BulkEnrollStudents(ctx context.Context, courseID string, studentIDs []string, sectionID *string) (map[string]interface{}, error) {
+	// Courses of past terms are read-only
+	course, err := s.findWritableCourse(ctx, courseID)
 	if err != nil {
 		return nil, err
 	}
 
-	if course == nil {
-		return nil, errors.New("course not found")
-	}
-
 	// Check if course is open for enrollment
 	if !course.EnrollmentOpen {
 		return nil, errors.New("course is not open for enrollment")
@@ -422,18 +528,26 @@ func (s *CourseService) BulkEnrollStudents(ctx context.Context, courseID string,
 		return nil, errors.New("cannot enroll in a course without teachers")
 	}
 
+	sectionID, err = s.checkEnrollmentSection(ctx, courseID, sectionID)
+	if err != nil {
+		return nil, err
+	}
+
 	results := map[string]interface{}{
 		"successful": make([]string, 0),
+		"waitlisted": make([]string, 0),
 		"failed":     make([]map[string]string, 0),
 	}
 
 	for _, studentID := range studentIDs {
-		err := s.EnrollStudentInCourse(ctx, courseID, studentID)
+		waiting, err := s.EnrollStudentInCourse(ctx, courseID, studentID, sectionID)
 		if err != nil {
 			results["failed"] = append(results["failed"].([]map[string]string), map[string]string{
 				"student_id": studentID,
 				"error":      err.Error(),
 			})
+		} else if waiting != nil {
+			results["waitlisted"] = append(results["waitlisted"].([]string), studentID)
 		} else {
 			results["successful"] = append(results["successful"].([]string), studentID)
 		}
@@ -575,12 +689,42 @@ func (s *CourseService) GetAvailableCourses(ctx context.Context, organizationID,
 				return nil, err
 			}
 
+			// Students choose a section when the course has them
+			sections, err := s.GetCourseSections(ctx, course.ID)
+			if err != nil {
+				return nil, err
+			}
+
+			// Students waiting for a seat see their place in the queue
+			waiting, err := s.courseRepo.FindWaitlistEntry(ctx, course.ID, studentID)
+			if err != nil {
+				return nil, err
+			}
+
+			var waitlistPosition *int
+			if waiting != nil {
+				waitlistPosition = &waiting.Position
+			}
+
+			// Courses whose prerequisites the student does not meet are listed with the reason
+			if course.Prerequisites, err = s.prerequisiteRepo.FindByCourse(ctx, course.ID); err != nil {
+				return nil, err
+			}
+
+			lockedReason, err := s.checkPrerequisites(ctx, course, studentID)
+			if err != nil {
+				return nil, err
+			}
+
 			coursesWithDetails = append(coursesWithDetails, &models.CourseWithDetails{
 				Course:           course,
 				OrganizationID:   course.OrganizationID,
 				OrganizationName: org.Name,
 				Teachers:         teachers,
 				StudentCount:     studentCount,
+				Sections:         sections,
+				WaitlistPosition: waitlistPosition,
+				LockedReason:     lockedReason,
 			})
 		}
 	}
@@ -0,0 +1,99 @@
+package services
+
+import (
+	"strings"
+
+	"assessment-management-system/models"
+)
+
+// maxDiffCells bounds the table of common subsequence lengths, so that comparing two long texts that differ
+// throughout cannot exhaust the server's memory
+const maxDiffCells = 1 << 20
+
+// diffLines computes a line-by-line diff between two texts from their longest common subsequence of lines. Lines
+// the texts start and end with alike are matched up front; when what differs between them is still too long to
+// compare, it is shown as the old lines deleted and the new ones inserted.
+func diffLines(before, after string) []models.DiffLine {
+	a := splitLines(before)
+	b := splitLines(after)
+
+	lines := []models.DiffLine{}
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		lines = append(lines, models.DiffLine{Operation: models.DiffEqual, Text: a[prefix]})
+		prefix++
+	}
+
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	lines = append(lines, diffMiddle(a[prefix:len(a)-suffix], b[prefix:len(b)-suffix])...)
+	for _, text := range a[len(a)-suffix:] {
+		lines = append(lines, models.DiffLine{Operation: models.DiffEqual, Text: text})
+	}
+
+	return lines
+}
+
+// diffMiddle diffs the lines between the common start and end of two texts
+func diffMiddle(a, b []string) []models.DiffLine {
+	lines := []models.DiffLine{}
+	if (len(a)+1)*(len(b)+1) > maxDiffCells {
+		for _, text := range a {
+			lines = append(lines, models.DiffLine{Operation: models.DiffDelete, Text: text})
+		}
+		for _, text := range b {
+			lines = append(lines, models.DiffLine{Operation: models.DiffInsert, Text: text})
+		}
+		return lines
+	}
+
+	// lcs[i][j] is the length of the longest common subsequence of a[i:] and b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			lines = append(lines, models.DiffLine{Operation: models.DiffEqual, Text: a[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			lines = append(lines, models.DiffLine{Operation: models.DiffDelete, Text: a[i]})
+			i++
+		default:
+			lines = append(lines, models.DiffLine{Operation: models.DiffInsert, Text: b[j]})
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		lines = append(lines, models.DiffLine{Operation: models.DiffDelete, Text: a[i]})
+	}
+	for ; j < len(b); j++ {
+		lines = append(lines, models.DiffLine{Operation: models.DiffInsert, Text: b[j]})
+	}
+
+	return lines
+}
+
+// splitLines splits a text into lines, an empty text has no lines
+func splitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	return strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+}
@@ -0,0 +1,44 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"assessment-management-system/models"
+)
+
+func TestDiffLines(t *testing.T) {
+	lines := diffLines("Introduction\nThe answer is 4.\nConclusion", "Introduction\nThe answer is 5.\nConclusion")
+
+	want := []models.DiffLine{
+		{Operation: models.DiffEqual, Text: "Introduction"},
+		{Operation: models.DiffDelete, Text: "The answer is 4."},
+		{Operation: models.DiffInsert, Text: "The answer is 5."},
+		{Operation: models.DiffEqual, Text: "Conclusion"},
+	}
+	if fmt.Sprint(lines) != fmt.Sprint(want) {
+		t.Fatalf("diffLines() = %v, want %v", lines, want)
+	}
+}
+
+func TestDiffLinesReplacesTooLongDifferences(t *testing.T) {
+	before := make([]string, 2000)
+	after := make([]string, 2000)
+	for i := range before {
+		before[i] = fmt.Sprintf("old %d", i)
+		after[i] = fmt.Sprintf("new %d", i)
+	}
+
+	lines := diffLines("Title\n"+strings.Join(before, "\n")+"\nEnd", "Title\n"+strings.Join(after, "\n")+"\nEnd")
+
+	if len(lines) != 4002 {
+		t.Fatalf("expected 4002 lines, got %d", len(lines))
+	}
+	if lines[0].Operation != models.DiffEqual || lines[len(lines)-1].Operation != models.DiffEqual {
+		t.Errorf("the common first and last lines are not kept")
+	}
+	if lines[1] != (models.DiffLine{Operation: models.DiffDelete, Text: "old 0"}) || lines[2001] != (models.DiffLine{Operation: models.DiffInsert, Text: "new 0"}) {
+		t.Errorf("expected the old lines deleted before the new ones inserted, got %v and %v", lines[1], lines[2001])
+	}
+}
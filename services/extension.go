@@ -0,0 +1,83 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"assessment-management-system/models"
+	"assessment-management-system/repositories"
+)
+
+// ExtensionService handles business logic for per-student assessment extensions
+type ExtensionService struct {
+	extensionRepo  *repositories.ExtensionRepository
+	assessmentRepo *repositories.AssessmentRepository
+	courseRepo     *repositories.CourseRepository
+}
+
+// NewExtensionService creates a new ExtensionService
+func NewExtensionService(
+	extensionRepo *repositories.ExtensionRepository,
+	assessmentRepo *repositories.AssessmentRepository,
+	courseRepo *repositories.CourseRepository,
+) *ExtensionService {
+	return &ExtensionService{
+		extensionRepo:  extensionRepo,
+		assessmentRepo: assessmentRepo,
+		courseRepo:     courseRepo,
+	}
+}
+
+// GrantExtension grants a student an extension on an assessment, replacing any previous one
+func (s *ExtensionService) GrantExtension(ctx context.Context, assessmentID, studentID, grantedBy string, req models.GrantExtensionRequest) (*models.Extension, error) {
+	assessment, err := s.assessmentRepo.FindByID(ctx, assessmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	if assessment == nil {
+		return nil, errors.New("assessment not found")
+	}
+
+	isEnrolled, err := s.courseRepo.IsStudentEnrolled(ctx, assessment.CourseID, studentID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !isEnrolled {
+		return nil, errors.New("student is not enrolled in this course")
+	}
+
+	if req.DueDate == nil && req.CutoffDate == nil && req.TimeLimitMinutes == nil && req.MaxAttempts == nil {
+		return nil, errors.New("an extension must override the due date, cutoff date, time limit or attempt count")
+	}
+
+	extension := &models.Extension{
+		AssessmentID:     assessmentID,
+		StudentID:        studentID,
+		DueDate:          req.DueDate,
+		CutoffDate:       req.CutoffDate,
+		TimeLimitMinutes: req.TimeLimitMinutes,
+		MaxAttempts:      req.MaxAttempts,
+		Reason:           req.Reason,
+		GrantedBy:        grantedBy,
+	}
+
+	// The student's deadlines must stay consistent once the overrides are applied
+	extended := extension.Apply(assessment)
+	if err := validateLatePolicy(extended.DueDate, extended.CutoffDate, extended.LatePenaltyType, extended.LatePenaltyValue); err != nil {
+		return nil, err
+	}
+
+	return s.extensionRepo.Save(ctx, extension)
+}
+
+// GetExtensions retrieves all extensions granted for an assessment
+func (s *ExtensionService) GetExtensions(ctx context.Context, assessmentID string) ([]*models.Extension, error) {
+	return s.extensionRepo.FindByAssessment(ctx, assessmentID)
+}
+
+// RevokeExtension removes a student's extension on an assessment
+func (s *ExtensionService) RevokeExtension(ctx context.Context, assessmentID, studentID string) error {
+	return s.extensionRepo.Delete(ctx, assessmentID, studentID)
+}
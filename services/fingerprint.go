@@ -0,0 +1,154 @@
+package services
+
+import (
+	"hash/fnv"
+	"strings"
+	"unicode"
+
+	"assessment-management-system/models"
+)
+
+const (
+	// shingleSize is the number of words in the overlapping k-grams a text is cut into
+	shingleSize = 5
+	// winnowWindow is the number of consecutive k-grams one fingerprint is chosen from. Any copied run of
+	// shingleSize+winnowWindow-1 words shares at least one fingerprint.
+	winnowWindow = 4
+)
+
+// word is a normalized word of a text with its character offsets, the end exclusive
+type word struct {
+	text       string
+	start, end int
+}
+
+// fingerprint is the hash of a k-gram chosen by winnowing, with the character offsets of the k-gram
+type fingerprint struct {
+	hash       uint64
+	start, end int
+}
+
+// splitWords splits a text into lower-case runs of letters and digits, ignoring whitespace, punctuation and case
+// so that reformatting copied work doesn't hide it
+func splitWords(text string) []word {
+	words := []word{}
+	start := -1
+	runes := []rune(text)
+	for i, r := range runes {
+		isWord := unicode.IsLetter(r) || unicode.IsDigit(r)
+		switch {
+		case isWord && start < 0:
+			start = i
+		case !isWord && start >= 0:
+			words = append(words, word{text: strings.ToLower(string(runes[start:i])), start: start, end: i})
+			start = -1
+		}
+	}
+	if start >= 0 {
+		words = append(words, word{text: strings.ToLower(string(runes[start:])), start: start, end: len(runes)})
+	}
+	return words
+}
+
+// winnow fingerprints a text: it hashes every k-gram of words and keeps the smallest hash of every window of
+// consecutive k-grams, the rightmost one on ties, as in Schleimer et al.'s winnowing
+func winnow(text string) []fingerprint {
+	words := splitWords(text)
+	if len(words) == 0 {
+		return nil
+	}
+
+	size := shingleSize
+	if len(words) < size {
+		size = len(words)
+	}
+
+	shingles := make([]fingerprint, 0, len(words)-size+1)
+	for i := 0; i+size <= len(words); i++ {
+		h := fnv.New64a()
+		for _, w := range words[i : i+size] {
+			h.Write([]byte(w.text))
+			h.Write([]byte{0})
+		}
+		shingles = append(shingles, fingerprint{hash: h.Sum64(), start: words[i].start, end: words[i+size-1].end})
+	}
+
+	window := winnowWindow
+	if len(shingles) < window {
+		window = len(shingles)
+	}
+
+	fingerprints := []fingerprint{}
+	chosen := -1
+	for i := 0; i+window <= len(shingles); i++ {
+		smallest := i
+		for j := i + 1; j < i+window; j++ {
+			if shingles[j].hash <= shingles[smallest].hash {
+				smallest = j
+			}
+		}
+		if smallest != chosen {
+			fingerprints = append(fingerprints, shingles[smallest])
+			chosen = smallest
+		}
+	}
+	return fingerprints
+}
+
+// compareFingerprints measures how much of two fingerprinted texts match: the share of the distinct fingerprints of
+// the text with fewer of them that the other text has too, in percent. The matching k-grams are merged into the
+// regions the texts share.
+func compareFingerprints(a, b []fingerprint) (float64, []*models.SimilarityRegion) {
+	inB := map[uint64][]fingerprint{}
+	for _, fp := range b {
+		inB[fp.hash] = append(inB[fp.hash], fp)
+	}
+
+	distinctA := map[uint64]bool{}
+	shared := map[uint64]bool{}
+	for _, fp := range a {
+		distinctA[fp.hash] = true
+		if _, ok := inB[fp.hash]; ok {
+			shared[fp.hash] = true
+		}
+	}
+
+	smaller := len(distinctA)
+	if len(inB) < smaller {
+		smaller = len(inB)
+	}
+	if smaller == 0 || len(shared) == 0 {
+		return 0, []*models.SimilarityRegion{}
+	}
+
+	regions := []*models.SimilarityRegion{}
+	var current *models.SimilarityRegion
+	for _, fp := range a {
+		occurrences, ok := inB[fp.hash]
+		if !ok {
+			continue
+		}
+
+		// Prefer the occurrence that continues the region being built
+		other := occurrences[0]
+		if current != nil {
+			for _, occurrence := range occurrences {
+				if occurrence.start >= current.OtherStart && occurrence.start <= current.OtherEnd {
+					other = occurrence
+					break
+				}
+			}
+		}
+
+		if current != nil && fp.start <= current.End && other.start >= current.OtherStart && other.start <= current.OtherEnd {
+			current.End = max(current.End, fp.end)
+			current.OtherEnd = max(current.OtherEnd, other.end)
+			continue
+		}
+
+		current = &models.SimilarityRegion{Start: fp.start, End: fp.end, OtherStart: other.start, OtherEnd: other.end}
+		regions = append(regions, current)
+	}
+
+	return 100 * float64(len(shared)) / float64(smaller), regions
+}
@@ -0,0 +1,42 @@
+package services
+
+import (
+	"context"
+
+	"assessment-management-system/models"
+)
+
+// gradeChangeReason is the reason recorded in a grade's history, a default one when the grader gives none
+func gradeChangeReason(reason string, regrade bool) string {
+	switch {
+	case reason != "":
+		return reason
+	case regrade:
+		return "Grade updated"
+	default:
+		return "Graded"
+	}
+}
+
+// GetGradeHistory retrieves every version of a submission's grade, oldest first
+func (s *AssessmentService) GetGradeHistory(ctx context.Context, submissionID string) ([]*models.GradeVersion, error) {
+	versions, err := s.assessmentRepo.FindGradeVersions(ctx, submissionID)
+	if err != nil {
+		return nil, err
+	}
+
+	submission, err := s.assessmentRepo.FindSubmissionByID(ctx, submissionID)
+	if err != nil || submission == nil {
+		return versions, err
+	}
+
+	assessment, err := s.assessmentRepo.FindByID(ctx, submission.AssessmentID)
+	if err != nil || assessment == nil {
+		return versions, err
+	}
+
+	if err := s.maskGradeHistory(ctx, assessment, submission, versions); err != nil {
+		return nil, err
+	}
+	return versions, nil
+}
@@ -0,0 +1,281 @@
+package services
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"assessment-management-system/models"
+)
+
+// gradeSheetHeader names the columns of a grade sheet, in order
+var gradeSheetHeader = []string{"student_id", "name", "email", "submission_id", "raw_score", "feedback"}
+
+// gradeSheetEntry is a row of a grade sheet together with what it stands for
+type gradeSheetEntry struct {
+	row        *models.GradeSheetRow
+	submission *models.AssessmentSubmission
+	grade      *models.Grade
+}
+
+// buildGradeSheet builds a row for every student enrolled in the assessment's course that the assessment is for, in
+// the sections the teacher teaches if they teach particular sections, with their latest attempt and its grade.
+// Students hidden by anonymous grading are shown by pseudonym only, and their rows are ordered by pseudonym so that
+// the order gives nobody away.
+func (s *AssessmentService) buildGradeSheet(ctx context.Context, assessment *models.Assessment, teacherID string) ([]*gradeSheetEntry, error) {
+	students, err := s.studentsForAssessment(ctx, assessment, teacherID)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := []*gradeSheetEntry{}
+	for _, student := range students {
+		entry := &gradeSheetEntry{
+			row: &models.GradeSheetRow{
+				StudentID: student.ID,
+				Name:      student.FirstName + " " + student.LastName,
+				Email:     student.Email,
+			},
+		}
+
+		entry.submission, err = s.assessmentRepo.FindSubmissionByStudentAndAssessment(ctx, assessment.ID, student.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		masked := assessment.AnonymousGrading
+		if entry.submission != nil {
+			entry.row.SubmissionID = entry.submission.ID
+
+			entry.grade, err = s.assessmentRepo.FindGradeBySubmission(ctx, entry.submission.ID)
+			if err != nil {
+				return nil, err
+			}
+
+			if entry.grade != nil {
+				rawScore := entry.grade.RawScore
+				entry.row.RawScore = &rawScore
+				entry.row.Feedback = entry.grade.Feedback
+			}
+
+			if masked, err = s.isMasked(ctx, assessment, entry.submission); err != nil {
+				return nil, err
+			}
+		}
+
+		if masked {
+			entry.row.StudentID = assessment.Pseudonym(student.ID)
+			entry.row.Name, entry.row.Email = "", ""
+		}
+		entries = append(entries, entry)
+	}
+
+	if assessment.AnonymousGrading {
+		sort.SliceStable(entries, func(i, j int) bool {
+			return entries[i].row.StudentID < entries[j].row.StudentID
+		})
+	}
+
+	return entries, nil
+}
+
+// GetGradeSheet retrieves the grade sheet of an assessment as a teacher sees it: a row for every student enrolled in
+// its course that it is for
+func (s *AssessmentService) GetGradeSheet(ctx context.Context, assessment *models.Assessment, teacherID string) ([]*models.GradeSheetRow, error) {
+	entries, err := s.buildGradeSheet(ctx, assessment, teacherID)
+	if err != nil {
+		return nil, err
+	}
+
+	rows := make([]*models.GradeSheetRow, 0, len(entries))
+	for _, entry := range entries {
+		rows = append(rows, entry.row)
+	}
+	return rows, nil
+}
+
+// WriteGradeSheet writes the rows of a grade sheet as CSV, with a header
+func WriteGradeSheet(w io.Writer, rows []*models.GradeSheetRow) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(gradeSheetHeader); err != nil {
+		return err
+	}
+
+	for _, row := range rows {
+		rawScore := ""
+		if row.RawScore != nil {
+			rawScore = strconv.FormatFloat(*row.RawScore, 'f', -1, 64)
+		}
+
+		if err := writer.Write([]string{row.StudentID, row.Name, row.Email, row.SubmissionID, rawScore, row.Feedback}); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// ImportGrades applies an edited grade sheet of an assessment. Columns are found by their header; name and email
+// are ignored. Every row with a raw score must name a student on the teacher's grade sheet and their latest
+// attempt, and the score is validated like a grade given by hand. Rows without a raw score are skipped. The late penalty is
+// applied as when grading by hand. Rows with errors are reported and left out; all other changed grades are saved
+// together.
+func (s *AssessmentService) ImportGrades(ctx context.Context, assessment *models.Assessment, teacherID string, r io.Reader) (*models.GradeImportResult, error) {
+	if assessment.RubricID != nil {
+		return nil, errors.New("this assessment is graded with a rubric, grade its submissions per criterion")
+	}
+
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, errors.New("the grade sheet has no header row")
+	}
+
+	columns := map[string]int{}
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))] = i
+	}
+	for _, name := range []string{"student_id", "submission_id", "raw_score", "feedback"} {
+		if _, ok := columns[name]; !ok {
+			return nil, fmt.Errorf("the grade sheet has no %s column", name)
+		}
+	}
+
+	entries, err := s.buildGradeSheet(ctx, assessment, teacherID)
+	if err != nil {
+		return nil, err
+	}
+
+	byStudent := map[string]*gradeSheetEntry{}
+	for _, entry := range entries {
+		byStudent[entry.row.StudentID] = entry
+	}
+
+	result := &models.GradeImportResult{Errors: []*models.GradeImportError{}}
+	grades := []*models.Grade{}
+	imported := map[string]*models.Grade{}
+	importedOn := map[string]int{}
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			result.Errors = append(result.Errors, &models.GradeImportError{Row: parseErr.StartLine, Message: parseErr.Err.Error()})
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		line, _ := reader.FieldPos(0)
+		field := func(name string) string {
+			if i := columns[name]; i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+
+		ref := field("student_id")
+		fail := func(message string) {
+			result.Errors = append(result.Errors, &models.GradeImportError{Row: line, StudentID: ref, Message: message})
+		}
+
+		if field("raw_score") == "" {
+			result.Skipped++
+			continue
+		}
+
+		entry, ok := byStudent[ref]
+		if !ok {
+			fail("the student is not on this grade sheet")
+			continue
+		}
+
+		if entry.submission == nil {
+			fail("the student has not submitted this assessment")
+			continue
+		}
+
+		if field("submission_id") != entry.submission.ID {
+			fail("the submission is not the student's latest attempt at this assessment")
+			continue
+		}
+
+		score, err := strconv.ParseFloat(field("raw_score"), 64)
+		if err != nil || math.IsNaN(score) {
+			fail("raw_score must be a number")
+			continue
+		}
+
+		if _, _, err := s.scoreSubmission(ctx, assessment, models.GradeSubmissionRequest{Score: &score}); err != nil {
+			fail(err.Error())
+			continue
+		}
+
+		// Feedback is taken as written
+		feedback := ""
+		if i := columns["feedback"]; i < len(record) {
+			feedback = record[i]
+		}
+
+		// Group members share a submission, so its row may come up more than once
+		if previous, ok := imported[entry.submission.ID]; ok {
+			if previous.RawScore != score || previous.Feedback != feedback {
+				fail(fmt.Sprintf("the submission is graded differently on row %d", importedOn[entry.submission.ID]))
+			}
+			continue
+		}
+		imported[entry.submission.ID] = &models.Grade{RawScore: score, Feedback: feedback}
+		importedOn[entry.submission.ID] = line
+
+		if entry.grade != nil && entry.grade.RawScore == score && entry.grade.Feedback == feedback {
+			result.Unchanged++
+			continue
+		}
+
+		// Double-marked submissions are graded with the mark the moderator agrees on
+		moderation, err := s.moderationRepo.FindBySubmission(ctx, entry.submission.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := checkModeratedGrade(moderation); err != nil {
+			fail(err.Error())
+			continue
+		}
+
+		// The late penalty is measured against the student's own due date
+		studentAssessment, _, err := s.applyExtension(ctx, assessment, entry.submission.StudentID)
+		if err != nil {
+			return nil, err
+		}
+
+		grades = append(grades, &models.Grade{
+			SubmissionID: entry.submission.ID,
+			RawScore:     score,
+			LatePenalty:  calculateLatePenalty(studentAssessment, entry.submission.SubmittedAt, score),
+			Feedback:     feedback,
+		})
+	}
+
+	if len(grades) > 0 {
+		if err := s.assessmentRepo.SaveGrades(ctx, grades, teacherID, "Imported from a grade sheet"); err != nil {
+			return nil, err
+		}
+	}
+
+	result.Applied = len(grades)
+	return result, nil
+}
@@ -0,0 +1,495 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sort"
+
+	"assessment-management-system/models"
+	"assessment-management-system/repositories"
+)
+
+// GradebookService handles business logic for course gradebooks
+type GradebookService struct {
+	gradebookRepo  *repositories.GradebookRepository
+	assessmentRepo *repositories.AssessmentRepository
+	courseRepo     *repositories.CourseRepository
+	schemeRepo     *repositories.GradingSchemeRepository
+	sectionRepo    *repositories.SectionRepository
+}
+
+// NewGradebookService creates a new GradebookService
+func NewGradebookService(
+	gradebookRepo *repositories.GradebookRepository,
+	assessmentRepo *repositories.AssessmentRepository,
+	courseRepo *repositories.CourseRepository,
+	schemeRepo *repositories.GradingSchemeRepository,
+	sectionRepo *repositories.SectionRepository,
+) *GradebookService {
+	return &GradebookService{
+		gradebookRepo:  gradebookRepo,
+		assessmentRepo: assessmentRepo,
+		courseRepo:     courseRepo,
+		schemeRepo:     schemeRepo,
+		sectionRepo:    sectionRepo,
+	}
+}
+
+// checkTotalWeight checks that the weights of a course's categories do not add up to more than 100,
+// counting weight for the category being saved instead of its current weight
+func (s *GradebookService) checkTotalWeight(ctx context.Context, courseID, categoryID string, weight float64) error {
+	categories, err := s.gradebookRepo.FindCategoriesByCourse(ctx, courseID)
+	if err != nil {
+		return err
+	}
+
+	total := weight
+	for _, category := range categories {
+		if category.ID != categoryID {
+			total += category.Weight
+		}
+	}
+
+	if total > 100 {
+		return errors.New("the category weights of a course cannot add up to more than 100")
+	}
+	return nil
+}
+
+// CreateCategory adds a grade category to a course
+func (s *GradebookService) CreateCategory(ctx context.Context, courseID string, req models.CreateGradeCategoryRequest) (*models.GradeCategory, error) {
+	course, err := s.courseRepo.FindByID(ctx, courseID)
+	if err != nil {
+		return nil, err
+	}
+
+	if course == nil {
+		return nil, errors.New("course not found")
+	}
+
+	if err := s.checkTotalWeight(ctx, courseID, "", req.Weight); err != nil {
+		return nil, err
+	}
+
+	return s.gradebookRepo.CreateCategory(ctx, courseID, req)
+}
+
+// GetCategories retrieves the grade categories of a course
+func (s *GradebookService) GetCategories(ctx context.Context, courseID string) ([]*models.GradeCategory, error) {
+	return s.gradebookRepo.FindCategoriesByCourse(ctx, courseID)
+}
+
+// getCategory retrieves a grade category and checks that it belongs to the course
+func (s *GradebookService) getCategory(ctx context.Context, courseID, categoryID string) (*models.GradeCategory, error) {
+	category, err := s.gradebookRepo.FindCategoryByID(ctx, categoryID)
+	if err != nil {
+		return nil, err
+	}
+
+	if category == nil || category.CourseID != courseID {
+		return nil, errors.New("grade category not found")
+	}
+
+	return category, nil
+}
+
+// UpdateCategory updates the name, weight or drop count of a grade category
+func (s *GradebookService) UpdateCategory(ctx context.Context, courseID, categoryID string, req models.UpdateGradeCategoryRequest) (*models.GradeCategory, error) {
+	category, err := s.getCategory(ctx, courseID, categoryID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Name != nil {
+		category.Name = *req.Name
+	}
+	if req.Weight != nil {
+		if err := s.checkTotalWeight(ctx, courseID, categoryID, *req.Weight); err != nil {
+			return nil, err
+		}
+		category.Weight = *req.Weight
+	}
+	if req.DropLowest != nil {
+		category.DropLowest = *req.DropLowest
+	}
+
+	return s.gradebookRepo.UpdateCategory(ctx, category)
+}
+
+// DeleteCategory deletes a grade category, its assessments no longer count towards the course grade
+func (s *GradebookService) DeleteCategory(ctx context.Context, courseID, categoryID string) error {
+	if _, err := s.getCategory(ctx, courseID, categoryID); err != nil {
+		return err
+	}
+
+	return s.gradebookRepo.DeleteCategory(ctx, categoryID)
+}
+
+// gradedAssessments retrieves the assessments of a course that appear in its gradebook, those students can see
+func (s *GradebookService) gradedAssessments(ctx context.Context, courseID string) ([]*models.Assessment, error) {
+	assessments, err := s.assessmentRepo.FindByCourse(ctx, courseID)
+	if err != nil {
+		return nil, err
+	}
+
+	visible := make([]*models.Assessment, 0, len(assessments))
+	for _, assessment := range assessments {
+		if assessment.IsVisibleToStudents() {
+			visible = append(visible, assessment)
+		}
+	}
+	return visible, nil
+}
+
+// forSection keeps the assessments that are for the students of a section
+func forSection(assessments []*models.Assessment, sectionID *string) []*models.Assessment {
+	kept := make([]*models.Assessment, 0, len(assessments))
+	for _, assessment := range assessments {
+		if assessment.IsForSection(sectionID) {
+			kept = append(kept, assessment)
+		}
+	}
+	return kept
+}
+
+// GetGradebook builds the gradebook of a course with the running grade of every enrolled student, as a teacher
+// sees it: teachers of particular sections only see their own students. Each student's grade counts the
+// assessments for their section only.
+func (s *GradebookService) GetGradebook(ctx context.Context, courseID, teacherID string) (*models.Gradebook, error) {
+	categories, err := s.gradebookRepo.FindCategoriesByCourse(ctx, courseID)
+	if err != nil {
+		return nil, err
+	}
+
+	assessments, err := s.gradedAssessments(ctx, courseID)
+	if err != nil {
+		return nil, err
+	}
+
+	students, err := s.courseRepo.FindStudentsByCourse(ctx, courseID)
+	if err != nil {
+		return nil, err
+	}
+
+	teacherSections, err := s.sectionRepo.FindTeacherSectionIDs(ctx, courseID, teacherID)
+	if err != nil {
+		return nil, err
+	}
+
+	studentSections, err := s.sectionRepo.FindStudentSections(ctx, courseID)
+	if err != nil {
+		return nil, err
+	}
+
+	scores, err := s.gradebookRepo.FindFinalScores(ctx, courseID, "", false)
+	if err != nil {
+		return nil, err
+	}
+
+	// Anonymously graded assessments only show the grades already released, the others stay masked
+	released, err := s.gradebookRepo.FindFinalScores(ctx, courseID, "", true)
+	if err != nil {
+		return nil, err
+	}
+
+	scheme, err := s.schemeRepo.FindForCourse(ctx, courseID)
+	if err != nil {
+		return nil, err
+	}
+
+	anonymous := make(map[string]bool)
+	for _, assessment := range assessments {
+		if assessment.AnonymousGrading {
+			anonymous[assessment.ID] = true
+		}
+	}
+
+	// Index the scores by student, then by assessment. Scores of anonymously graded assessments are masked until
+	// they are released.
+	byStudent := make(map[string]map[string]float64)
+	masked := make(map[string]map[string]bool)
+	for _, score := range scores {
+		if anonymous[score.AssessmentID] {
+			if masked[score.StudentID] == nil {
+				masked[score.StudentID] = make(map[string]bool)
+			}
+			masked[score.StudentID][score.AssessmentID] = true
+			continue
+		}
+
+		if byStudent[score.StudentID] == nil {
+			byStudent[score.StudentID] = make(map[string]float64)
+		}
+		byStudent[score.StudentID][score.AssessmentID] = score.Score
+	}
+
+	for _, score := range released {
+		if !anonymous[score.AssessmentID] {
+			continue
+		}
+
+		if byStudent[score.StudentID] == nil {
+			byStudent[score.StudentID] = make(map[string]float64)
+		}
+		byStudent[score.StudentID][score.AssessmentID] = score.Score
+		delete(masked[score.StudentID], score.AssessmentID)
+	}
+
+	gradebook := &models.Gradebook{
+		CourseID:      courseID,
+		GradingScheme: scheme,
+		Categories:    categories,
+		Columns:       make([]*models.GradebookColumn, 0, len(assessments)),
+		Students:      make([]*models.StudentStanding, 0, len(students)),
+	}
+
+	for _, assessment := range assessments {
+		gradebook.Columns = append(gradebook.Columns, &models.GradebookColumn{
+			AssessmentID: assessment.ID,
+			Title:        assessment.Title,
+			CategoryID:   assessment.CategoryID,
+			MaxScore:     assessment.MaxScore,
+			ExtraCredit:  assessment.ExtraCredit,
+		})
+	}
+
+	for _, student := range students {
+		sectionID := studentSections[student.ID]
+		if len(teacherSections) > 0 && !inSections(sectionID, teacherSections) {
+			continue
+		}
+
+		standing := computeStanding(categories, forSection(assessments, sectionID), byStudent[student.ID])
+		for _, entry := range standing.Entries {
+			entry.Masked = masked[student.ID][entry.AssessmentID]
+		}
+		standing.StudentID = student.ID
+		standing.StudentName = student.FirstName + " " + student.LastName
+		applyScheme(scheme, standing)
+		gradebook.Students = append(gradebook.Students, standing)
+	}
+
+	return gradebook, nil
+}
+
+// GetStudentStanding computes a student's running grade in a course from the grades released to them
+func (s *GradebookService) GetStudentStanding(ctx context.Context, courseID, studentID string) (*models.StudentStanding, error) {
+	categories, err := s.gradebookRepo.FindCategoriesByCourse(ctx, courseID)
+	if err != nil {
+		return nil, err
+	}
+
+	assessments, err := s.gradedAssessments(ctx, courseID)
+	if err != nil {
+		return nil, err
+	}
+
+	scores, err := s.gradebookRepo.FindFinalScores(ctx, courseID, studentID, true)
+	if err != nil {
+		return nil, err
+	}
+
+	// Only the assessments for the student's section count
+	sectionID, err := s.sectionRepo.FindStudentSectionID(ctx, courseID, studentID)
+	if err != nil {
+		return nil, err
+	}
+	assessments = forSection(assessments, sectionID)
+
+	scheme, err := s.schemeRepo.FindForCourse(ctx, courseID)
+	if err != nil {
+		return nil, err
+	}
+
+	byAssessment := make(map[string]float64, len(scores))
+	for _, score := range scores {
+		byAssessment[score.AssessmentID] = score.Score
+	}
+
+	standing := computeStanding(categories, assessments, byAssessment)
+	standing.StudentID = studentID
+	applyScheme(scheme, standing)
+	return standing, nil
+}
+
+// GetTranscript lists a student's running grade in each of their courses, from the grades released to them
+func (s *GradebookService) GetTranscript(ctx context.Context, studentID string) (*models.Transcript, error) {
+	courses, err := s.courseRepo.FindByStudent(ctx, studentID)
+	if err != nil {
+		return nil, err
+	}
+
+	transcript := &models.Transcript{
+		StudentID: studentID,
+		Courses:   make([]*models.TranscriptCourse, 0, len(courses)),
+	}
+
+	var gpaTotal float64
+	var gpaCourses int
+	for _, course := range courses {
+		standing, err := s.GetStudentStanding(ctx, course.ID, studentID)
+		if err != nil {
+			return nil, err
+		}
+
+		transcript.Courses = append(transcript.Courses, &models.TranscriptCourse{
+			CourseID:    course.ID,
+			CourseName:  course.Name,
+			Percentage:  standing.Percentage,
+			LetterGrade: standing.LetterGrade,
+		})
+
+		if standing.LetterGrade != nil && standing.LetterGrade.GPAPoints != nil {
+			gpaTotal += *standing.LetterGrade.GPAPoints
+			gpaCourses++
+		}
+	}
+
+	if gpaCourses > 0 {
+		gpa := roundScore(gpaTotal / float64(gpaCourses))
+		transcript.GPA = &gpa
+	}
+
+	return transcript, nil
+}
+
+// applyScheme labels the percentages of a standing with the letter grades of the course's scheme, if it has one
+func applyScheme(scheme *models.GradingScheme, standing *models.StudentStanding) {
+	if scheme == nil {
+		return
+	}
+
+	for _, entry := range standing.Entries {
+		if entry.Percentage != nil {
+			if letter := scheme.Apply(*entry.Percentage); letter != nil {
+				entry.Letter = letter.Label
+			}
+		}
+	}
+
+	for _, category := range standing.Categories {
+		if category.Percentage != nil {
+			if letter := scheme.Apply(*category.Percentage); letter != nil {
+				category.Letter = letter.Label
+			}
+		}
+	}
+
+	if standing.Percentage != nil {
+		standing.LetterGrade = scheme.Apply(*standing.Percentage)
+	}
+}
+
+// computeStanding computes a student's running grade from their scores by assessment. Ungraded assessments do not
+// count. Without categories the grade is the share of points earned; with categories it is the weighted average of
+// the categories graded so far, and assessments outside every category do not count.
+func computeStanding(categories []*models.GradeCategory, assessments []*models.Assessment, scores map[string]float64) *models.StudentStanding {
+	standing := &models.StudentStanding{
+		Entries:    make([]*models.GradebookEntry, 0, len(assessments)),
+		Categories: make([]*models.CategoryStanding, 0, len(categories)),
+	}
+
+	entries := make(map[string]*models.GradebookEntry, len(assessments))
+	for _, assessment := range assessments {
+		entry := &models.GradebookEntry{AssessmentID: assessment.ID}
+		if score, ok := scores[assessment.ID]; ok {
+			entry.Score = &score
+			entry.Percentage = percentage(score, float64(assessment.MaxScore))
+		}
+		entries[assessment.ID] = entry
+		standing.Entries = append(standing.Entries, entry)
+	}
+
+	if len(categories) == 0 {
+		earned, possible := sumPoints(assessments, entries)
+		standing.Percentage = percentage(earned, possible)
+		return standing
+	}
+
+	var weighted, totalWeight float64
+	for _, category := range categories {
+		var members []*models.Assessment
+		for _, assessment := range assessments {
+			if assessment.CategoryID != nil && *assessment.CategoryID == category.ID {
+				members = append(members, assessment)
+			}
+		}
+
+		dropLowest(category.DropLowest, members, entries)
+		earned, possible := sumPoints(members, entries)
+
+		result := &models.CategoryStanding{
+			CategoryID: category.ID,
+			Name:       category.Name,
+			Weight:     category.Weight,
+			Earned:     earned,
+			Possible:   possible,
+			Percentage: percentage(earned, possible),
+		}
+		standing.Categories = append(standing.Categories, result)
+
+		if result.Percentage != nil {
+			weighted += category.Weight * *result.Percentage
+			totalWeight += category.Weight
+		}
+	}
+
+	if totalWeight > 0 {
+		grade := roundScore(weighted / totalWeight)
+		standing.Percentage = &grade
+	}
+
+	return standing
+}
+
+// dropLowest marks the n lowest graded scores of a category as dropped, by percentage. At least one score is
+// always kept, and extra credit is never dropped.
+func dropLowest(n int, members []*models.Assessment, entries map[string]*models.GradebookEntry) {
+	var graded []*models.GradebookEntry
+	for _, assessment := range members {
+		if entry := entries[assessment.ID]; entry.Percentage != nil && !assessment.ExtraCredit {
+			graded = append(graded, entry)
+		}
+	}
+
+	sort.SliceStable(graded, func(i, j int) bool {
+		return *graded[i].Percentage < *graded[j].Percentage
+	})
+
+	for i := 0; i < n && i < len(graded)-1; i++ {
+		graded[i].Dropped = true
+	}
+}
+
+// sumPoints adds up the points earned and possible over the graded assessments that were not dropped.
+// Extra credit adds to the points earned only.
+func sumPoints(assessments []*models.Assessment, entries map[string]*models.GradebookEntry) (earned, possible float64) {
+	for _, assessment := range assessments {
+		entry := entries[assessment.ID]
+		if entry.Score == nil || entry.Dropped {
+			continue
+		}
+
+		earned += *entry.Score
+		if !assessment.ExtraCredit {
+			possible += float64(assessment.MaxScore)
+		}
+	}
+	return earned, possible
+}
+
+// percentage returns earned as a percentage of possible, or nil when nothing was possible
+func percentage(earned, possible float64) *float64 {
+	if possible <= 0 {
+		return nil
+	}
+
+	result := roundScore(earned / possible * 100)
+	return &result
+}
+
+// roundScore rounds a score or percentage to two decimals
+func roundScore(value float64) float64 {
+	return math.Round(value*100) / 100
+}
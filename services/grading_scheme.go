@@ -0,0 +1,110 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"sort"
+
+	"assessment-management-system/models"
+	"assessment-management-system/repositories"
+)
+
+// GradingSchemeService handles business logic for letter-grade schemes
+type GradingSchemeService struct {
+	schemeRepo *repositories.GradingSchemeRepository
+	orgRepo    *repositories.OrganizationRepository
+	courseRepo *repositories.CourseRepository
+}
+
+// NewGradingSchemeService creates a new GradingSchemeService
+func NewGradingSchemeService(
+	schemeRepo *repositories.GradingSchemeRepository,
+	orgRepo *repositories.OrganizationRepository,
+	courseRepo *repositories.CourseRepository,
+) *GradingSchemeService {
+	return &GradingSchemeService{
+		schemeRepo: schemeRepo,
+		orgRepo:    orgRepo,
+		courseRepo: courseRepo,
+	}
+}
+
+// normalizeBands orders the bands of a scheme from the highest minimum percentage down and checks that
+// every percentage falls in exactly one band
+func normalizeBands(bands []models.GradeBand) ([]models.GradeBand, error) {
+	sorted := append([]models.GradeBand(nil), bands...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].MinPercentage > sorted[j].MinPercentage
+	})
+
+	for i := 1; i < len(sorted); i++ {
+		if sorted[i].MinPercentage == sorted[i-1].MinPercentage {
+			return nil, errors.New("two bands cannot start at the same percentage")
+		}
+	}
+
+	if sorted[len(sorted)-1].MinPercentage != 0 {
+		return nil, errors.New("the lowest band must start at 0 percent")
+	}
+
+	return sorted, nil
+}
+
+// SaveOrganizationScheme defines the default grading scheme of an organization's courses
+func (s *GradingSchemeService) SaveOrganizationScheme(ctx context.Context, organizationID string, req models.SaveGradingSchemeRequest) (*models.GradingScheme, error) {
+	organization, err := s.orgRepo.FindByID(ctx, organizationID)
+	if err != nil {
+		return nil, err
+	}
+
+	if organization == nil {
+		return nil, errors.New("organization not found")
+	}
+
+	bands, err := normalizeBands(req.Bands)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.schemeRepo.SaveForOrganization(ctx, organizationID, req.Name, bands)
+}
+
+// GetOrganizationScheme retrieves the grading scheme of an organization, nil when it has none
+func (s *GradingSchemeService) GetOrganizationScheme(ctx context.Context, organizationID string) (*models.GradingScheme, error) {
+	return s.schemeRepo.FindByOrganization(ctx, organizationID)
+}
+
+// DeleteOrganizationScheme deletes the grading scheme of an organization
+func (s *GradingSchemeService) DeleteOrganizationScheme(ctx context.Context, organizationID string) error {
+	return s.schemeRepo.DeleteForOrganization(ctx, organizationID)
+}
+
+// SaveCourseScheme overrides the organization's grading scheme for a course
+func (s *GradingSchemeService) SaveCourseScheme(ctx context.Context, courseID string, req models.SaveGradingSchemeRequest) (*models.GradingScheme, error) {
+	course, err := s.courseRepo.FindByID(ctx, courseID)
+	if err != nil {
+		return nil, err
+	}
+
+	if course == nil {
+		return nil, errors.New("course not found")
+	}
+
+	bands, err := normalizeBands(req.Bands)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.schemeRepo.SaveForCourse(ctx, courseID, req.Name, bands)
+}
+
+// GetCourseScheme retrieves the grading scheme that applies to a course, nil when neither the course
+// nor its organization has one
+func (s *GradingSchemeService) GetCourseScheme(ctx context.Context, courseID string) (*models.GradingScheme, error) {
+	return s.schemeRepo.FindForCourse(ctx, courseID)
+}
+
+// DeleteCourseScheme deletes the override of a course, which then follows its organization's scheme again
+func (s *GradingSchemeService) DeleteCourseScheme(ctx context.Context, courseID string) error {
+	return s.schemeRepo.DeleteForCourse(ctx, courseID)
+}
@@ -0,0 +1,106 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"assessment-management-system/models"
+	"assessment-management-system/repositories"
+)
+
+// GroupService handles business logic for course groups
+type GroupService struct {
+	groupRepo  *repositories.GroupRepository
+	courseRepo *repositories.CourseRepository
+}
+
+// NewGroupService creates a new GroupService
+func NewGroupService(
+	groupRepo *repositories.GroupRepository,
+	courseRepo *repositories.CourseRepository,
+) *GroupService {
+	return &GroupService{
+		groupRepo:  groupRepo,
+		courseRepo: courseRepo,
+	}
+}
+
+// CreateGroup creates a group in a course
+func (s *GroupService) CreateGroup(ctx context.Context, courseID, teacherID string, req models.CreateGroupRequest) (*models.CourseGroup, error) {
+	course, err := s.courseRepo.FindByID(ctx, courseID)
+	if err != nil {
+		return nil, err
+	}
+
+	if course == nil {
+		return nil, errors.New("course not found")
+	}
+
+	return s.groupRepo.Create(ctx, courseID, teacherID, req)
+}
+
+// GetCourseGroups retrieves the groups of a course with their members
+func (s *GroupService) GetCourseGroups(ctx context.Context, courseID string) ([]*models.CourseGroup, error) {
+	return s.groupRepo.FindByCourse(ctx, courseID)
+}
+
+// GetGroup retrieves a group by ID with its members
+func (s *GroupService) GetGroup(ctx context.Context, id string) (*models.CourseGroup, error) {
+	return s.groupRepo.FindByID(ctx, id)
+}
+
+// GetStudentGroup retrieves the group a student belongs to in a course, if any
+func (s *GroupService) GetStudentGroup(ctx context.Context, courseID, studentID string) (*models.CourseGroup, error) {
+	return s.groupRepo.FindByStudent(ctx, courseID, studentID)
+}
+
+// UpdateGroup updates a group. The size limit cannot drop below the current number of members.
+func (s *GroupService) UpdateGroup(ctx context.Context, group *models.CourseGroup, req models.UpdateGroupRequest) (*models.CourseGroup, error) {
+	if req.MaxSize != nil && *req.MaxSize < len(group.Members) {
+		return nil, errors.New("the group already has more members than the new size limit")
+	}
+
+	return s.groupRepo.Update(ctx, group.ID, req)
+}
+
+// DeleteGroup deletes a group. Grades of the submissions it made stay with the members who made them.
+func (s *GroupService) DeleteGroup(ctx context.Context, id string) error {
+	return s.groupRepo.Delete(ctx, id)
+}
+
+// AddMember places a student enrolled in the group's course in the group
+func (s *GroupService) AddMember(ctx context.Context, group *models.CourseGroup, studentID string) (*models.CourseGroup, error) {
+	isEnrolled, err := s.courseRepo.IsStudentEnrolled(ctx, group.CourseID, studentID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !isEnrolled {
+		return nil, errors.New("student is not enrolled in this course")
+	}
+
+	return s.groupRepo.AddMember(ctx, group.ID, studentID)
+}
+
+// RemoveMember takes a student out of a group. Submissions the group already made still count for them.
+func (s *GroupService) RemoveMember(ctx context.Context, group *models.CourseGroup, studentID string) error {
+	return s.groupRepo.RemoveMember(ctx, group.ID, studentID)
+}
+
+// JoinGroup lets a student join a self-signup group of their course that has room
+func (s *GroupService) JoinGroup(ctx context.Context, group *models.CourseGroup, studentID string) (*models.CourseGroup, error) {
+	if !group.SelfSignup {
+		return nil, errors.New("students cannot join this group themselves")
+	}
+
+	return s.AddMember(ctx, group, studentID)
+}
+
+// LeaveGroup lets a student leave a self-signup group
+func (s *GroupService) LeaveGroup(ctx context.Context, group *models.CourseGroup, studentID string) error {
+	if !group.SelfSignup {
+		return errors.New("students cannot leave this group themselves, ask your teacher")
+	}
+
+	return s.groupRepo.RemoveMember(ctx, group.ID, studentID)
+}
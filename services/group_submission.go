@@ -0,0 +1,167 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"assessment-management-system/models"
+)
+
+// findSubmittingGroup returns the group a student submits a group assessment with, if any, and the number of
+// attempts the group has made
+func (s *AssessmentService) findSubmittingGroup(ctx context.Context, assessment *models.Assessment, studentID string) (*models.CourseGroup, int, error) {
+	group, err := s.groupRepo.FindByStudent(ctx, assessment.CourseID, studentID)
+	if err != nil || group == nil {
+		return nil, 0, err
+	}
+
+	attempts, err := s.groupRepo.CountAttempts(ctx, assessment.ID, group.ID)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return group, attempts, nil
+}
+
+// applyMemberAdjustment gives a member of a group submission the score a teacher adjusted for them, keeping the
+// group's score alongside
+func (s *AssessmentService) applyMemberAdjustment(ctx context.Context, submission *models.AssessmentSubmission, grade *models.Grade, studentID string) error {
+	if grade == nil || submission.GroupID == nil {
+		return nil
+	}
+
+	member, err := s.groupRepo.FindSubmissionMember(ctx, submission.ID, studentID)
+	if err != nil || member == nil || member.AdjustedScore == nil {
+		return err
+	}
+
+	groupScore := grade.Score
+	grade.GroupScore = &groupScore
+	grade.Score = *member.AdjustedScore
+	return nil
+}
+
+// GetSubmissionMembers retrieves the members of the group that made a submission, with any adjusted scores. While
+// the assessment is graded anonymously and the grade is not released, members go by their pseudonym.
+func (s *AssessmentService) GetSubmissionMembers(ctx context.Context, submission *models.AssessmentSubmission) ([]*models.SubmissionMember, error) {
+	assessment, err := s.findSubmissionAssessment(ctx, submission)
+	if err != nil {
+		return nil, err
+	}
+
+	members, err := s.groupRepo.FindSubmissionMembers(ctx, submission.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	masked, err := s.isMasked(ctx, assessment, submission)
+	if err != nil || !masked {
+		return members, err
+	}
+
+	for _, member := range members {
+		maskMember(assessment, member)
+	}
+	return members, nil
+}
+
+// findSubmissionAssessment retrieves the assessment of a submission
+func (s *AssessmentService) findSubmissionAssessment(ctx context.Context, submission *models.AssessmentSubmission) (*models.Assessment, error) {
+	assessment, err := s.assessmentRepo.FindByID(ctx, submission.AssessmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	if assessment == nil {
+		return nil, errors.New("assessment not found")
+	}
+
+	return assessment, nil
+}
+
+// maskMember replaces a member of a group submission with their pseudonym
+func maskMember(assessment *models.Assessment, member *models.SubmissionMember) {
+	member.Pseudonym = assessment.Pseudonym(member.StudentID)
+	member.StudentID = ""
+}
+
+// resolveMember finds the student a teacher names for a member of a group submission. While members go by their
+// pseudonym, teachers must name them by it; the member is reported as masked so that it can be masked again.
+func (s *AssessmentService) resolveMember(ctx context.Context, assessment *models.Assessment, submission *models.AssessmentSubmission, name string) (string, bool, error) {
+	masked, err := s.isMasked(ctx, assessment, submission)
+	if err != nil || !masked {
+		return name, false, err
+	}
+
+	members, err := s.groupRepo.FindSubmissionMembers(ctx, submission.ID)
+	if err != nil {
+		return "", false, err
+	}
+
+	for _, member := range members {
+		if assessment.Pseudonym(member.StudentID) == name {
+			return member.StudentID, true, nil
+		}
+	}
+	return "", true, errors.New("this assessment is graded anonymously, refer to the student by pseudonym")
+}
+
+// AdjustMemberGrade overrides the grade of a graded group submission for one member of the group. The score
+// replaces the member's final score, late penalty included.
+func (s *AssessmentService) AdjustMemberGrade(ctx context.Context, submission *models.AssessmentSubmission, studentID, teacherID string, req models.AdjustMemberGradeRequest) (*models.SubmissionMember, error) {
+	assessment, err := s.findSubmissionAssessment(ctx, submission)
+	if err != nil {
+		return nil, err
+	}
+
+	if *req.Score > float64(assessment.MaxScore) {
+		return nil, errors.New("score must be between 0 and the maximum score")
+	}
+
+	grade, err := s.assessmentRepo.FindGradeBySubmission(ctx, submission.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if grade == nil {
+		return nil, errors.New("grade the group submission before adjusting it for a member")
+	}
+
+	studentID, masked, err := s.resolveMember(ctx, assessment, submission, studentID)
+	if err != nil {
+		return nil, err
+	}
+
+	member, err := s.groupRepo.AdjustMemberScore(ctx, submission.ID, studentID, req.Score, req.Reason, teacherID)
+	if err != nil {
+		return nil, err
+	}
+
+	if masked {
+		maskMember(assessment, member)
+	}
+	return member, nil
+}
+
+// ClearMemberAdjustment gives a member of a group submission the group's grade again
+func (s *AssessmentService) ClearMemberAdjustment(ctx context.Context, submission *models.AssessmentSubmission, studentID, teacherID string) (*models.SubmissionMember, error) {
+	assessment, err := s.findSubmissionAssessment(ctx, submission)
+	if err != nil {
+		return nil, err
+	}
+
+	studentID, masked, err := s.resolveMember(ctx, assessment, submission, studentID)
+	if err != nil {
+		return nil, err
+	}
+
+	member, err := s.groupRepo.AdjustMemberScore(ctx, submission.ID, studentID, nil, "Group grade restored for the member", teacherID)
+	if err != nil {
+		return nil, err
+	}
+
+	if masked {
+		maskMember(assessment, member)
+	}
+	return member, nil
+}
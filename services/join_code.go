@@ -0,0 +1,107 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"assessment-management-system/models"
+	"assessment-management-system/utils"
+)
+
+// CreateJoinCode creates a join code for a course. Teachers of particular sections can only create codes for their
+// own sections.
+func (s *CourseService) CreateJoinCode(ctx context.Context, courseID, teacherID string, req models.CreateJoinCodeRequest) (*models.JoinCode, error) {
+	// Courses of past terms are read-only
+	if _, err := s.findWritableCourse(ctx, courseID); err != nil {
+		return nil, err
+	}
+
+	if !req.ExpiresAt.After(time.Now()) {
+		return nil, errors.New("expiry time must be in the future")
+	}
+
+	if req.SectionID != nil && *req.SectionID == "" {
+		req.SectionID = nil
+	}
+
+	if req.SectionID != nil {
+		if _, err := s.findSection(ctx, courseID, *req.SectionID); err != nil {
+			return nil, err
+		}
+	}
+
+	teacherSections, err := s.sectionRepo.FindTeacherSectionIDs(ctx, courseID, teacherID)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(teacherSections) > 0 && !inSections(req.SectionID, teacherSections) {
+		return nil, errors.New("you can only create join codes for the sections you teach")
+	}
+
+	// Codes are short, so make sure a new one is not already taken
+	for attempt := 0; attempt < 5; attempt++ {
+		code, err := utils.GenerateJoinCode()
+		if err != nil {
+			return nil, err
+		}
+
+		existing, err := s.joinCodeRepo.FindByCode(ctx, code)
+		if err != nil {
+			return nil, err
+		}
+
+		if existing == nil {
+			return s.joinCodeRepo.Create(ctx, courseID, code, teacherID, req)
+		}
+	}
+
+	return nil, errors.New("failed to generate a unique join code, try again")
+}
+
+// GetJoinCodes retrieves the join codes of a course, newest first
+func (s *CourseService) GetJoinCodes(ctx context.Context, courseID string) ([]*models.JoinCode, error) {
+	return s.joinCodeRepo.FindByCourse(ctx, courseID)
+}
+
+// GetJoinCode retrieves a join code by ID
+func (s *CourseService) GetJoinCode(ctx context.Context, id string) (*models.JoinCode, error) {
+	return s.joinCodeRepo.FindByID(ctx, id)
+}
+
+// FindJoinCode retrieves a join code by the code a student typed, ignoring case and surrounding spaces
+func (s *CourseService) FindJoinCode(ctx context.Context, code string) (*models.JoinCode, error) {
+	return s.joinCodeRepo.FindByCode(ctx, strings.ToUpper(strings.TrimSpace(code)))
+}
+
+// RevokeJoinCode stops a join code and its invite links from being used. Students who enrolled with it stay enrolled.
+func (s *CourseService) RevokeJoinCode(ctx context.Context, code *models.JoinCode) error {
+	// Courses of past terms are read-only
+	if _, err := s.findWritableCourse(ctx, code.CourseID); err != nil {
+		return err
+	}
+
+	return s.joinCodeRepo.Revoke(ctx, code.ID)
+}
+
+// GetJoinCodeStudents retrieves the students enrolled in a course with a join code
+func (s *CourseService) GetJoinCodeStudents(ctx context.Context, id string) ([]*models.User, error) {
+	return s.joinCodeRepo.FindStudents(ctx, id)
+}
+
+// JoinCourse enrolls a student in the course of a join code, even when the course's enrollment is closed. Codes
+// that name a section enroll into it. As with ordinary enrollment, the student must meet the course's prerequisites
+// and waits for a seat when the course is full.
+func (s *CourseService) JoinCourse(ctx context.Context, studentID string, code *models.JoinCode, sectionID *string) (*models.WaitlistEntry, error) {
+	if reason := code.UnusableReason(time.Now()); reason != "" {
+		return nil, errors.New(reason)
+	}
+
+	if code.SectionID != nil {
+		sectionID = code.SectionID
+	}
+
+	return s.enrollStudent(ctx, code.CourseID, studentID, sectionID, code)
+}
@@ -0,0 +1,46 @@
+package services
+
+import (
+	"errors"
+	"math"
+	"time"
+
+	"assessment-management-system/models"
+)
+
+// validateLatePolicy checks that a late policy is consistent with the assessment's due date
+func validateLatePolicy(dueDate, cutoffDate *time.Time, penaltyType models.LatePenaltyType, penaltyValue float64) error {
+	if cutoffDate != nil {
+		if dueDate == nil {
+			return errors.New("a cutoff date requires a due date")
+		}
+		if cutoffDate.Before(*dueDate) {
+			return errors.New("cutoff date must not be before the due date")
+		}
+	}
+
+	if penaltyType == models.LatePenaltyPercentPerDay && penaltyValue > 100 {
+		return errors.New("a daily late penalty cannot exceed 100 percent")
+	}
+
+	return nil
+}
+
+// calculateLatePenalty returns the points deducted from a raw score for a submission made at submittedAt.
+// Every started day past the due date counts as a full day, and the penalty never exceeds the raw score.
+func calculateLatePenalty(assessment *models.Assessment, submittedAt time.Time, rawScore float64) float64 {
+	if assessment.DueDate == nil || !submittedAt.After(*assessment.DueDate) {
+		return 0
+	}
+
+	var penalty float64
+	switch assessment.LatePenaltyType {
+	case models.LatePenaltyPercentPerDay:
+		daysLate := math.Ceil(submittedAt.Sub(*assessment.DueDate).Hours() / 24)
+		penalty = float64(assessment.MaxScore) * assessment.LatePenaltyValue / 100 * daysLate
+	case models.LatePenaltyFlat:
+		penalty = assessment.LatePenaltyValue
+	}
+
+	return math.Round(math.Min(penalty, rawScore)*100) / 100
+}
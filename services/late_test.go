@@ -0,0 +1,37 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"assessment-management-system/models"
+)
+
+func TestCalculateLatePenalty(t *testing.T) {
+	due := time.Date(2025, 5, 15, 23, 59, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		penaltyType models.LatePenaltyType
+		value       float64
+		submittedAt time.Time
+		rawScore    float64
+		want        float64
+	}{
+		{"on time", models.LatePenaltyPercentPerDay, 10, due, 80, 0},
+		{"no penalty", models.LatePenaltyNone, 0, due.Add(48 * time.Hour), 80, 0},
+		{"one started day", models.LatePenaltyPercentPerDay, 10, due.Add(time.Minute), 80, 10},
+		{"two started days", models.LatePenaltyPercentPerDay, 10, due.Add(25 * time.Hour), 80, 20},
+		{"flat", models.LatePenaltyFlat, 7.5, due.Add(72 * time.Hour), 80, 7.5},
+		{"never more than the raw score", models.LatePenaltyPercentPerDay, 50, due.Add(72 * time.Hour), 60, 60},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assessment := &models.Assessment{MaxScore: 100, DueDate: &due, LatePenaltyType: tt.penaltyType, LatePenaltyValue: tt.value}
+			if got := calculateLatePenalty(assessment, tt.submittedAt, tt.rawScore); got != tt.want {
+				t.Errorf("calculateLatePenalty() = %g, want %g", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,36 @@
+package services
+
+import (
+	"errors"
+	"time"
+
+	"assessment-management-system/models"
+)
+
+// validateStatus checks that an assessment's status agrees with its available_from date
+func validateStatus(status models.AssessmentStatus, availableFrom *time.Time, now time.Time) error {
+	switch status {
+	case models.AssessmentStatusScheduled:
+		if availableFrom == nil || !availableFrom.After(now) {
+			return errors.New("a scheduled assessment needs an available_from date in the future")
+		}
+	case models.AssessmentStatusPublished:
+		if availableFrom != nil && availableFrom.After(now) {
+			return errors.New("an assessment available from a future date must be scheduled, not published")
+		}
+	}
+	return nil
+}
+
+// ensureStatusChangeAllowed checks a status change against the submissions already made: an assessment
+// students have submitted to cannot be hidden again as a draft or scheduled assessment
+func ensureStatusChangeAllowed(current, next models.AssessmentStatus, submissionCount int) error {
+	if current == next || submissionCount == 0 {
+		return nil
+	}
+
+	if next == models.AssessmentStatusDraft || next == models.AssessmentStatusScheduled {
+		return errors.New("an assessment with submissions cannot go back to draft or scheduled")
+	}
+	return nil
+}
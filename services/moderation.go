@@ -0,0 +1,222 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"assessment-management-system/models"
+)
+
+// AssignSecondMarker puts an exam submission up for double marking with a second marker, who must be able to grade
+// the assessment. Until the moderator agrees the final mark, grading the submission records the marker's mark
+// instead of a grade.
+func (s *AssessmentService) AssignSecondMarker(ctx context.Context, submissionID, assignedBy string, req models.AssignSecondMarkerRequest) (*models.Moderation, error) {
+	submission, err := s.assessmentRepo.FindSubmissionByID(ctx, submissionID)
+	if err != nil {
+		return nil, err
+	}
+
+	if submission == nil {
+		return nil, errors.New("submission not found")
+	}
+
+	assessment, err := s.assessmentRepo.FindByID(ctx, submission.AssessmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	if assessment == nil {
+		return nil, errors.New("assessment not found")
+	}
+
+	if assessment.Type != models.AssessmentTypeExam {
+		return nil, errors.New("only exam submissions are double marked")
+	}
+
+	grade, err := s.assessmentRepo.FindGradeBySubmission(ctx, submissionID)
+	if err != nil {
+		return nil, err
+	}
+
+	if grade != nil {
+		return nil, errors.New("this submission is already graded")
+	}
+
+	marker, err := s.userRepo.FindByID(ctx, req.SecondMarkerID)
+	if err != nil {
+		return nil, err
+	}
+
+	if marker == nil || marker.Role != models.RoleTeacher {
+		return nil, errors.New("the second marker must be a teacher")
+	}
+
+	if assessment.TeacherID != marker.ID {
+		isAssigned, err := s.courseRepo.IsTeacherAssigned(ctx, assessment.CourseID, marker.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		if !isAssigned {
+			return nil, errors.New("the second marker must teach this course")
+		}
+	}
+
+	moderation, err := s.moderationRepo.FindBySubmission(ctx, submissionID)
+	if err != nil {
+		return nil, err
+	}
+
+	if moderation != nil && moderation.FirstMarkerID != nil && *moderation.FirstMarkerID == marker.ID {
+		return nil, errors.New("the second marker cannot be the teacher who gave the first mark")
+	}
+
+	return s.moderationRepo.Assign(ctx, submissionID, marker.ID, assignedBy)
+}
+
+// GetModeration retrieves the double marking of a submission as a teacher sees it: while marking is in progress,
+// markers see only their own mark. It returns nil when the submission is not double marked.
+func (s *AssessmentService) GetModeration(ctx context.Context, submissionID, teacherID string) (*models.Moderation, error) {
+	moderation, err := s.moderationRepo.FindBySubmission(ctx, submissionID)
+	if err != nil || moderation == nil {
+		return moderation, err
+	}
+
+	moderation.Evaluate(s.moderationThreshold(ctx, moderation))
+	moderation.HideMarksFrom(teacherID)
+	return moderation, nil
+}
+
+// GetModerationQueue retrieves the double-marked submissions of the assessments a teacher can grade, optionally by status
+func (s *AssessmentService) GetModerationQueue(ctx context.Context, teacherID string, status models.ModerationStatus) ([]*models.Moderation, error) {
+	switch status {
+	case "", models.ModerationStatusMarking, models.ModerationStatusReady, models.ModerationStatusFlagged, models.ModerationStatusAgreed:
+	default:
+		return nil, errors.New("status must be marking, ready, flagged or agreed")
+	}
+
+	moderations, err := s.moderationRepo.FindForTeacher(ctx, teacherID, status)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, moderation := range moderations {
+		moderation.Evaluate(s.moderationThreshold(ctx, moderation))
+		moderation.HideMarksFrom(teacherID)
+	}
+
+	return moderations, nil
+}
+
+// moderationThreshold returns the discrepancy threshold of the assessment a double marking belongs to. Only the
+// discrepancy shown depends on it, the stored status was evaluated when the marks were recorded.
+func (s *AssessmentService) moderationThreshold(ctx context.Context, moderation *models.Moderation) float64 {
+	assessment, err := s.assessmentRepo.FindByID(ctx, moderation.AssessmentID)
+	if err != nil || assessment == nil {
+		return 0
+	}
+	return assessment.ModerationThreshold
+}
+
+// RecordModerationMark records a teacher's mark for a double-marked submission: the second marker's mark, or the
+// first marker's. The first other teacher to mark the submission becomes its first marker, and no other teacher can
+// mark it after that. Each marker may revise their mark until the final mark is agreed. Marks that differ by more
+// than the assessment's threshold are flagged for the moderator.
+func (s *AssessmentService) RecordModerationMark(ctx context.Context, submissionID, teacherID string, req models.GradeSubmissionRequest) (*models.Moderation, error) {
+	moderation, err := s.moderationRepo.FindBySubmission(ctx, submissionID)
+	if err != nil {
+		return nil, err
+	}
+
+	if moderation == nil {
+		return nil, errors.New("this submission is not double marked")
+	}
+
+	if moderation.Status == models.ModerationStatusAgreed {
+		return nil, errors.New("the final mark of this submission is already agreed")
+	}
+
+	assessment, err := s.assessmentRepo.FindByID(ctx, moderation.AssessmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	if assessment == nil {
+		return nil, errors.New("assessment not found")
+	}
+
+	score, _, err := s.scoreSubmission(ctx, assessment, req)
+	if err != nil {
+		return nil, err
+	}
+
+	moderation, err = s.moderationRepo.RecordMark(ctx, submissionID, teacherID, score, req.Feedback, assessment.ModerationThreshold)
+	if err != nil {
+		return nil, err
+	}
+
+	moderation.Evaluate(assessment.ModerationThreshold)
+	moderation.HideMarksFrom(teacherID)
+	return moderation, nil
+}
+
+// AgreeModeratedMark sets the final mark of a double-marked submission once both marks are in. The mark becomes the
+// submission's grade, with the late penalty applied, and later changes only go through regrade requests. Assessments
+// with a rubric are agreed per criterion.
+func (s *AssessmentService) AgreeModeratedMark(ctx context.Context, submissionID, moderatorID string, req models.AgreeMarkRequest) (*models.Moderation, error) {
+	submission, err := s.assessmentRepo.FindSubmissionByID(ctx, submissionID)
+	if err != nil {
+		return nil, err
+	}
+
+	if submission == nil {
+		return nil, errors.New("submission not found")
+	}
+
+	assessment, err := s.assessmentRepo.FindByID(ctx, submission.AssessmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	if assessment == nil {
+		return nil, errors.New("assessment not found")
+	}
+
+	// The late penalty is measured against the student's own due date
+	assessment, _, err = s.applyExtension(ctx, assessment, submission.StudentID)
+	if err != nil {
+		return nil, err
+	}
+
+	score, rubricScores, err := s.scoreSubmission(ctx, assessment, models.GradeSubmissionRequest{Score: req.Score, Rubric: req.Rubric})
+	if err != nil {
+		return nil, err
+	}
+
+	reason := req.Reason
+	if reason == "" {
+		reason = "Agreed moderated mark"
+	}
+
+	latePenalty := calculateLatePenalty(assessment, submission.SubmittedAt, score)
+	moderation, err := s.moderationRepo.Agree(ctx, submissionID, moderatorID, score, latePenalty, req.Feedback, reason, rubricScores)
+	if err != nil {
+		return nil, err
+	}
+
+	moderation.Evaluate(assessment.ModerationThreshold)
+	return moderation, nil
+}
+
+// checkModeratedGrade refuses grading a double-marked submission by hand: its grade is the final mark the moderator
+// agrees, which only changes through regrade requests afterwards
+func checkModeratedGrade(moderation *models.Moderation) error {
+	switch {
+	case moderation == nil:
+		return nil
+	case moderation.Status == models.ModerationStatusAgreed:
+		return errors.New("the final mark of this submission was agreed by a moderator, it can only change through a regrade request")
+	default:
+		return errors.New("this submission is being double marked, its grade is set by the moderator")
+	}
+}
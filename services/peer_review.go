@@ -0,0 +1,309 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"time"
+
+	"assessment-management-system/models"
+)
+
+// DistributePeerReviews starts the peer-review phase of an assessment once submissions have closed. The latest
+// attempt of every student or group goes to the configured number of peers who submitted as well, each reviewer
+// reviews as many submissions as their own gets reviews, and nobody reviews their own or their group's work.
+func (s *AssessmentService) DistributePeerReviews(ctx context.Context, assessment *models.Assessment) ([]*models.PeerReviewSummary, error) {
+	if assessment.PeerReviewsPerSubmission == 0 {
+		return nil, errors.New("peer review is not enabled for this assessment")
+	}
+
+	deadline := assessment.SubmissionDeadline()
+	if deadline == nil {
+		return nil, errors.New("peer review needs an assessment with a due date")
+	}
+
+	if time.Now().Before(*deadline) {
+		return nil, errors.New("peer review starts once the assessment no longer accepts submissions")
+	}
+
+	submissions, err := s.assessmentRepo.FindSubmissionsByAssessment(ctx, assessment.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	// At group assessments, the group's latest attempt is its submission
+	authors := latestBySubmitter(submissions)
+	if len(authors) < 2 {
+		return nil, errors.New("at least two students or groups must have submitted for peer review")
+	}
+
+	submitters := make(map[string]map[string]bool, len(authors))
+	for _, submission := range authors {
+		if submitters[submission.ID], err = s.submitters(ctx, submission); err != nil {
+			return nil, err
+		}
+	}
+
+	// Shuffled into a circle, the student who made each submission reviews the next submissions along it, skipping
+	// those they or anyone of their group worked on
+	rand.Shuffle(len(authors), func(i, j int) {
+		authors[i], authors[j] = authors[j], authors[i]
+	})
+
+	assignments := make([]models.PeerReviewAssignment, 0, len(authors)*assessment.PeerReviewsPerSubmission)
+	for i, submission := range authors {
+		assigned := map[string]bool{}
+		for offset := 1; offset < len(authors) && len(assigned) < assessment.PeerReviewsPerSubmission; offset++ {
+			reviewer := authors[(i+offset)%len(authors)]
+			if assigned[reviewer.StudentID] || sharesSubmitter(submitters[submission.ID], submitters[reviewer.ID]) {
+				continue
+			}
+
+			assignments = append(assignments, models.PeerReviewAssignment{
+				SubmissionID: submission.ID,
+				ReviewerID:   reviewer.StudentID,
+			})
+			assigned[reviewer.StudentID] = true
+		}
+	}
+
+	if err := s.peerReviewRepo.Distribute(ctx, assessment.ID, assignments); err != nil {
+		return nil, err
+	}
+
+	return s.GetPeerReviewSummaries(ctx, assessment)
+}
+
+// GetPeerReviewSummaries retrieves the peer reviews of an assessment per submission, with the peer scores aggregated.
+// Students hidden by anonymous grading are shown by their pseudonym.
+func (s *AssessmentService) GetPeerReviewSummaries(ctx context.Context, assessment *models.Assessment) ([]*models.PeerReviewSummary, error) {
+	reviews, err := s.peerReviewRepo.FindByAssessment(ctx, assessment.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	summaries := []*models.PeerReviewSummary{}
+	bySubmission := map[string]*models.PeerReviewSummary{}
+	for _, review := range reviews {
+		summary, ok := bySubmission[review.SubmissionID]
+		if !ok {
+			submission, err := s.assessmentRepo.FindSubmissionByID(ctx, review.SubmissionID)
+			if err != nil {
+				return nil, err
+			}
+
+			if _, err := s.maskSubmission(ctx, assessment, submission); err != nil {
+				return nil, err
+			}
+
+			summary = &models.PeerReviewSummary{
+				SubmissionID: submission.ID,
+				StudentID:    submission.StudentID,
+				Pseudonym:    submission.Pseudonym,
+			}
+			bySubmission[review.SubmissionID] = summary
+			summaries = append(summaries, summary)
+		}
+		summary.Reviews = append(summary.Reviews, review)
+	}
+
+	for _, summary := range summaries {
+		summary.Summarize()
+	}
+
+	return summaries, nil
+}
+
+// GetAssignedPeerReviews retrieves the peer reviews a student has to do, each with the submission to review. The
+// authors of the submissions stay anonymous.
+func (s *AssessmentService) GetAssignedPeerReviews(ctx context.Context, reviewerID string) ([]*models.PeerReview, error) {
+	reviews, err := s.peerReviewRepo.FindByReviewer(ctx, reviewerID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, review := range reviews {
+		submission, err := s.assessmentRepo.FindSubmissionByID(ctx, review.SubmissionID)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := s.loadSubmissionDetails(ctx, submission); err != nil {
+			return nil, err
+		}
+
+		// Reviewers judge the work, not the marks it got
+		hideAnswerScores(submission)
+		submission.StudentID = ""
+		review.Submission = submission
+	}
+
+	return reviews, nil
+}
+
+// GetPeerReview retrieves a peer review by ID
+func (s *AssessmentService) GetPeerReview(ctx context.Context, id string) (*models.PeerReview, error) {
+	return s.peerReviewRepo.FindByID(ctx, id)
+}
+
+// SubmitPeerReview stores a student's review of a peer's submission. Reviews can be revised until the assessment's
+// peer-review due date, and are scored like a grade: with the assessment's rubric if it has one, otherwise with a
+// score up to the maximum score.
+func (s *AssessmentService) SubmitPeerReview(ctx context.Context, review *models.PeerReview, req models.SubmitPeerReviewRequest) (*models.PeerReview, error) {
+	assessment, err := s.assessmentRepo.FindByID(ctx, review.AssessmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	if assessment.PeerReviewDueDate != nil && time.Now().After(*assessment.PeerReviewDueDate) {
+		return nil, errors.New("the peer-review phase of this assessment has ended")
+	}
+
+	if assessment.PeerReviewWeight != nil {
+		return nil, errors.New("the peer scores of this assessment are already folded into its grades")
+	}
+
+	score, rubric, err := s.scoreSubmission(ctx, assessment, models.GradeSubmissionRequest{
+		Score:  req.Score,
+		Rubric: req.Rubric,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.peerReviewRepo.Submit(ctx, review.ID, score, req.Comments, rubric)
+}
+
+// GetPeerFeedback retrieves the submitted peer reviews of a student's latest attempt at an assessment, without
+// the reviewers
+func (s *AssessmentService) GetPeerFeedback(ctx context.Context, assessmentID, studentID string) ([]*models.PeerReview, error) {
+	submission, err := s.assessmentRepo.FindSubmissionByStudentAndAssessment(ctx, assessmentID, studentID)
+	if err != nil {
+		return nil, err
+	}
+
+	if submission == nil {
+		return nil, errors.New("you have not submitted this assessment")
+	}
+
+	reviews, err := s.peerReviewRepo.FindBySubmission(ctx, submission.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	feedback := []*models.PeerReview{}
+	for _, review := range reviews {
+		if review.IsSubmitted() {
+			review.ReviewerID = ""
+			feedback = append(feedback, review)
+		}
+	}
+
+	return feedback, nil
+}
+
+// ApplyPeerScores folds the average peer score of every reviewed submission into its grade, once the peer-review
+// phase has ended. The new raw score weighs the peer average against the teacher's raw score, and the late penalty
+// is applied again. Submissions without a teacher's grade get the peer average only at a weight of 100%; those
+// without a submitted review, or still being double marked, keep their grade. Peer scores are folded in once.
+func (s *AssessmentService) ApplyPeerScores(ctx context.Context, assessment *models.Assessment, teacherID string, req models.ApplyPeerScoresRequest) (*models.PeerScoresResult, error) {
+	if assessment.PeerReviewWeight != nil {
+		return nil, errors.New("the peer scores of this assessment are already folded into its grades")
+	}
+
+	if assessment.PeerReviewDueDate != nil && time.Now().Before(*assessment.PeerReviewDueDate) {
+		return nil, errors.New("the peer-review phase of this assessment has not ended")
+	}
+
+	summaries, err := s.GetPeerReviewSummaries(ctx, assessment)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(summaries) == 0 {
+		return nil, errors.New("the submissions of this assessment have not been distributed for peer review")
+	}
+
+	weight := req.Weight / 100
+	result := &models.PeerScoresResult{Weight: req.Weight, Skipped: []string{}}
+	grades := []*models.Grade{}
+	for _, summary := range summaries {
+		grade, err := s.peerGrade(ctx, assessment, summary, weight)
+		if err != nil {
+			return nil, err
+		}
+
+		if grade == nil {
+			result.Skipped = append(result.Skipped, summary.SubmissionID)
+			continue
+		}
+		grades = append(grades, grade)
+	}
+
+	reason := fmt.Sprintf("Peer scores folded in at %g%%", req.Weight)
+	if err := s.peerReviewRepo.ApplyScores(ctx, assessment.ID, req.Weight, grades, teacherID, reason); err != nil {
+		return nil, err
+	}
+
+	result.Graded = len(grades)
+	return result, nil
+}
+
+// peerGrade computes the grade a submission gets with its peer average folded in, or nil when it keeps its grade
+func (s *AssessmentService) peerGrade(ctx context.Context, assessment *models.Assessment, summary *models.PeerReviewSummary, weight float64) (*models.Grade, error) {
+	if summary.AverageScore == nil {
+		return nil, nil
+	}
+
+	moderation, err := s.moderationRepo.FindBySubmission(ctx, summary.SubmissionID)
+	if err != nil {
+		return nil, err
+	}
+
+	if moderation != nil && moderation.Status != models.ModerationStatusAgreed {
+		return nil, nil
+	}
+
+	existing, err := s.assessmentRepo.FindGradeBySubmission(ctx, summary.SubmissionID)
+	if err != nil {
+		return nil, err
+	}
+
+	var rawScore float64
+	switch {
+	case existing != nil:
+		rawScore = (1-weight)*existing.RawScore + weight**summary.AverageScore
+	case weight == 1:
+		rawScore = *summary.AverageScore
+	default:
+		return nil, nil
+	}
+
+	submission, err := s.assessmentRepo.FindSubmissionByID(ctx, summary.SubmissionID)
+	if err != nil {
+		return nil, err
+	}
+
+	// The late penalty is measured against the student's own due date
+	studentAssessment, _, err := s.applyExtension(ctx, assessment, submission.StudentID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.Grade{
+		SubmissionID: summary.SubmissionID,
+		RawScore:     rawScore,
+		LatePenalty:  calculateLatePenalty(studentAssessment, submission.SubmittedAt, rawScore),
+	}, nil
+}
+
+// sharesSubmitter reports whether any student worked on both of two submissions
+func sharesSubmitter(students, others map[string]bool) bool {
+	for studentID := range students {
+		if others[studentID] {
+			return true
+		}
+	}
+	return false
+}
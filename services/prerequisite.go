@@ -0,0 +1,185 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"assessment-management-system/models"
+)
+
+// GetPrerequisites retrieves the prerequisites of a course
+func (s *CourseService) GetPrerequisites(ctx context.Context, courseID string) ([]*models.CoursePrerequisite, error) {
+	return s.prerequisiteRepo.FindByCourse(ctx, courseID)
+}
+
+// SetPrerequisites replaces the prerequisites of a course. Required courses must belong to the course's
+// organization and to a term, whose end makes their grades final, and a course cannot require itself, directly or
+// through the courses it requires.
+func (s *CourseService) SetPrerequisites(ctx context.Context, courseID string, req models.SetPrerequisitesRequest) ([]*models.CoursePrerequisite, error) {
+	// Courses of past terms are read-only
+	course, err := s.findWritableCourse(ctx, courseID)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := map[string]bool{}
+	for _, rule := range req.Prerequisites {
+		if seen[rule.RequiredCourseID] {
+			return nil, errors.New("each course can only be required once")
+		}
+		seen[rule.RequiredCourseID] = true
+
+		required, err := s.courseRepo.FindByID(ctx, rule.RequiredCourseID)
+		if err != nil {
+			return nil, err
+		}
+
+		if required == nil || required.OrganizationID != course.OrganizationID {
+			return nil, errors.New("required course not found in this organization")
+		}
+
+		// Offerings share the course's name, so no offering of the course can be required either
+		if required.ID == course.ID || required.Name == course.Name {
+			return nil, errors.New("a course cannot require itself")
+		}
+
+		if required.TermID == nil {
+			return nil, errors.New("a required course must belong to a term")
+		}
+
+		requiresCourse, err := s.requires(ctx, required.ID, course.ID, map[string]bool{})
+		if err != nil {
+			return nil, err
+		}
+
+		if requiresCourse {
+			return nil, fmt.Errorf("%s already requires this course", required.Name)
+		}
+	}
+
+	if err := s.prerequisiteRepo.Set(ctx, courseID, req.Mode, req.Prerequisites); err != nil {
+		return nil, err
+	}
+
+	return s.prerequisiteRepo.FindByCourse(ctx, courseID)
+}
+
+// requires reports whether a course requires another, directly or through the courses it requires
+func (s *CourseService) requires(ctx context.Context, courseID, otherID string, visited map[string]bool) (bool, error) {
+	if visited[courseID] {
+		return false, nil
+	}
+	visited[courseID] = true
+
+	prerequisites, err := s.prerequisiteRepo.FindByCourse(ctx, courseID)
+	if err != nil {
+		return false, err
+	}
+
+	for _, prerequisite := range prerequisites {
+		if prerequisite.RequiredCourseID == otherID {
+			return true, nil
+		}
+
+		requiresOther, err := s.requires(ctx, prerequisite.RequiredCourseID, otherID, visited)
+		if err != nil || requiresOther {
+			return requiresOther, err
+		}
+	}
+	return false, nil
+}
+
+// checkPrerequisites explains why a student does not meet the prerequisites of a course, or returns an empty
+// reason when they do
+func (s *CourseService) checkPrerequisites(ctx context.Context, course *models.Course, studentID string) (string, error) {
+	prerequisites, err := s.prerequisiteRepo.FindByCourse(ctx, course.ID)
+	if err != nil || len(prerequisites) == 0 {
+		return "", err
+	}
+
+	var unmet []string
+	for _, prerequisite := range prerequisites {
+		reason, err := s.checkPrerequisite(ctx, prerequisite, studentID)
+		if err != nil {
+			return "", err
+		}
+
+		if reason == "" {
+			if course.PrerequisiteMode == models.PrerequisiteModeAny {
+				return "", nil
+			}
+			continue
+		}
+		unmet = append(unmet, reason)
+	}
+
+	if len(unmet) == 0 {
+		return "", nil
+	}
+
+	separator := " and "
+	if course.PrerequisiteMode == models.PrerequisiteModeAny {
+		separator = " or "
+	}
+	return "requires " + strings.Join(unmet, separator), nil
+}
+
+// checkPrerequisite explains why a student does not meet one prerequisite, or returns an empty reason when they do.
+// Any offering of the required course counts, that is any course of the organization with its name, and the
+// student's best final grade among them decides. A grade is final once the offering's term has ended.
+func (s *CourseService) checkPrerequisite(ctx context.Context, prerequisite *models.CoursePrerequisite, studentID string) (string, error) {
+	requirement := fmt.Sprintf("a final grade of at least %g%% in %s", prerequisite.MinGrade, prerequisite.RequiredCourseName)
+
+	required, err := s.courseRepo.FindByID(ctx, prerequisite.RequiredCourseID)
+	if err != nil {
+		return "", err
+	}
+
+	if required == nil {
+		return requirement + " (not taken)", nil
+	}
+
+	offerings, err := s.courseRepo.FindOfferingsByStudent(ctx, required.OrganizationID, required.Name, studentID)
+	if err != nil {
+		return "", err
+	}
+
+	if len(offerings) == 0 {
+		return requirement + " (not taken)", nil
+	}
+
+	var best *float64
+	finished := false
+	for _, offering := range offerings {
+		// Running grades of a course still in progress are not final
+		if !offering.IsArchived(time.Now()) {
+			continue
+		}
+		finished = true
+
+		standing, err := s.gradebookService.GetStudentStanding(ctx, offering.ID, studentID)
+		if err != nil {
+			return "", err
+		}
+
+		if standing.Percentage != nil && (best == nil || *standing.Percentage > *best) {
+			best = standing.Percentage
+		}
+	}
+
+	if !finished {
+		return requirement + " (not finished yet)", nil
+	}
+
+	if best == nil {
+		return requirement + " (no grade)", nil
+	}
+
+	if *best < prerequisite.MinGrade {
+		return fmt.Sprintf("%s (yours is %g%%)", requirement, *best), nil
+	}
+	return "", nil
+}
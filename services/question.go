@@ -0,0 +1,561 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"math/rand"
+	"strings"
+
+	"assessment-management-system/models"
+	"assessment-management-system/repositories"
+)
+
+// QuestionService handles business logic for assessment questions
+type QuestionService struct {
+	questionRepo   *repositories.QuestionRepository
+	assessmentRepo *repositories.AssessmentRepository
+	bankRepo       *repositories.QuestionBankRepository
+}
+
+// NewQuestionService creates a new QuestionService
+func NewQuestionService(
+	questionRepo *repositories.QuestionRepository,
+	assessmentRepo *repositories.AssessmentRepository,
+	bankRepo *repositories.QuestionBankRepository,
+) *QuestionService {
+	return &QuestionService{
+		questionRepo:   questionRepo,
+		assessmentRepo: assessmentRepo,
+		bankRepo:       bankRepo,
+	}
+}
+
+// ensureQuestionsEditable checks that the assessment exists and has no submissions yet,
+// since changing questions afterwards would invalidate already computed scores
+func (s *QuestionService) ensureQuestionsEditable(ctx context.Context, assessmentID string) error {
+	assessment, err := s.assessmentRepo.FindByID(ctx, assessmentID)
+	if err != nil {
+		return err
+	}
+
+	if assessment == nil {
+		return errors.New("assessment not found")
+	}
+
+	submissionCount, err := s.assessmentRepo.CountSubmissionsByAssessment(ctx, assessmentID)
+	if err != nil {
+		return err
+	}
+
+	if submissionCount > 0 {
+		return errors.New("questions cannot be changed once submissions exist")
+	}
+
+	return nil
+}
+
+// AddQuestion adds a question to an assessment
+func (s *QuestionService) AddQuestion(ctx context.Context, assessmentID string, req models.CreateQuestionRequest) (*models.Question, error) {
+	if err := s.ensureQuestionsEditable(ctx, assessmentID); err != nil {
+		return nil, err
+	}
+
+	question := &models.Question{
+		AssessmentID:  assessmentID,
+		Type:          req.Type,
+		Prompt:        req.Prompt,
+		Options:       req.Options,
+		Points:        req.Points,
+		CorrectAnswer: req.CorrectAnswer,
+		Tolerance:     req.Tolerance,
+		CaseSensitive: req.CaseSensitive,
+	}
+
+	if err := validateQuestionDefinition(question); err != nil {
+		return nil, err
+	}
+
+	// Append to the end unless a valid position was requested
+	count, err := s.questionRepo.CountByAssessment(ctx, assessmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	question.Position = count + 1
+	if req.Position != nil && *req.Position <= count {
+		question.Position = *req.Position
+	}
+
+	return s.questionRepo.Create(ctx, question)
+}
+
+// GetQuestions retrieves all questions of an assessment including the answer keys
+func (s *QuestionService) GetQuestions(ctx context.Context, assessmentID string) ([]*models.Question, error) {
+	return s.questionRepo.FindByAssessment(ctx, assessmentID)
+}
+
+// GetQuestionsForStudent retrieves the questions a student has to answer without the answer keys.
+// Questions drawn from the course's bank are drawn on first access and stay the same afterwards.
+func (s *QuestionService) GetQuestionsForStudent(ctx context.Context, assessmentID, studentID string) ([]*models.Question, error) {
+	assessment, err := s.assessmentRepo.FindByID(ctx, assessmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	if assessment == nil {
+		return nil, errors.New("assessment not found")
+	}
+
+	questions, err := resolveStudentQuestions(ctx, s.questionRepo, s.bankRepo, assessment, studentID)
+	if err != nil {
+		return nil, err
+	}
+
+	redacted := make([]*models.Question, 0, len(questions))
+	for _, question := range questions {
+		redacted = append(redacted, question.WithoutAnswer())
+	}
+
+	return redacted, nil
+}
+
+// getAssessmentQuestion retrieves a question and checks that it belongs to the assessment
+func (s *QuestionService) getAssessmentQuestion(ctx context.Context, assessmentID, questionID string) (*models.Question, error) {
+	question, err := s.questionRepo.FindByID(ctx, questionID)
+	if err != nil {
+		return nil, err
+	}
+
+	if question == nil || question.AssessmentID != assessmentID {
+		return nil, errors.New("question not found")
+	}
+
+	return question, nil
+}
+
+// UpdateQuestion updates a question of an assessment
+func (s *QuestionService) UpdateQuestion(ctx context.Context, assessmentID, questionID string, req models.UpdateQuestionRequest) (*models.Question, error) {
+	if err := s.ensureQuestionsEditable(ctx, assessmentID); err != nil {
+		return nil, err
+	}
+
+	question, err := s.getAssessmentQuestion(ctx, assessmentID, questionID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Update fields that are provided
+	if req.Type != nil {
+		question.Type = *req.Type
+	}
+	if req.Prompt != nil {
+		question.Prompt = *req.Prompt
+	}
+	if req.Options != nil {
+		question.Options = req.Options
+	}
+	if req.Points != nil {
+		question.Points = *req.Points
+	}
+	if req.CorrectAnswer != nil {
+		question.CorrectAnswer = req.CorrectAnswer
+	}
+	if req.Tolerance != nil {
+		question.Tolerance = *req.Tolerance
+	}
+	if req.CaseSensitive != nil {
+		question.CaseSensitive = *req.CaseSensitive
+	}
+
+	if err := validateQuestionDefinition(question); err != nil {
+		return nil, err
+	}
+
+	return s.questionRepo.Update(ctx, question)
+}
+
+// DeleteQuestion removes a question from an assessment
+func (s *QuestionService) DeleteQuestion(ctx context.Context, assessmentID, questionID string) error {
+	if err := s.ensureQuestionsEditable(ctx, assessmentID); err != nil {
+		return err
+	}
+
+	if _, err := s.getAssessmentQuestion(ctx, assessmentID, questionID); err != nil {
+		return err
+	}
+
+	return s.questionRepo.Delete(ctx, questionID)
+}
+
+// ReorderQuestions changes the order of an assessment's questions
+func (s *QuestionService) ReorderQuestions(ctx context.Context, assessmentID string, questionIDs []string) ([]*models.Question, error) {
+	if err := s.ensureQuestionsEditable(ctx, assessmentID); err != nil {
+		return nil, err
+	}
+
+	questions, err := s.questionRepo.FindByAssessment(ctx, assessmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	// The new order must mention every question exactly once
+	if len(questionIDs) != len(questions) {
+		return nil, errors.New("the new order must include every question of the assessment exactly once")
+	}
+
+	seen := make(map[string]bool, len(questionIDs))
+	for _, id := range questionIDs {
+		if seen[id] {
+			return nil, errors.New("the new order must include every question of the assessment exactly once")
+		}
+		seen[id] = true
+	}
+
+	if err := s.questionRepo.Reorder(ctx, assessmentID, questionIDs); err != nil {
+		return nil, err
+	}
+
+	return s.questionRepo.FindByAssessment(ctx, assessmentID)
+}
+
+// AddDrawRule makes an assessment draw random questions from its course's bank
+func (s *QuestionService) AddDrawRule(ctx context.Context, assessment *models.Assessment, req models.CreateDrawRuleRequest) (*models.QuestionDrawRule, error) {
+	if err := s.ensureQuestionsEditable(ctx, assessment.ID); err != nil {
+		return nil, err
+	}
+
+	// Make sure the bank can satisfy the rule before accepting it
+	difficulty := ""
+	if req.Difficulty != nil {
+		difficulty = string(*req.Difficulty)
+	}
+
+	candidates, err := s.bankRepo.FindByCourse(ctx, assessment.CourseID, req.Tags, difficulty)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(candidates) < req.QuestionCount {
+		return nil, fmt.Errorf("only %d bank questions match this rule, %d requested", len(candidates), req.QuestionCount)
+	}
+
+	rule := &models.QuestionDrawRule{
+		AssessmentID:      assessment.ID,
+		Tags:              req.Tags,
+		Difficulty:        req.Difficulty,
+		QuestionCount:     req.QuestionCount,
+		PointsPerQuestion: req.PointsPerQuestion,
+	}
+	if rule.Tags == nil {
+		rule.Tags = []string{}
+	}
+
+	created, err := s.bankRepo.CreateDrawRule(ctx, rule)
+	if err != nil {
+		return nil, err
+	}
+
+	// Draws made while the assessment was still being prepared no longer match its rules
+	if err := s.bankRepo.DeleteDraws(ctx, assessment.ID); err != nil {
+		return nil, err
+	}
+
+	return created, nil
+}
+
+// GetDrawRules retrieves the draw rules of an assessment
+func (s *QuestionService) GetDrawRules(ctx context.Context, assessmentID string) ([]*models.QuestionDrawRule, error) {
+	return s.bankRepo.FindDrawRulesByAssessment(ctx, assessmentID)
+}
+
+// DeleteDrawRule removes a draw rule from an assessment
+func (s *QuestionService) DeleteDrawRule(ctx context.Context, assessmentID, ruleID string) error {
+	if err := s.ensureQuestionsEditable(ctx, assessmentID); err != nil {
+		return err
+	}
+
+	if err := s.bankRepo.DeleteDrawRule(ctx, assessmentID, ruleID); err != nil {
+		return err
+	}
+
+	return s.bankRepo.DeleteDraws(ctx, assessmentID)
+}
+
+// resolveStudentQuestions returns the questions a student has to answer: the assessment's own questions
+// followed by the bank questions drawn for the student. The draw is made and persisted on first use.
+func resolveStudentQuestions(
+	ctx context.Context,
+	questionRepo *repositories.QuestionRepository,
+	bankRepo *repositories.QuestionBankRepository,
+	assessment *models.Assessment,
+	studentID string,
+) ([]*models.Question, error) {
+	questions, err := questionRepo.FindByAssessment(ctx, assessment.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	rules, err := bankRepo.FindDrawRulesByAssessment(ctx, assessment.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(rules) == 0 {
+		return questions, nil
+	}
+
+	drawn, err := bankRepo.FindDrawnQuestions(ctx, assessment.ID, studentID)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(drawn) == 0 {
+		selection, err := drawQuestions(ctx, bankRepo, assessment, studentID, rules)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := bankRepo.SaveDraw(ctx, assessment.ID, studentID, selection); err != nil {
+			return nil, err
+		}
+
+		// Reload, a concurrent request may have persisted its draw first
+		drawn, err = bankRepo.FindDrawnQuestions(ctx, assessment.ID, studentID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Drawn questions follow the assessment's own questions
+	for i, question := range drawn {
+		question.Position = len(questions) + i + 1
+	}
+
+	return append(questions, drawn...), nil
+}
+
+// drawQuestions picks the bank questions for a student. The random source is seeded from the assessment,
+// student and rule, so the same student always gets the same draw while students differ from each other.
+func drawQuestions(
+	ctx context.Context,
+	bankRepo *repositories.QuestionBankRepository,
+	assessment *models.Assessment,
+	studentID string,
+	rules []*models.QuestionDrawRule,
+) ([]*models.DrawnQuestion, error) {
+	picked := make(map[string]bool)
+	var selection []*models.DrawnQuestion
+
+	for _, rule := range rules {
+		difficulty := ""
+		if rule.Difficulty != nil {
+			difficulty = string(*rule.Difficulty)
+		}
+
+		candidates, err := bankRepo.FindByCourse(ctx, assessment.CourseID, rule.Tags, difficulty)
+		if err != nil {
+			return nil, err
+		}
+
+		// A question drawn by an earlier rule is not drawn twice
+		available := make([]*models.BankQuestion, 0, len(candidates))
+		for _, candidate := range candidates {
+			if !picked[candidate.ID] {
+				available = append(available, candidate)
+			}
+		}
+
+		if len(available) < rule.QuestionCount {
+			return nil, errors.New("the question bank no longer has enough questions for this assessment")
+		}
+
+		seed := fnv.New64a()
+		seed.Write([]byte(assessment.ID + ":" + studentID + ":" + rule.ID))
+		order := rand.New(rand.NewSource(int64(seed.Sum64()))).Perm(len(available))
+
+		for _, index := range order[:rule.QuestionCount] {
+			question := available[index]
+			picked[question.ID] = true
+
+			points := question.Points
+			if rule.PointsPerQuestion != nil {
+				points = *rule.PointsPerQuestion
+			}
+
+			selection = append(selection, &models.DrawnQuestion{
+				DrawID:     rule.ID,
+				QuestionID: question.ID,
+				Position:   len(selection) + 1,
+				Points:     points,
+			})
+		}
+	}
+
+	return selection, nil
+}
+
+// validateQuestionDefinition checks that a question's options and answer key match its type
+func validateQuestionDefinition(question *models.Question) error {
+	key := question.CorrectAnswer
+	if key == nil {
+		return errors.New("correct answer is required")
+	}
+
+	switch question.Type {
+	case models.QuestionTypeMultipleChoice:
+		if len(question.Options) < 2 {
+			return errors.New("multiple choice questions need at least two options")
+		}
+		if key.Choice == nil || *key.Choice < 0 || *key.Choice >= len(question.Options) {
+			return errors.New("correct answer must reference one of the options")
+		}
+		*key = models.QuestionAnswer{Choice: key.Choice}
+	case models.QuestionTypeMultiSelect:
+		if len(question.Options) < 2 {
+			return errors.New("multi-select questions need at least two options")
+		}
+		if len(key.Choices) == 0 {
+			return errors.New("correct answer must reference at least one option")
+		}
+		seen := make(map[int]bool, len(key.Choices))
+		for _, choice := range key.Choices {
+			if choice < 0 || choice >= len(question.Options) {
+				return errors.New("correct answer must reference the options of the question")
+			}
+			if seen[choice] {
+				return errors.New("correct answer lists the same option more than once")
+			}
+			seen[choice] = true
+		}
+		*key = models.QuestionAnswer{Choices: key.Choices}
+	case models.QuestionTypeTrueFalse:
+		if key.Boolean == nil {
+			return errors.New("true/false questions need a boolean correct answer")
+		}
+		question.Options = nil
+		*key = models.QuestionAnswer{Boolean: key.Boolean}
+	case models.QuestionTypeNumeric:
+		if key.Number == nil {
+			return errors.New("numeric questions need a numeric correct answer")
+		}
+		question.Options = nil
+		*key = models.QuestionAnswer{Number: key.Number}
+	case models.QuestionTypeShortText:
+		if key.Text == nil || strings.TrimSpace(*key.Text) == "" {
+			return errors.New("short text questions need a text correct answer")
+		}
+		question.Options = nil
+		*key = models.QuestionAnswer{Text: key.Text}
+	default:
+		return errors.New("unsupported question type")
+	}
+
+	if question.Tolerance < 0 {
+		return errors.New("tolerance cannot be negative")
+	}
+
+	if question.Type != models.QuestionTypeNumeric {
+		question.Tolerance = 0
+	}
+
+	return nil
+}
+
+// isAnswerCorrect checks a single answer against the question's answer key.
+// Multi-select questions are scored all-or-nothing.
+func isAnswerCorrect(question *models.Question, answer *models.QuestionAnswer) bool {
+	key := question.CorrectAnswer
+	if answer == nil || key == nil {
+		return false
+	}
+
+	switch question.Type {
+	case models.QuestionTypeMultipleChoice:
+		return answer.Choice != nil && key.Choice != nil && *answer.Choice == *key.Choice
+	case models.QuestionTypeMultiSelect:
+		if len(answer.Choices) != len(key.Choices) {
+			return false
+		}
+		expected := make(map[int]bool, len(key.Choices))
+		for _, choice := range key.Choices {
+			expected[choice] = true
+		}
+		for _, choice := range answer.Choices {
+			if !expected[choice] {
+				return false
+			}
+			delete(expected, choice)
+		}
+		return len(expected) == 0
+	case models.QuestionTypeTrueFalse:
+		return answer.Boolean != nil && key.Boolean != nil && *answer.Boolean == *key.Boolean
+	case models.QuestionTypeNumeric:
+		// A small epsilon absorbs floating point noise at the tolerance boundary
+		return answer.Number != nil && key.Number != nil &&
+			math.Abs(*answer.Number-*key.Number) <= question.Tolerance+1e-9
+	case models.QuestionTypeShortText:
+		if answer.Text == nil || key.Text == nil {
+			return false
+		}
+		given := strings.TrimSpace(*answer.Text)
+		expected := strings.TrimSpace(*key.Text)
+		if question.CaseSensitive {
+			return given == expected
+		}
+		return strings.EqualFold(given, expected)
+	}
+
+	return false
+}
+
+// gradeAnswers scores submitted answers against the questions. Every question gets an answer record,
+// unanswered questions score zero. It returns the answers together with the earned and available points.
+func gradeAnswers(questions []*models.Question, submitted []models.SubmitAnswerRequest) ([]*models.SubmissionAnswer, float64, float64, error) {
+	byQuestion := make(map[string]*models.QuestionAnswer, len(submitted))
+	for _, answer := range submitted {
+		if _, duplicate := byQuestion[answer.QuestionID]; duplicate {
+			return nil, 0, 0, fmt.Errorf("question %s is answered more than once", answer.QuestionID)
+		}
+		byQuestion[answer.QuestionID] = answer.Answer
+	}
+
+	var earned, total float64
+	answers := make([]*models.SubmissionAnswer, 0, len(questions))
+	for _, question := range questions {
+		answer := byQuestion[question.ID]
+		delete(byQuestion, question.ID)
+
+		correct := isAnswerCorrect(question, answer)
+		var points float64
+		if correct {
+			points = question.Points
+		}
+
+		earned += points
+		total += question.Points
+		answers = append(answers, &models.SubmissionAnswer{
+			QuestionID:    question.ID,
+			Position:      len(answers) + 1,
+			Answer:        answer,
+			IsCorrect:     correct,
+			PointsAwarded: points,
+		})
+	}
+
+	for questionID := range byQuestion {
+		return nil, 0, 0, fmt.Errorf("question %s does not belong to this assessment", questionID)
+	}
+
+	return answers, earned, total, nil
+}
+
+// scaleScore converts earned question points into a score out of the assessment's maximum score
+func scaleScore(earned, total float64, maxScore int) float64 {
+	if total <= 0 {
+		return 0
+	}
+	return math.Round(earned/total*float64(maxScore)*100) / 100
+}
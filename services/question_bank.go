@@ -0,0 +1,174 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"assessment-management-system/models"
+	"assessment-management-system/repositories"
+)
+
+// QuestionBankService handles business logic for course question banks
+type QuestionBankService struct {
+	bankRepo   *repositories.QuestionBankRepository
+	courseRepo *repositories.CourseRepository
+}
+
+// NewQuestionBankService creates a new QuestionBankService
+func NewQuestionBankService(
+	bankRepo *repositories.QuestionBankRepository,
+	courseRepo *repositories.CourseRepository,
+) *QuestionBankService {
+	return &QuestionBankService{
+		bankRepo:   bankRepo,
+		courseRepo: courseRepo,
+	}
+}
+
+// validateBankQuestion checks the question definition the same way assessment questions are checked
+func validateBankQuestion(question *models.BankQuestion) error {
+	definition := question.AsQuestion("", 0, question.Points)
+	if err := validateQuestionDefinition(definition); err != nil {
+		return err
+	}
+
+	// Validation normalizes the options and answer key for the question type
+	question.Options = definition.Options
+	question.CorrectAnswer = definition.CorrectAnswer
+	question.Tolerance = definition.Tolerance
+	return nil
+}
+
+// CreateQuestion adds a question to a course's bank
+func (s *QuestionBankService) CreateQuestion(ctx context.Context, courseID, teacherID string, req models.CreateBankQuestionRequest) (*models.BankQuestion, error) {
+	course, err := s.courseRepo.FindByID(ctx, courseID)
+	if err != nil {
+		return nil, err
+	}
+
+	if course == nil {
+		return nil, errors.New("course not found")
+	}
+
+	question := &models.BankQuestion{
+		CourseID:      courseID,
+		CreatedBy:     teacherID,
+		Type:          req.Type,
+		Prompt:        req.Prompt,
+		Options:       req.Options,
+		Points:        req.Points,
+		CorrectAnswer: req.CorrectAnswer,
+		Tolerance:     req.Tolerance,
+		CaseSensitive: req.CaseSensitive,
+		Tags:          req.Tags,
+		Difficulty:    req.Difficulty,
+	}
+
+	if question.Tags == nil {
+		question.Tags = []string{}
+	}
+	if question.Difficulty == "" {
+		question.Difficulty = models.DifficultyMedium
+	}
+
+	if err := validateBankQuestion(question); err != nil {
+		return nil, err
+	}
+
+	return s.bankRepo.Create(ctx, question)
+}
+
+// GetQuestions retrieves the bank questions of a course, filtered by tags and difficulty when given
+func (s *QuestionBankService) GetQuestions(ctx context.Context, courseID string, tags []string, difficulty string) ([]*models.BankQuestion, error) {
+	return s.bankRepo.FindByCourse(ctx, courseID, tags, difficulty)
+}
+
+// GetQuestion retrieves a bank question and checks that it belongs to the course
+func (s *QuestionBankService) GetQuestion(ctx context.Context, courseID, questionID string) (*models.BankQuestion, error) {
+	question, err := s.bankRepo.FindByID(ctx, questionID)
+	if err != nil {
+		return nil, err
+	}
+
+	if question == nil || question.CourseID != courseID {
+		return nil, errors.New("question not found")
+	}
+
+	return question, nil
+}
+
+// UpdateQuestion updates a bank question. Once a question has been drawn for a student only its tags and
+// difficulty can change, so that existing attempts keep being shown and scored against the question they got.
+func (s *QuestionBankService) UpdateQuestion(ctx context.Context, courseID, questionID string, req models.UpdateBankQuestionRequest) (*models.BankQuestion, error) {
+	question, err := s.GetQuestion(ctx, courseID, questionID)
+	if err != nil {
+		return nil, err
+	}
+
+	changesContent := req.Type != nil || req.Prompt != nil || req.Options != nil || req.Points != nil ||
+		req.CorrectAnswer != nil || req.Tolerance != nil || req.CaseSensitive != nil
+	if changesContent {
+		drawn, err := s.bankRepo.IsDrawn(ctx, questionID)
+		if err != nil {
+			return nil, err
+		}
+
+		if drawn {
+			return nil, errors.New("question has already been drawn for a student, only its tags and difficulty can be changed")
+		}
+	}
+
+	// Update fields that are provided
+	if req.Type != nil {
+		question.Type = *req.Type
+	}
+	if req.Prompt != nil {
+		question.Prompt = *req.Prompt
+	}
+	if req.Options != nil {
+		question.Options = req.Options
+	}
+	if req.Points != nil {
+		question.Points = *req.Points
+	}
+	if req.CorrectAnswer != nil {
+		question.CorrectAnswer = req.CorrectAnswer
+	}
+	if req.Tolerance != nil {
+		question.Tolerance = *req.Tolerance
+	}
+	if req.CaseSensitive != nil {
+		question.CaseSensitive = *req.CaseSensitive
+	}
+	if req.Tags != nil {
+		question.Tags = req.Tags
+	}
+	if req.Difficulty != nil {
+		question.Difficulty = *req.Difficulty
+	}
+
+	if err := validateBankQuestion(question); err != nil {
+		return nil, err
+	}
+
+	return s.bankRepo.Update(ctx, question)
+}
+
+// DeleteQuestion removes a question from a course's bank. Questions already drawn for a student
+// are kept so existing submissions stay readable.
+func (s *QuestionBankService) DeleteQuestion(ctx context.Context, courseID, questionID string) error {
+	if _, err := s.GetQuestion(ctx, courseID, questionID); err != nil {
+		return err
+	}
+
+	drawn, err := s.bankRepo.IsDrawn(ctx, questionID)
+	if err != nil {
+		return err
+	}
+
+	if drawn {
+		return errors.New("question has already been drawn for a student and cannot be deleted")
+	}
+
+	return s.bankRepo.Delete(ctx, questionID)
+}
@@ -0,0 +1,247 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"assessment-management-system/models"
+	"assessment-management-system/repositories"
+)
+
+// RegradeService handles business logic for regrade requests
+type RegradeService struct {
+	regradeRepo    *repositories.RegradeRepository
+	assessmentRepo *repositories.AssessmentRepository
+	extensionRepo  *repositories.ExtensionRepository
+	rubricRepo     *repositories.RubricRepository
+	questionRepo   *repositories.QuestionRepository
+	courseRepo     *repositories.CourseRepository
+}
+
+// NewRegradeService creates a new RegradeService
+func NewRegradeService(
+	regradeRepo *repositories.RegradeRepository,
+	assessmentRepo *repositories.AssessmentRepository,
+	extensionRepo *repositories.ExtensionRepository,
+	rubricRepo *repositories.RubricRepository,
+	questionRepo *repositories.QuestionRepository,
+	courseRepo *repositories.CourseRepository,
+) *RegradeService {
+	return &RegradeService{
+		regradeRepo:    regradeRepo,
+		assessmentRepo: assessmentRepo,
+		extensionRepo:  extensionRepo,
+		rubricRepo:     rubricRepo,
+		questionRepo:   questionRepo,
+		courseRepo:     courseRepo,
+	}
+}
+
+// OpenRequest opens a regrade request against one of the student's graded submissions. The grade must have
+// been released, and a submission has at most one open request at a time.
+func (s *RegradeService) OpenRequest(ctx context.Context, submissionID, studentID string, req models.CreateRegradeRequest) (*models.RegradeRequest, error) {
+	submission, err := s.assessmentRepo.FindSubmissionByID(ctx, submissionID)
+	if err != nil {
+		return nil, err
+	}
+
+	if submission == nil {
+		return nil, errors.New("submission not found")
+	}
+
+	// Any member of a group can dispute the group's grade
+	isOwn, err := s.assessmentRepo.IsSubmissionStudent(ctx, submissionID, studentID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !isOwn {
+		return nil, errors.New("submission not found")
+	}
+
+	assessment, err := s.assessmentRepo.FindByID(ctx, submission.AssessmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Courses of past terms are read-only
+	course, err := s.courseRepo.FindByID(ctx, assessment.CourseID)
+	if err != nil {
+		return nil, err
+	}
+
+	if course != nil && course.IsArchived(time.Now()) {
+		return nil, ErrCourseArchived
+	}
+
+	grade, err := s.assessmentRepo.FindGradeBySubmission(ctx, submissionID)
+	if err != nil {
+		return nil, err
+	}
+
+	if grade == nil || !assessment.IsGradeReleased(grade, time.Now()) {
+		return nil, errors.New("only graded submissions can be regraded")
+	}
+
+	open, err := s.regradeRepo.FindOpenBySubmission(ctx, submissionID)
+	if err != nil {
+		return nil, err
+	}
+
+	if open != nil {
+		return nil, errors.New("this submission already has an open regrade request")
+	}
+
+	if req.CriterionID != nil && req.QuestionID != nil {
+		return nil, errors.New("a regrade request is about either a rubric criterion or a question, not both")
+	}
+
+	if req.CriterionID != nil {
+		if err := s.checkCriterion(ctx, submissionID, *req.CriterionID); err != nil {
+			return nil, err
+		}
+	}
+
+	if req.QuestionID != nil {
+		if err := s.checkQuestion(ctx, submissionID, *req.QuestionID); err != nil {
+			return nil, err
+		}
+	}
+
+	return s.regradeRepo.Create(ctx, submissionID, studentID, req)
+}
+
+// checkCriterion checks that the submission was graded on the rubric criterion
+func (s *RegradeService) checkCriterion(ctx context.Context, submissionID, criterionID string) error {
+	scores, err := s.rubricRepo.FindScoresBySubmission(ctx, submissionID)
+	if err != nil {
+		return err
+	}
+
+	for _, score := range scores {
+		if score.CriterionID == criterionID {
+			return nil
+		}
+	}
+	return errors.New("the submission was not graded on this rubric criterion")
+}
+
+// checkQuestion checks that the submission answered the question
+func (s *RegradeService) checkQuestion(ctx context.Context, submissionID, questionID string) error {
+	answers, err := s.questionRepo.FindAnswersBySubmission(ctx, submissionID)
+	if err != nil {
+		return err
+	}
+
+	for _, answer := range answers {
+		if answer.QuestionID == questionID {
+			return nil
+		}
+	}
+	return errors.New("the submission has no answer to this question")
+}
+
+// GetRequest retrieves a regrade request by ID
+func (s *RegradeService) GetRequest(ctx context.Context, id string) (*models.RegradeRequest, error) {
+	return s.regradeRepo.FindByID(ctx, id)
+}
+
+// GetStudentRequests retrieves the regrade requests a student opened
+func (s *RegradeService) GetStudentRequests(ctx context.Context, studentID string) ([]*models.RegradeRequest, error) {
+	return s.regradeRepo.FindByStudent(ctx, studentID)
+}
+
+// GetTeacherQueue retrieves the regrade requests of the assessments a teacher can grade, optionally by status
+func (s *RegradeService) GetTeacherQueue(ctx context.Context, teacherID string, status models.RegradeStatus) ([]*models.RegradeRequest, error) {
+	switch status {
+	case "", models.RegradeStatusOpen, models.RegradeStatusAccepted, models.RegradeStatusRejected:
+	default:
+		return nil, errors.New("status must be open, accepted or rejected")
+	}
+
+	return s.regradeRepo.FindForTeacher(ctx, teacherID, status)
+}
+
+// AcceptRequest accepts an open regrade request and regrades the submission with the new score. The late
+// penalty of the submission is applied again to the new score.
+func (s *RegradeService) AcceptRequest(ctx context.Context, request *models.RegradeRequest, teacherID string, req models.AcceptRegradeRequest) (*models.RegradeRequest, error) {
+	if request.Status != models.RegradeStatusOpen {
+		return nil, errors.New("this regrade request is already resolved")
+	}
+
+	submission, err := s.assessmentRepo.FindSubmissionByID(ctx, request.SubmissionID)
+	if err != nil {
+		return nil, err
+	}
+
+	if submission == nil {
+		return nil, errors.New("submission not found")
+	}
+
+	assessment, err := s.assessmentRepo.FindByID(ctx, submission.AssessmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	if assessment == nil {
+		return nil, errors.New("assessment not found")
+	}
+
+	score, rubricScores, err := s.scoreRegrade(ctx, assessment, req)
+	if err != nil {
+		return nil, err
+	}
+
+	// The late penalty is measured against the student's own due date
+	extension, err := s.extensionRepo.FindByStudentAndAssessment(ctx, assessment.ID, submission.StudentID)
+	if err != nil {
+		return nil, err
+	}
+
+	latePenalty := calculateLatePenalty(extension.Apply(assessment), submission.SubmittedAt, score)
+	return s.regradeRepo.Accept(ctx, request.ID, score, latePenalty, req.Response, teacherID, rubricScores)
+}
+
+// scoreRegrade computes the new raw score of a regrade. Assessments graded with a rubric are rescored per
+// criterion, so that the breakdown the student sees keeps adding up to their grade.
+func (s *RegradeService) scoreRegrade(ctx context.Context, assessment *models.Assessment, req models.AcceptRegradeRequest) (float64, []*models.RubricScore, error) {
+	if assessment.RubricID == nil {
+		if len(req.Rubric) > 0 {
+			return 0, nil, errors.New("this assessment has no rubric")
+		}
+
+		if req.Score == nil {
+			return 0, nil, errors.New("score is required")
+		}
+
+		if *req.Score > float64(assessment.MaxScore) {
+			return 0, nil, errors.New("score must be between 0 and the maximum score")
+		}
+		return *req.Score, nil, nil
+	}
+
+	if req.Score != nil {
+		return 0, nil, errors.New("this assessment is graded with a rubric, choose a level per criterion instead of a score")
+	}
+
+	rubric, err := s.rubricRepo.FindByID(ctx, *assessment.RubricID)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	if rubric == nil {
+		return 0, nil, errors.New("rubric not found")
+	}
+
+	return scoreRubric(rubric, assessment, req.Rubric)
+}
+
+// RejectRequest rejects an open regrade request, leaving the grade unchanged
+func (s *RegradeService) RejectRequest(ctx context.Context, request *models.RegradeRequest, teacherID string, req models.RejectRegradeRequest) (*models.RegradeRequest, error) {
+	if request.Status != models.RegradeStatusOpen {
+		return nil, errors.New("this regrade request is already resolved")
+	}
+
+	return s.regradeRepo.Reject(ctx, request.ID, req.Response, teacherID)
+}
@@ -0,0 +1,120 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"assessment-management-system/db/dbtest"
+	"assessment-management-system/models"
+	"assessment-management-system/repositories"
+)
+
+func TestAcceptRequestRescoresRubricAndReappliesLatePenalty(t *testing.T) {
+	database := dbtest.Open(t)
+	ctx := context.Background()
+
+	assessments := repositories.NewAssessmentRepository(database)
+	rubrics := repositories.NewRubricRepository(database)
+	regrades := repositories.NewRegradeRepository(database)
+	extensions := repositories.NewExtensionRepository(database)
+	service := NewRegradeService(regrades, assessments, extensions, rubrics, repositories.NewQuestionRepository(database),
+		repositories.NewCourseRepository(database))
+
+	org := dbtest.Organization(t, database)
+	term := dbtest.CurrentTerm(t, database, org.ID)
+	course := dbtest.Course(t, database, org.ID, term.ID, "Writing", nil)
+	teacher := dbtest.User(t, database, org.ID, models.RoleTeacher)
+	late := dbtest.User(t, database, org.ID, models.RoleStudent)
+	extended := dbtest.User(t, database, org.ID, models.RoleStudent)
+	dbtest.Enroll(t, database, course.ID, late.ID, nil)
+	dbtest.Enroll(t, database, course.ID, extended.ID, nil)
+
+	// Due a day and a bit ago: submissions made now are two started days late
+	due := time.Now().Add(-30 * time.Hour)
+	cutoff := time.Now().Add(24 * time.Hour)
+	assessment := dbtest.Assessment(t, database, teacher.ID, models.CreateAssessmentRequest{
+		CourseID:         course.ID,
+		DueDate:          &due,
+		CutoffDate:       &cutoff,
+		LatePenaltyType:  models.LatePenaltyPercentPerDay,
+		LatePenaltyValue: 10,
+	})
+
+	rubric := testRubric()
+	rubric.CourseID = course.ID
+	rubric.CreatedBy = teacher.ID
+	rubric.Title = "Essay"
+	rubric, err := rubrics.Create(ctx, rubric)
+	if err != nil {
+		t.Fatalf("failed to create rubric: %v", err)
+	}
+	if _, err := assessments.SetRubric(ctx, assessment.ID, &rubric.ID); err != nil {
+		t.Fatalf("failed to attach rubric: %v", err)
+	}
+
+	// The student with an extension submitted before their own due date
+	extendedDue := time.Now().Add(time.Hour)
+	if _, err := extensions.Save(ctx, &models.Extension{AssessmentID: assessment.ID, StudentID: extended.ID,
+		DueDate: &extendedDue, Reason: "Illness", GrantedBy: teacher.ID}); err != nil {
+		t.Fatalf("failed to grant extension: %v", err)
+	}
+
+	// Both pick the second content level and the best structure level: 15 of 20 rubric points, 75 of 100
+	selections := []models.RubricSelectionRequest{
+		{CriterionID: rubric.Criteria[0].ID, LevelID: rubric.Criteria[0].Levels[1].ID},
+		{CriterionID: rubric.Criteria[1].ID, LevelID: rubric.Criteria[1].Levels[1].ID, Comment: "Better argued now"},
+	}
+
+	tests := []struct {
+		name        string
+		student     *models.User
+		latePenalty float64
+	}{
+		{"late", late, 20},
+		{"extended", extended, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			submission := dbtest.Submission(t, database, assessment.ID, tt.student.ID, "Essay")
+			if _, err := assessments.CreateGrade(ctx, submission.ID, 50, tt.latePenalty, "", teacher.ID, ""); err != nil {
+				t.Fatalf("failed to grade: %v", err)
+			}
+
+			request, err := regrades.Create(ctx, submission.ID, tt.student.ID, models.CreateRegradeRequest{Reason: "My argument was missed"})
+			if err != nil {
+				t.Fatalf("failed to open regrade request: %v", err)
+			}
+
+			if _, err := service.AcceptRequest(ctx, request, teacher.ID, models.AcceptRegradeRequest{Score: new(float64), Rubric: selections}); err == nil {
+				t.Fatalf("a score was accepted for an assessment graded with a rubric")
+			}
+
+			accepted, err := service.AcceptRequest(ctx, request, teacher.ID, models.AcceptRegradeRequest{Rubric: selections, Response: "Agreed"})
+			if err != nil {
+				t.Fatalf("failed to accept regrade: %v", err)
+			}
+			if accepted.Status != models.RegradeStatusAccepted {
+				t.Fatalf("regrade status = %s, want accepted", accepted.Status)
+			}
+
+			grade, err := assessments.FindGradeBySubmission(ctx, submission.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if grade.RawScore != 75 || grade.LatePenalty != tt.latePenalty || grade.Score != 75-tt.latePenalty {
+				t.Errorf("grade = raw %g, penalty %g, score %g; want raw 75, penalty %g, score %g",
+					grade.RawScore, grade.LatePenalty, grade.Score, tt.latePenalty, 75-tt.latePenalty)
+			}
+
+			scores, err := rubrics.FindScoresBySubmission(ctx, submission.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(scores) != 2 || scores[0].Points+scores[1].Points != 15 {
+				t.Errorf("the rubric breakdown does not add up to the regrade: %+v", scores)
+			}
+		})
+	}
+}
@@ -0,0 +1,61 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"assessment-management-system/models"
+)
+
+// validateGradeRelease checks that a scheduled grade release has a release date
+func validateGradeRelease(mode models.GradeReleaseMode, releaseAt *time.Time) error {
+	if mode == models.GradeReleaseScheduled && releaseAt == nil {
+		return errors.New("a scheduled grade release needs a grades_release_at date")
+	}
+	return nil
+}
+
+// ReleaseGrades releases every grade of an assessment students cannot see yet and returns how many were released.
+// Grades given afterwards are held back again until the next release, unless they are released immediately.
+func (s *AssessmentService) ReleaseGrades(ctx context.Context, assessmentID string) (int, error) {
+	assessment, err := s.assessmentRepo.FindByID(ctx, assessmentID)
+	if err != nil {
+		return 0, err
+	}
+
+	if assessment == nil {
+		return 0, errors.New("assessment not found")
+	}
+
+	return s.assessmentRepo.ReleaseGrades(ctx, assessmentID)
+}
+
+// GetReleasedAttempts retrieves the attempts of a student, oldest first, as the student sees them: grades that
+// have not been released are left out, together with the automatic scores of the answers
+func (s *AssessmentService) GetReleasedAttempts(ctx context.Context, assessment *models.Assessment, studentID string) ([]*models.SubmissionAttempt, error) {
+	attempts, err := s.getAttempts(ctx, assessment.ID, studentID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	for _, attempt := range attempts {
+		if attempt.Grade != nil && assessment.IsGradeReleased(attempt.Grade, now) {
+			continue
+		}
+
+		attempt.Grade = nil
+		hideAnswerScores(attempt.Submission)
+	}
+
+	return attempts, nil
+}
+
+// hideAnswerScores clears the automatic scores of a submission's answers, which would give away an unreleased grade
+func hideAnswerScores(submission *models.AssessmentSubmission) {
+	for _, answer := range submission.Answers {
+		answer.IsCorrect = false
+		answer.PointsAwarded = 0
+	}
+}
@@ -0,0 +1,195 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"assessment-management-system/models"
+	"assessment-management-system/repositories"
+)
+
+// RubricService handles business logic for grading rubrics
+type RubricService struct {
+	rubricRepo     *repositories.RubricRepository
+	courseRepo     *repositories.CourseRepository
+	assessmentRepo *repositories.AssessmentRepository
+}
+
+// NewRubricService creates a new RubricService
+func NewRubricService(
+	rubricRepo *repositories.RubricRepository,
+	courseRepo *repositories.CourseRepository,
+	assessmentRepo *repositories.AssessmentRepository,
+) *RubricService {
+	return &RubricService{
+		rubricRepo:     rubricRepo,
+		courseRepo:     courseRepo,
+		assessmentRepo: assessmentRepo,
+	}
+}
+
+// buildCriteria converts criterion requests into rubric criteria
+func buildCriteria(requests []models.RubricCriterionRequest) ([]*models.RubricCriterion, error) {
+	criteria := make([]*models.RubricCriterion, 0, len(requests))
+	for _, req := range requests {
+		criterion := &models.RubricCriterion{
+			Title:       req.Title,
+			Description: req.Description,
+		}
+
+		for _, levelReq := range req.Levels {
+			criterion.Levels = append(criterion.Levels, &models.RubricLevel{
+				Title:      levelReq.Title,
+				Descriptor: levelReq.Descriptor,
+				Points:     levelReq.Points,
+			})
+		}
+
+		if criterion.MaxPoints() <= 0 {
+			return nil, errors.New("every criterion needs a level worth more than zero points")
+		}
+
+		criteria = append(criteria, criterion)
+	}
+	return criteria, nil
+}
+
+// CreateRubric creates a rubric for a course
+func (s *RubricService) CreateRubric(ctx context.Context, courseID, teacherID string, req models.CreateRubricRequest) (*models.Rubric, error) {
+	course, err := s.courseRepo.FindByID(ctx, courseID)
+	if err != nil {
+		return nil, err
+	}
+
+	if course == nil {
+		return nil, errors.New("course not found")
+	}
+
+	criteria, err := buildCriteria(req.Criteria)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.rubricRepo.Create(ctx, &models.Rubric{
+		CourseID:    courseID,
+		CreatedBy:   teacherID,
+		Title:       req.Title,
+		Description: req.Description,
+		Criteria:    criteria,
+	})
+}
+
+// GetRubrics retrieves all rubrics of a course
+func (s *RubricService) GetRubrics(ctx context.Context, courseID string) ([]*models.Rubric, error) {
+	return s.rubricRepo.FindByCourse(ctx, courseID)
+}
+
+// GetRubric retrieves a rubric and checks that it belongs to the course
+func (s *RubricService) GetRubric(ctx context.Context, courseID, rubricID string) (*models.Rubric, error) {
+	rubric, err := s.rubricRepo.FindByID(ctx, rubricID)
+	if err != nil {
+		return nil, err
+	}
+
+	if rubric == nil || rubric.CourseID != courseID {
+		return nil, errors.New("rubric not found")
+	}
+
+	return rubric, nil
+}
+
+// UpdateRubric updates a rubric. Criteria cannot be replaced once a grade was given with the rubric.
+func (s *RubricService) UpdateRubric(ctx context.Context, courseID, rubricID string, req models.UpdateRubricRequest) (*models.Rubric, error) {
+	rubric, err := s.GetRubric(ctx, courseID, rubricID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Title != nil {
+		rubric.Title = *req.Title
+	}
+	if req.Description != nil {
+		rubric.Description = *req.Description
+	}
+
+	replaceCriteria := req.Criteria != nil
+	if replaceCriteria {
+		used, err := s.rubricRepo.IsUsedForGrading(ctx, rubricID)
+		if err != nil {
+			return nil, err
+		}
+
+		if used {
+			return nil, errors.New("criteria cannot be changed once submissions have been graded with this rubric")
+		}
+
+		rubric.Criteria, err = buildCriteria(req.Criteria)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return s.rubricRepo.Update(ctx, rubric, replaceCriteria)
+}
+
+// DeleteRubric deletes a rubric that is not attached to any assessment
+func (s *RubricService) DeleteRubric(ctx context.Context, courseID, rubricID string) error {
+	if _, err := s.GetRubric(ctx, courseID, rubricID); err != nil {
+		return err
+	}
+
+	attached, err := s.rubricRepo.IsAttached(ctx, rubricID)
+	if err != nil {
+		return err
+	}
+
+	if attached {
+		return errors.New("rubric is attached to an assessment and cannot be deleted")
+	}
+
+	return s.rubricRepo.Delete(ctx, rubricID)
+}
+
+// ensureRubricReplaceable checks that no submission of the assessment was graded with its current rubric
+func (s *RubricService) ensureRubricReplaceable(ctx context.Context, assessment *models.Assessment) error {
+	if assessment.RubricID == nil {
+		return nil
+	}
+
+	graded, err := s.rubricRepo.HasRubricGrades(ctx, assessment.ID)
+	if err != nil {
+		return err
+	}
+
+	if graded {
+		return errors.New("submissions have already been graded with the current rubric")
+	}
+
+	return nil
+}
+
+// AttachRubric makes an assignment or project graded with a rubric of its course
+func (s *RubricService) AttachRubric(ctx context.Context, assessment *models.Assessment, rubricID string) (*models.Assessment, error) {
+	if !assessment.Type.SupportsRubric() {
+		return nil, errors.New("only assignments and projects can be graded with a rubric")
+	}
+
+	if _, err := s.GetRubric(ctx, assessment.CourseID, rubricID); err != nil {
+		return nil, err
+	}
+
+	if err := s.ensureRubricReplaceable(ctx, assessment); err != nil {
+		return nil, err
+	}
+
+	return s.assessmentRepo.SetRubric(ctx, assessment.ID, &rubricID)
+}
+
+// DetachRubric removes the rubric from an assessment
+func (s *RubricService) DetachRubric(ctx context.Context, assessment *models.Assessment) (*models.Assessment, error) {
+	if err := s.ensureRubricReplaceable(ctx, assessment); err != nil {
+		return nil, err
+	}
+
+	return s.assessmentRepo.SetRubric(ctx, assessment.ID, nil)
+}
@@ -0,0 +1,278 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"assessment-management-system/models"
+)
+
+// checkEnrollmentSection checks the section a student enrolls into and returns it as it should be saved: courses
+// with sections need one of their own, courses without sections take none
+func (s *CourseService) checkEnrollmentSection(ctx context.Context, courseID string, sectionID *string) (*string, error) {
+	if sectionID != nil && *sectionID == "" {
+		sectionID = nil
+	}
+
+	if sectionID == nil {
+		sections, err := s.sectionRepo.CountByCourse(ctx, courseID)
+		if err != nil {
+			return nil, err
+		}
+
+		if sections > 0 {
+			return nil, errors.New("this course has sections, choose the section to enroll into")
+		}
+		return nil, nil
+	}
+
+	if _, err := s.findSection(ctx, courseID, *sectionID); err != nil {
+		return nil, err
+	}
+	return sectionID, nil
+}
+
+// findSection retrieves a section of a course
+func (s *CourseService) findSection(ctx context.Context, courseID, sectionID string) (*models.CourseSection, error) {
+	section, err := s.sectionRepo.FindByID(ctx, sectionID)
+	if err != nil {
+		return nil, err
+	}
+
+	if section == nil || section.CourseID != courseID {
+		return nil, errors.New("section not found in this course")
+	}
+
+	return section, nil
+}
+
+// CreateSection creates a section in a course. Section names are unique within the course.
+func (s *CourseService) CreateSection(ctx context.Context, courseID string, req models.CreateSectionRequest) (*models.CourseSection, error) {
+	// Courses of past terms are read-only
+	if _, err := s.findWritableCourse(ctx, courseID); err != nil {
+		return nil, err
+	}
+
+	existing, err := s.sectionRepo.FindByName(ctx, courseID, req.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	if existing != nil {
+		return nil, errors.New("section name already exists in this course")
+	}
+
+	section, err := s.sectionRepo.Create(ctx, courseID, req.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	section.Teachers = []*models.User{}
+	return section, nil
+}
+
+// GetCourseSections retrieves the sections of a course with their teachers and student counts
+func (s *CourseService) GetCourseSections(ctx context.Context, courseID string) ([]*models.CourseSection, error) {
+	sections, err := s.sectionRepo.FindByCourse(ctx, courseID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, section := range sections {
+		if err := s.loadSectionDetails(ctx, section); err != nil {
+			return nil, err
+		}
+	}
+
+	return sections, nil
+}
+
+// GetSection retrieves a section by ID with its teachers and student count
+func (s *CourseService) GetSection(ctx context.Context, id string) (*models.CourseSection, error) {
+	section, err := s.sectionRepo.FindByID(ctx, id)
+	if err != nil || section == nil {
+		return section, err
+	}
+
+	if err := s.loadSectionDetails(ctx, section); err != nil {
+		return nil, err
+	}
+
+	return section, nil
+}
+
+// loadSectionDetails loads the teachers and the student count of a section
+func (s *CourseService) loadSectionDetails(ctx context.Context, section *models.CourseSection) error {
+	teachers, err := s.sectionRepo.FindTeachers(ctx, section.ID)
+	if err != nil {
+		return err
+	}
+
+	studentCount, err := s.sectionRepo.CountStudents(ctx, section.ID)
+	if err != nil {
+		return err
+	}
+
+	section.Teachers, section.StudentCount = teachers, studentCount
+	return nil
+}
+
+// UpdateSection renames a section
+func (s *CourseService) UpdateSection(ctx context.Context, section *models.CourseSection, req models.UpdateSectionRequest) (*models.CourseSection, error) {
+	// Courses of past terms are read-only
+	if _, err := s.findWritableCourse(ctx, section.CourseID); err != nil {
+		return nil, err
+	}
+
+	if req.Name == nil || *req.Name == section.Name {
+		return section, nil
+	}
+
+	existing, err := s.sectionRepo.FindByName(ctx, section.CourseID, *req.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	if existing != nil {
+		return nil, errors.New("section name already exists in this course")
+	}
+
+	updated, err := s.sectionRepo.Update(ctx, section.ID, *req.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.loadSectionDetails(ctx, updated); err != nil {
+		return nil, err
+	}
+
+	return updated, nil
+}
+
+// DeleteSection deletes a section that has no students and no assessments
+func (s *CourseService) DeleteSection(ctx context.Context, section *models.CourseSection) error {
+	// Courses of past terms are read-only
+	if _, err := s.findWritableCourse(ctx, section.CourseID); err != nil {
+		return err
+	}
+
+	students, err := s.sectionRepo.CountStudents(ctx, section.ID)
+	if err != nil {
+		return err
+	}
+
+	if students > 0 {
+		return errors.New("cannot delete a section with enrolled students, move them to another section first")
+	}
+
+	assessments, err := s.sectionRepo.CountAssessments(ctx, section.ID)
+	if err != nil {
+		return err
+	}
+
+	if assessments > 0 {
+		return errors.New("cannot delete a section that assessments are for")
+	}
+
+	return s.sectionRepo.Delete(ctx, section.ID)
+}
+
+// AssignSectionTeacher assigns one of the course's teachers to a section. From then on the teacher only sees and
+// grades the work of the students of their sections.
+func (s *CourseService) AssignSectionTeacher(ctx context.Context, section *models.CourseSection, teacherID string) error {
+	// Courses of past terms are read-only
+	if _, err := s.findWritableCourse(ctx, section.CourseID); err != nil {
+		return err
+	}
+
+	isAssigned, err := s.courseRepo.IsTeacherAssigned(ctx, section.CourseID, teacherID)
+	if err != nil {
+		return err
+	}
+
+	if !isAssigned {
+		return errors.New("teacher is not assigned to this course, assign them to the course first")
+	}
+
+	isAssigned, err = s.sectionRepo.IsTeacherAssigned(ctx, section.ID, teacherID)
+	if err != nil {
+		return err
+	}
+
+	if isAssigned {
+		return errors.New("teacher is already assigned to this section")
+	}
+
+	return s.sectionRepo.AssignTeacher(ctx, section.ID, teacherID)
+}
+
+// RemoveSectionTeacher removes a teacher from a section. Teachers left without sections teach the whole course.
+func (s *CourseService) RemoveSectionTeacher(ctx context.Context, section *models.CourseSection, teacherID string) error {
+	// Courses of past terms are read-only
+	if _, err := s.findWritableCourse(ctx, section.CourseID); err != nil {
+		return err
+	}
+
+	return s.sectionRepo.RemoveTeacher(ctx, section.ID, teacherID)
+}
+
+// GetSectionStudents retrieves the students enrolled in a section
+func (s *CourseService) GetSectionStudents(ctx context.Context, sectionID string) ([]*models.User, error) {
+	return s.sectionRepo.FindStudents(ctx, sectionID)
+}
+
+// ChangeStudentSection moves an enrolled student to another section of the course
+func (s *CourseService) ChangeStudentSection(ctx context.Context, courseID, studentID, sectionID string) error {
+	// Courses of past terms are read-only
+	if _, err := s.findWritableCourse(ctx, courseID); err != nil {
+		return err
+	}
+
+	if _, err := s.findSection(ctx, courseID, sectionID); err != nil {
+		return err
+	}
+
+	return s.sectionRepo.MoveStudent(ctx, courseID, studentID, sectionID)
+}
+
+// GetTeacherSectionIDs retrieves the sections of a course a teacher is assigned to. Teachers without sections teach
+// the whole course.
+func (s *CourseService) GetTeacherSectionIDs(ctx context.Context, courseID, teacherID string) ([]string, error) {
+	return s.sectionRepo.FindTeacherSectionIDs(ctx, courseID, teacherID)
+}
+
+// FilterStudentsForTeacher keeps the students of a course in the sections a teacher is assigned to, or all of them
+// when the teacher teaches the whole course
+func (s *CourseService) FilterStudentsForTeacher(ctx context.Context, courseID, teacherID string, students []*models.User) ([]*models.User, error) {
+	sectionIDs, err := s.sectionRepo.FindTeacherSectionIDs(ctx, courseID, teacherID)
+	if err != nil || len(sectionIDs) == 0 {
+		return students, err
+	}
+
+	studentSections, err := s.sectionRepo.FindStudentSections(ctx, courseID)
+	if err != nil {
+		return nil, err
+	}
+
+	visible := make([]*models.User, 0, len(students))
+	for _, student := range students {
+		if inSections(studentSections[student.ID], sectionIDs) {
+			visible = append(visible, student)
+		}
+	}
+	return visible, nil
+}
+
+// inSections reports whether a section is one of the sections. Students without a section are in none.
+func inSections(sectionID *string, sectionIDs []string) bool {
+	if sectionID == nil {
+		return false
+	}
+
+	for _, id := range sectionIDs {
+		if id == *sectionID {
+			return true
+		}
+	}
+	return false
+}
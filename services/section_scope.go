@@ -0,0 +1,181 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"assessment-management-system/models"
+)
+
+// checkAssessmentSections checks the sections an assessment is for and returns them as they should be saved. Every
+// section must belong to the course. Teachers of particular sections can only set assessments for their own
+// sections, and their assessments are for their sections when they name none.
+func (s *AssessmentService) checkAssessmentSections(ctx context.Context, courseID, teacherID string, sectionIDs []string) ([]string, error) {
+	teacherSections, err := s.sectionRepo.FindTeacherSectionIDs(ctx, courseID, teacherID)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(sectionIDs) == 0 {
+		return teacherSections, nil
+	}
+
+	unique := make([]string, 0, len(sectionIDs))
+	seen := map[string]bool{}
+	for _, id := range sectionIDs {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+
+		section, err := s.sectionRepo.FindByID(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+
+		if section == nil || section.CourseID != courseID {
+			return nil, errors.New("section not found in this course")
+		}
+
+		if len(teacherSections) > 0 && !inSections(&id, teacherSections) {
+			return nil, errors.New("you can only set assessments for the sections you teach")
+		}
+		unique = append(unique, id)
+	}
+
+	return unique, nil
+}
+
+// isForStudent checks if an assessment is for the section a student is enrolled in
+func (s *AssessmentService) isForStudent(ctx context.Context, assessment *models.Assessment, studentID string) (bool, error) {
+	if len(assessment.SectionIDs) == 0 {
+		return true, nil
+	}
+
+	sectionID, err := s.sectionRepo.FindStudentSectionID(ctx, assessment.CourseID, studentID)
+	if err != nil {
+		return false, err
+	}
+
+	return assessment.IsForSection(sectionID), nil
+}
+
+// CanTeacherSeeSubmission checks if a teacher of the course may see and grade a submission. Teachers without
+// sections see every submission; teachers of particular sections see those of their students, and any submission
+// they were asked to second mark.
+func (s *AssessmentService) CanTeacherSeeSubmission(ctx context.Context, courseID, submissionID, teacherID string) (bool, error) {
+	sectionIDs, err := s.sectionRepo.FindTeacherSectionIDs(ctx, courseID, teacherID)
+	if err != nil || len(sectionIDs) == 0 {
+		return err == nil, err
+	}
+
+	moderation, err := s.moderationRepo.FindBySubmission(ctx, submissionID)
+	if err != nil {
+		return false, err
+	}
+
+	if moderation != nil && moderation.SecondMarkerID == teacherID {
+		return true, nil
+	}
+
+	return s.sectionRepo.IsSubmissionInSections(ctx, submissionID, sectionIDs)
+}
+
+// CanTeacherSeeStudent checks if a student of the course is in the sections a teacher teaches, if the teacher has any
+func (s *AssessmentService) CanTeacherSeeStudent(ctx context.Context, courseID, teacherID, studentID string) (bool, error) {
+	sectionIDs, err := s.sectionRepo.FindTeacherSectionIDs(ctx, courseID, teacherID)
+	if err != nil || len(sectionIDs) == 0 {
+		return err == nil, err
+	}
+
+	sectionID, err := s.sectionRepo.FindStudentSectionID(ctx, courseID, studentID)
+	if err != nil {
+		return false, err
+	}
+
+	return inSections(sectionID, sectionIDs), nil
+}
+
+// FilterSubmissionsForTeacher keeps the submissions of a course a teacher may see
+func (s *AssessmentService) FilterSubmissionsForTeacher(ctx context.Context, courseID, teacherID string, submissions []*models.AssessmentSubmission) ([]*models.AssessmentSubmission, error) {
+	visible := make([]*models.AssessmentSubmission, 0, len(submissions))
+	for _, submission := range submissions {
+		canSee, err := s.CanTeacherSeeSubmission(ctx, courseID, submission.ID, teacherID)
+		if err != nil {
+			return nil, err
+		}
+
+		if canSee {
+			visible = append(visible, submission)
+		}
+	}
+	return visible, nil
+}
+
+// studentsForAssessment retrieves the students enrolled in the assessment's course that the assessment is for.
+// With a teacher given, only the students in the sections the teacher teaches are kept, if they teach particular
+// sections.
+func (s *AssessmentService) studentsForAssessment(ctx context.Context, assessment *models.Assessment, teacherID string) ([]*models.User, error) {
+	students, err := s.courseRepo.FindStudentsByCourse(ctx, assessment.CourseID)
+	if err != nil {
+		return nil, err
+	}
+
+	var teacherSections []string
+	if teacherID != "" {
+		if teacherSections, err = s.sectionRepo.FindTeacherSectionIDs(ctx, assessment.CourseID, teacherID); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(assessment.SectionIDs) == 0 && len(teacherSections) == 0 {
+		return students, nil
+	}
+
+	studentSections, err := s.sectionRepo.FindStudentSections(ctx, assessment.CourseID)
+	if err != nil {
+		return nil, err
+	}
+
+	kept := make([]*models.User, 0, len(students))
+	for _, student := range students {
+		sectionID := studentSections[student.ID]
+		if !assessment.IsForSection(sectionID) {
+			continue
+		}
+
+		if len(teacherSections) > 0 && !inSections(sectionID, teacherSections) {
+			continue
+		}
+		kept = append(kept, student)
+	}
+	return kept, nil
+}
+
+// FilterPeerReviewsForTeacher keeps the peer review summaries of the submissions a teacher may see
+func (s *AssessmentService) FilterPeerReviewsForTeacher(ctx context.Context, courseID, teacherID string, summaries []*models.PeerReviewSummary) ([]*models.PeerReviewSummary, error) {
+	visible := make([]*models.PeerReviewSummary, 0, len(summaries))
+	for _, summary := range summaries {
+		canSee, err := s.CanTeacherSeeSubmission(ctx, courseID, summary.SubmissionID, teacherID)
+		if err != nil {
+			return nil, err
+		}
+
+		if canSee {
+			visible = append(visible, summary)
+		}
+	}
+	return visible, nil
+}
+
+// CanTeacherSeeMatch checks if a teacher may see both submissions of a similarity match. Matches across sections
+// are left to the teachers of the whole course.
+func (s *AssessmentService) CanTeacherSeeMatch(ctx context.Context, courseID, teacherID string, match *models.SimilarityMatch) (bool, error) {
+	for _, submissionID := range []string{match.SubmissionID, match.OtherSubmissionID} {
+		canSee, err := s.CanTeacherSeeSubmission(ctx, courseID, submissionID, teacherID)
+		if err != nil || !canSee {
+			return false, err
+		}
+	}
+	return true, nil
+}
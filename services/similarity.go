@@ -0,0 +1,316 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"strings"
+	"time"
+
+	"assessment-management-system/models"
+)
+
+// defaultSimilarityThreshold is the similarity in percent from which pairs of submissions are reported
+const defaultSimilarityThreshold = 30
+
+// similarityCheckTimeout is how long a similarity check may run before it is given up as failed
+const similarityCheckTimeout = 30 * time.Minute
+
+// StartSimilarityCheck starts comparing the latest submissions of an assessment with each other, and with the
+// submissions made earlier to the other assessments of the course when asked to. The check runs in the background;
+// its matches are ready once its status is completed.
+func (s *AssessmentService) StartSimilarityCheck(ctx context.Context, assessment *models.Assessment, teacherID string, req models.StartSimilarityCheckRequest) (*models.SimilarityCheck, error) {
+	threshold := float64(defaultSimilarityThreshold)
+	if req.Threshold != nil {
+		threshold = *req.Threshold
+	}
+
+	check, err := s.similarityRepo.Create(ctx, assessment.ID, req.IncludePrevious, threshold, teacherID)
+	if err != nil {
+		return nil, err
+	}
+
+	go s.runSimilarityCheck(assessment, check)
+
+	return check, nil
+}
+
+// runSimilarityCheck compares the submissions of a check and stores its matches, or the error that stopped it
+func (s *AssessmentService) runSimilarityCheck(assessment *models.Assessment, check *models.SimilarityCheck) {
+	// A check that panics must not take the server down, nor stay running forever
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("Warning: similarity check %s panicked: %v", check.ID, r)
+			s.failSimilarityCheck(check, fmt.Sprintf("the check stopped unexpectedly: %v", r))
+		}
+	}()
+
+	// The request that started the check is long gone
+	ctx, cancel := context.WithTimeout(context.Background(), similarityCheckTimeout)
+	defer cancel()
+
+	checked, matches, err := s.findSimilarSubmissions(ctx, assessment, check)
+	if err == nil {
+		err = s.similarityRepo.Complete(ctx, check.ID, checked, matches)
+	}
+
+	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			err = errors.New("the check took too long")
+		}
+		s.failSimilarityCheck(check, err.Error())
+	}
+}
+
+// failSimilarityCheck records why a similarity check failed. It does not use the check's own context, which may
+// have timed out.
+func (s *AssessmentService) failSimilarityCheck(check *models.SimilarityCheck, message string) {
+	if err := s.similarityRepo.Fail(context.Background(), check.ID, message); err != nil {
+		log.Printf("Warning: failed to record the failure of similarity check %s: %v", check.ID, err)
+	}
+}
+
+// findSimilarSubmissions compares the fingerprints of every pair of latest submissions, and of every latest
+// submission with every one made to the assessment's earlier offerings, and returns the number of submissions
+// checked with the pairs at or above the check's threshold. Work is never compared with work by the same student,
+// alone or in a group.
+func (s *AssessmentService) findSimilarSubmissions(ctx context.Context, assessment *models.Assessment, check *models.SimilarityCheck) (int, []*models.SimilarityMatch, error) {
+	submissions, err := s.assessmentRepo.FindSubmissionsByAssessment(ctx, assessment.ID)
+	if err != nil {
+		return 0, nil, err
+	}
+	current := latestBySubmitter(submissions)
+
+	var earlier []*models.AssessmentSubmission
+	if check.IncludePrevious {
+		submissions, err := s.assessmentRepo.FindPreviousOfferingSubmissions(ctx, assessment.ID)
+		if err != nil {
+			return 0, nil, err
+		}
+		earlier = latestBySubmitter(submissions)
+	}
+
+	fingerprints := map[string][]fingerprint{}
+	submitters := map[string]map[string]bool{}
+	for _, submission := range append(current, earlier...) {
+		text, err := s.similarityText(ctx, submission)
+		if err != nil {
+			return 0, nil, err
+		}
+		fingerprints[submission.ID] = winnow(text)
+
+		if submitters[submission.ID], err = s.submitters(ctx, submission); err != nil {
+			return 0, nil, err
+		}
+	}
+
+	matches := []*models.SimilarityMatch{}
+	compare := func(submission, other *models.AssessmentSubmission) {
+		if sharesSubmitter(submitters[submission.ID], submitters[other.ID]) {
+			return
+		}
+
+		similarity, regions := compareFingerprints(fingerprints[submission.ID], fingerprints[other.ID])
+		similarity = math.Round(similarity*100) / 100
+		if similarity < check.Threshold {
+			return
+		}
+
+		matches = append(matches, &models.SimilarityMatch{
+			SubmissionID:      submission.ID,
+			OtherSubmissionID: other.ID,
+			Similarity:        similarity,
+			Regions:           regions,
+		})
+	}
+
+	for i, submission := range current {
+		if err := ctx.Err(); err != nil {
+			return 0, nil, err
+		}
+
+		for _, other := range current[i+1:] {
+			compare(submission, other)
+		}
+		for _, other := range earlier {
+			compare(submission, other)
+		}
+	}
+
+	return len(current) + len(earlier), matches, nil
+}
+
+// submitters returns the students who made a submission: the student, or the members of the group at the time
+func (s *AssessmentService) submitters(ctx context.Context, submission *models.AssessmentSubmission) (map[string]bool, error) {
+	students := map[string]bool{submission.StudentID: true}
+	if submission.GroupID == nil {
+		return students, nil
+	}
+
+	members, err := s.groupRepo.FindSubmissionMembers(ctx, submission.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, member := range members {
+		students[member.StudentID] = true
+	}
+	return students, nil
+}
+
+// latestBySubmitter keeps the latest attempt of every student or group at every assessment
+func latestBySubmitter(submissions []*models.AssessmentSubmission) []*models.AssessmentSubmission {
+	latest := []*models.AssessmentSubmission{}
+	index := map[string]int{}
+	for _, submission := range submissions {
+		submitter := submission.AssessmentID + "/" + submission.StudentID
+		if submission.GroupID != nil {
+			submitter = submission.AssessmentID + "/group/" + *submission.GroupID
+		}
+
+		i, ok := index[submitter]
+		switch {
+		case !ok:
+			index[submitter] = len(latest)
+			latest = append(latest, submission)
+		case submission.AttemptNumber > latest[i].AttemptNumber:
+			latest[i] = submission
+		}
+	}
+	return latest
+}
+
+// similarityText is the text of a submission that similarity checks compare: its content followed by its text
+// answers. Match regions are character offsets into this text.
+func (s *AssessmentService) similarityText(ctx context.Context, submission *models.AssessmentSubmission) (string, error) {
+	answers, err := s.questionRepo.FindAnswersBySubmission(ctx, submission.ID)
+	if err != nil {
+		return "", err
+	}
+
+	parts := []string{}
+	if submission.Content != "" {
+		parts = append(parts, submission.Content)
+	}
+	for _, answer := range answers {
+		if answer.Answer != nil && answer.Answer.Text != nil && *answer.Answer.Text != "" {
+			parts = append(parts, *answer.Answer.Text)
+		}
+	}
+	return strings.Join(parts, "\n\n"), nil
+}
+
+// GetSimilarityChecks retrieves the similarity checks of an assessment, newest first
+func (s *AssessmentService) GetSimilarityChecks(ctx context.Context, assessmentID string) ([]*models.SimilarityCheck, error) {
+	return s.similarityRepo.FindByAssessment(ctx, assessmentID)
+}
+
+// GetSimilarityCheck retrieves a similarity check of an assessment with its matches, most similar first. Students
+// hidden by anonymous grading are shown by their pseudonym.
+func (s *AssessmentService) GetSimilarityCheck(ctx context.Context, assessment *models.Assessment, checkID string) (*models.SimilarityCheck, error) {
+	check, err := s.similarityRepo.FindByID(ctx, checkID)
+	if err != nil {
+		return nil, err
+	}
+
+	if check == nil || check.AssessmentID != assessment.ID {
+		return nil, nil
+	}
+
+	matches, err := s.similarityRepo.FindMatches(ctx, check.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	assessments := map[string]*models.Assessment{assessment.ID: assessment}
+	for _, match := range matches {
+		if _, _, err := s.identifyMatch(ctx, assessments, match); err != nil {
+			return nil, err
+		}
+	}
+
+	check.Matches = matches
+	return check, nil
+}
+
+// GetSimilarityReport retrieves a match of a similarity check with the compared texts of both submissions, for
+// highlighting the regions they share. It returns nil when the check or the match is not found.
+func (s *AssessmentService) GetSimilarityReport(ctx context.Context, assessment *models.Assessment, checkID, matchID string) (*models.SimilarityMatch, error) {
+	check, err := s.similarityRepo.FindByID(ctx, checkID)
+	if err != nil {
+		return nil, err
+	}
+
+	if check == nil || check.AssessmentID != assessment.ID {
+		return nil, nil
+	}
+
+	match, err := s.similarityRepo.FindMatch(ctx, matchID)
+	if err != nil {
+		return nil, err
+	}
+
+	if match == nil || match.CheckID != check.ID {
+		return nil, nil
+	}
+
+	assessments := map[string]*models.Assessment{assessment.ID: assessment}
+	submission, other, err := s.identifyMatch(ctx, assessments, match)
+	if err != nil {
+		return nil, err
+	}
+
+	if match.Text, err = s.similarityText(ctx, submission); err != nil {
+		return nil, err
+	}
+	if match.OtherText, err = s.similarityText(ctx, other); err != nil {
+		return nil, err
+	}
+
+	return match, nil
+}
+
+// identifyMatch fills in the students of both submissions of a match, masked while their assessment is graded
+// anonymously, and returns the submissions. Assessments are looked up once in the given cache.
+func (s *AssessmentService) identifyMatch(ctx context.Context, assessments map[string]*models.Assessment, match *models.SimilarityMatch) (*models.AssessmentSubmission, *models.AssessmentSubmission, error) {
+	submission, err := s.maskedSubmission(ctx, assessments, match.SubmissionID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	other, err := s.maskedSubmission(ctx, assessments, match.OtherSubmissionID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	match.StudentID, match.Pseudonym = submission.StudentID, submission.Pseudonym
+	match.OtherStudentID, match.OtherPseudonym = other.StudentID, other.Pseudonym
+	return submission, other, nil
+}
+
+// maskedSubmission retrieves a submission with its student masked by its assessment's anonymous grading
+func (s *AssessmentService) maskedSubmission(ctx context.Context, assessments map[string]*models.Assessment, submissionID string) (*models.AssessmentSubmission, error) {
+	submission, err := s.assessmentRepo.FindSubmissionByID(ctx, submissionID)
+	if err != nil {
+		return nil, err
+	}
+
+	if submission == nil {
+		return nil, errors.New("submission not found")
+	}
+
+	assessment, ok := assessments[submission.AssessmentID]
+	if !ok {
+		if assessment, err = s.assessmentRepo.FindByID(ctx, submission.AssessmentID); err != nil {
+			return nil, err
+		}
+		assessments[submission.AssessmentID] = assessment
+	}
+
+	if _, err := s.maskSubmission(ctx, assessment, submission); err != nil {
+		return nil, err
+	}
+	return submission, nil
+}
@@ -0,0 +1,128 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"assessment-management-system/models"
+	"assessment-management-system/repositories"
+)
+
+// TermService handles academic term business logic
+type TermService struct {
+	termRepo *repositories.TermRepository
+	orgRepo  *repositories.OrganizationRepository
+}
+
+// NewTermService creates a new TermService
+func NewTermService(termRepo *repositories.TermRepository, orgRepo *repositories.OrganizationRepository) *TermService {
+	return &TermService{
+		termRepo: termRepo,
+		orgRepo:  orgRepo,
+	}
+}
+
+// CreateTerm creates an academic term in an organization
+func (s *TermService) CreateTerm(ctx context.Context, organizationID string, req models.CreateTermRequest) (*models.Term, error) {
+	org, err := s.orgRepo.FindByID(ctx, organizationID)
+	if err != nil {
+		return nil, err
+	}
+
+	if org == nil {
+		return nil, errors.New("organization not found")
+	}
+
+	existing, err := s.termRepo.FindByName(ctx, organizationID, req.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	if existing != nil {
+		return nil, errors.New("term name already exists in this organization")
+	}
+
+	return s.termRepo.Create(ctx, organizationID, req)
+}
+
+// GetTerms retrieves the terms of an organization, latest first
+func (s *TermService) GetTerms(ctx context.Context, organizationID string) ([]*models.Term, error) {
+	return s.termRepo.FindByOrganization(ctx, organizationID)
+}
+
+// GetTerm retrieves a term by ID
+func (s *TermService) GetTerm(ctx context.Context, id string) (*models.Term, error) {
+	return s.termRepo.FindByID(ctx, id)
+}
+
+// UpdateTerm updates the name and dates of a term. Past terms are read-only, and the dates of the assessments of
+// the term's courses must stay within the term.
+func (s *TermService) UpdateTerm(ctx context.Context, term *models.Term, req models.UpdateTermRequest) (*models.Term, error) {
+	if term.HasEnded(time.Now()) {
+		return nil, errors.New("the term has ended, it is read-only")
+	}
+
+	name, startDate, endDate := term.Name, term.StartDate, term.EndDate
+	if req.Name != nil && *req.Name != term.Name {
+		existing, err := s.termRepo.FindByName(ctx, term.OrganizationID, *req.Name)
+		if err != nil {
+			return nil, err
+		}
+
+		if existing != nil {
+			return nil, errors.New("term name already exists in this organization")
+		}
+		name = *req.Name
+	}
+	if req.StartDate != nil {
+		startDate = *req.StartDate
+	}
+	if req.EndDate != nil {
+		endDate = *req.EndDate
+	}
+
+	if !endDate.After(startDate) {
+		return nil, errors.New("the term must end after it starts")
+	}
+
+	outside, err := s.termRepo.CountAssessmentsOutside(ctx, term.ID, startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
+
+	if outside > 0 {
+		return nil, fmt.Errorf("%d assessments of the term's courses have dates outside the new dates", outside)
+	}
+
+	return s.termRepo.Update(ctx, term.ID, name, startDate, endDate)
+}
+
+// DeleteTerm deletes a term that no course is offered in
+func (s *TermService) DeleteTerm(ctx context.Context, term *models.Term) error {
+	courses, err := s.termRepo.CountCourses(ctx, term.ID)
+	if err != nil {
+		return err
+	}
+
+	if courses > 0 {
+		return errors.New("cannot delete a term with courses")
+	}
+
+	return s.termRepo.Delete(ctx, term.ID)
+}
+
+// validateTermDates checks that the dates of an assessment fall within the term of its course, if the course has one
+func validateTermDates(course *models.Course, dates ...*time.Time) error {
+	if course.Term == nil {
+		return nil
+	}
+
+	for _, date := range dates {
+		if date != nil && !course.Term.Contains(*date) {
+			return fmt.Errorf("assessment dates must fall within the term %s", course.Term.Name)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,114 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"assessment-management-system/models"
+)
+
+// StartAttempt starts a timed attempt for a student, or returns the attempt that is still running. The time
+// window is the assessment's time limit, cut short by the submission deadline.
+func (s *AssessmentService) StartAttempt(ctx context.Context, assessmentID, studentID string) (*models.AttemptSession, error) {
+	assessment, err := s.GetAssessmentForStudent(ctx, assessmentID, studentID)
+	if err != nil {
+		return nil, err
+	}
+
+	if assessment == nil {
+		return nil, errors.New("assessment not found")
+	}
+
+	if assessment.Status != models.AssessmentStatusPublished {
+		return nil, errors.New("assessment is not open for submissions")
+	}
+
+	if !assessment.IsTimed() {
+		return nil, errors.New("this assessment has no time limit")
+	}
+
+	isEnrolled, err := s.courseRepo.IsStudentEnrolled(ctx, assessment.CourseID, studentID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !isEnrolled {
+		return nil, errors.New("student is not enrolled in this course")
+	}
+
+	now := time.Now()
+	active, err := s.getActiveAttempt(ctx, assessment, studentID, now)
+	if err != nil || active != nil {
+		return active, err
+	}
+
+	deadline := assessment.SubmissionDeadline()
+	if deadline != nil && deadline.Before(now) {
+		return nil, errors.New("assessment is past due")
+	}
+
+	used, err := s.countAttemptsUsed(ctx, assessment, studentID)
+	if err != nil {
+		return nil, err
+	}
+
+	if used >= assessment.MaxAttempts {
+		return nil, fmt.Errorf("all %d attempts at this assessment have been used", assessment.MaxAttempts)
+	}
+
+	endsAt := now.Add(time.Duration(*assessment.TimeLimitMinutes) * time.Minute)
+	if deadline != nil && deadline.Before(endsAt) {
+		endsAt = *deadline
+	}
+
+	return s.assessmentRepo.CreateAttemptSession(ctx, assessment.ID, studentID, now, endsAt)
+}
+
+// getActiveAttempt returns the student's running attempt at a timed assessment, or nil when no attempt
+// accepts a submission at the given time
+func (s *AssessmentService) getActiveAttempt(ctx context.Context, assessment *models.Assessment, studentID string, at time.Time) (*models.AttemptSession, error) {
+	session, err := s.assessmentRepo.FindOpenAttemptSession(ctx, assessment.ID, studentID)
+	if err != nil || session == nil {
+		return nil, err
+	}
+
+	if !session.AcceptsSubmissionAt(at, assessment.GracePeriodSeconds) {
+		return nil, nil
+	}
+	return session, nil
+}
+
+// GetActiveAttempt returns the student's running attempt at a timed assessment, if any
+func (s *AssessmentService) GetActiveAttempt(ctx context.Context, assessment *models.Assessment, studentID string) (*models.AttemptSession, error) {
+	return s.getActiveAttempt(ctx, assessment, studentID, time.Now())
+}
+
+// countAttemptsUsed counts the attempts a student has used. Every started timed attempt counts, even when
+// it ran out without a submission, and at group assessments so does every attempt of the student's group.
+func (s *AssessmentService) countAttemptsUsed(ctx context.Context, assessment *models.Assessment, studentID string) (int, error) {
+	submitted, err := s.assessmentRepo.CountAttempts(ctx, assessment.ID, studentID)
+	if err != nil {
+		return 0, err
+	}
+
+	if assessment.GroupSubmission {
+		_, groupAttempts, err := s.findSubmittingGroup(ctx, assessment, studentID)
+		if err != nil {
+			return 0, err
+		}
+		submitted = max(submitted, groupAttempts)
+	}
+
+	if !assessment.IsTimed() {
+		return submitted, nil
+	}
+
+	started, err := s.assessmentRepo.CountAttemptSessions(ctx, assessment.ID, studentID)
+	if err != nil {
+		return 0, err
+	}
+
+	return max(submitted, started), nil
+}
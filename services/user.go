@@ -16,6 +16,7 @@ type UserService struct {
 	orgRepo        *repositories.OrganizationRepository
 	courseRepo     *repositories.CourseRepository
 	assessmentRepo *repositories.AssessmentRepository
+	rubricRepo     *repositories.RubricRepository
 }
 
 // NewUserService creates a new UserService
@@ -24,12 +25,14 @@ func NewUserService(
 	orgRepo *repositories.OrganizationRepository,
 	courseRepo *repositories.CourseRepository,
 	assessmentRepo *repositories.AssessmentRepository,
+	rubricRepo *repositories.RubricRepository,
 ) *UserService {
 	return &UserService{
 		userRepo:       userRepo,
 		orgRepo:        orgRepo,
 		courseRepo:     courseRepo,
 		assessmentRepo: assessmentRepo,
+		rubricRepo:     rubricRepo,
 	}
 }
 
@@ -294,12 +297,19 @@ func (s *UserService) GetTeacherStats(ctx context.Context, teacherID, organizati
 		return nil, err
 	}
 
+	// Get per-criterion rubric averages
+	rubricCriteria, err := s.rubricRepo.FindCriterionStatsByTeacher(ctx, teacherID)
+	if err != nil {
+		return nil, err
+	}
+
 	// Create the stats object
 	stats := &models.TeacherStats{
 		User:               user,
 		AssignedCourses:    assignedCourses,
 		CreatedAssessments: createdAssessments,
 		PendingGrading:     pendingGrading,
+		RubricCriteria:     rubricCriteria,
 	}
 
 	return stats, nil
@@ -341,6 +351,12 @@ func (s *UserService) GetStudentStats(ctx context.Context, studentID, organizati
 		return nil, err
 	}
 
+	// Get per-criterion rubric averages
+	rubricCriteria, err := s.rubricRepo.FindCriterionStatsByStudent(ctx, studentID)
+	if err != nil {
+		return nil, err
+	}
+
 	// Create the stats object
 	stats := &models.StudentStats{
 		User:                 user,
@@ -348,6 +364,7 @@ func (s *UserService) GetStudentStats(ctx context.Context, studentID, organizati
 		CompletedAssessments: completedAssessments,
 		PendingAssessments:   pendingAssessments,
 		AverageGrade:         averageGrade,
+		RubricCriteria:       rubricCriteria,
 	}
 
 	return stats, nil
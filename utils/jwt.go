@@ -1,6 +1,8 @@
 package utils
 
 import (
+	"crypto/hmac"
+	"crypto/sha256"
 	"errors"
 	"time"
 
@@ -72,3 +74,53 @@ func ValidateToken(tokenString, jwtSecret string) (*JWTClaims, error) {
 
 	return nil, errors.New("invalid token")
 }
+
+// InviteClaims represents the claims in a signed course invite link
+type InviteClaims struct {
+	JoinCodeID string `json:"join_code_id"`
+	jwt.RegisteredClaims
+}
+
+// inviteKey derives the key invite links are signed with from the JWT secret, so that invite links and login
+// tokens can never be used in place of each other
+func inviteKey(jwtSecret string) []byte {
+	mac := hmac.New(sha256.New, []byte(jwtSecret))
+	mac.Write([]byte("course-invite"))
+	return mac.Sum(nil)
+}
+
+// GenerateInviteToken generates a signed token for an invite link to a join code, valid until the code expires
+func GenerateInviteToken(joinCodeID string, expiresAt time.Time, jwtSecret string) (string, error) {
+	claims := InviteClaims{
+		JoinCodeID: joinCodeID,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+		},
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	return token.SignedString(inviteKey(jwtSecret))
+}
+
+// ValidateInviteToken validates the token of an invite link and returns the ID of its join code
+func ValidateInviteToken(tokenString, jwtSecret string) (string, error) {
+	token, err := jwt.ParseWithClaims(tokenString, &InviteClaims{}, func(token *jwt.Token) (interface{}, error) {
+		// Make sure the signing method is correct
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, errors.New("unexpected signing method")
+		}
+
+		return inviteKey(jwtSecret), nil
+	})
+
+	if err != nil {
+		return "", err
+	}
+
+	if claims, ok := token.Claims.(*InviteClaims); ok && token.Valid && claims.JoinCodeID != "" {
+		return claims.JoinCodeID, nil
+	}
+
+	return "", errors.New("invalid invite link")
+}